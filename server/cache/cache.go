@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+)
+
+const (
+	TypeMemory = "memory"
+	TypeRedis  = "redis"
+	TypeNone   = "none"
+
+	defaultCapacity = 10000
+	defaultTTL      = 60 * time.Second
+)
+
+// Cache 通用的键值缓存，值统一为序列化后的字节，便于本地与 Redis 实现互换
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	// Set 写入缓存，ttl <= 0 表示不过期
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+	Delete(ctx context.Context, keys ...string)
+}
+
+var (
+	Instance Cache
+	TTL      = defaultTTL
+)
+
+// Init 根据配置初始化缓存，未配置或配置为 none 时不启用缓存
+func Init() {
+	conf := config.Config.CacheConfig
+	if conf == nil || conf.Type == "" || conf.Type == TypeNone {
+		log.Logger.Infof("Cache is disabled")
+		return
+	}
+	if conf.TTLSeconds > 0 {
+		TTL = time.Duration(conf.TTLSeconds) * time.Second
+	}
+	switch conf.Type {
+	case TypeMemory:
+		capacity := conf.Capacity
+		if capacity <= 0 {
+			capacity = defaultCapacity
+		}
+		Instance = NewMemoryCache(capacity)
+	case TypeRedis:
+		if conf.Redis == nil || conf.Redis.Addr == "" {
+			panic("cache type is redis but redis address is not configured")
+		}
+		Instance = NewRedisCache(conf.Redis.Addr, conf.Redis.Password, conf.Redis.DB)
+	default:
+		panic("unsupported cache type: " + conf.Type)
+	}
+	log.Logger.Infof("Cache initialized, type: %s, ttl: %v", conf.Type, TTL)
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryEntry struct {
+	key      string
+	value    []byte
+	expireAt time.Time // 零值表示不过期
+}
+
+// MemoryCache 进程内 LRU 缓存，超过容量时淘汰最久未访问的键
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+	now      func() time.Time
+}
+
+func NewMemoryCache(capacity int) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+func (m *MemoryCache) Get(_ context.Context, key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	elem, ok := m.items[key]
+	if !ok {
+		return nil, false
+	}
+	entry := elem.Value.(*memoryEntry)
+	if !entry.expireAt.IsZero() && m.now().After(entry.expireAt) {
+		m.removeElement(elem)
+		return nil, false
+	}
+	m.ll.MoveToFront(elem)
+	return entry.value, true
+}
+
+func (m *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var expireAt time.Time
+	if ttl > 0 {
+		expireAt = m.now().Add(ttl)
+	}
+	if elem, ok := m.items[key]; ok {
+		entry := elem.Value.(*memoryEntry)
+		entry.value = value
+		entry.expireAt = expireAt
+		m.ll.MoveToFront(elem)
+		return
+	}
+	m.items[key] = m.ll.PushFront(&memoryEntry{key: key, value: value, expireAt: expireAt})
+	for m.ll.Len() > m.capacity {
+		m.removeElement(m.ll.Back())
+	}
+}
+
+func (m *MemoryCache) Delete(_ context.Context, keys ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		if elem, ok := m.items[key]; ok {
+			m.removeElement(elem)
+		}
+	}
+}
+
+func (m *MemoryCache) removeElement(elem *list.Element) {
+	m.ll.Remove(elem)
+	delete(m.items, elem.Value.(*memoryEntry).key)
+}
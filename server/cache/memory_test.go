@@ -0,0 +1,60 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Get returns value that was set", func(t *testing.T) {
+		c := NewMemoryCache(2)
+		c.Set(ctx, "a", []byte("1"), time.Minute)
+		value, ok := c.Get(ctx, "a")
+		if !ok || string(value) != "1" {
+			t.Errorf("Expected hit with value 1, got %q, %v", value, ok)
+		}
+	})
+
+	t.Run("Least recently used key is evicted when over capacity", func(t *testing.T) {
+		c := NewMemoryCache(2)
+		c.Set(ctx, "a", []byte("1"), 0)
+		c.Set(ctx, "b", []byte("2"), 0)
+		c.Get(ctx, "a") // a becomes the most recently used
+		c.Set(ctx, "c", []byte("3"), 0)
+		if _, ok := c.Get(ctx, "b"); ok {
+			t.Errorf("Expected b to be evicted")
+		}
+		if _, ok := c.Get(ctx, "a"); !ok {
+			t.Errorf("Expected a to be kept")
+		}
+		if _, ok := c.Get(ctx, "c"); !ok {
+			t.Errorf("Expected c to be kept")
+		}
+	})
+
+	t.Run("Expired key is treated as miss", func(t *testing.T) {
+		c := NewMemoryCache(2)
+		now := time.Now()
+		c.now = func() time.Time { return now }
+		c.Set(ctx, "a", []byte("1"), time.Second)
+		now = now.Add(2 * time.Second)
+		if _, ok := c.Get(ctx, "a"); ok {
+			t.Errorf("Expected a to be expired")
+		}
+		if c.ll.Len() != 0 {
+			t.Errorf("Expected expired entry to be removed, got %d entries", c.ll.Len())
+		}
+	})
+
+	t.Run("Delete removes keys", func(t *testing.T) {
+		c := NewMemoryCache(2)
+		c.Set(ctx, "a", []byte("1"), 0)
+		c.Delete(ctx, "a", "missing")
+		if _, ok := c.Get(ctx, "a"); ok {
+			t.Errorf("Expected a to be deleted")
+		}
+	})
+}
@@ -0,0 +1,55 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCache 基于 Redis 的缓存，多副本共享，写入失效对所有副本立即生效
+type RedisCache struct {
+	client *redis.Client
+}
+
+func NewRedisCache(addr string, password string, db int) *RedisCache {
+	return &RedisCache{
+		client: redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: password,
+			DB:       db,
+		}),
+	}
+}
+
+// Get 读取失败时按未命中处理，缓存故障不影响主流程
+func (r *RedisCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	value, err := r.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			log.Logger.Warnf("RedisCache: Get: Failed to get key %s: %v", key, err)
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+func (r *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	if ttl < 0 {
+		ttl = 0
+	}
+	if err := r.client.Set(ctx, key, value, ttl).Err(); err != nil {
+		log.Logger.Warnf("RedisCache: Set: Failed to set key %s: %v", key, err)
+	}
+}
+
+func (r *RedisCache) Delete(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		log.Logger.Warnf("RedisCache: Delete: Failed to delete keys %v: %v", keys, err)
+	}
+}
@@ -17,6 +17,15 @@ type Conf struct {
 	MySQLConfig *MySQL               `mapstructure:"mysql"`
 	S3Config    *S3Config            `mapstructure:"s3Config"`
 	KafkaConfig *KafkaConsumerConfig `mapstructure:"kafka"`
+	CacheConfig *CacheConfig         `mapstructure:"cache"`
+
+	SchedulerConfig *SchedulerConfig `mapstructure:"scheduler"`
+	AdminConfig     *AdminConfig     `mapstructure:"admin"`
+
+	PublishCheckConfig *PublishCheckConfig `mapstructure:"publish_checks"`
+	PricingConfig      *PricingConfig      `mapstructure:"pricing"`
+	CurrencyConfig     *CurrencyConfig     `mapstructure:"currency"`
+	CartConfig         *CartConfig         `mapstructure:"cart"`
 }
 
 type KafkaConsumerConfig struct {
@@ -26,6 +35,67 @@ type KafkaConsumerConfig struct {
 	CommitInterval int      `mapstructure:"commit_interval"`
 }
 
+type CacheConfig struct {
+	Type       string       `mapstructure:"type"` // memory | redis | none
+	Capacity   int          `mapstructure:"capacity"`
+	TTLSeconds int          `mapstructure:"ttl_seconds"`
+	Redis      *RedisConfig `mapstructure:"redis"`
+}
+
+type RedisConfig struct {
+	Addr     string `mapstructure:"addr"`
+	Password string `mapstructure:"password"`
+	DB       int    `mapstructure:"db"`
+}
+
+type SchedulerConfig struct {
+	IntervalSeconds int `mapstructure:"interval_seconds"` // 扫描到期任务的间隔
+	LeaseSeconds    int `mapstructure:"lease_seconds"`    // 领取任务后的独占时长
+	BatchSize       int `mapstructure:"batch_size"`
+	MaxAttempts     int `mapstructure:"max_attempts"` // 执行出错的任务最多尝试的次数，超过后标记为失败
+
+	GuestCartPurgeIntervalSeconds int `mapstructure:"guest_cart_purge_interval_seconds"` // 清理过期游客购物车的间隔
+}
+
+type AdminConfig struct {
+	UserIDs []int `mapstructure:"user_ids"` // 允许访问 /admin 接口的用户
+}
+
+// PublishCheckConfig 商品上架前的检查项
+type PublishCheckConfig struct {
+	RequiredFields       []string `mapstructure:"required_fields"` // 不能为空的字段，使用 json 字段名
+	RequireImage         bool     `mapstructure:"require_image"`
+	RequirePositivePrice bool     `mapstructure:"require_positive_price"`
+	RequirePositiveStock bool     `mapstructure:"require_positive_stock"`
+	Categories           []string `mapstructure:"categories"` // 允许的分类，为空时不限制
+}
+
+// PricingConfig 购物车价格估算规则，金额单位均为分
+type PricingConfig struct {
+	DefaultRegion string                    `mapstructure:"default_region"` // 请求未指定地区时使用
+	Regions       map[string]*RegionPricing `mapstructure:"regions"`        // key 为地区代码，不区分大小写
+}
+
+type RegionPricing struct {
+	TaxRateBps            int  `mapstructure:"tax_rate_bps"`            // 税率，单位万分之一，900 即 9%
+	TaxShipping           bool `mapstructure:"tax_shipping"`            // 运费是否计税
+	ShippingFee           int  `mapstructure:"shipping_fee"`            // 运费
+	FreeShippingThreshold int  `mapstructure:"free_shipping_threshold"` // 商品金额超过该值免运费，0 表示不免运费
+}
+
+// CurrencyConfig 商品价格以基础货币的最小单位存储，其他币种仅用于展示
+type CurrencyConfig struct {
+	Base      string `mapstructure:"base"`       // 基础货币代码 (ISO 4217)
+	Decimals  int    `mapstructure:"decimals"`   // 基础货币最小单位的小数位数，分为 2
+	RatesFile string `mapstructure:"rates_file"` // 汇率文件，为空时只支持基础货币
+}
+
+// CartConfig 购物车上限，0 表示不限制
+type CartConfig struct {
+	MaxDistinctItems   int `mapstructure:"max_distinct_items"`    // 购物车内商品种类数上限
+	MaxQuantityPerItem int `mapstructure:"max_quantity_per_item"` // 每种商品的数量上限
+}
+
 type HttpConfig struct {
 	Host string `mapstructure:"host"`
 	Port int    `mapstructure:"port"`
@@ -83,4 +153,8 @@ func Init() {
 	} else {
 		panic("MYSQL_PASSWORD environment variable is not set")
 	}
+	redisPassword := os.Getenv("REDIS_PASSWORD")
+	if redisPassword != "" && Config.CacheConfig != nil && Config.CacheConfig.Redis != nil {
+		Config.CacheConfig.Redis.Password = redisPassword
+	}
 }
@@ -15,6 +15,129 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/exchange-rates/{currency}": {
+            "put": {
+                "description": "Admin only. Overrides the rate from the rates file, or adds a currency the file does not list. Takes effect on the next request of every replica",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Currency"
+                ],
+                "summary": "Set an exchange rate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ISO 4217 currency code",
+                        "name": "currency",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "exchange rate",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/data.SetExchangeRateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/products/purge": {
+            "post": {
+                "description": "管理员接口，永久删除软删除超过指定天数的商品及其标签、专题关联、状态记录和定时任务，删除后无法恢复",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "管理"
+                ],
+                "summary": "永久删除已软删除的商品",
+                "parameters": [
+                    {
+                        "description": "清理条件",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PurgeDeletedProductsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除的商品数",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "没有管理员权限",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/customer/cart": {
             "get": {
                 "description": "Get user's cart info",
@@ -28,6 +151,14 @@ const docTemplate = `{
                     "Cart"
                 ],
                 "summary": "Get user's cart info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "display currency (ISO 4217), defaults to the base currency",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -47,6 +178,100 @@ const docTemplate = `{
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/customer/cart/coupon": {
+            "put": {
+                "description": "Apply a promo code to the cart, replacing any code applied before. The price estimate reports whether it takes effect.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Apply a coupon to the cart",
+                "parameters": [
+                    {
+                        "description": "coupon code",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/data.ApplyCouponRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the promo code applied to the cart",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Remove the coupon from the cart",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -250,9 +475,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/customer/cart/price-estimate": {
-            "get": {
-                "description": "Calculate order price",
+        "/customer/cart/items/batch-delete": {
+            "post": {
+                "description": "Delete several cart items at once. Items not in the cart are reported in the results",
                 "consumes": [
                     "application/json"
                 ],
@@ -262,7 +487,18 @@ const docTemplate = `{
                 "tags": [
                     "Cart"
                 ],
-                "summary": "Calculate order price",
+                "summary": "Delete several cart items",
+                "parameters": [
+                    {
+                        "description": "items to delete",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/data.CartBatchDeleteRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -275,13 +511,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/data.CartPriceEstimateResult"
+                                            "$ref": "#/definitions/data.CartBatchResult"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -297,9 +539,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/customer/cart/selected-num": {
-            "get": {
-                "description": "Get number of selected items in cart",
+        "/customer/cart/items/quantities": {
+            "put": {
+                "description": "Update several quantities at once. Items that are not in the cart or lack stock keep their quantity and are reported in the results; the others are updated together",
                 "consumes": [
                     "application/json"
                 ],
@@ -309,7 +551,18 @@ const docTemplate = `{
                 "tags": [
                     "Cart"
                 ],
-                "summary": "Get number of selected items in cart",
+                "summary": "Update the quantities of several cart items",
+                "parameters": [
+                    {
+                        "description": "new quantities",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/data.CartQuantityUpdateRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -322,13 +575,19 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "type": "integer"
+                                            "$ref": "#/definitions/data.CartBatchResult"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -344,9 +603,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/customer/product/{id}": {
-            "get": {
-                "description": "根据商品ID获取商品详细信息",
+        "/customer/cart/items/{item_id}/save": {
+            "post": {
+                "description": "Remove the item from the cart and save it for later, or put it on the wishlist",
                 "consumes": [
                     "application/json"
                 ],
@@ -354,21 +613,82 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "商品"
+                    "Saved Items"
                 ],
-                "summary": "获取商品详情(用户侧)",
+                "summary": "Move a cart item to a saved list",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "商品ID",
-                        "name": "id",
+                        "description": "cart item ID",
+                        "name": "item_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "target list",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/data.MoveFromCartRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "成功",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/customer/cart/merge": {
+            "post": {
+                "description": "Call after login with the guest cart token. Quantities of the same product are summed up to the available stock; items that could not be merged in full are listed in unmerged. The guest cart is deleted afterwards",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Merge the guest cart into the user's cart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "guest cart token",
+                        "name": "X-Cart-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "allOf": [
                                 {
@@ -378,27 +698,27 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/types.ProductInfo"
+                                            "$ref": "#/definitions/data.CartMergeResult"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "请求参数错误",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
                     },
                     "404": {
-                        "description": "商品不存在",
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
                     },
                     "500": {
-                        "description": "服务器内部错误",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
@@ -406,9 +726,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/customer/products": {
+        "/customer/cart/price-estimate": {
             "get": {
-                "description": "支持按关键词搜索、分类筛选、分页，并按更新时间排序",
+                "description": "Calculate order price",
                 "consumes": [
                     "application/json"
                 ],
@@ -416,32 +736,20 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "商品"
+                    "Cart"
                 ],
-                "summary": "用户端获取商品列表",
+                "summary": "Calculate order price",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "搜索关键词",
-                        "name": "keyword",
+                        "description": "region code for tax and shipping, defaults to the configured region",
+                        "name": "region",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "商品分类",
-                        "name": "category",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "偏移量，默认0",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "排序方式：0-按更新时间降序，1-按更新时间升序，默认0",
-                        "name": "order_by",
+                        "description": "display currency (ISO 4217), defaults to the base currency",
+                        "name": "currency",
                         "in": "query"
                     }
                 ],
@@ -449,7 +757,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/data.BaseResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/data.CartPriceEstimateResult"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -458,6 +778,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/data.BaseResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -467,9 +793,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/merchant/images/upload-urls": {
-            "post": {
-                "description": "Get presigned URL for image upload",
+        "/customer/cart/selected-num": {
+            "get": {
+                "description": "Get number of selected items in cart",
                 "consumes": [
                     "application/json"
                 ],
@@ -477,17 +803,64 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Image"
+                    "Cart"
                 ],
-                "summary": "Get presigned URL for image upload",
+                "summary": "Get number of selected items in cart",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/customer/cart/selection": {
+            "put": {
+                "description": "Select or unselect several cart items at once. Leave item_ids empty to apply to the whole cart. Items not in the cart are reported in the results",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Select or unselect cart items",
                 "parameters": [
                     {
-                        "description": "image_type=(jpg|jpeg|png)",
-                        "name": "product",
+                        "description": "items to select",
+                        "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/data.ImgUploadRequest"
+                            "$ref": "#/definitions/data.CartSelectionRequest"
                         }
                     }
                 ],
@@ -495,7 +868,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/data.ImgUploadResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/data.CartBatchResult"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -503,27 +888,77 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
                     }
                 }
             }
         },
-        "/merchant/product/{id}": {
+        "/customer/collections": {
             "get": {
-                "description": "根据商品ID获取商品详细信息",
-                "consumes": [
+                "description": "只返回用户可见的专题",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "专题"
+                ],
+                "summary": "用户端获取专题列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/types.CollectionInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/customer/collections/{id}": {
+            "get": {
+                "description": "只有用户可见的专题才能查看",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "商品"
+                    "专题"
                 ],
-                "summary": "获取商品详情",
+                "summary": "用户端获取专题详情",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "商品ID",
+                        "description": "专题ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -541,7 +976,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/types.ProductInfo"
+                                            "$ref": "#/definitions/types.CollectionInfo"
                                         }
                                     }
                                 }
@@ -555,7 +990,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "商品不存在",
+                        "description": "专题不存在",
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
@@ -569,31 +1004,23 @@ const docTemplate = `{
                 }
             }
         },
-        "/merchant/products": {
+        "/customer/collections/{id}/products": {
             "get": {
-                "description": "支持按关键词搜索、分类筛选、分页，并按更新时间排序",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "按专题内顺序分页返回已上架的商品",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "商品"
+                    "专题"
                 ],
-                "summary": "商家端获取商品列表",
+                "summary": "用户端浏览专题商品",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "搜索关键词",
-                        "name": "keyword",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "商品分类",
-                        "name": "category",
-                        "in": "query"
+                        "type": "integer",
+                        "description": "专题ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "integer",
@@ -602,10 +1029,16 @@ const docTemplate = `{
                         "in": "query"
                     },
                     {
-                        "type": "integer",
-                        "description": "排序方式：0-按更新时间降序，1-按更新时间升序，默认0",
-                        "name": "order_by",
+                        "type": "string",
+                        "description": "展示币种 (ISO 4217)，默认基础货币",
+                        "name": "currency",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "上次响应的 ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -615,52 +1048,102 @@ const docTemplate = `{
                             "$ref": "#/definitions/data.BaseResponse"
                         }
                     },
+                    "304": {
+                        "description": "列表未变化"
+                    },
                     "400": {
-                        "description": "Bad Request",
+                        "description": "请求参数错误或不支持的币种",
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "专题不存在",
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
-                    }
-                }
-            },
-            "post": {
-                "description": "新增一个商品",
-                "consumes": [
-                    "application/json"
-                ],
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/customer/currencies": {
+            "get": {
+                "description": "List the currencies prices can be shown in, with their exchange rate against the base currency. Pass the code as the currency query parameter of product and cart endpoints",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "商品"
+                    "Currency"
                 ],
-                "summary": "添加商品",
-                "parameters": [
-                    {
-                        "description": "商品信息",
-                        "name": "product",
-                        "in": "body",
-                        "required": true,
+                "summary": "List display currencies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.ProductInfo"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/data.CurrencyVO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
                         }
                     }
+                }
+            }
+        },
+        "/customer/guest-cart": {
+            "post": {
+                "description": "Issue a cart token for a visitor who has not logged in. Send it as the X-Cart-Token header on the cart endpoints, and to /customer/cart/merge after login",
+                "produces": [
+                    "application/json"
                 ],
+                "tags": [
+                    "Cart"
+                ],
+                "summary": "Create a guest cart",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/data.BaseResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/data.GuestCartVO"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
@@ -668,9 +1151,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/merchant/products/:id/status": {
-            "patch": {
-                "description": "将商品状态更改为上架状态",
+        "/customer/product/{id}": {
+            "get": {
+                "description": "根据商品ID获取商品详细信息",
                 "consumes": [
                     "application/json"
                 ],
@@ -680,27 +1163,52 @@ const docTemplate = `{
                 "tags": [
                     "商品"
                 ],
-                "summary": "上架商品",
+                "summary": "获取商品详情(用户侧)",
                 "parameters": [
                     {
-                        "description": "商品上架请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.UpdateProductStatusRequest"
-                        }
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "展示币种 (ISO 4217)，默认基础货币",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "上次响应的 ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "上架成功",
+                        "description": "成功",
                         "schema": {
-                            "$ref": "#/definitions/data.BaseResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.ProductInfo"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
+                    "304": {
+                        "description": "商品未变化"
+                    },
                     "400": {
-                        "description": "请求参数错误",
+                        "description": "请求参数错误或不支持的币种",
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
@@ -720,33 +1228,79 @@ const docTemplate = `{
                 }
             }
         },
-        "/merchant/products/:id/stock": {
-            "patch": {
-                "description": "只有当商品处于下架状态时，才能更改商品库存",
-                "consumes": [
-                    "application/json"
-                ],
+        "/customer/product/{id}/stock-subscription": {
+            "post": {
+                "description": "已上架但无库存的商品可以订阅，补货后通知一次并自动取消订阅；重复订阅不报错",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "商品"
                 ],
-                "summary": "商家端更新商品库存",
+                "summary": "订阅到货通知",
                 "parameters": [
                     {
-                        "description": "更新商品库存请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "订阅成功",
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateProductStockRequest"
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误或商品有库存",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "未登录",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
                         }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "取消当前用户对商品的到货通知订阅，未订阅时也返回成功",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "取消到货通知",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "更新成功",
+                        "description": "取消成功",
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
@@ -756,13 +1310,25 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
+                    },
+                    "401": {
+                        "description": "未登录",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
                     }
                 }
             }
         },
-        "/merchant/products/{id}": {
-            "put": {
-                "description": "根据商品ID更新商品详细信息",
+        "/customer/products": {
+            "get": {
+                "description": "支持按关键词搜索、分类筛选、分页，并按更新时间排序",
                 "consumes": [
                     "application/json"
                 ],
@@ -772,277 +1338,4003 @@ const docTemplate = `{
                 "tags": [
                     "商品"
                 ],
-                "summary": "编辑商品信息",
+                "summary": "用户端获取商品列表",
                 "parameters": [
                     {
-                        "description": "编辑商品请求",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.UpdateProductInfoRequest"
-                        }
+                        "type": "string",
+                        "description": "搜索关键词",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "商品分类",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "商品标签",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "偏移量，默认0",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "排序方式：0-按更新时间降序，1-按更新时间升序，默认0",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "展示币种 (ISO 4217)，默认基础货币",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "上次响应的 ETag",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "编辑成功",
+                        "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
                     },
-                    "400": {
-                        "description": "请求参数错误",
-                        "schema": {
-                            "$ref": "#/definitions/data.BaseResponse"
-                        }
+                    "304": {
+                        "description": "列表未变化"
                     },
-                    "404": {
-                        "description": "商品不存在",
+                    "400": {
+                        "description": "请求参数错误或不支持的币种",
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
                     },
                     "500": {
-                        "description": "服务器内部错误",
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/data.BaseResponse"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "data.BaseResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
+        },
+        "/customer/saved-items": {
+            "get": {
+                "description": "List the wishlist and save-for-later items with the current price and availability of each product",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Saved Items"
+                ],
+                "summary": "List saved items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "wishlist or save_for_later, both lists when empty",
+                        "name": "list",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "display currency (ISO 4217), defaults to the base currency",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/data.SavedListVO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Put a product on the wishlist or save it for later. Saving a product already on the list replaces its quantity",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Saved Items"
+                ],
+                "summary": "Save a product",
+                "parameters": [
+                    {
+                        "description": "product to save",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/data.SaveItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "saved item ID",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/customer/saved-items/{id}": {
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Saved Items"
+                ],
+                "summary": "Delete a saved item",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "saved item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/customer/saved-items/{id}/move-to-cart": {
+            "post": {
+                "description": "Add the saved quantity to the cart and remove the item from its list. Fails, keeping the item saved, when the product is not on sale or lacks stock",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Saved Items"
+                ],
+                "summary": "Move a saved item to the cart",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "saved item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/collections": {
+            "get": {
+                "description": "返回全部专题，包括隐藏的专题",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "专题"
+                ],
+                "summary": "商家端获取专题列表",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/types.CollectionInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "商家创建一个跨分类的商品专题",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "专题"
+                ],
+                "summary": "创建专题",
+                "parameters": [
+                    {
+                        "description": "专题信息",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.SaveCollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "专题ID",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/collections/{id}": {
+            "get": {
+                "description": "返回专题信息及按顺序排列的商品ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "专题"
+                ],
+                "summary": "商家端获取专题详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "专题ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "专题不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "更新专题名称、描述、封面及可见状态",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "专题"
+                ],
+                "summary": "编辑专题",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "专题ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "专题信息",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.SaveCollectionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "编辑成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "专题不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "删除专题及其商品关联，商品本身不受影响",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "专题"
+                ],
+                "summary": "删除专题",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "专题ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "专题不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/collections/{id}/products": {
+            "put": {
+                "description": "按给定顺序覆盖专题内的商品，数组顺序即展示顺序",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "专题"
+                ],
+                "summary": "设置专题商品",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "专题ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "商品ID列表",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateCollectionProductsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "设置成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "专题不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/coupons": {
+            "get": {
+                "description": "List coupons, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupon"
+                ],
+                "summary": "List coupons",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "offset, defaults to 0",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, defaults to 20, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/data.CouponVO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a percentage, fixed amount or free shipping promo code",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupon"
+                ],
+                "summary": "Create a coupon",
+                "parameters": [
+                    {
+                        "description": "coupon info",
+                        "name": "coupon",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/data.CreateCouponRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "coupon ID",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/coupons/{id}/status": {
+            "patch": {
+                "description": "Disabled coupons stay applied to carts but are rejected by the price estimate",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Coupon"
+                ],
+                "summary": "Enable or disable a coupon",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "coupon ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "coupon status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/data.UpdateCouponStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/images/upload-urls": {
+            "post": {
+                "description": "Get presigned URL for image upload",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Image"
+                ],
+                "summary": "Get presigned URL for image upload",
+                "parameters": [
+                    {
+                        "description": "image_type=(jpg|jpeg|png)",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/data.ImgUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/data.ImgUploadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/product/{id}": {
+            "get": {
+                "description": "根据商品ID获取商品详细信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "获取商品详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.ProductInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products": {
+            "get": {
+                "description": "支持按关键词搜索、分类筛选、分页，并按更新时间排序",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "商家端获取商品列表",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "搜索关键词",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "商品分类",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "商品标签",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "偏移量，默认0",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "排序方式：0-按更新时间降序，1-按更新时间升序，默认0",
+                        "name": "order_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "新增一个商品",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "添加商品",
+                "parameters": [
+                    {
+                        "description": "商品信息",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.ProductInfo"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/:id/stock": {
+            "patch": {
+                "description": "只有草稿或下架状态的商品可以直接设置库存，上架中的商品请使用按增量调整库存的接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "商家端更新商品库存",
+                "parameters": [
+                    {
+                        "description": "更新商品库存请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateProductStockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "更新成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/low-stock": {
+            "get": {
+                "description": "返回库存小于等于预警阈值的商品，库存少的在前",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "获取低库存商品列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "偏移量，默认0",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认20，最大100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "低库存商品",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/types.LowStockProductInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}": {
+            "put": {
+                "description": "根据商品ID更新商品详细信息，请求需携带读取商品时返回的 version，版本不一致时返回 409",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "编辑商品信息",
+                "parameters": [
+                    {
+                        "description": "编辑商品请求",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateProductInfoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "编辑成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "商品已被他人修改",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "软删除商品并从所有用户的购物车中移除，上架中的商品需要先下架；删除后可通过恢复接口恢复",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "删除商品",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "删除成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "当前状态不允许删除",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "只修改请求中给出的字段：未传或传 null 的字段保持不变，传空字符串表示清空该字段；请求需携带 version，版本不一致时返回 409",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "部分更新商品信息",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "需要修改的字段",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.PatchProductInfoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "商品已被他人修改",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/archive": {
+            "post": {
+                "description": "归档后商品不再展示和销售，已上架的商品会同时下架，可通过恢复接口恢复为下架状态",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "归档商品",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "归档成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "当前状态不允许归档",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/inventory-ledger": {
+            "get": {
+                "description": "按时间倒序分页返回商品的全部库存变化，包括初始库存、商家设置、订单扣减和库存调整",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "获取库存流水",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "偏移量，默认0",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认20，最大100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "库存流水",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/types.InventoryLedgerEntryInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/inventory-reconciliation": {
+            "get": {
+                "description": "将库存流水的增减合计与商品当前库存比较，consistent 为 false 时存在未记录流水的库存变化",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "库存对账",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "对账结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.InventoryReconciliation"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/low-stock-threshold": {
+            "put": {
+                "description": "库存小于等于阈值时进入低库存列表，库存从阈值以上降到阈值及以下时发送预警事件；0 表示关闭预警",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "设置库存预警阈值",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "预警阈值",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateLowStockThresholdRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "设置成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/price-history": {
+            "get": {
+                "description": "按生效时间倒序分页返回商品的价格变更记录，包括创建商品时的初始价格；effective_to 为空表示当前价格",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "获取价格历史",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "偏移量，默认0",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认20，最大100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "价格历史",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/types.PriceChangeInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/publish-readiness": {
+            "get": {
+                "description": "按配置的检查项检查商品能否上架，返回所有未通过的检查项，不会修改商品",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "上架前检查",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "检查结果",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.PublishReadiness"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/purchase-limit": {
+            "put": {
+                "description": "每位顾客累计最多购买的数量，已下单的数量计入限购，取消订单后释放；0 表示不限购。加购和下单扣减库存时检查",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "设置商品限购",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "限购数量",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdatePurchaseLimitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "设置成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/restore": {
+            "post": {
+                "description": "将已归档或已软删除的商品恢复为下架状态",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "恢复已归档或已删除的商品",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "恢复成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "商品未归档或删除",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/schedules": {
+            "get": {
+                "description": "按执行时间升序返回商品的定时上下架任务",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "定时任务"
+                ],
+                "summary": "获取商品的定时任务",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "只返回等待执行的任务",
+                        "name": "pending",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/types.ProductScheduleInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "在指定时间自动上架或下架商品，多副本部署时任务只会执行一次",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "定时任务"
+                ],
+                "summary": "创建定时上下架任务",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "定时任务",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateProductScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "任务ID",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/schedules/{scheduleId}": {
+            "delete": {
+                "description": "取消等待执行的定时任务，已执行或正在执行的任务不能取消",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "定时任务"
+                ],
+                "summary": "取消定时任务",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "任务ID",
+                        "name": "scheduleId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "取消成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "任务已执行或已取消",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "任务不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/status": {
+            "patch": {
+                "description": "按商品生命周期规则变更状态 (0-下架, 1-上架, 2-草稿, 3-待审核, 4-已归档, 5-已删除)，不允许的变更返回错误；上架时商品需通过上架检查，未通过的检查项在 errors 中返回",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "变更商品状态",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "目标状态",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateProductStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "变更成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/status-history": {
+            "get": {
+                "description": "返回商品的状态变更记录，包括操作人和时间，最新的在前",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "获取商品状态变更记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/types.ProductStatusTransitionInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/stock-adjustments": {
+            "get": {
+                "description": "按时间倒序分页返回商品的库存调整记录",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "获取库存调整记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "偏移量，默认0",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认20，最大100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "调整记录",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/types.StockAdjustmentInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "上架中的商品也可以调整。restock 只能增加库存，damage 只能减少库存，correction 用于盘点修正；每次调整都会记录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "按增量调整商品库存",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "库存调整",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.AdjustStockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "调整记录",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/types.StockAdjustmentInfo"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误或库存不足",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "库存并发修改，请重试",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/stock-mode": {
+            "put": {
+                "description": "preorder 为预售，必须填写预计发货日期；backorder 为现货售完后继续接单。库存最低可扣减到 -max_backorder，normal 表示只售卖现有库存",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "设置预售/缺货下单",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "预售设置",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateStockModeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "设置成功",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误或已超卖数量超过上限",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/products/{id}/tags": {
+            "get": {
+                "description": "返回商品的全部标签",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "获取商品标签",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "覆盖商品的全部标签，标签统一转为小写并去重",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "商品"
+                ],
+                "summary": "设置商品标签",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "商品ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "标签列表",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateProductTagsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "设置后的标签",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "type": "string"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "请求参数错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "商品不存在",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/promotions": {
+            "get": {
+                "description": "List promotions, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "List promotions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "offset, defaults to 0",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size, defaults to 20, at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/data.PromotionVO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a sale (percentage off), bundle (buy X get Y free) or tiered (percentage off by quantity) promotion that applies without a code during its time window",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Create an automatic promotion",
+                "parameters": [
+                    {
+                        "description": "promotion info",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/data.CreatePromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "promotion ID",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "integer"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/promotions/{id}/status": {
+            "patch": {
+                "description": "Disabled promotions stop applying immediately, even inside their time window",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Promotion"
+                ],
+                "summary": "Enable or disable a promotion",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "promotion status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/data.UpdatePromotionStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/merchant/schedules": {
+            "get": {
+                "description": "按执行时间升序返回所有商品等待执行的定时上下架任务",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "定时任务"
+                ],
+                "summary": "获取所有等待执行的定时任务",
+                "responses": {
+                    "200": {
+                        "description": "成功",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/data.BaseResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/types.ProductScheduleInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "服务器内部错误",
+                        "schema": {
+                            "$ref": "#/definitions/data.BaseResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "data.AppliedCouponVO": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "reject_reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "data.ApplyCouponRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "maxLength": 64
+                }
+            }
+        },
+        "data.BaseResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "data": {},
+                "err_msg": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.FieldError"
+                    }
+                }
+            }
+        },
+        "data.CartBatchDeleteRequest": {
+            "type": "object",
+            "required": [
+                "item_ids"
+            ],
+            "properties": {
+                "item_ids": {
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "data.CartBatchResult": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "description": "one per requested item, in request order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.CartItemResultVO"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer"
+                }
+            }
+        },
+        "data.CartItemBasicVO": {
+            "type": "object",
+            "required": [
+                "product_id",
+                "quantity"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "selected": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "data.CartItemDetailVO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "product_info": {
+                    "$ref": "#/definitions/types.ProductSimplifiedInfo"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "selected": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "1: normal, 2: out of stock, 3: preorder or backorder",
+                    "type": "integer"
+                },
+                "total_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "data.CartItemResultVO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "error code of a failed item, e.g. -2 insufficient stock",
+                    "type": "integer"
+                },
+                "item_id": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "data.CartListVO": {
+            "type": "object",
+            "properties": {
+                "cart_items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.CartItemDetailVO"
+                    }
+                },
+                "currency": {
+                    "description": "ISO 4217 code of every amount in the cart",
+                    "type": "string"
+                },
+                "notices": {
+                    "description": "changes since the cart was last shown, each reported once",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.CartNoticeVO"
+                    }
+                },
+                "selected_item_count": {
+                    "type": "integer"
+                },
+                "selected_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "data.CartMergeIssueVO": {
+            "type": "object",
+            "properties": {
+                "merged": {
+                    "description": "quantity added to the user's cart",
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "requested": {
+                    "description": "quantity in the guest cart",
+                    "type": "integer"
+                }
+            }
+        },
+        "data.CartMergeResult": {
+            "type": "object",
+            "properties": {
+                "merged_item_count": {
+                    "type": "integer"
+                },
+                "unmerged": {
+                    "description": "guest items that were dropped or only partly merged",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.CartMergeIssueVO"
+                    }
+                }
+            }
+        },
+        "data.CartNoticeVO": {
+            "type": "object",
+            "properties": {
+                "item_id": {
+                    "type": "integer"
+                },
+                "new_price": {
+                    "type": "integer"
+                },
+                "new_quantity": {
+                    "type": "integer"
+                },
+                "old_price": {
+                    "description": "unit prices of a price notice",
+                    "type": "integer"
+                },
+                "old_quantity": {
+                    "description": "quantities of a quantity notice",
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "data.CartPriceEstimateResult": {
+            "type": "object",
+            "properties": {
+                "coupon": {
+                    "$ref": "#/definitions/data.AppliedCouponVO"
+                },
+                "currency": {
+                    "description": "ISO 4217 code of every amount in the estimate",
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "description": "itemised breakdown in the order it was applied",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.PriceLine"
+                    }
+                },
+                "product_price": {
+                    "type": "integer"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "shipping_price": {
+                    "type": "integer"
+                },
+                "tax": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "data.CartQuantityUpdateRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "maxItems": 200,
+                    "minItems": 1,
+                    "uniqueItems": true,
+                    "items": {
+                        "$ref": "#/definitions/data.CartQuantityVO"
+                    }
+                }
+            }
+        },
+        "data.CartQuantityVO": {
+            "type": "object",
+            "required": [
+                "item_id",
+                "quantity"
+            ],
+            "properties": {
+                "item_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "data.CartSelectionRequest": {
+            "type": "object",
+            "required": [
+                "selected"
+            ],
+            "properties": {
+                "item_ids": {
+                    "description": "empty applies to every item in the cart",
+                    "type": "array",
+                    "maxItems": 200,
+                    "uniqueItems": true,
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "selected": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "data.CouponVO": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "max_discount": {
+                    "type": "integer"
+                },
+                "min_spend": {
+                    "type": "integer"
+                },
+                "per_user_limit": {
+                    "type": "integer"
+                },
+                "product_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "usage_limit": {
+                    "type": "integer"
+                },
+                "used_count": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "data.CreateCouponRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "type"
+            ],
+            "properties": {
+                "categories": {
+                    "description": "empty means every category",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "code": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "max_discount": {
+                    "description": "cap for percentage coupons, 0 means no cap",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "min_spend": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "per_user_limit": {
+                    "description": "0 means unlimited",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "product_ids": {
+                    "description": "empty means every product",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "percentage",
+                        "fixed_amount",
+                        "free_shipping"
+                    ]
+                },
+                "usage_limit": {
+                    "description": "0 means unlimited",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "value": {
+                    "description": "percent for percentage coupons, cents for fixed amount coupons",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "data.CreatePromotionRequest": {
+            "type": "object",
+            "required": [
+                "ends_at",
+                "name",
+                "starts_at",
+                "type"
+            ],
+            "properties": {
+                "buy_quantity": {
+                    "description": "bundle promotions, e.g. buy 2",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "categories": {
+                    "description": "empty means every category",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "free_quantity": {
+                    "description": "bundle promotions, e.g. get 1 free",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "percentage": {
+                    "description": "sale promotions",
+                    "type": "integer",
+                    "maximum": 99,
+                    "minimum": 0
+                },
+                "product_ids": {
+                    "description": "empty means every product",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "tiers": {
+                    "description": "tiered promotions",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.PromotionTierVO"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "sale",
+                        "bundle",
+                        "tiered"
+                    ]
+                }
+            }
+        },
+        "data.CurrencyVO": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "decimals": {
+                    "description": "digits of the minor unit amounts are given in",
+                    "type": "integer"
+                },
+                "rate": {
+                    "description": "units of the currency per unit of the base currency",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "base, file or admin",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "data.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "data.GuestCartVO": {
+            "type": "object",
+            "properties": {
+                "cart_token": {
+                    "description": "send as the X-Cart-Token header on /customer/cart requests",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "data.ImgUploadRequest": {
+            "type": "object",
+            "required": [
+                "image_type"
+            ],
+            "properties": {
+                "image_type": {
+                    "type": "string",
+                    "enum": [
+                        "jpg",
+                        "png",
+                        "jpeg"
+                    ]
+                }
+            }
+        },
+        "data.ImgUploadResponse": {
+            "type": "object",
+            "properties": {
+                "image_id": {
+                    "type": "string"
+                },
+                "upload_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "data.MoveFromCartRequest": {
+            "type": "object",
+            "properties": {
+                "list": {
+                    "description": "defaults to save_for_later",
+                    "type": "string",
+                    "enum": [
+                        "wishlist",
+                        "save_for_later"
+                    ]
+                }
+            }
+        },
+        "data.PriceLine": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "discounts are negative",
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "subtotal, discount, shipping, tax",
+                    "type": "string"
+                }
+            }
+        },
+        "data.PromotionTierVO": {
+            "type": "object",
+            "properties": {
+                "min_quantity": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "percentage": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                }
+            }
+        },
+        "data.PromotionVO": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "ends_at": {
+                    "type": "string"
+                },
+                "free_quantity": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "integer"
+                },
+                "product_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "starts_at": {
+                    "type": "string"
+                },
+                "tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.PromotionTierVO"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "data.SaveItemRequest": {
+            "type": "object",
+            "required": [
+                "list",
+                "product_id"
+            ],
+            "properties": {
+                "list": {
+                    "type": "string",
+                    "enum": [
+                        "wishlist",
+                        "save_for_later"
+                    ]
+                },
+                "product_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "quantity": {
+                    "description": "defaults to 1",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "data.SavedItemVO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "list": {
+                    "type": "string"
+                },
+                "product_info": {
+                    "description": "current price and availability",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.ProductSimplifiedInfo"
+                        }
+                    ]
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "saved_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "as cart items, plus 4: no longer on sale",
+                    "type": "integer"
+                },
+                "total_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "data.SavedListVO": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "description": "ISO 4217 code of every amount in the list",
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.SavedItemVO"
+                    }
+                }
+            }
+        },
+        "data.SetExchangeRateRequest": {
+            "type": "object",
+            "required": [
+                "decimals",
+                "rate"
+            ],
+            "properties": {
+                "decimals": {
+                    "type": "integer",
+                    "maximum": 4,
+                    "minimum": 0
+                },
+                "rate": {
+                    "description": "decimal string, e.g. \"0.74\"",
+                    "type": "string",
+                    "maxLength": 32
+                }
+            }
+        },
+        "data.UpdateCouponStatusRequest": {
+            "type": "object",
+            "required": [
+                "active"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "data.UpdatePromotionStatusRequest": {
+            "type": "object",
+            "required": [
+                "active"
+            ],
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "types.AdjustStockRequest": {
+            "type": "object",
+            "required": [
+                "delta",
+                "reason"
+            ],
+            "properties": {
+                "delta": {
+                    "description": "库存变化量，正数增加，负数减少",
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "reason": {
+                    "description": "restock 只能增加，damage 只能减少",
+                    "type": "string",
+                    "enum": [
+                        "restock",
+                        "damage",
+                        "correction"
+                    ]
+                }
+            }
+        },
+        "types.CollectionInfo": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pic_info": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "0: 隐藏, 1: 用户可见",
+                    "type": "integer"
+                }
+            }
+        },
+        "types.CreateProductScheduleRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "scheduled_at"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "publish",
+                        "unpublish"
+                    ]
+                },
+                "scheduled_at": {
+                    "description": "RFC3339 格式，必须晚于当前时间",
+                    "type": "string"
+                }
+            }
+        },
+        "types.InventoryLedgerEntryInfo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "delta": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "operator_id": {
+                    "type": "integer"
+                },
+                "reference_id": {
+                    "description": "订单号或库存调整记录ID",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "initial, merchant_set, order, adjustment",
+                    "type": "string"
+                },
+                "stock_after": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.InventoryReconciliation": {
+            "type": "object",
+            "properties": {
+                "consistent": {
+                    "type": "boolean"
+                },
+                "difference": {
+                    "description": "Stock - LedgerTotal",
+                    "type": "integer"
+                },
+                "entry_count": {
+                    "type": "integer"
+                },
+                "ledger_total": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.LowStockProductInfo": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "low_stock_threshold": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.PatchProductInfoRequest": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "capacity": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "care_instructions": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "desc": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "dimensions": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "material": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "pic_info": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "version": {
+                    "description": "读取商品时返回的版本号，必填",
+                    "type": "integer"
+                },
+                "weight": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "types.PriceChangeInfo": {
+            "type": "object",
+            "properties": {
+                "effective_from": {
+                    "type": "string"
+                },
+                "effective_to": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "new_price": {
+                    "type": "integer"
+                },
+                "old_price": {
+                    "description": "创建商品时的初始价格为 0",
+                    "type": "integer"
+                },
+                "operator_id": {
+                    "description": "0 表示系统操作",
+                    "type": "integer"
+                }
+            }
+        },
+        "types.ProductInfo": {
+            "type": "object",
+            "required": [
+                "category",
+                "name"
+            ],
+            "properties": {
+                "availability": {
+                    "description": "仅响应: in_stock, out_of_stock, preorder, backorder",
+                    "type": "string"
+                },
+                "capacity": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "care_instructions": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "category": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "currency": {
+                    "description": "仅响应: 以上价格的币种 (ISO 4217)，商家侧始终为基础货币",
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string",
+                    "maxLength": 5000
+                },
+                "dimensions": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "expected_ship_date": {
+                    "description": "预售商品必填",
+                    "type": "string"
+                },
+                "low_stock_threshold": {
+                    "description": "库存小于等于该值时预警，0 表示不预警",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "lowest_price_30d": {
+                    "description": "仅响应 (用户侧): 近 30 天内的最低原价",
                     "type": "integer"
                 },
-                "data": {},
-                "err_msg": {
+                "material": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "max_backorder": {
+                    "description": "库存为 0 后还可以下单的数量",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "max_per_customer": {
+                    "description": "每位顾客累计最多购买的数量，0 表示不限购",
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "pic_info": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "promotion_name": {
+                    "description": "仅响应: 促销活动名称",
+                    "type": "string"
+                },
+                "sale_price": {
+                    "description": "仅响应: 进行中促销的价格，price 为原价",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "0: 下架, 1: 上架, 2: 草稿, 3: 待审核, 4: 已归档, 5: 已删除",
+                    "type": "integer"
+                },
+                "stock": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "stock_mode": {
+                    "description": "默认 normal",
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "preorder",
+                        "backorder"
+                    ]
+                },
+                "updated_at": {
+                    "description": "最后修改时间",
                     "type": "string"
+                },
+                "version": {
+                    "description": "商品版本号，每次修改递增",
+                    "type": "integer"
+                },
+                "weight": {
+                    "type": "string",
+                    "maxLength": 255
                 }
             }
         },
-        "data.CartItemBasicVO": {
+        "types.ProductScheduleInfo": {
             "type": "object",
-            "required": [
-                "product_id",
-                "quantity"
-            ],
             "properties": {
+                "action": {
+                    "description": "publish | unpublish",
+                    "type": "string"
+                },
+                "attempts": {
+                    "description": "执行出错后重试的次数",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "executed_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
+                "last_error": {
+                    "type": "string"
+                },
                 "product_id": {
                     "type": "integer"
                 },
-                "quantity": {
-                    "type": "integer",
-                    "minimum": 1
-                },
-                "selected": {
-                    "type": "boolean"
+                "scheduled_at": {
+                    "type": "string"
                 },
-                "user_id": {
+                "status": {
+                    "description": "0: 等待执行, 1: 已执行, 2: 已取消, 3: 执行失败",
                     "type": "integer"
                 }
             }
         },
-        "data.CartItemDetailVO": {
+        "types.ProductSimplifiedInfo": {
             "type": "object",
             "properties": {
+                "availability": {
+                    "description": "in_stock, out_of_stock, preorder, backorder",
+                    "type": "string"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "价格的币种 (ISO 4217)",
+                    "type": "string"
+                },
+                "desc": {
+                    "type": "string"
+                },
+                "expected_ship_date": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "product_info": {
-                    "$ref": "#/definitions/types.ProductSimplifiedInfo"
+                "name": {
+                    "type": "string"
                 },
-                "quantity": {
+                "pic_info": {
+                    "type": "string"
+                },
+                "price": {
                     "type": "integer"
                 },
-                "selected": {
-                    "type": "boolean"
+                "promotion_name": {
+                    "type": "string"
+                },
+                "sale_price": {
+                    "description": "进行中促销的价格，price 为原价",
+                    "type": "integer"
                 },
                 "status": {
-                    "description": "1: normal, 2: out of stock",
+                    "description": "0: 下架, 1: 上架, 2: 草稿, 3: 待审核, 4: 已归档, 5: 已删除",
                     "type": "integer"
                 },
-                "total_price": {
+                "stock": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
                     "type": "integer"
                 }
             }
         },
-        "data.CartListVO": {
+        "types.ProductStatusTransitionInfo": {
             "type": "object",
             "properties": {
-                "cart_items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/data.CartItemDetailVO"
-                    }
+                "created_at": {
+                    "type": "string"
                 },
-                "selected_item_count": {
+                "from_status": {
                     "type": "integer"
                 },
-                "selected_price": {
+                "operator_id": {
+                    "description": "0 表示系统操作",
+                    "type": "integer"
+                },
+                "to_status": {
                     "type": "integer"
                 }
             }
         },
-        "data.CartPriceEstimateResult": {
+        "types.PublishReadiness": {
             "type": "object",
             "properties": {
-                "product_price": {
-                    "type": "integer"
-                },
-                "shipping_price": {
-                    "type": "integer"
-                },
-                "tax": {
-                    "type": "integer"
+                "failed_checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ReadinessCheck"
+                    }
                 },
-                "total": {
-                    "type": "integer"
+                "ready": {
+                    "type": "boolean"
                 }
             }
         },
-        "data.ImgUploadRequest": {
+        "types.PurgeDeletedProductsRequest": {
             "type": "object",
             "required": [
-                "image_type"
+                "older_than_days"
             ],
             "properties": {
-                "image_type": {
-                    "type": "string",
-                    "enum": [
-                        "jpg",
-                        "png",
-                        "jpeg"
-                    ]
+                "older_than_days": {
+                    "description": "只清理软删除超过该天数的商品",
+                    "type": "integer",
+                    "minimum": 1
                 }
             }
         },
-        "data.ImgUploadResponse": {
+        "types.ReadinessCheck": {
             "type": "object",
             "properties": {
-                "image_id": {
+                "check": {
+                    "description": "required_field | image | price | stock | category",
                     "type": "string"
                 },
-                "upload_url": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
                     "type": "string"
                 }
             }
         },
-        "types.ProductInfo": {
+        "types.SaveCollectionRequest": {
             "type": "object",
             "properties": {
-                "capacity": {
-                    "type": "string"
-                },
-                "care_instructions": {
-                    "type": "string"
-                },
-                "category": {
-                    "type": "string"
-                },
                 "desc": {
                     "type": "string"
                 },
-                "dimensions": {
-                    "type": "string"
-                },
-                "material": {
-                    "type": "string"
-                },
                 "name": {
                     "type": "string"
                 },
                 "pic_info": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "integer"
-                },
                 "status": {
-                    "description": "0: 未上架, 1: 已上架",
+                    "description": "0: 隐藏, 1: 用户可见",
                     "type": "integer"
-                },
-                "stock": {
-                    "type": "integer"
-                },
-                "weight": {
-                    "type": "string"
                 }
             }
         },
-        "types.ProductSimplifiedInfo": {
+        "types.StockAdjustmentInfo": {
             "type": "object",
             "properties": {
-                "category": {
+                "created_at": {
                     "type": "string"
                 },
-                "desc": {
-                    "type": "string"
+                "delta": {
+                    "type": "integer"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
-                    "type": "string"
-                },
-                "pic_info": {
+                "note": {
                     "type": "string"
                 },
-                "price": {
+                "operator_id": {
                     "type": "integer"
                 },
-                "status": {
-                    "description": "0: 未上架, 1: 已上架",
+                "reason": {
+                    "type": "string"
+                },
+                "stock_after": {
                     "type": "integer"
                 },
-                "stock": {
+                "stock_before": {
                     "type": "integer"
                 }
             }
         },
+        "types.UpdateCollectionProductsRequest": {
+            "type": "object",
+            "properties": {
+                "product_ids": {
+                    "description": "按展示顺序排列",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "types.UpdateLowStockThresholdRequest": {
+            "type": "object",
+            "properties": {
+                "threshold": {
+                    "description": "0 表示关闭预警",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
         "types.UpdateProductInfoRequest": {
             "type": "object",
+            "required": [
+                "category",
+                "name",
+                "version"
+            ],
             "properties": {
                 "capacity": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 255
                 },
                 "care_instructions": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 5000
                 },
                 "category": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 255
                 },
                 "desc": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 5000
                 },
                 "dimensions": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 255
                 },
                 "id": {
                     "type": "integer"
                 },
                 "material": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 255
                 },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 255
                 },
                 "pic_info": {
                     "type": "string"
                 },
                 "price": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "version": {
+                    "description": "读取商品时返回的版本号，用于检测并发修改",
                     "type": "integer"
                 },
                 "weight": {
-                    "type": "string"
+                    "type": "string",
+                    "maxLength": 255
                 }
             }
         },
@@ -1050,7 +5342,7 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "status": {
-                    "description": "0-新的状态是下架，1-新的状态是上架",
+                    "description": "目标状态，需符合商品生命周期的状态流转规则",
                     "type": "integer"
                 }
             }
@@ -1059,7 +5351,52 @@ const docTemplate = `{
             "type": "object",
             "properties": {
                 "stock": {
-                    "type": "integer"
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "types.UpdateProductTagsRequest": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "types.UpdatePurchaseLimitRequest": {
+            "type": "object",
+            "properties": {
+                "max_per_customer": {
+                    "description": "0 表示不限购",
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "types.UpdateStockModeRequest": {
+            "type": "object",
+            "required": [
+                "mode"
+            ],
+            "properties": {
+                "expected_ship_date": {
+                    "type": "string"
+                },
+                "max_backorder": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "preorder",
+                        "backorder"
+                    ]
                 }
             }
         }
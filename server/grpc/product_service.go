@@ -2,25 +2,94 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"strconv"
+	"strings"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/common/productpb"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"google.golang.org/grpc/metadata"
 )
 
+// orderIDMetadataKey 订单服务在调用 UpdateStockWithCAS 时通过 metadata 传递订单号，用于库存流水和优惠券核销
+const orderIDMetadataKey = "x-order-id"
+
+// customerIDMetadataKey 下单顾客的 userID，用于检查商品限购和核销优惠券。扣减限购商品的库存时必须传递，否则扣减被拒绝
+const customerIDMetadataKey = "x-user-id"
+
+// couponCodeMetadataKey 订单使用的优惠券，下单扣减库存时核销。必须同时通过 orderLinesMetadataKey 传递订单的全部商品
+const couponCodeMetadataKey = "x-coupon-code"
+
+// orderLinesMetadataKey 订单的全部商品，格式为 "商品ID:数量,商品ID:数量"，用于按订单内容检查优惠券
+const orderLinesMetadataKey = "x-order-lines"
+
+// orderCancelledMetadataKey 取消订单回补库存时传递 "true"，此时退回订单核销的优惠券。其他回补库存不会退回
+const orderCancelledMetadataKey = "x-order-cancelled"
+
 type ProductService struct {
 	productpb.UnimplementedProductServiceServer
 }
 
 func (p *ProductService) UpdateStockWithCAS(ctx context.Context, req *productpb.UpdateStockWithCASRequest) (*productpb.UpdateStockWithCASResponse, error) {
+	var couponCode string
+	var orderLines []service.OrderLine
+	var orderCancelled bool
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if codes := md.Get(couponCodeMetadataKey); len(codes) > 0 {
+			couponCode = codes[0]
+		}
+		if lines := md.Get(orderLinesMetadataKey); len(lines) > 0 {
+			orderLines = parseOrderLines(lines[0])
+		}
+		if cancelled := md.Get(orderCancelledMetadataKey); len(cancelled) > 0 {
+			orderCancelled, _ = strconv.ParseBool(cancelled[0])
+		}
+		if orderIDs := md.Get(orderIDMetadataKey); len(orderIDs) > 0 {
+			ctx = types.WithStockReference(ctx, orderIDs[0])
+		}
+		if customerIDs := md.Get(customerIDMetadataKey); len(customerIDs) > 0 {
+			if customerID, err := strconv.Atoi(customerIDs[0]); err == nil {
+				ctx = types.WithCustomer(ctx, customerID)
+			}
+		}
+	}
+
+	// 下单扣减库存时按订单内容核销订单使用的优惠券，取消订单回补库存时退回，按订单号保证幂等
+	orderID := types.StockReferenceFromContext(ctx)
+	redeemCoupon := orderID != "" && couponCode != "" && types.CustomerFromContext(ctx) > 0 && req.Deta < 0
+	var err error
+	if redeemCoupon {
+		err = service.GetCartService().RedeemOrderCoupon(ctx, types.CustomerFromContext(ctx), orderID, couponCode, orderLines)
+	} else if orderID != "" && orderCancelled && req.Deta > 0 {
+		err = service.GetCartService().ReleaseRedeemedCoupon(ctx, orderID)
+	}
+
 	// execute
-	err := service.GetProductServiceInstance().UpdateStockWithCAS(ctx, int(req.Id), int(req.Deta))
+	if err == nil {
+		err = service.GetProductServiceInstance().UpdateStockWithCAS(ctx, int(req.Id), int(req.Deta))
+		// 扣减失败时订单不会创建，退回已核销的优惠券
+		if err != nil && redeemCoupon {
+			if releaseErr := service.GetCartService().ReleaseRedeemedCoupon(ctx, orderID); releaseErr != nil {
+				log.Logger.Errorf("UpdateStockWithCAS: release coupon of order %s failed, err: %v", orderID, releaseErr)
+			}
+		}
+	}
 	
 	// failed
 	if err != nil {
+		code := productpb.ResponseCode_INTERNAL_ERROR
+		var bizErr *types.BizError
+		if errors.As(err, &bizErr) && (bizErr.Code == service.ProductCheckStatus_PurchaseLimitExceeded ||
+			bizErr.Code == service.ProductCheckStatus_InvalidParam ||
+			bizErr.Code == service.CouponCheckStatus_InvalidParam || bizErr.Code == service.CouponCheckStatus_Unusable) {
+			code = productpb.ResponseCode_INVALID_PARAM
+		}
 		return &productpb.UpdateStockWithCASResponse{
 			Base: &productpb.BaseResponse{
-				Code: int32(productpb.ResponseCode_INTERNAL_ERROR),
-				Msg: productpb.ResponseCode_name[int32(productpb.ResponseCode_INTERNAL_ERROR)],
+				Code: int32(code),
+				Msg: productpb.ResponseCode_name[int32(code)],
 			},
 		}, err
 	}
@@ -42,11 +111,16 @@ func (p *ProductService) GetProductList(ctx context.Context, req *productpb.GetP
 			continue
 		}
 
+		// 订单按促销价下单
+		price := productRaw.Price
+		if productRaw.SalePrice > 0 {
+			price = productRaw.SalePrice
+		}
 		productList = append(productList, &productpb.Product{
 			Id: id,
 			Name: productRaw.Name,
 			Stock: productRaw.Stock,
-			Price: productRaw.Price,
+			Price: price,
 			Status: productRaw.Status,
 		})
 	}
@@ -59,3 +133,24 @@ func (p *ProductService) GetProductList(ctx context.Context, req *productpb.GetP
 		Products: productList,
 	}, nil
 }
+
+// parseOrderLines 解析 orderLinesMetadataKey 的值，忽略格式错误的项
+func parseOrderLines(value string) []service.OrderLine {
+	lines := make([]service.OrderLine, 0)
+	for _, part := range strings.Split(value, ",") {
+		productID, quantity, found := strings.Cut(strings.TrimSpace(part), ":")
+		if !found {
+			continue
+		}
+		id, err := strconv.Atoi(productID)
+		if err != nil {
+			continue
+		}
+		count, err := strconv.Atoi(quantity)
+		if err != nil || count <= 0 {
+			continue
+		}
+		lines = append(lines, service.OrderLine{ProductID: id, Quantity: count})
+	}
+	return lines
+}
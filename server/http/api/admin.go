@@ -0,0 +1,68 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin 只允许配置中的管理员访问，需要在 AuthMiddleware 之后使用
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists || !isAdmin(userID) {
+			c.AbortWithStatusJSON(http.StatusForbidden, data.ResponseFailed("Admin permission required"))
+			return
+		}
+		c.Next()
+	}
+}
+
+func isAdmin(userID interface{}) bool {
+	id, ok := userID.(int)
+	if !ok || config.Config.AdminConfig == nil {
+		return false
+	}
+	for _, adminID := range config.Config.AdminConfig.UserIDs {
+		if adminID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// PurgeDeletedProducts godoc
+// @Summary 永久删除已软删除的商品
+// @Description 管理员接口，永久删除软删除超过指定天数的商品及其标签、专题关联、状态记录和定时任务，删除后无法恢复
+// @Tags 管理
+// @Accept json
+// @Produce json
+// @Param request body types.PurgeDeletedProductsRequest true "清理条件"
+// @Success 200 {object} data.BaseResponse{data=int} "删除的商品数"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 403 {object} data.BaseResponse "没有管理员权限"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /admin/products/purge [post]
+func PurgeDeletedProducts(c *gin.Context) {
+	var req types.PurgeDeletedProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("PurgeDeletedProducts: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	deletedBefore := time.Now().AddDate(0, 0, -req.OlderThanDays)
+	purged, err := service.GetProductServiceInstance().PurgeDeletedProducts(c.Request.Context(), deletedBefore)
+	if err != nil {
+		log.Logger.Errorf("PurgeDeletedProducts: Failed to purge deleted products: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to purge deleted products"))
+		return
+	}
+	log.Logger.Infof("PurgeDeletedProducts: %d products deleted before %v purged by user %v", purged, deletedBefore, c.GetInt("userID"))
+	c.JSON(http.StatusOK, data.ResponseSuccess(purged))
+}
@@ -1,11 +1,13 @@
 package api
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
 	"github.com/gin-gonic/gin"
 )
@@ -142,7 +144,6 @@ func DeleteCartItem(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to delete cart item"))
 		return
 	}
-	// todo delete with itemId+userId
 	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
 }
 
@@ -152,7 +153,9 @@ func DeleteCartItem(c *gin.Context) {
 // @Tags Cart
 // @Accept json
 // @Produce json
+// @Param currency query string false "display currency (ISO 4217), defaults to the base currency"
 // @Success 200 {object} data.BaseResponse{data=data.CartListVO}
+// @Failure 400 {object} data.BaseResponse
 // @Failure 401 {object} data.BaseResponse
 // @Failure 500 {object} data.BaseResponse
 // @Router /customer/cart [get]
@@ -165,7 +168,11 @@ func GetUserCartInfo(c *gin.Context) {
 	}
 	userID = userID.(int)
 	log.Logger.Infof("GetUserCartInfo: userID=%d", userID)
-	ret, err := service.GetCartService().GetCartItems(c.Request.Context(), userID.(int))
+	ret, err := service.GetCartService().GetCartItems(c.Request.Context(), userID.(int), c.Query("currency"))
+	if errors.Is(err, pricing.ErrUnknownCurrency) {
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Unsupported currency"))
+		return
+	}
 	if err != nil {
 		log.Logger.Errorf("GetUserCartInfo: Failed to get cart items: %v", err)
 		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get cart items"))
@@ -208,7 +215,10 @@ func GetCartSelctedNum(c *gin.Context) {
 // @Tags Cart
 // @Accept json
 // @Produce json
+// @Param region query string false "region code for tax and shipping, defaults to the configured region"
+// @Param currency query string false "display currency (ISO 4217), defaults to the base currency"
 // @Success 200 {object} data.BaseResponse{data=data.CartPriceEstimateResult}
+// @Failure 400 {object} data.BaseResponse
 // @Failure 401 {object} data.BaseResponse
 // @Failure 500 {object} data.BaseResponse
 // @Router /customer/cart/price-estimate [get]
@@ -220,7 +230,15 @@ func GetEstimatePrice(c *gin.Context) {
 	}
 	userIdInt := userId.(int)
 	log.Logger.Infof("CalOrderPrice: userID=%d", userIdInt)
-	ret, err := service.GetCartService().EstimatePrice(c.Request.Context(), userIdInt)
+	ret, err := service.GetCartService().EstimatePrice(c.Request.Context(), userIdInt, c.Query("region"), c.Query("currency"))
+	if errors.Is(err, pricing.ErrUnknownRegion) {
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Unsupported region"))
+		return
+	}
+	if errors.Is(err, pricing.ErrUnknownCurrency) {
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Unsupported currency"))
+		return
+	}
 	if err != nil {
 		log.Logger.Errorf("CalOrderPrice: Failed to estimate price: %v", err)
 		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to estimate price"))
@@ -228,3 +246,105 @@ func GetEstimatePrice(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, data.ResponseSuccess(ret))
 }
+
+// SelectCartItems godoc
+// @Summary Select or unselect cart items
+// @Description Select or unselect several cart items at once. Leave item_ids empty to apply to the whole cart. Items not in the cart are reported in the results
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param request body data.CartSelectionRequest true "items to select"
+// @Success 200 {object} data.BaseResponse{data=data.CartBatchResult}
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/cart/selection [put]
+func SelectCartItems(c *gin.Context) {
+	var req data.CartSelectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("SelectCartItems: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("SelectCartItems: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	ret, err := service.GetCartService().SelectItems(c.Request.Context(), userID.(int), req.ItemIDs, *req.Selected)
+	if err != nil {
+		log.Logger.Errorf("SelectCartItems: Failed to select cart items: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to select cart items"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(ret))
+}
+
+// DeleteCartItems godoc
+// @Summary Delete several cart items
+// @Description Delete several cart items at once. Items not in the cart are reported in the results
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param request body data.CartBatchDeleteRequest true "items to delete"
+// @Success 200 {object} data.BaseResponse{data=data.CartBatchResult}
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/cart/items/batch-delete [post]
+func DeleteCartItems(c *gin.Context) {
+	var req data.CartBatchDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("DeleteCartItems: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("DeleteCartItems: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	ret, err := service.GetCartService().DeleteItems(c.Request.Context(), userID.(int), req.ItemIDs)
+	if err != nil {
+		log.Logger.Errorf("DeleteCartItems: Failed to delete cart items: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to delete cart items"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(ret))
+}
+
+// UpdateCartQuantities godoc
+// @Summary Update the quantities of several cart items
+// @Description Update several quantities at once. Items that are not in the cart or lack stock keep their quantity and are reported in the results; the others are updated together
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param request body data.CartQuantityUpdateRequest true "new quantities"
+// @Success 200 {object} data.BaseResponse{data=data.CartBatchResult}
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/cart/items/quantities [put]
+func UpdateCartQuantities(c *gin.Context) {
+	var req data.CartQuantityUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("UpdateCartQuantities: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("UpdateCartQuantities: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	ret, err := service.GetCartService().UpdateQuantities(c.Request.Context(), userID.(int), req.Items)
+	if err != nil {
+		log.Logger.Errorf("UpdateCartQuantities: Failed to update cart quantities: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to update cart quantities"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(ret))
+}
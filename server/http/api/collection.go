@@ -0,0 +1,358 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateCollection godoc
+// @Summary 创建专题
+// @Description 商家创建一个跨分类的商品专题
+// @Tags 专题
+// @Accept json
+// @Produce json
+// @Param request body types.SaveCollectionRequest true "专题信息"
+// @Success 200 {object} data.BaseResponse{data=int} "专题ID"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/collections [post]
+func CreateCollection(c *gin.Context) {
+	var req types.SaveCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("CreateCollection: Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed(err.Error()))
+		return
+	}
+	collectionId, err := service.GetCollectionService().CreateCollection(c.Request.Context(), &req)
+	if err != nil {
+		log.Logger.Errorf("CreateCollection: Failed to create collection: %v", err)
+		responseServiceError(c, err, "Failed to create collection")
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(collectionId))
+}
+
+// UpdateCollection godoc
+// @Summary 编辑专题
+// @Description 更新专题名称、描述、封面及可见状态
+// @Tags 专题
+// @Accept json
+// @Produce json
+// @Param id path int true "专题ID"
+// @Param request body types.SaveCollectionRequest true "专题信息"
+// @Success 200 {object} data.BaseResponse "编辑成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 404 {object} data.BaseResponse "专题不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/collections/{id} [put]
+func UpdateCollection(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("UpdateCollection: Invalid collection ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid collection ID"))
+		return
+	}
+	var req types.SaveCollectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("UpdateCollection: Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed(err.Error()))
+		return
+	}
+	err = service.GetCollectionService().UpdateCollection(c.Request.Context(), id, &req)
+	if err != nil {
+		log.Logger.Errorf("UpdateCollection: Failed to update collection: %v", err)
+		responseServiceError(c, err, "Failed to update collection", service.CollectionCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// DeleteCollection godoc
+// @Summary 删除专题
+// @Description 删除专题及其商品关联，商品本身不受影响
+// @Tags 专题
+// @Produce json
+// @Param id path int true "专题ID"
+// @Success 200 {object} data.BaseResponse "删除成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 404 {object} data.BaseResponse "专题不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/collections/{id} [delete]
+func DeleteCollection(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("DeleteCollection: Invalid collection ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid collection ID"))
+		return
+	}
+	err = service.GetCollectionService().DeleteCollection(c.Request.Context(), id)
+	if err != nil {
+		log.Logger.Errorf("DeleteCollection: Failed to delete collection: %v", err)
+		responseServiceError(c, err, "Failed to delete collection", service.CollectionCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// GetMerchantCollectionList godoc
+// @Summary 商家端获取专题列表
+// @Description 返回全部专题，包括隐藏的专题
+// @Tags 专题
+// @Produce json
+// @Success 200 {object} data.BaseResponse{data=[]types.CollectionInfo}
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/collections [get]
+func GetMerchantCollectionList(c *gin.Context) {
+	getCollectionList(c, false)
+}
+
+// GetCustomerCollectionList godoc
+// @Summary 用户端获取专题列表
+// @Description 只返回用户可见的专题
+// @Tags 专题
+// @Produce json
+// @Success 200 {object} data.BaseResponse{data=[]types.CollectionInfo}
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /customer/collections [get]
+func GetCustomerCollectionList(c *gin.Context) {
+	getCollectionList(c, true)
+}
+
+func getCollectionList(c *gin.Context, isCustomer bool) {
+	list, err := service.GetCollectionService().ListCollections(c.Request.Context(), isCustomer)
+	if err != nil {
+		log.Logger.Errorf("GetCollectionList: Failed to list collections: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get collection list"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(list))
+}
+
+// GetMerchantCollection godoc
+// @Summary 商家端获取专题详情
+// @Description 返回专题信息及按顺序排列的商品ID
+// @Tags 专题
+// @Produce json
+// @Param id path int true "专题ID"
+// @Success 200 {object} data.BaseResponse "成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 404 {object} data.BaseResponse "专题不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/collections/{id} [get]
+func GetMerchantCollection(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("GetMerchantCollection: Invalid collection ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid collection ID"))
+		return
+	}
+	collectionService := service.GetCollectionService()
+	collection, err := collectionService.GetCollection(c.Request.Context(), id, false)
+	if err != nil {
+		log.Logger.Errorf("GetMerchantCollection: Failed to get collection: %v", err)
+		responseServiceError(c, err, "Failed to get collection", service.CollectionCheckStatus_NotExist)
+		return
+	}
+	productIds, err := collectionService.GetCollectionProductIDs(c.Request.Context(), id)
+	if err != nil {
+		log.Logger.Errorf("GetMerchantCollection: Failed to get collection products: %v", err)
+		responseServiceError(c, err, "Failed to get collection", service.CollectionCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(gin.H{
+		"collection":  collection,
+		"product_ids": productIds,
+	}))
+}
+
+// UpdateCollectionProducts godoc
+// @Summary 设置专题商品
+// @Description 按给定顺序覆盖专题内的商品，数组顺序即展示顺序
+// @Tags 专题
+// @Accept json
+// @Produce json
+// @Param id path int true "专题ID"
+// @Param request body types.UpdateCollectionProductsRequest true "商品ID列表"
+// @Success 200 {object} data.BaseResponse "设置成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 404 {object} data.BaseResponse "专题不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/collections/{id}/products [put]
+func UpdateCollectionProducts(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("UpdateCollectionProducts: Invalid collection ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid collection ID"))
+		return
+	}
+	var req types.UpdateCollectionProductsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("UpdateCollectionProducts: Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed(err.Error()))
+		return
+	}
+	err = service.GetCollectionService().SetCollectionProducts(c.Request.Context(), id, req.ProductIDs)
+	if err != nil {
+		log.Logger.Errorf("UpdateCollectionProducts: Failed to set collection products: %v", err)
+		responseServiceError(c, err, "Failed to update collection products", service.CollectionCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// GetCustomerCollection godoc
+// @Summary 用户端获取专题详情
+// @Description 只有用户可见的专题才能查看
+// @Tags 专题
+// @Produce json
+// @Param id path int true "专题ID"
+// @Success 200 {object} data.BaseResponse{data=types.CollectionInfo} "成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 404 {object} data.BaseResponse "专题不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /customer/collections/{id} [get]
+func GetCustomerCollection(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("GetCustomerCollection: Invalid collection ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid collection ID"))
+		return
+	}
+	collection, err := service.GetCollectionService().GetCollection(c.Request.Context(), id, true)
+	if err != nil {
+		log.Logger.Errorf("GetCustomerCollection: Failed to get collection: %v", err)
+		responseServiceError(c, err, "Failed to get collection", service.CollectionCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(collection))
+}
+
+// GetCustomerCollectionProducts godoc
+// @Summary 用户端浏览专题商品
+// @Description 按专题内顺序分页返回已上架的商品
+// @Tags 专题
+// @Produce json
+// @Param id path int true "专题ID"
+// @Param offset query int false "偏移量，默认0"
+// @Param currency query string false "展示币种 (ISO 4217)，默认基础货币"
+// @Param If-None-Match header string false "上次响应的 ETag"
+// @Success 200 {object} data.BaseResponse
+// @Success 304 "列表未变化"
+// @Failure 400 {object} data.BaseResponse "请求参数错误或不支持的币种"
+// @Failure 404 {object} data.BaseResponse "专题不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /customer/collections/{id}/products [get]
+func GetCustomerCollectionProducts(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("GetCustomerCollectionProducts: Invalid collection ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid collection ID"))
+		return
+	}
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err = strconv.Atoi(offsetStr)
+		if err != nil {
+			log.Logger.Errorf("GetCustomerCollectionProducts: Invalid offset parameter: %v", err)
+			c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid offset parameter"))
+			return
+		}
+	}
+
+	// 专题不可见时不允许浏览其中的商品
+	if _, err := service.GetCollectionService().GetCollection(c.Request.Context(), id, true); err != nil {
+		log.Logger.Errorf("GetCustomerCollectionProducts: Failed to get collection: %v", err)
+		responseServiceError(c, err, "Failed to get collection", service.CollectionCheckStatus_NotExist)
+		return
+	}
+
+	productList, total, err := service.GetProductServiceInstance().GetProductList(c.Request.Context(), types.GetProductListQuery{
+		CollectionID: id,
+		Limit:        10,
+		Offset:       offset,
+		IsCustomer:   true,
+		Currency:     c.Query("currency"),
+	})
+	if errors.Is(err, pricing.ErrUnknownCurrency) {
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Unsupported currency"))
+		return
+	}
+	if err != nil {
+		log.Logger.Errorf("GetCustomerCollectionProducts: Failed to get product list: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get product list"))
+		return
+	}
+	responseCacheable(c, productListETag(c, total, productList), data.ResponseSuccess(gin.H{
+		"total": total,
+		"list":  productList,
+	}))
+}
+
+// GetProductTags godoc
+// @Summary 获取商品标签
+// @Description 返回商品的全部标签
+// @Tags 商品
+// @Produce json
+// @Param id path int true "商品ID"
+// @Success 200 {object} data.BaseResponse{data=[]string}
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/tags [get]
+func GetProductTags(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("GetProductTags: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	tags, err := service.GetCollectionService().GetProductTags(c.Request.Context(), id)
+	if err != nil {
+		log.Logger.Errorf("GetProductTags: Failed to get product tags: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get product tags"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(tags))
+}
+
+// UpdateProductTags godoc
+// @Summary 设置商品标签
+// @Description 覆盖商品的全部标签，标签统一转为小写并去重
+// @Tags 商品
+// @Accept json
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param request body types.UpdateProductTagsRequest true "标签列表"
+// @Success 200 {object} data.BaseResponse{data=[]string} "设置后的标签"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/tags [put]
+func UpdateProductTags(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("UpdateProductTags: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	var req types.UpdateProductTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("UpdateProductTags: Invalid request body: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed(err.Error()))
+		return
+	}
+	tags, err := service.GetCollectionService().SetProductTags(c.Request.Context(), id, req.Tags)
+	if err != nil {
+		log.Logger.Errorf("UpdateProductTags: Failed to set product tags: %v", err)
+		responseServiceError(c, err, "Failed to update product tags", service.ProductCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(tags))
+}
@@ -0,0 +1,163 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateCoupon godoc
+// @Summary Create a coupon
+// @Description Create a percentage, fixed amount or free shipping promo code
+// @Tags Coupon
+// @Accept json
+// @Produce json
+// @Param coupon body data.CreateCouponRequest true "coupon info"
+// @Success 200 {object} data.BaseResponse{data=int} "coupon ID"
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /merchant/coupons [post]
+func CreateCoupon(c *gin.Context) {
+	var req data.CreateCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("CreateCoupon: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	couponId, err := service.GetCouponService().CreateCoupon(c.Request.Context(), &req)
+	if err != nil {
+		log.Logger.Errorf("CreateCoupon: Failed to create coupon: %v", err)
+		responseServiceError(c, err, "Failed to create coupon")
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(couponId))
+}
+
+// GetCouponList godoc
+// @Summary List coupons
+// @Description List coupons, newest first
+// @Tags Coupon
+// @Produce json
+// @Param offset query int false "offset, defaults to 0"
+// @Param limit query int false "page size, defaults to 20, at most 100"
+// @Success 200 {object} data.BaseResponse{data=[]data.CouponVO}
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /merchant/coupons [get]
+func GetCouponList(c *gin.Context) {
+	offset, limit, ok := parsePagination(c, 20, 100)
+	if !ok {
+		return
+	}
+	list, total, err := service.GetCouponService().ListCoupons(c.Request.Context(), offset, limit)
+	if err != nil {
+		log.Logger.Errorf("GetCouponList: Failed to list coupons: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get coupon list"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(gin.H{
+		"total": total,
+		"list":  list,
+	}))
+}
+
+// UpdateCouponStatus godoc
+// @Summary Enable or disable a coupon
+// @Description Disabled coupons stay applied to carts but are rejected by the price estimate
+// @Tags Coupon
+// @Accept json
+// @Produce json
+// @Param id path int true "coupon ID"
+// @Param request body data.UpdateCouponStatusRequest true "coupon status"
+// @Success 200 {object} data.BaseResponse
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 404 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /merchant/coupons/{id}/status [patch]
+func UpdateCouponStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		log.Logger.Errorf("UpdateCouponStatus: Invalid coupon ID: %s", c.Param("id"))
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid coupon ID"))
+		return
+	}
+	var req data.UpdateCouponStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("UpdateCouponStatus: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	err = service.GetCouponService().SetCouponActive(c.Request.Context(), id, *req.Active)
+	if err != nil {
+		log.Logger.Errorf("UpdateCouponStatus: Failed to update coupon: %v", err)
+		responseServiceError(c, err, "Failed to update coupon", service.CouponCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// ApplyCartCoupon godoc
+// @Summary Apply a coupon to the cart
+// @Description Apply a promo code to the cart, replacing any code applied before. The price estimate reports whether it takes effect.
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param request body data.ApplyCouponRequest true "coupon code"
+// @Success 200 {object} data.BaseResponse
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 404 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/cart/coupon [put]
+func ApplyCartCoupon(c *gin.Context) {
+	var req data.ApplyCouponRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("ApplyCartCoupon: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("ApplyCartCoupon: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	err := service.GetCartService().ApplyCoupon(c.Request.Context(), userID.(int), req.Code)
+	if err != nil {
+		log.Logger.Errorf("ApplyCartCoupon: Failed to apply coupon: %v", err)
+		responseServiceError(c, err, "Failed to apply coupon", service.CouponCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// RemoveCartCoupon godoc
+// @Summary Remove the coupon from the cart
+// @Description Remove the promo code applied to the cart
+// @Tags Cart
+// @Produce json
+// @Success 200 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/cart/coupon [delete]
+func RemoveCartCoupon(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("RemoveCartCoupon: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	if err := service.GetCartService().RemoveCoupon(c.Request.Context(), userID.(int)); err != nil {
+		log.Logger.Errorf("RemoveCartCoupon: Failed to remove coupon: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to remove coupon"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
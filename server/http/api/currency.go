@@ -0,0 +1,57 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GetCurrencyList godoc
+// @Summary List display currencies
+// @Description List the currencies prices can be shown in, with their exchange rate against the base currency. Pass the code as the currency query parameter of product and cart endpoints
+// @Tags Currency
+// @Produce json
+// @Success 200 {object} data.BaseResponse{data=[]data.CurrencyVO}
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/currencies [get]
+func GetCurrencyList(c *gin.Context) {
+	list, err := service.GetCurrencyService().ListCurrencies(c.Request.Context())
+	if err != nil {
+		log.Logger.Errorf("GetCurrencyList: Failed to list currencies: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get currency list"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(list))
+}
+
+// SetExchangeRate godoc
+// @Summary Set an exchange rate
+// @Description Admin only. Overrides the rate from the rates file, or adds a currency the file does not list. Takes effect on the next request of every replica
+// @Tags Currency
+// @Accept json
+// @Produce json
+// @Param currency path string true "ISO 4217 currency code"
+// @Param request body data.SetExchangeRateRequest true "exchange rate"
+// @Success 200 {object} data.BaseResponse
+// @Failure 400 {object} data.BaseResponse
+// @Failure 403 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /admin/exchange-rates/{currency} [put]
+func SetExchangeRate(c *gin.Context) {
+	var req data.SetExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("SetExchangeRate: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	err := service.GetCurrencyService().SetExchangeRate(operatorContext(c), c.Param("currency"), &req)
+	if err != nil {
+		log.Logger.Errorf("SetExchangeRate: Failed to set exchange rate: %v", err)
+		responseServiceError(c, err, "Failed to set exchange rate")
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
@@ -0,0 +1,84 @@
+package api
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// 用户侧商品接口允许 CDN 缓存，过期后凭 ETag 回源校验
+	customerCacheControl = "public, max-age=30, s-maxage=60, stale-while-revalidate=30"
+)
+
+// etagBuilder 根据影响响应内容的字段计算弱 ETag
+type etagBuilder struct {
+	parts []string
+}
+
+func newETagBuilder(parts ...string) *etagBuilder {
+	return &etagBuilder{parts: parts}
+}
+
+func (e *etagBuilder) addProduct(id int, version int64, updatedAt time.Time) *etagBuilder {
+	e.parts = append(e.parts, strconv.Itoa(id), strconv.FormatInt(version, 10), strconv.FormatInt(updatedAt.UnixNano(), 10))
+	return e
+}
+
+// addSalePrice 促销开始或结束时商品版本不变，需要单独计入 ETag
+func (e *etagBuilder) addSalePrice(salePrice int64) *etagBuilder {
+	e.parts = append(e.parts, strconv.FormatInt(salePrice, 10))
+	return e
+}
+
+// addDisplayPrice 汇率调整后商品版本不变，按展示币种与换算后的价格计入 ETag
+func (e *etagBuilder) addDisplayPrice(currency string, price int64) *etagBuilder {
+	e.parts = append(e.parts, currency, strconv.FormatInt(price, 10))
+	return e
+}
+
+// addLowestPrice 近 30 天最低价会随时间窗口滑动而变化，商品版本不变，需要单独计入 ETag
+func (e *etagBuilder) addLowestPrice(lowestPrice int64) *etagBuilder {
+	e.parts = append(e.parts, strconv.FormatInt(lowestPrice, 10))
+	return e
+}
+
+func (e *etagBuilder) String() string {
+	h := sha1.New()
+	for _, part := range e.parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil))[:20] + `"`
+}
+
+// responseCacheable 写入缓存相关响应头，客户端携带的 If-None-Match 命中时返回 304
+func responseCacheable(c *gin.Context, etag string, body interface{}) {
+	c.Header("Cache-Control", customerCacheControl)
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, body)
+}
+
+// etagMatches 按弱比较规则判断 If-None-Match 是否命中，支持多个 ETag 与 *
+func etagMatches(ifNoneMatch string, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == target {
+			return true
+		}
+	}
+	return false
+}
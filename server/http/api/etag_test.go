@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestETagMatches(t *testing.T) {
+	etag := newETagBuilder("product").addProduct(1, 2, time.Unix(100, 0)).String()
+	testCases := []struct {
+		name        string
+		ifNoneMatch string
+		expect      bool
+	}{
+		{"empty header", "", false},
+		{"exact match", etag, true},
+		{"strong form of weak etag", etag[2:], true},
+		{"one of several", `"other", ` + etag, true},
+		{"wildcard", "*", true},
+		{"different etag", `W/"other"`, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := etagMatches(tc.ifNoneMatch, etag); got != tc.expect {
+				t.Errorf("Expected %v, got %v", tc.expect, got)
+			}
+		})
+	}
+}
+
+func TestETagBuilder(t *testing.T) {
+	updatedAt := time.Unix(100, 0)
+	base := newETagBuilder("product").addProduct(1, 2, updatedAt).String()
+	if base != newETagBuilder("product").addProduct(1, 2, updatedAt).String() {
+		t.Errorf("Expected ETag to be stable for the same input")
+	}
+	if base == newETagBuilder("product").addProduct(1, 3, updatedAt).String() {
+		t.Errorf("Expected ETag to change with version")
+	}
+	if base == newETagBuilder("product").addProduct(1, 2, updatedAt.Add(time.Second)).String() {
+		t.Errorf("Expected ETag to change with update time")
+	}
+	onSale := newETagBuilder("product").addProduct(1, 2, updatedAt).addSalePrice(800).String()
+	if onSale == newETagBuilder("product").addProduct(1, 2, updatedAt).addSalePrice(0).String() {
+		t.Errorf("Expected ETag to change with sale price")
+	}
+	inUSD := newETagBuilder("product").addProduct(1, 2, updatedAt).addDisplayPrice("USD", 740).String()
+	if inUSD == newETagBuilder("product").addProduct(1, 2, updatedAt).addDisplayPrice("USD", 750).String() {
+		t.Errorf("Expected ETag to change with exchange rate")
+	}
+	lowest := newETagBuilder("product").addProduct(1, 2, updatedAt).addLowestPrice(900).String()
+	if lowest == newETagBuilder("product").addProduct(1, 2, updatedAt).addLowestPrice(1000).String() {
+		t.Errorf("Expected ETag to change with the 30-day lowest price")
+	}
+}
+
+func TestResponseCacheable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	etag := newETagBuilder("product").addProduct(1, 1, time.Unix(100, 0)).String()
+	r := gin.New()
+	r.GET("/product", func(c *gin.Context) {
+		responseCacheable(c, etag, gin.H{"name": "mug"})
+	})
+
+	t.Run("Returns body with cache headers", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/product", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected 200, got %d", w.Code)
+		}
+		if w.Header().Get("ETag") != etag {
+			t.Errorf("Expected ETag %s, got %s", etag, w.Header().Get("ETag"))
+		}
+		if w.Header().Get("Cache-Control") != customerCacheControl {
+			t.Errorf("Unexpected Cache-Control: %s", w.Header().Get("Cache-Control"))
+		}
+	})
+
+	t.Run("Returns 304 when If-None-Match matches", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/product", nil)
+		req.Header.Set("If-None-Match", etag)
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusNotModified {
+			t.Errorf("Expected 304, got %d", w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("Expected empty body, got %s", w.Body.String())
+		}
+	})
+}
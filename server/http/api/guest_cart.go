@@ -0,0 +1,84 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-user-mservice/common/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+const cartTokenHeader = "X-Cart-Token"
+
+// CartOwner authenticates cart requests. Logged in users always get their own cart. Visitors without
+// the auth cookie may send a guest cart token instead, and the guest cart's owner ID is set as the
+// userID, so the cart handlers serve both alike.
+func CartOwner() gin.HandlerFunc {
+	auth := middleware.AuthMiddleware()
+	return func(c *gin.Context) {
+		token := c.GetHeader(cartTokenHeader)
+		if _, err := c.Cookie("auth-token"); err == nil || token == "" {
+			auth(c)
+			return
+		}
+		ownerID, err := service.GetCartService().ResolveGuestCart(c.Request.Context(), token)
+		if isBizErrorCode(err, service.GuestCartStatus_NotExist) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, data.ResponseFailed("Invalid or expired cart token"))
+			return
+		}
+		if err != nil {
+			log.Logger.Errorf("CartOwner: Failed to resolve guest cart: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get guest cart"))
+			return
+		}
+		c.Set("userID", ownerID)
+		c.Next()
+	}
+}
+
+// CreateGuestCart godoc
+// @Summary Create a guest cart
+// @Description Issue a cart token for a visitor who has not logged in. Send it as the X-Cart-Token header on the cart endpoints, and to /customer/cart/merge after login
+// @Tags Cart
+// @Produce json
+// @Success 200 {object} data.BaseResponse{data=data.GuestCartVO}
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/guest-cart [post]
+func CreateGuestCart(c *gin.Context) {
+	ret, err := service.GetCartService().CreateGuestCart(c.Request.Context())
+	if err != nil {
+		log.Logger.Errorf("CreateGuestCart: Failed to create guest cart: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to create guest cart"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(ret))
+}
+
+// MergeGuestCart godoc
+// @Summary Merge the guest cart into the user's cart
+// @Description Call after login with the guest cart token. Quantities of the same product are summed up to the available stock; items that could not be merged in full are listed in unmerged. The guest cart is deleted afterwards
+// @Tags Cart
+// @Produce json
+// @Param X-Cart-Token header string true "guest cart token"
+// @Success 200 {object} data.BaseResponse{data=data.CartMergeResult}
+// @Failure 401 {object} data.BaseResponse
+// @Failure 404 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/cart/merge [post]
+func MergeGuestCart(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("MergeGuestCart: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	ret, err := service.GetCartService().MergeGuestCart(c.Request.Context(), userID.(int), c.GetHeader(cartTokenHeader))
+	if err != nil {
+		log.Logger.Errorf("MergeGuestCart: Failed to merge guest cart: %v", err)
+		responseServiceError(c, err, "Failed to merge guest cart", service.GuestCartStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(ret))
+}
@@ -1,11 +1,14 @@
 package api
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
 	"github.com/gin-gonic/gin"
@@ -25,13 +28,18 @@ func AddProduct(c *gin.Context) {
 	var req types.ProductInfo
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Logger.Errorf("AddProduct: Invalid request body: %v", err)
-		c.JSON(http.StatusBadRequest, data.ResponseFailed(err.Error()))
+		responseBindError(c, err)
 		return
 	}
-	productId, err := service.GetProductServiceInstance().Create(c.Request.Context(), &req)
+	productId, err := service.GetProductServiceInstance().Create(operatorContext(c), &req)
 	if err != nil {
 		log.Logger.Errorf("AddProduct: Failed to create product: %v", err)
-		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to create product"))
+		var notReady *service.PublishNotReadyError
+		if errors.As(err, &notReady) {
+			c.JSON(http.StatusOK, data.ResponseFailedWithErrors("product is not ready to publish", readinessFieldErrors(notReady.FailedChecks)))
+			return
+		}
+		responseServiceError(c, err, "Failed to create product")
 		return
 	}
 	c.JSON(http.StatusOK, data.ResponseSuccess(productId))
@@ -77,18 +85,17 @@ func GetProductMerchant(c *gin.Context) {
 	c.JSON(http.StatusOK, data.ResponseSuccess(product))
 }
 
-// PublishProduct godoc
-// @Summary 上架商品
-// @Description 将商品状态更改为上架状态
+// UpdateProductStatus godoc
+// @Summary 变更商品状态
+// @Description 按商品生命周期规则变更状态 (0-下架, 1-上架, 2-草稿, 3-待审核, 4-已归档, 5-已删除)，不允许的变更返回错误；上架时商品需通过上架检查，未通过的检查项在 errors 中返回
 // @Tags 商品
 // @Accept json
 // @Produce json
-// @Param request body types.UpdateProductStatusRequest true "商品上架请求"
-// @Success 200 {object} data.BaseResponse "上架成功"
+// @Param id path int true "商品ID"
+// @Param request body types.UpdateProductStatusRequest true "目标状态"
+// @Success 200 {object} data.BaseResponse "变更成功"
 // @Failure 400 {object} data.BaseResponse "请求参数错误"
-// @Failure 404 {object} data.BaseResponse "商品不存在"
-// @Failure 500 {object} data.BaseResponse "服务器内部错误"
-// @Router /merchant/products/:id/status [patch]
+// @Router /merchant/products/{id}/status [patch]
 func UpdateProductStatus(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -101,32 +108,169 @@ func UpdateProductStatus(c *gin.Context) {
 	var req types.UpdateProductStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Logger.Errorf("PublishProduct: Invalid request body: %v", err)
-		c.JSON(http.StatusBadRequest, data.ResponseFailed(err.Error()))
+		responseBindError(c, err)
 		return
 	}
 
-	if req.Status == 1 {
-		err := service.GetProductServiceInstance().PublishProduct(c.Request.Context(), id)
-		if err != nil {
-			log.Logger.Errorf("UpdateProductStatus: Failed to publish product: %v", err)
-			c.JSON(http.StatusOK, data.ResponseFailed(err.Error()))
-			return
-		}
-		c.JSON(http.StatusOK, data.ResponseSuccess("publish product success"))
-	} else {
-		err := service.GetProductServiceInstance().UnpublishProduct(c.Request.Context(), id)
-		if err != nil {
-			log.Logger.Errorf("UpdateProductStatus: Failed to unpublish product: %v", err)
-			c.JSON(http.StatusOK, data.ResponseFailed(err.Error()))
+	err = service.GetProductServiceInstance().TransitionProductStatus(operatorContext(c), id, req.Status)
+	if err != nil {
+		log.Logger.Errorf("UpdateProductStatus: Failed to update product status: %v", err)
+		var notReady *service.PublishNotReadyError
+		if errors.As(err, &notReady) {
+			c.JSON(http.StatusOK, data.ResponseFailedWithErrors("product is not ready to publish", readinessFieldErrors(notReady.FailedChecks)))
 			return
 		}
-		c.JSON(http.StatusOK, data.ResponseSuccess("unpublish product success"))
+		c.JSON(http.StatusOK, data.ResponseFailed(err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess("update product status success"))
+}
+
+// CheckPublishReadiness godoc
+// @Summary 上架前检查
+// @Description 按配置的检查项检查商品能否上架，返回所有未通过的检查项，不会修改商品
+// @Tags 商品
+// @Produce json
+// @Param id path int true "商品ID"
+// @Success 200 {object} data.BaseResponse{data=types.PublishReadiness} "检查结果"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/publish-readiness [get]
+func CheckPublishReadiness(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("CheckPublishReadiness: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	readiness, err := service.GetProductServiceInstance().CheckPublishReadiness(c.Request.Context(), id)
+	if err != nil {
+		log.Logger.Errorf("CheckPublishReadiness: Failed to check product: %v", err)
+		responseServiceError(c, err, "Failed to check product", service.ProductCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(readiness))
+}
+
+func readinessFieldErrors(checks []types.ReadinessCheck) []data.FieldError {
+	errs := make([]data.FieldError, 0, len(checks))
+	for _, check := range checks {
+		errs = append(errs, data.FieldError{Field: check.Field, Message: check.Message})
+	}
+	return errs
+}
+
+// ArchiveProduct godoc
+// @Summary 归档商品
+// @Description 归档后商品不再展示和销售，已上架的商品会同时下架，可通过恢复接口恢复为下架状态
+// @Tags 商品
+// @Produce json
+// @Param id path int true "商品ID"
+// @Success 200 {object} data.BaseResponse "归档成功"
+// @Failure 400 {object} data.BaseResponse "当前状态不允许归档"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/archive [post]
+func ArchiveProduct(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("ArchiveProduct: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	err = service.GetProductServiceInstance().ArchiveProduct(operatorContext(c), id)
+	if err != nil {
+		log.Logger.Errorf("ArchiveProduct: Failed to archive product: %v", err)
+		responseServiceError(c, err, "Failed to archive product", service.ProductCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// RestoreProduct godoc
+// @Summary 恢复已归档或已删除的商品
+// @Description 将已归档或已软删除的商品恢复为下架状态
+// @Tags 商品
+// @Produce json
+// @Param id path int true "商品ID"
+// @Success 200 {object} data.BaseResponse "恢复成功"
+// @Failure 400 {object} data.BaseResponse "商品未归档或删除"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/restore [post]
+func RestoreProduct(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("RestoreProduct: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	err = service.GetProductServiceInstance().RestoreProduct(operatorContext(c), id)
+	if err != nil {
+		log.Logger.Errorf("RestoreProduct: Failed to restore product: %v", err)
+		responseServiceError(c, err, "Failed to restore product", service.ProductCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// DeleteProduct godoc
+// @Summary 删除商品
+// @Description 软删除商品并从所有用户的购物车中移除，上架中的商品需要先下架；删除后可通过恢复接口恢复
+// @Tags 商品
+// @Produce json
+// @Param id path int true "商品ID"
+// @Success 200 {object} data.BaseResponse "删除成功"
+// @Failure 400 {object} data.BaseResponse "当前状态不允许删除"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id} [delete]
+func DeleteProduct(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("DeleteProduct: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	err = service.GetProductServiceInstance().DeleteProduct(operatorContext(c), id)
+	if err != nil {
+		log.Logger.Errorf("DeleteProduct: Failed to delete product: %v", err)
+		responseServiceError(c, err, "Failed to delete product", service.ProductCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// GetProductStatusHistory godoc
+// @Summary 获取商品状态变更记录
+// @Description 返回商品的状态变更记录，包括操作人和时间，最新的在前
+// @Tags 商品
+// @Produce json
+// @Param id path int true "商品ID"
+// @Success 200 {object} data.BaseResponse{data=[]types.ProductStatusTransitionInfo} "成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/status-history [get]
+func GetProductStatusHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("GetProductStatusHistory: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	history, err := service.GetProductServiceInstance().GetStatusHistory(c.Request.Context(), id)
+	if err != nil {
+		log.Logger.Errorf("GetProductStatusHistory: Failed to get status history: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get status history"))
+		return
 	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(history))
 }
 
 // UpdateProductStock godoc
 // @Summary 商家端更新商品库存
-// @Description 只有当商品处于下架状态时，才能更改商品库存
+// @Description 只有草稿或下架状态的商品可以直接设置库存，上架中的商品请使用按增量调整库存的接口
 // @Tags 商品
 // @Accept json
 // @Produce json
@@ -146,11 +290,11 @@ func UpdateProductStock(c *gin.Context) {
 	var req types.UpdateProductStockRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Logger.Errorf("UpdateProductStock: Invalid request body: %v", err)
-		c.JSON(http.StatusBadRequest, data.ResponseFailed(err.Error()))
+		responseBindError(c, err)
 		return
 	}
 
-	err = service.GetProductServiceInstance().UpdateProductStock(c.Request.Context(), id, req.Stock)
+	err = service.GetProductServiceInstance().UpdateProductStock(operatorContext(c), id, req.Stock)
 	if err != nil {
 		log.Logger.Errorf("UpdateProductStock: Failed to update product stock: %v", err)
 		c.JSON(http.StatusOK, data.ResponseFailed(err.Error()))
@@ -160,6 +304,326 @@ func UpdateProductStock(c *gin.Context) {
 	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
 }
 
+// AdjustProductStock godoc
+// @Summary 按增量调整商品库存
+// @Description 上架中的商品也可以调整。restock 只能增加库存，damage 只能减少库存，correction 用于盘点修正；每次调整都会记录
+// @Tags 商品
+// @Accept json
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param request body types.AdjustStockRequest true "库存调整"
+// @Success 200 {object} data.BaseResponse{data=types.StockAdjustmentInfo} "调整记录"
+// @Failure 400 {object} data.BaseResponse "请求参数错误或库存不足"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 409 {object} data.BaseResponse "库存并发修改，请重试"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/stock-adjustments [post]
+func AdjustProductStock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("AdjustProductStock: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	var req types.AdjustStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("AdjustProductStock: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	adjustment, err := service.GetProductServiceInstance().AdjustStock(operatorContext(c), id, &req)
+	if err != nil {
+		log.Logger.Errorf("AdjustProductStock: Failed to adjust stock: %v", err)
+		if isBizErrorCode(err, service.ProductCheckStatus_VersionConflict) {
+			c.JSON(http.StatusConflict, data.ResponseFailed(err.Error()))
+			return
+		}
+		responseServiceError(c, err, "Failed to adjust stock", service.ProductCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(adjustment))
+}
+
+// GetStockAdjustmentList godoc
+// @Summary 获取库存调整记录
+// @Description 按时间倒序分页返回商品的库存调整记录
+// @Tags 商品
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param offset query int false "偏移量，默认0"
+// @Param limit query int false "每页数量，默认20，最大100"
+// @Success 200 {object} data.BaseResponse{data=[]types.StockAdjustmentInfo} "调整记录"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/stock-adjustments [get]
+func GetStockAdjustmentList(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("GetStockAdjustmentList: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	offset, limit, ok := parsePagination(c, 20, 100)
+	if !ok {
+		return
+	}
+	list, total, err := service.GetProductServiceInstance().GetStockAdjustments(c.Request.Context(), id, offset, limit)
+	if err != nil {
+		log.Logger.Errorf("GetStockAdjustmentList: Failed to get stock adjustments: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get stock adjustments"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(gin.H{
+		"total": total,
+		"list":  list,
+	}))
+}
+
+// GetInventoryLedger godoc
+// @Summary 获取库存流水
+// @Description 按时间倒序分页返回商品的全部库存变化，包括初始库存、商家设置、订单扣减和库存调整
+// @Tags 商品
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param offset query int false "偏移量，默认0"
+// @Param limit query int false "每页数量，默认20，最大100"
+// @Success 200 {object} data.BaseResponse{data=[]types.InventoryLedgerEntryInfo} "库存流水"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/inventory-ledger [get]
+func GetInventoryLedger(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("GetInventoryLedger: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	offset, limit, ok := parsePagination(c, 20, 100)
+	if !ok {
+		return
+	}
+	list, total, err := service.GetProductServiceInstance().GetInventoryLedger(c.Request.Context(), id, offset, limit)
+	if err != nil {
+		log.Logger.Errorf("GetInventoryLedger: Failed to get inventory ledger: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get inventory ledger"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(gin.H{
+		"total": total,
+		"list":  list,
+	}))
+}
+
+// GetPriceHistory godoc
+// @Summary 获取价格历史
+// @Description 按生效时间倒序分页返回商品的价格变更记录，包括创建商品时的初始价格；effective_to 为空表示当前价格
+// @Tags 商品
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param offset query int false "偏移量，默认0"
+// @Param limit query int false "每页数量，默认20，最大100"
+// @Success 200 {object} data.BaseResponse{data=[]types.PriceChangeInfo} "价格历史"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/price-history [get]
+func GetPriceHistory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("GetPriceHistory: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	offset, limit, ok := parsePagination(c, 20, 100)
+	if !ok {
+		return
+	}
+	list, total, err := service.GetProductServiceInstance().GetPriceHistory(c.Request.Context(), id, offset, limit)
+	if err != nil {
+		log.Logger.Errorf("GetPriceHistory: Failed to get price history: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get price history"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(gin.H{
+		"total": total,
+		"list":  list,
+	}))
+}
+
+// ReconcileInventory godoc
+// @Summary 库存对账
+// @Description 将库存流水的增减合计与商品当前库存比较，consistent 为 false 时存在未记录流水的库存变化
+// @Tags 商品
+// @Produce json
+// @Param id path int true "商品ID"
+// @Success 200 {object} data.BaseResponse{data=types.InventoryReconciliation} "对账结果"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/inventory-reconciliation [get]
+func ReconcileInventory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("ReconcileInventory: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	ret, err := service.GetProductServiceInstance().ReconcileInventory(c.Request.Context(), id)
+	if err != nil {
+		log.Logger.Errorf("ReconcileInventory: Failed to reconcile inventory: %v", err)
+		responseServiceError(c, err, "Failed to reconcile inventory", service.ProductCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(ret))
+}
+
+// UpdateLowStockThreshold godoc
+// @Summary 设置库存预警阈值
+// @Description 库存小于等于阈值时进入低库存列表，库存从阈值以上降到阈值及以下时发送预警事件；0 表示关闭预警
+// @Tags 商品
+// @Accept json
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param request body types.UpdateLowStockThresholdRequest true "预警阈值"
+// @Success 200 {object} data.BaseResponse "设置成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/low-stock-threshold [put]
+func UpdateLowStockThreshold(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("UpdateLowStockThreshold: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	var req types.UpdateLowStockThresholdRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("UpdateLowStockThreshold: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	err = service.GetProductServiceInstance().SetLowStockThreshold(c.Request.Context(), id, req.Threshold)
+	if err != nil {
+		log.Logger.Errorf("UpdateLowStockThreshold: Failed to set threshold: %v", err)
+		responseServiceError(c, err, "Failed to set low stock threshold", service.ProductCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// UpdatePurchaseLimit godoc
+// @Summary 设置商品限购
+// @Description 每位顾客累计最多购买的数量，已下单的数量计入限购，取消订单后释放；0 表示不限购。加购和下单扣减库存时检查
+// @Tags 商品
+// @Accept json
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param request body types.UpdatePurchaseLimitRequest true "限购数量"
+// @Success 200 {object} data.BaseResponse "设置成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/purchase-limit [put]
+func UpdatePurchaseLimit(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("UpdatePurchaseLimit: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	var req types.UpdatePurchaseLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("UpdatePurchaseLimit: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	err = service.GetProductServiceInstance().SetPurchaseLimit(c.Request.Context(), id, req.MaxPerCustomer)
+	if err != nil {
+		log.Logger.Errorf("UpdatePurchaseLimit: Failed to set purchase limit: %v", err)
+		responseServiceError(c, err, "Failed to set purchase limit", service.ProductCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// UpdateStockMode godoc
+// @Summary 设置预售/缺货下单
+// @Description preorder 为预售，必须填写预计发货日期；backorder 为现货售完后继续接单。库存最低可扣减到 -max_backorder，normal 表示只售卖现有库存
+// @Tags 商品
+// @Accept json
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param request body types.UpdateStockModeRequest true "预售设置"
+// @Success 200 {object} data.BaseResponse "设置成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误或已超卖数量超过上限"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/stock-mode [put]
+func UpdateStockMode(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("UpdateStockMode: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	var req types.UpdateStockModeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("UpdateStockMode: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	err = service.GetProductServiceInstance().SetStockMode(c.Request.Context(), id, &req)
+	if err != nil {
+		log.Logger.Errorf("UpdateStockMode: Failed to set stock mode: %v", err)
+		responseServiceError(c, err, "Failed to set stock mode", service.ProductCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// GetLowStockProductList godoc
+// @Summary 获取低库存商品列表
+// @Description 返回库存小于等于预警阈值的商品，库存少的在前
+// @Tags 商品
+// @Produce json
+// @Param offset query int false "偏移量，默认0"
+// @Param limit query int false "每页数量，默认20，最大100"
+// @Success 200 {object} data.BaseResponse{data=[]types.LowStockProductInfo} "低库存商品"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/low-stock [get]
+func GetLowStockProductList(c *gin.Context) {
+	offset, limit, ok := parsePagination(c, 20, 100)
+	if !ok {
+		return
+	}
+	list, total, err := service.GetProductServiceInstance().GetLowStockProducts(c.Request.Context(), offset, limit)
+	if err != nil {
+		log.Logger.Errorf("GetLowStockProductList: Failed to get low stock products: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get low stock products"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(gin.H{
+		"total": total,
+		"list":  list,
+	}))
+}
+
+// parsePagination 解析 offset 和 limit 查询参数，参数非法时直接返回 400
+func parsePagination(c *gin.Context, defaultLimit int, maxLimit int) (offset int, limit int, ok bool) {
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid offset parameter"))
+		return 0, 0, false
+	}
+	limit, err = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultLimit)))
+	if err != nil || limit <= 0 || limit > maxLimit {
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid limit parameter"))
+		return 0, 0, false
+	}
+	return offset, limit, true
+}
+
 // GetCustomerProductList godoc
 // @Summary 用户端获取商品列表
 // @Description 支持按关键词搜索、分类筛选、分页，并按更新时间排序
@@ -168,10 +632,14 @@ func UpdateProductStock(c *gin.Context) {
 // @Produce json
 // @Param keyword query string false "搜索关键词"
 // @Param category query string false "商品分类"
+// @Param tag query string false "商品标签"
 // @Param offset query int false "偏移量，默认0"
 // @Param order_by query int false "排序方式：0-按更新时间降序，1-按更新时间升序，默认0"
+// @Param currency query string false "展示币种 (ISO 4217)，默认基础货币"
+// @Param If-None-Match header string false "上次响应的 ETag"
 // @Success 200 {object} data.BaseResponse
-// @Failure 400 {object} data.BaseResponse
+// @Success 304 "列表未变化"
+// @Failure 400 {object} data.BaseResponse "请求参数错误或不支持的币种"
 // @Failure 500 {object} data.BaseResponse
 // @Router /customer/products [get]
 func GetCustomerProductList(c *gin.Context) {
@@ -180,6 +648,7 @@ func GetCustomerProductList(c *gin.Context) {
 	// 获取查询参数
 	req.Keyword = c.Query("keyword")
 	req.Category = c.Query("category")
+	req.Tag = c.Query("tag")
 	offsetStr := c.Query("offset")
 	orderByStr := c.DefaultQuery("order_by", "0")
 
@@ -210,24 +679,40 @@ func GetCustomerProductList(c *gin.Context) {
 		Offset:     req.Offset,
 		OrderBy:    req.OrderBy,
 		Category:   req.Category,
+		Tag:        req.Tag,
 		IsCustomer: true,
+		Currency:   c.Query("currency"),
 	}
 
 	// 调用service层获取商品列表
 	productList, total, err := service.GetProductServiceInstance().GetProductList(c.Request.Context(), query)
+	if errors.Is(err, pricing.ErrUnknownCurrency) {
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Unsupported currency"))
+		return
+	}
 	if err != nil {
 		log.Logger.Errorf("GetCustomerProductList: Failed to get product list: %v", err)
 		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get product list"))
 		return
 	}
 
-	// 返回结果
-	c.JSON(http.StatusOK, data.ResponseSuccess(gin.H{
+	// 返回结果，列表内容未变化时返回 304
+	responseCacheable(c, productListETag(c, total, productList), data.ResponseSuccess(gin.H{
 		"total": total,
 		"list":  productList,
 	}))
 }
 
+// productListETag 由查询参数、总数以及每个商品的版本、修改时间、促销价与展示价格计算列表 ETag
+func productListETag(c *gin.Context, total int, list []*types.ProductSimplifiedInfo) string {
+	etag := newETagBuilder("product-list", c.Request.URL.RequestURI(), strconv.Itoa(total))
+	for _, product := range list {
+		etag.addProduct(product.ID, product.Version, product.UpdatedAt).addSalePrice(product.SalePrice).
+			addDisplayPrice(product.Currency, product.Price)
+	}
+	return etag.String()
+}
+
 // GetMerchantProductList godoc
 // @Summary 商家端获取商品列表
 // @Description 支持按关键词搜索、分类筛选、分页，并按更新时间排序
@@ -236,6 +721,7 @@ func GetCustomerProductList(c *gin.Context) {
 // @Produce json
 // @Param keyword query string false "搜索关键词"
 // @Param category query string false "商品分类"
+// @Param tag query string false "商品标签"
 // @Param offset query int false "偏移量，默认0"
 // @Param order_by query int false "排序方式：0-按更新时间降序，1-按更新时间升序，默认0"
 // @Success 200 {object} data.BaseResponse
@@ -248,6 +734,7 @@ func GetMerchantProductList(c *gin.Context) {
 	// 获取查询参数
 	req.Keyword = c.Query("keyword")
 	req.Category = c.Query("category")
+	req.Tag = c.Query("tag")
 	offsetStr := c.Query("offset")
 	orderByStr := c.DefaultQuery("order_by", "0")
 
@@ -278,6 +765,7 @@ func GetMerchantProductList(c *gin.Context) {
 		Offset:     req.Offset,
 		OrderBy:    req.OrderBy,
 		Category:   req.Category,
+		Tag:        req.Tag,
 		IsCustomer: false,
 	}
 
@@ -298,7 +786,7 @@ func GetMerchantProductList(c *gin.Context) {
 
 // EditProductInfo godoc
 // @Summary 编辑商品信息
-// @Description 根据商品ID更新商品详细信息
+// @Description 根据商品ID更新商品详细信息，请求需携带读取商品时返回的 version，版本不一致时返回 409
 // @Tags 商品
 // @Accept json
 // @Produce json
@@ -306,6 +794,7 @@ func GetMerchantProductList(c *gin.Context) {
 // @Success 200 {object} data.BaseResponse "编辑成功"
 // @Failure 400 {object} data.BaseResponse "请求参数错误"
 // @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 409 {object} data.BaseResponse "商品已被他人修改"
 // @Failure 500 {object} data.BaseResponse "服务器内部错误"
 // @Router /merchant/products/{id} [put]
 func EditProductInfo(c *gin.Context) {
@@ -320,17 +809,21 @@ func EditProductInfo(c *gin.Context) {
 	var req types.UpdateProductInfoRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		log.Logger.Errorf("EditProductInfo: Invalid request body: %v", err)
-		c.JSON(http.StatusBadRequest, data.ResponseFailed(err.Error()))
+		responseBindError(c, err)
 		return
 	}
 
 	req.ID = id
 
 	// 调用 service 层更新商品信息
-	err = service.GetProductServiceInstance().UpdateProductInfo(c.Request.Context(), &req)
+	err = service.GetProductServiceInstance().UpdateProductInfo(operatorContext(c), &req)
 	if err != nil {
 		log.Logger.Errorf("EditProductInfo: Failed to update product info: %v", err)
-		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to update product info"))
+		if isBizErrorCode(err, service.ProductCheckStatus_VersionConflict) {
+			c.JSON(http.StatusConflict, data.ResponseFailed(err.Error()))
+			return
+		}
+		responseServiceError(c, err, "Failed to update product info", service.ProductCheckStatus_NotExist)
 		return
 	}
 
@@ -344,8 +837,11 @@ func EditProductInfo(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path int true "商品ID"
+// @Param currency query string false "展示币种 (ISO 4217)，默认基础货币"
+// @Param If-None-Match header string false "上次响应的 ETag"
 // @Success 200 {object} data.BaseResponse{data=types.ProductInfo} "成功"
-// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Success 304 "商品未变化"
+// @Failure 400 {object} data.BaseResponse "请求参数错误或不支持的币种"
 // @Failure 404 {object} data.BaseResponse "商品不存在"
 // @Failure 500 {object} data.BaseResponse "服务器内部错误"
 // @Router /customer/product/{id} [get]
@@ -360,7 +856,11 @@ func GetProductCustomer(c *gin.Context) {
 	}
 
 	// 调用 service 层获取商品信息
-	product, err := service.GetProductServiceInstance().GetPublishedProductByID(c.Request.Context(), id)
+	product, err := service.GetProductServiceInstance().GetPublishedProductByID(c.Request.Context(), id, c.Query("currency"))
+	if errors.Is(err, pricing.ErrUnknownCurrency) {
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Unsupported currency"))
+		return
+	}
 	if err != nil {
 		log.Logger.Errorf("GetProduct: Failed to get product details: %v", err)
 		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get product details"))
@@ -373,6 +873,129 @@ func GetProductCustomer(c *gin.Context) {
 		return
 	}
 
-	// 返回商品信息
-	c.JSON(http.StatusOK, data.ResponseSuccess(product))
+	// 返回商品信息，内容未变化时返回 304
+	etag := newETagBuilder("product").addProduct(id, product.Version, product.UpdatedAt).addSalePrice(product.SalePrice).
+		addDisplayPrice(product.Currency, product.Price).addLowestPrice(product.LowestPrice30d).String()
+	responseCacheable(c, etag, data.ResponseSuccess(product))
+}
+
+// SubscribeBackInStock godoc
+// @Summary 订阅到货通知
+// @Description 已上架但无库存的商品可以订阅，补货后通知一次并自动取消订阅；重复订阅不报错
+// @Tags 商品
+// @Produce json
+// @Param id path int true "商品ID"
+// @Success 200 {object} data.BaseResponse "订阅成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误或商品有库存"
+// @Failure 401 {object} data.BaseResponse "未登录"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /customer/product/{id}/stock-subscription [post]
+func SubscribeBackInStock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("SubscribeBackInStock: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("SubscribeBackInStock: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	err = service.GetProductServiceInstance().SubscribeBackInStock(c.Request.Context(), id, userID.(int))
+	if err != nil {
+		log.Logger.Errorf("SubscribeBackInStock: Failed to subscribe: %v", err)
+		responseServiceError(c, err, "Failed to subscribe", service.ProductCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// UnsubscribeBackInStock godoc
+// @Summary 取消到货通知
+// @Description 取消当前用户对商品的到货通知订阅，未订阅时也返回成功
+// @Tags 商品
+// @Produce json
+// @Param id path int true "商品ID"
+// @Success 200 {object} data.BaseResponse "取消成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 401 {object} data.BaseResponse "未登录"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /customer/product/{id}/stock-subscription [delete]
+func UnsubscribeBackInStock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("UnsubscribeBackInStock: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("UnsubscribeBackInStock: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	err = service.GetProductServiceInstance().UnsubscribeBackInStock(c.Request.Context(), id, userID.(int))
+	if err != nil {
+		log.Logger.Errorf("UnsubscribeBackInStock: Failed to unsubscribe: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to unsubscribe"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// PatchProductInfo godoc
+// @Summary 部分更新商品信息
+// @Description 只修改请求中给出的字段：未传或传 null 的字段保持不变，传空字符串表示清空该字段；请求需携带 version，版本不一致时返回 409
+// @Tags 商品
+// @Accept json
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param request body types.PatchProductInfoRequest true "需要修改的字段"
+// @Success 200 {object} data.BaseResponse "修改成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 409 {object} data.BaseResponse "商品已被他人修改"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id} [patch]
+func PatchProductInfo(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("PatchProductInfo: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+
+	var req types.PatchProductInfoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("PatchProductInfo: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+
+	err = service.GetProductServiceInstance().PatchProductInfo(operatorContext(c), id, &req)
+	if err != nil {
+		log.Logger.Errorf("PatchProductInfo: Failed to patch product info: %v", err)
+		if isBizErrorCode(err, service.ProductCheckStatus_VersionConflict) {
+			c.JSON(http.StatusConflict, data.ResponseFailed(err.Error()))
+			return
+		}
+		responseServiceError(c, err, "Failed to update product info", service.ProductCheckStatus_NotExist)
+		return
+	}
+
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// operatorContext 返回带有当前登录用户的请求 context，用于记录操作人
+func operatorContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if userID, exists := c.Get("userID"); exists {
+		if id, ok := userID.(int); ok {
+			ctx = types.WithOperator(ctx, id)
+		}
+	}
+	return ctx
 }
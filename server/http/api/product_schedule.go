@@ -0,0 +1,124 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/gin-gonic/gin"
+)
+
+// CreateProductSchedule godoc
+// @Summary 创建定时上下架任务
+// @Description 在指定时间自动上架或下架商品，多副本部署时任务只会执行一次
+// @Tags 定时任务
+// @Accept json
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param request body types.CreateProductScheduleRequest true "定时任务"
+// @Success 200 {object} data.BaseResponse{data=int} "任务ID"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 404 {object} data.BaseResponse "商品不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/schedules [post]
+func CreateProductSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("CreateProductSchedule: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	var req types.CreateProductScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("CreateProductSchedule: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	scheduleId, err := service.GetProductScheduleService().CreateSchedule(operatorContext(c), id, &req)
+	if err != nil {
+		log.Logger.Errorf("CreateProductSchedule: Failed to create schedule: %v", err)
+		responseServiceError(c, err, "Failed to create schedule", service.ProductCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(scheduleId))
+}
+
+// GetProductScheduleList godoc
+// @Summary 获取商品的定时任务
+// @Description 按执行时间升序返回商品的定时上下架任务
+// @Tags 定时任务
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param pending query bool false "只返回等待执行的任务"
+// @Success 200 {object} data.BaseResponse{data=[]types.ProductScheduleInfo} "成功"
+// @Failure 400 {object} data.BaseResponse "请求参数错误"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/schedules [get]
+func GetProductScheduleList(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("GetProductScheduleList: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	listSchedules(c, id, c.Query("pending") == "true")
+}
+
+// GetPendingScheduleList godoc
+// @Summary 获取所有等待执行的定时任务
+// @Description 按执行时间升序返回所有商品等待执行的定时上下架任务
+// @Tags 定时任务
+// @Produce json
+// @Success 200 {object} data.BaseResponse{data=[]types.ProductScheduleInfo} "成功"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/schedules [get]
+func GetPendingScheduleList(c *gin.Context) {
+	listSchedules(c, 0, true)
+}
+
+func listSchedules(c *gin.Context, productId int, onlyPending bool) {
+	list, err := service.GetProductScheduleService().ListSchedules(c.Request.Context(), productId, onlyPending)
+	if err != nil {
+		log.Logger.Errorf("ListSchedules: Failed to list schedules: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to list schedules"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(list))
+}
+
+// CancelProductSchedule godoc
+// @Summary 取消定时任务
+// @Description 取消等待执行的定时任务，已执行或正在执行的任务不能取消
+// @Tags 定时任务
+// @Produce json
+// @Param id path int true "商品ID"
+// @Param scheduleId path int true "任务ID"
+// @Success 200 {object} data.BaseResponse "取消成功"
+// @Failure 400 {object} data.BaseResponse "任务已执行或已取消"
+// @Failure 404 {object} data.BaseResponse "任务不存在"
+// @Failure 500 {object} data.BaseResponse "服务器内部错误"
+// @Router /merchant/products/{id}/schedules/{scheduleId} [delete]
+func CancelProductSchedule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("CancelProductSchedule: Invalid product ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid product ID"))
+		return
+	}
+	scheduleId, err := strconv.Atoi(c.Param("scheduleId"))
+	if err != nil {
+		log.Logger.Errorf("CancelProductSchedule: Invalid schedule ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid schedule ID"))
+		return
+	}
+	err = service.GetProductScheduleService().CancelSchedule(c.Request.Context(), id, scheduleId)
+	if err != nil {
+		log.Logger.Errorf("CancelProductSchedule: Failed to cancel schedule: %v", err)
+		responseServiceError(c, err, "Failed to cancel schedule", service.ScheduleCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
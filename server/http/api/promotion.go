@@ -0,0 +1,104 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
+	"github.com/gin-gonic/gin"
+)
+
+// CreatePromotion godoc
+// @Summary Create an automatic promotion
+// @Description Create a sale (percentage off), bundle (buy X get Y free) or tiered (percentage off by quantity) promotion that applies without a code during its time window
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Param promotion body data.CreatePromotionRequest true "promotion info"
+// @Success 200 {object} data.BaseResponse{data=int} "promotion ID"
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /merchant/promotions [post]
+func CreatePromotion(c *gin.Context) {
+	var req data.CreatePromotionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("CreatePromotion: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	promotionId, err := service.GetPromotionService().CreatePromotion(c.Request.Context(), &req)
+	if err != nil {
+		log.Logger.Errorf("CreatePromotion: Failed to create promotion: %v", err)
+		responseServiceError(c, err, "Failed to create promotion")
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(promotionId))
+}
+
+// GetPromotionList godoc
+// @Summary List promotions
+// @Description List promotions, newest first
+// @Tags Promotion
+// @Produce json
+// @Param offset query int false "offset, defaults to 0"
+// @Param limit query int false "page size, defaults to 20, at most 100"
+// @Success 200 {object} data.BaseResponse{data=[]data.PromotionVO}
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /merchant/promotions [get]
+func GetPromotionList(c *gin.Context) {
+	offset, limit, ok := parsePagination(c, 20, 100)
+	if !ok {
+		return
+	}
+	list, total, err := service.GetPromotionService().ListPromotions(c.Request.Context(), offset, limit)
+	if err != nil {
+		log.Logger.Errorf("GetPromotionList: Failed to list promotions: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get promotion list"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(gin.H{
+		"total": total,
+		"list":  list,
+	}))
+}
+
+// UpdatePromotionStatus godoc
+// @Summary Enable or disable a promotion
+// @Description Disabled promotions stop applying immediately, even inside their time window
+// @Tags Promotion
+// @Accept json
+// @Produce json
+// @Param id path int true "promotion ID"
+// @Param request body data.UpdatePromotionStatusRequest true "promotion status"
+// @Success 200 {object} data.BaseResponse
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 404 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /merchant/promotions/{id}/status [patch]
+func UpdatePromotionStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		log.Logger.Errorf("UpdatePromotionStatus: Invalid promotion ID: %s", c.Param("id"))
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid promotion ID"))
+		return
+	}
+	var req data.UpdatePromotionStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("UpdatePromotionStatus: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	err = service.GetPromotionService().SetPromotionActive(c.Request.Context(), id, *req.Active)
+	if err != nil {
+		log.Logger.Errorf("UpdatePromotionStatus: Failed to update promotion: %v", err)
+		responseServiceError(c, err, "Failed to update promotion", service.PromotionCheckStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
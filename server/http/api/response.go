@@ -0,0 +1,33 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/gin-gonic/gin"
+)
+
+// responseServiceError 将 service 层返回的错误写回客户端：
+// BizError 视为请求问题返回 400 (不存在返回 404)，其余错误返回 500 并隐藏细节
+func responseServiceError(c *gin.Context, err error, internalMsg string, notFoundCodes ...int) {
+	var bizErr *types.BizError
+	if !errors.As(err, &bizErr) {
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed(internalMsg))
+		return
+	}
+	for _, code := range notFoundCodes {
+		if bizErr.Code == code {
+			c.JSON(http.StatusNotFound, data.ResponseFailed(bizErr.Message))
+			return
+		}
+	}
+	c.JSON(http.StatusBadRequest, data.ResponseFailed(bizErr.Message))
+}
+
+// isBizErrorCode 判断错误是否为指定错误码的 BizError
+func isBizErrorCode(err error, code int) bool {
+	var bizErr *types.BizError
+	return errors.As(err, &bizErr) && bizErr.Code == code
+}
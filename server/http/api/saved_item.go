@@ -0,0 +1,196 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
+	"github.com/gin-gonic/gin"
+)
+
+// GetSavedItemList godoc
+// @Summary List saved items
+// @Description List the wishlist and save-for-later items with the current price and availability of each product
+// @Tags Saved Items
+// @Produce json
+// @Param list query string false "wishlist or save_for_later, both lists when empty"
+// @Param currency query string false "display currency (ISO 4217), defaults to the base currency"
+// @Success 200 {object} data.BaseResponse{data=data.SavedListVO}
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/saved-items [get]
+func GetSavedItemList(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("GetSavedItemList: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	list := c.Query("list")
+	if list != "" && list != model.SavedListWishlist && list != model.SavedListSaveForLater {
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid list parameter"))
+		return
+	}
+	ret, err := service.GetCartService().ListSavedItems(c.Request.Context(), userID.(int), list, c.Query("currency"))
+	if errors.Is(err, pricing.ErrUnknownCurrency) {
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Unsupported currency"))
+		return
+	}
+	if err != nil {
+		log.Logger.Errorf("GetSavedItemList: Failed to list saved items: %v", err)
+		c.JSON(http.StatusInternalServerError, data.ResponseFailed("Failed to get saved items"))
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(ret))
+}
+
+// SaveItem godoc
+// @Summary Save a product
+// @Description Put a product on the wishlist or save it for later. Saving a product already on the list replaces its quantity
+// @Tags Saved Items
+// @Accept json
+// @Produce json
+// @Param request body data.SaveItemRequest true "product to save"
+// @Success 200 {object} data.BaseResponse{data=int} "saved item ID"
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/saved-items [post]
+func SaveItem(c *gin.Context) {
+	var req data.SaveItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		log.Logger.Errorf("SaveItem: Invalid request body: %v", err)
+		responseBindError(c, err)
+		return
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("SaveItem: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	id, err := service.GetCartService().SaveItem(c.Request.Context(), userID.(int), &req)
+	if err != nil {
+		log.Logger.Errorf("SaveItem: Failed to save item: %v", err)
+		responseServiceError(c, err, "Failed to save item")
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(id))
+}
+
+// DeleteSavedItem godoc
+// @Summary Delete a saved item
+// @Tags Saved Items
+// @Produce json
+// @Param id path int true "saved item ID"
+// @Success 200 {object} data.BaseResponse
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 404 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/saved-items/{id} [delete]
+func DeleteSavedItem(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("DeleteSavedItem: Invalid saved item ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid saved item ID"))
+		return
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("DeleteSavedItem: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	err = service.GetCartService().DeleteSavedItem(c.Request.Context(), userID.(int), id)
+	if err != nil {
+		log.Logger.Errorf("DeleteSavedItem: Failed to delete saved item: %v", err)
+		responseServiceError(c, err, "Failed to delete saved item", service.SavedItemStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// MoveSavedItemToCart godoc
+// @Summary Move a saved item to the cart
+// @Description Add the saved quantity to the cart and remove the item from its list. Fails, keeping the item saved, when the product is not on sale or lacks stock
+// @Tags Saved Items
+// @Produce json
+// @Param id path int true "saved item ID"
+// @Success 200 {object} data.BaseResponse
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 404 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/saved-items/{id}/move-to-cart [post]
+func MoveSavedItemToCart(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		log.Logger.Errorf("MoveSavedItemToCart: Invalid saved item ID: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid saved item ID"))
+		return
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("MoveSavedItemToCart: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	err = service.GetCartService().MoveToCart(c.Request.Context(), userID.(int), id)
+	if err != nil {
+		log.Logger.Errorf("MoveSavedItemToCart: Failed to move saved item to cart: %v", err)
+		responseServiceError(c, err, "Failed to move saved item to cart", service.SavedItemStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
+
+// MoveCartItemToSaved godoc
+// @Summary Move a cart item to a saved list
+// @Description Remove the item from the cart and save it for later, or put it on the wishlist
+// @Tags Saved Items
+// @Accept json
+// @Produce json
+// @Param item_id path int true "cart item ID"
+// @Param request body data.MoveFromCartRequest false "target list"
+// @Success 200 {object} data.BaseResponse
+// @Failure 400 {object} data.BaseResponse
+// @Failure 401 {object} data.BaseResponse
+// @Failure 404 {object} data.BaseResponse
+// @Failure 500 {object} data.BaseResponse
+// @Router /customer/cart/items/{item_id}/save [post]
+func MoveCartItemToSaved(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		log.Logger.Errorf("MoveCartItemToSaved: Invalid item_id parameter: %v", err)
+		c.JSON(http.StatusBadRequest, data.ResponseFailed("Invalid item_id parameter"))
+		return
+	}
+	var req data.MoveFromCartRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			log.Logger.Errorf("MoveCartItemToSaved: Invalid request body: %v", err)
+			responseBindError(c, err)
+			return
+		}
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		log.Logger.Error("MoveCartItemToSaved: User ID not found in context")
+		c.JSON(http.StatusUnauthorized, data.ResponseFailed("User not authenticated"))
+		return
+	}
+	err = service.GetCartService().MoveFromCart(c.Request.Context(), userID.(int), id, req.List)
+	if err != nil {
+		log.Logger.Errorf("MoveCartItemToSaved: Failed to move cart item: %v", err)
+		responseServiceError(c, err, "Failed to move cart item", service.CartItemStatus_NotExist)
+		return
+	}
+	c.JSON(http.StatusOK, data.ResponseSuccess(nil))
+}
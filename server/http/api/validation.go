@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// imageIDPattern 图片上传接口生成的对象 key: 十六进制时间戳 + 扩展名
+var imageIDPattern = regexp.MustCompile(`^[0-9a-f]+\.(jpg|jpeg|png)$`)
+
+// RegisterValidators 向 gin 的校验器注册自定义规则，并让错误中的字段名使用 json 名称
+func RegisterValidators() error {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return errors.New("unexpected validator engine")
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" || name == "" {
+			return field.Name
+		}
+		return name
+	})
+	if err := v.RegisterValidation("imageid", validateImageIDs); err != nil {
+		return err
+	}
+	return v.RegisterValidation("productstatus", validateProductStatus)
+}
+
+// validateImageIDs 校验 pic_info，多张图片以逗号分隔，空值表示没有图片
+func validateImageIDs(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value == "" {
+		return true
+	}
+	for _, id := range strings.Split(value, ",") {
+		if !imageIDPattern.MatchString(strings.TrimSpace(id)) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateProductStatus(fl validator.FieldLevel) bool {
+	return service.IsValidProductStatus(int(fl.Field().Int()))
+}
+
+// responseBindError 将请求绑定错误写回客户端，校验失败时逐个列出出错字段
+func responseBindError(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		c.JSON(http.StatusBadRequest, data.ResponseFailed(err.Error()))
+		return
+	}
+	fieldErrs := make([]data.FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fieldErrs = append(fieldErrs, data.FieldError{
+			Field:   fe.Field(),
+			Message: fieldErrorMessage(fe),
+		})
+	}
+	c.JSON(http.StatusBadRequest, data.ResponseValidationFailed(fieldErrs))
+}
+
+func fieldErrorMessage(fe validator.FieldError) string {
+	isString := fe.Kind() == reflect.String
+	switch fe.Tag() {
+	case "required":
+		return fmt.Sprintf("%s is required", fe.Field())
+	case "min":
+		if isString {
+			return fmt.Sprintf("%s must be at least %s characters", fe.Field(), fe.Param())
+		}
+		return fmt.Sprintf("%s must be greater than or equal to %s", fe.Field(), fe.Param())
+	case "max":
+		if isString {
+			return fmt.Sprintf("%s must be at most %s characters", fe.Field(), fe.Param())
+		}
+		return fmt.Sprintf("%s must be less than or equal to %s", fe.Field(), fe.Param())
+	case "oneof":
+		return fmt.Sprintf("%s must be one of [%s]", fe.Field(), fe.Param())
+	case "imageid":
+		return fmt.Sprintf("%s must be image ids returned by the upload url api", fe.Field())
+	case "productstatus":
+		return fmt.Sprintf("%s is not a valid product status", fe.Field())
+	default:
+		return fmt.Sprintf("%s failed on the '%s' rule", fe.Field(), fe.Tag())
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/gin-gonic/gin"
+)
+
+func bindForTest(t *testing.T, body string, req interface{}) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+	if err := RegisterValidators(); err != nil {
+		t.Fatalf("Failed to register validators: %v", err)
+	}
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if err := c.ShouldBindJSON(req); err != nil {
+		responseBindError(c, err)
+		return w, false
+	}
+	return w, true
+}
+
+func TestResponseBindError_ProductInfo(t *testing.T) {
+	body := `{"name":"","category":"Cups","price":-1,"stock":-5,"pic_info":"../etc/passwd","status":7}`
+	w, ok := bindForTest(t, body, &types.ProductInfo{})
+	if ok {
+		t.Fatal("Expected validation to fail")
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+
+	var resp data.BaseResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	got := map[string]string{}
+	for _, fe := range resp.Errors {
+		got[fe.Field] = fe.Message
+	}
+	for _, field := range []string{"name", "price", "stock", "pic_info", "status"} {
+		if _, exist := got[field]; !exist {
+			t.Errorf("Expected field error for %s, got %v", field, resp.Errors)
+		}
+	}
+	if _, exist := got["category"]; exist {
+		t.Errorf("Unexpected field error for category: %s", got["category"])
+	}
+}
+
+func TestResponseBindError_ValidRequests(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+		req  interface{}
+	}{
+		{"create product", `{"name":"Mug","category":"Cups","price":100,"stock":3,"pic_info":"18a2b3c.jpg,18a2b3d.png","status":1}`, &types.ProductInfo{}},
+		{"create product without picture", `{"name":"Mug","category":"Cups"}`, &types.ProductInfo{}},
+		{"patch clears fields", `{"version":1,"desc":"","pic_info":""}`, &types.PatchProductInfoRequest{}},
+		{"stock zero", `{"stock":0}`, &types.UpdateProductStockRequest{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if w, ok := bindForTest(t, tc.body, tc.req); !ok {
+				t.Errorf("Expected request to pass validation, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestResponseBindError_PatchProductInfo(t *testing.T) {
+	w, ok := bindForTest(t, `{"version":1,"name":""}`, &types.PatchProductInfoRequest{})
+	if ok {
+		t.Fatal("Expected empty name to fail validation")
+	}
+	if !strings.Contains(w.Body.String(), `"field":"name"`) {
+		t.Errorf("Expected field error for name, got %s", w.Body.String())
+	}
+
+	w, ok = bindForTest(t, `{"name":"Mug"}`, &types.PatchProductInfoRequest{})
+	if ok {
+		t.Fatal("Expected missing version to fail validation")
+	}
+	if !strings.Contains(w.Body.String(), `"field":"version"`) {
+		t.Errorf("Expected field error for version, got %s", w.Body.String())
+	}
+}
@@ -1,6 +1,10 @@
 package data
 
-import "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+import (
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+)
 
 type CartItemBasicVO struct {
 	ID        int  `json:"id"`
@@ -13,6 +17,9 @@ type CartItemBasicVO struct {
 const (
 	CartItemStatus_Normal     = 1
 	CartItemStatus_OutOfStock = 2
+	CartItemStatus_Backorder  = 3
+	// only saved items show this; the cart drops products that are no longer on sale
+	CartItemStatus_Unavailable = 4
 )
 
 type CartItemDetailVO struct {
@@ -20,7 +27,7 @@ type CartItemDetailVO struct {
 	ProductInfo types.ProductSimplifiedInfo `json:"product_info"`
 	Quantity    int                         `json:"quantity"`
 	TotalPrice  int                         `json:"total_price"`
-	Status      int                         `json:"status"` // 1: normal, 2: out of stock
+	Status      int                         `json:"status"` // 1: normal, 2: out of stock, 3: preorder or backorder
 	Selected    bool                        `json:"selected"`
 }
 
@@ -28,11 +35,90 @@ type CartListVO struct {
 	CartItems         []CartItemDetailVO `json:"cart_items"`
 	SelectedItemCount int                `json:"selected_item_count"`
 	SelectedPrice     int                `json:"selected_price"`
+	Currency          string             `json:"currency"` // ISO 4217 code of every amount in the cart
+	Notices           []CartNoticeVO     `json:"notices"`  // changes since the cart was last shown, each reported once
+}
+
+const (
+	CartNotice_PriceIncreased     = "price_increased"
+	CartNotice_PriceDecreased     = "price_decreased"
+	CartNotice_RemovedUnpublished = "removed_unpublished"
+	CartNotice_QuantityReduced    = "quantity_reduced"
+)
+
+type CartNoticeVO struct {
+	ItemID      int    `json:"item_id"`
+	ProductID   int    `json:"product_id"`
+	ProductName string `json:"product_name,omitempty"`
+	Type        string `json:"type"`
+	OldPrice    int    `json:"old_price,omitempty"` // unit prices of a price notice
+	NewPrice    int    `json:"new_price,omitempty"`
+	OldQuantity int    `json:"old_quantity,omitempty"` // quantities of a quantity notice
+	NewQuantity int    `json:"new_quantity,omitempty"`
 }
 
 type CartPriceEstimateResult struct {
-	ProductPrice  int `json:"product_price"`
-	ShippingPrice int `json:"shipping_price"`
-	Tax           int `json:"tax"`
-	Total         int `json:"total"`
+	Region        string           `json:"region"`
+	Currency      string           `json:"currency"` // ISO 4217 code of every amount in the estimate
+	ProductPrice  int              `json:"product_price"`
+	Discount      int              `json:"discount"`
+	ShippingPrice int              `json:"shipping_price"`
+	Tax           int              `json:"tax"`
+	Total         int              `json:"total"`
+	Lines         []PriceLine      `json:"lines"` // itemised breakdown in the order it was applied
+	Coupon        *AppliedCouponVO `json:"coupon,omitempty"`
+}
+
+type PriceLine struct {
+	Type        string `json:"type"` // subtotal, discount, shipping, tax
+	Code        string `json:"code,omitempty"`
+	Description string `json:"description"`
+	Amount      int    `json:"amount"` // discounts are negative
+}
+
+type GuestCartVO struct {
+	CartToken string    `json:"cart_token"` // send as the X-Cart-Token header on /customer/cart requests
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type CartMergeResult struct {
+	MergedItemCount int                `json:"merged_item_count"`
+	Unmerged        []CartMergeIssueVO `json:"unmerged"` // guest items that were dropped or only partly merged
+}
+
+type CartMergeIssueVO struct {
+	ProductID int    `json:"product_id"`
+	Requested int    `json:"requested"` // quantity in the guest cart
+	Merged    int    `json:"merged"`    // quantity added to the user's cart
+	Reason    string `json:"reason"`
+}
+
+type CartSelectionRequest struct {
+	ItemIDs  []int `json:"item_ids" binding:"max=200,unique,dive,min=1"` // empty applies to every item in the cart
+	Selected *bool `json:"selected" binding:"required"`
+}
+
+type CartBatchDeleteRequest struct {
+	ItemIDs []int `json:"item_ids" binding:"required,min=1,max=200,unique,dive,min=1"`
+}
+
+type CartQuantityUpdateRequest struct {
+	Items []CartQuantityVO `json:"items" binding:"required,min=1,max=200,unique=ItemID,dive"`
+}
+
+type CartQuantityVO struct {
+	ItemID   int `json:"item_id" binding:"required,min=1"`
+	Quantity int `json:"quantity" binding:"required,min=1"`
+}
+
+type CartBatchResult struct {
+	Succeeded int                `json:"succeeded"`
+	Results   []CartItemResultVO `json:"results"` // one per requested item, in request order
+}
+
+type CartItemResultVO struct {
+	ItemID  int    `json:"item_id"`
+	Success bool   `json:"success"`
+	Code    int    `json:"code,omitempty"` // error code of a failed item, e.g. -2 insufficient stock
+	Message string `json:"message,omitempty"`
 }
@@ -6,9 +6,16 @@ const (
 )
 
 type BaseResponse struct {
-	Code   int         `json:"code"`
-	ErrMsg string      `json:"err_msg,omitempty"`
-	Data   interface{} `json:"data,omitempty"`
+	Code   int          `json:"code"`
+	ErrMsg string       `json:"err_msg,omitempty"`
+	Data   interface{}  `json:"data,omitempty"`
+	Errors []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError 描述单个请求字段的校验失败原因
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
 
 func ResponseSuccess(data interface{}) BaseResponse {
@@ -25,3 +32,15 @@ func ResponseFailed(errMsg string) BaseResponse {
 		ErrMsg: errMsg,
 	}
 }
+
+func ResponseValidationFailed(errs []FieldError) BaseResponse {
+	return ResponseFailedWithErrors("invalid request parameters", errs)
+}
+
+func ResponseFailedWithErrors(errMsg string, errs []FieldError) BaseResponse {
+	return BaseResponse{
+		Code:   CodeFailed,
+		ErrMsg: errMsg,
+		Errors: errs,
+	}
+}
@@ -0,0 +1,50 @@
+package data
+
+import "time"
+
+type CreateCouponRequest struct {
+	Code         string     `json:"code" binding:"required,max=64"`
+	Type         string     `json:"type" binding:"required,oneof=percentage fixed_amount free_shipping"`
+	Value        int        `json:"value" binding:"min=0"`        // percent for percentage coupons, cents for fixed amount coupons
+	MaxDiscount  int        `json:"max_discount" binding:"min=0"` // cap for percentage coupons, 0 means no cap
+	MinSpend     int        `json:"min_spend" binding:"min=0"`
+	StartsAt     *time.Time `json:"starts_at"`
+	EndsAt       *time.Time `json:"ends_at"`
+	UsageLimit   int        `json:"usage_limit" binding:"min=0"`    // 0 means unlimited
+	PerUserLimit int        `json:"per_user_limit" binding:"min=0"` // 0 means unlimited
+	Categories   []string   `json:"categories"`                     // empty means every category
+	ProductIDs   []int      `json:"product_ids"`                    // empty means every product
+}
+
+type CouponVO struct {
+	ID           int        `json:"id"`
+	Code         string     `json:"code"`
+	Type         string     `json:"type"`
+	Value        int        `json:"value"`
+	MaxDiscount  int        `json:"max_discount"`
+	MinSpend     int        `json:"min_spend"`
+	StartsAt     *time.Time `json:"starts_at,omitempty"`
+	EndsAt       *time.Time `json:"ends_at,omitempty"`
+	UsageLimit   int        `json:"usage_limit"`
+	PerUserLimit int        `json:"per_user_limit"`
+	UsedCount    int        `json:"used_count"`
+	Categories   []string   `json:"categories"`
+	ProductIDs   []int      `json:"product_ids"`
+	Active       bool       `json:"active"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type UpdateCouponStatusRequest struct {
+	Active *bool `json:"active" binding:"required"`
+}
+
+type ApplyCouponRequest struct {
+	Code string `json:"code" binding:"required,max=64"`
+}
+
+// AppliedCouponVO reports whether the code applied to the cart took effect.
+type AppliedCouponVO struct {
+	Code         string `json:"code"`
+	Applied      bool   `json:"applied"`
+	RejectReason string `json:"reject_reason,omitempty"`
+}
@@ -0,0 +1,16 @@
+package data
+
+import "time"
+
+type CurrencyVO struct {
+	Currency  string     `json:"currency"`
+	Rate      string     `json:"rate"`     // units of the currency per unit of the base currency
+	Decimals  int        `json:"decimals"` // digits of the minor unit amounts are given in
+	Source    string     `json:"source"`   // base, file or admin
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type SetExchangeRateRequest struct {
+	Rate     string `json:"rate" binding:"required,max=32"` // decimal string, e.g. "0.74"
+	Decimals *int   `json:"decimals" binding:"required,min=0,max=4"`
+}
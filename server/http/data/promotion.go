@@ -0,0 +1,41 @@
+package data
+
+import "time"
+
+type PromotionTierVO struct {
+	MinQuantity int `json:"min_quantity" binding:"min=1"`
+	Percentage  int `json:"percentage" binding:"min=1,max=100"`
+}
+
+type CreatePromotionRequest struct {
+	Name         string            `json:"name" binding:"required,max=255"`
+	Type         string            `json:"type" binding:"required,oneof=sale bundle tiered"`
+	Percentage   int               `json:"percentage" binding:"min=0,max=99"` // sale promotions
+	BuyQuantity  int               `json:"buy_quantity" binding:"min=0"`      // bundle promotions, e.g. buy 2
+	FreeQuantity int               `json:"free_quantity" binding:"min=0"`     // bundle promotions, e.g. get 1 free
+	Tiers        []PromotionTierVO `json:"tiers" binding:"dive"`              // tiered promotions
+	Categories   []string          `json:"categories"`                        // empty means every category
+	ProductIDs   []int             `json:"product_ids"`                       // empty means every product
+	StartsAt     time.Time         `json:"starts_at" binding:"required"`
+	EndsAt       time.Time         `json:"ends_at" binding:"required"`
+}
+
+type PromotionVO struct {
+	ID           int               `json:"id"`
+	Name         string            `json:"name"`
+	Type         string            `json:"type"`
+	Percentage   int               `json:"percentage"`
+	BuyQuantity  int               `json:"buy_quantity"`
+	FreeQuantity int               `json:"free_quantity"`
+	Tiers        []PromotionTierVO `json:"tiers"`
+	Categories   []string          `json:"categories"`
+	ProductIDs   []int             `json:"product_ids"`
+	StartsAt     time.Time         `json:"starts_at"`
+	EndsAt       time.Time         `json:"ends_at"`
+	Active       bool              `json:"active"`
+	CreatedAt    time.Time         `json:"created_at"`
+}
+
+type UpdatePromotionStatusRequest struct {
+	Active *bool `json:"active" binding:"required"`
+}
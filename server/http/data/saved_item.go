@@ -0,0 +1,32 @@
+package data
+
+import (
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+)
+
+type SaveItemRequest struct {
+	ProductID int    `json:"product_id" binding:"required,min=1"`
+	List      string `json:"list" binding:"required,oneof=wishlist save_for_later"`
+	Quantity  int    `json:"quantity" binding:"omitempty,min=1"` // defaults to 1
+}
+
+type MoveFromCartRequest struct {
+	List string `json:"list" binding:"omitempty,oneof=wishlist save_for_later"` // defaults to save_for_later
+}
+
+type SavedItemVO struct {
+	ID          int                         `json:"id"`
+	List        string                      `json:"list"`
+	ProductInfo types.ProductSimplifiedInfo `json:"product_info"` // current price and availability
+	Quantity    int                         `json:"quantity"`
+	TotalPrice  int                         `json:"total_price"`
+	Status      int                         `json:"status"` // as cart items, plus 4: no longer on sale
+	SavedAt     time.Time                   `json:"saved_at"`
+}
+
+type SavedListVO struct {
+	Items    []SavedItemVO `json:"items"`
+	Currency string        `json:"currency"` // ISO 4217 code of every amount in the list
+}
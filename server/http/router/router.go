@@ -6,6 +6,7 @@ import (
 
 	_ "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/docs"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/api"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/metrics"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-user-mservice/common/middleware"
 	swaggerFiles "github.com/swaggo/files"
@@ -18,6 +19,9 @@ const (
 
 func NewRouter() *gin.Engine {
 	r := gin.Default()
+	if err := api.RegisterValidators(); err != nil {
+		log.Logger.Fatalf("Failed to register request validators: %v", err)
+	}
 
 	baseRouter := r.Group(servicePrefix)
 	{
@@ -40,28 +44,92 @@ func NewRouter() *gin.Engine {
 			merchantRouter.POST("/products", api.AddProduct)
 			merchantRouter.GET("/product/:id", api.GetProductMerchant)
 			merchantRouter.PATCH("/products/:id/status", api.UpdateProductStatus)
+			merchantRouter.GET("/products/:id/publish-readiness", api.CheckPublishReadiness)
+			merchantRouter.POST("/products/:id/archive", api.ArchiveProduct)
+			merchantRouter.POST("/products/:id/restore", api.RestoreProduct)
+			merchantRouter.DELETE("/products/:id", api.DeleteProduct)
+			merchantRouter.GET("/products/:id/status-history", api.GetProductStatusHistory)
+			merchantRouter.POST("/products/:id/schedules", api.CreateProductSchedule)
+			merchantRouter.GET("/products/:id/schedules", api.GetProductScheduleList)
+			merchantRouter.DELETE("/products/:id/schedules/:scheduleId", api.CancelProductSchedule)
+			merchantRouter.GET("/schedules", api.GetPendingScheduleList)
 			merchantRouter.PATCH("/products/:id/stock", api.UpdateProductStock)
+			merchantRouter.POST("/products/:id/stock-adjustments", api.AdjustProductStock)
+			merchantRouter.GET("/products/:id/stock-adjustments", api.GetStockAdjustmentList)
+			merchantRouter.GET("/products/:id/inventory-ledger", api.GetInventoryLedger)
+			merchantRouter.GET("/products/:id/inventory-reconciliation", api.ReconcileInventory)
+			merchantRouter.GET("/products/:id/price-history", api.GetPriceHistory)
+			merchantRouter.PUT("/products/:id/low-stock-threshold", api.UpdateLowStockThreshold)
+			merchantRouter.PUT("/products/:id/purchase-limit", api.UpdatePurchaseLimit)
+			merchantRouter.PUT("/products/:id/stock-mode", api.UpdateStockMode)
+			merchantRouter.GET("/products/low-stock", api.GetLowStockProductList)
 			merchantRouter.POST("/images/upload-urls", api.GetImageUploadPresignURL)
 			merchantRouter.GET("/products", api.GetMerchantProductList)
 			merchantRouter.PUT("/products/:id", api.EditProductInfo)
+			merchantRouter.PATCH("/products/:id", api.PatchProductInfo)
+			merchantRouter.GET("/products/:id/tags", api.GetProductTags)
+			merchantRouter.PUT("/products/:id/tags", api.UpdateProductTags)
+			merchantRouter.POST("/collections", api.CreateCollection)
+			merchantRouter.GET("/collections", api.GetMerchantCollectionList)
+			merchantRouter.GET("/collections/:id", api.GetMerchantCollection)
+			merchantRouter.PUT("/collections/:id", api.UpdateCollection)
+			merchantRouter.DELETE("/collections/:id", api.DeleteCollection)
+			merchantRouter.PUT("/collections/:id/products", api.UpdateCollectionProducts)
+			merchantRouter.POST("/coupons", api.CreateCoupon)
+			merchantRouter.GET("/coupons", api.GetCouponList)
+			merchantRouter.PATCH("/coupons/:id/status", api.UpdateCouponStatus)
+			merchantRouter.POST("/promotions", api.CreatePromotion)
+			merchantRouter.GET("/promotions", api.GetPromotionList)
+			merchantRouter.PATCH("/promotions/:id/status", api.UpdatePromotionStatus)
 		}
 
 		customerRouter := baseRouter.Group("/customer")
 		{
 			customerRouter.GET("/products", api.GetCustomerProductList)
 			customerRouter.GET("/product/:id", api.GetProductCustomer)
+			customerRouter.GET("/collections", api.GetCustomerCollectionList)
+			customerRouter.GET("/collections/:id", api.GetCustomerCollection)
+			customerRouter.GET("/collections/:id/products", api.GetCustomerCollectionProducts)
+			customerRouter.GET("/currencies", api.GetCurrencyList)
+			customerRouter.POST("/guest-cart", api.CreateGuestCart)
+
+			// visitors without a login use the guest cart of their X-Cart-Token
+			cart := customerRouter.Group("/cart")
+			{
+				cart.Use(api.CartOwner())
+				cart.GET("", api.GetUserCartInfo)
+				cart.POST("/items", api.CreateCartItem)
+				cart.PUT("/items/:item_id", api.UpdateCartItem)
+				cart.DELETE("/items/:item_id", api.DeleteCartItem)
+				cart.PUT("/items/quantities", api.UpdateCartQuantities)
+				cart.POST("/items/batch-delete", api.DeleteCartItems)
+				cart.PUT("/selection", api.SelectCartItems)
+				cart.GET("/selected-num", api.GetCartSelctedNum)
+				cart.GET("/price-estimate", api.GetEstimatePrice)
+			}
 
 			authed := customerRouter.Group("")
 			{
 				authed.Use(middleware.AuthMiddleware())
-				authed.GET("/cart", api.GetUserCartInfo)
-				authed.POST("/cart/items", api.CreateCartItem)
-				authed.PUT("/cart/items/:item_id", api.UpdateCartItem)
-				authed.DELETE("/cart/items/:item_id", api.DeleteCartItem)
-				authed.GET("/cart/selected-num", api.GetCartSelctedNum)
-				authed.GET("/cart/price-estimate", api.GetEstimatePrice)
+				authed.POST("/cart/merge", api.MergeGuestCart)
+				authed.POST("/cart/items/:item_id/save", api.MoveCartItemToSaved)
+				authed.GET("/saved-items", api.GetSavedItemList)
+				authed.POST("/saved-items", api.SaveItem)
+				authed.DELETE("/saved-items/:id", api.DeleteSavedItem)
+				authed.POST("/saved-items/:id/move-to-cart", api.MoveSavedItemToCart)
+				authed.PUT("/cart/coupon", api.ApplyCartCoupon)
+				authed.DELETE("/cart/coupon", api.RemoveCartCoupon)
+				authed.POST("/product/:id/stock-subscription", api.SubscribeBackInStock)
+				authed.DELETE("/product/:id/stock-subscription", api.UnsubscribeBackInStock)
 			}
 		}
+
+		adminRouter := baseRouter.Group("/admin")
+		{
+			adminRouter.Use(middleware.AuthMiddleware(), api.RequireAdmin())
+			adminRouter.POST("/products/purge", api.PurgeDeletedProducts)
+			adminRouter.PUT("/exchange-rates/:currency", api.SetExchangeRate)
+		}
 	}
 	return r
 }
@@ -5,6 +5,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/cache"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/grpc"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http"
@@ -12,6 +13,7 @@ import (
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/metrics"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/mq"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/scheduler"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-user-mservice/common/utils"
 )
 
@@ -26,8 +28,10 @@ func main() {
 	log.InitLogger()
 	metrics.RegisterMetrics()
 	repository.Init()
+	cache.Init()
 	utils.InitJwtSecret()
 	mq.Init()
+	scheduler.Init()
 	go grpc.Init(sigCh)
 	go http.Init(sigCh)
 	// listen terminage signal
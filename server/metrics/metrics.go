@@ -30,8 +30,26 @@ var (
 		},
 		[]string{"method", "path", "status"},
 	)
+
+	// 缓存命中情况（用于命中率）
+	CacheRequestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_requests_total",
+			Help: "Total number of cache lookups partitioned by cache name and result (hit/miss).",
+		},
+		[]string{"cache", "result"},
+	)
+
+	// 低于预警阈值的商品当前库存，恢复后删除对应的 product_id
+	LowStockProducts = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "product_low_stock",
+			Help: "Current stock of products at or below their low-stock threshold.",
+		},
+		[]string{"product_id"},
+	)
 )
 
 func RegisterMetrics() {
-	prometheus.MustRegister(HttpRequestsTotal, HttpRequestDuration, HttpRequestsErrors)
+	prometheus.MustRegister(HttpRequestsTotal, HttpRequestDuration, HttpRequestsErrors, CacheRequestsTotal, LowStockProducts)
 }
@@ -31,6 +31,11 @@ func clearCartProcess(msg []byte) error {
 	for _, item := range orderCreatedMessage.OrderItemList {
 		productIds = append(productIds, item.ProductID)
 	}
+	// the coupon was redeemed when stock was deducted for the order, it only leaves the cart here
+	if err := service.GetCartService().RemoveCoupon(context.Background(), orderCreatedMessage.UserID); err != nil {
+		log.Logger.Errorf("Failed to remove cart coupon for user ID %d: %v", orderCreatedMessage.UserID, err)
+		return err
+	}
 	err = service.GetCartService().DeleteItemByProductIds(context.Background(), orderCreatedMessage.UserID, productIds)
 	if err != nil {
 		log.Logger.Errorf("Failed to delete user_cart for user ID %d: %v", orderCreatedMessage.UserID, err)
@@ -0,0 +1,154 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
+	"github.com/spf13/viper"
+)
+
+// ErrUnknownCurrency is returned when a display currency has no exchange rate.
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+const (
+	defaultBaseCurrency = "SGD"
+	defaultBaseDecimals = 2
+	maxDecimals         = 4
+)
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Currency is a display currency. Prices are stored in minor units of the base currency and
+// every displayed amount is converted from a base amount by the same rule: multiply by the
+// rate, shift to the currency's minor units and round half away from zero.
+type Currency struct {
+	Code     string
+	Rate     string // units of this currency bought by one unit of the base currency, e.g. "0.74"
+	Decimals int    // digits of the minor unit: 2 for cents, 0 for yen
+	factor   *big.Rat
+}
+
+var (
+	baseCurrency     *Currency
+	baseCurrencyOnce sync.Once
+)
+
+// Base returns the currency prices are stored in, from the currency section of the config.
+func Base() *Currency {
+	baseCurrencyOnce.Do(func() {
+		code, decimals := defaultBaseCurrency, defaultBaseDecimals
+		if conf := config.Config.CurrencyConfig; conf != nil && conf.Base != "" {
+			code, decimals = strings.ToUpper(conf.Base), conf.Decimals
+		}
+		baseCurrency = &Currency{Code: code, Rate: "1", Decimals: decimals, factor: big.NewRat(1, 1)}
+	})
+	return baseCurrency
+}
+
+// NormalizeCurrencyCode upper-cases and trims an ISO 4217 code.
+func NormalizeCurrencyCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// NewCurrency builds a display currency from its exchange rate against the base currency.
+func NewCurrency(code, rate string, decimals int) (*Currency, error) {
+	code = NormalizeCurrencyCode(code)
+	if !currencyCodePattern.MatchString(code) {
+		return nil, fmt.Errorf("invalid currency code %q", code)
+	}
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(rate))
+	if !ok || r.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid exchange rate %q for %s", rate, code)
+	}
+	if decimals < 0 || decimals > maxDecimals {
+		return nil, fmt.Errorf("decimals of %s must be between 0 and %d", code, maxDecimals)
+	}
+	shift := decimals - Base().Decimals
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(shift))), nil))
+	if shift < 0 {
+		scale.Inv(scale)
+	}
+	return &Currency{Code: code, Rate: strings.TrimSpace(rate), Decimals: decimals, factor: r.Mul(r, scale)}, nil
+}
+
+// Convert turns an amount in minor units of the base currency into minor units of c.
+func (c *Currency) Convert(amount int64) int64 {
+	v := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), c.factor)
+	q, r := new(big.Int).QuoRem(new(big.Int).Abs(v.Num()), v.Denom(), new(big.Int))
+	// round half away from zero
+	if r.Lsh(r, 1).Cmp(v.Denom()) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if v.Sign() < 0 {
+		q.Neg(q)
+	}
+	return q.Int64()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// ExchangeRates maps upper-case currency codes to display currencies.
+type ExchangeRates map[string]*Currency
+
+// Lookup returns the currency for code. An empty code or the base currency's code returns the base currency.
+func (r ExchangeRates) Lookup(code string) (*Currency, error) {
+	code = NormalizeCurrencyCode(code)
+	if code == "" || code == Base().Code {
+		return Base(), nil
+	}
+	currency, ok := r[code]
+	if !ok {
+		return nil, ErrUnknownCurrency
+	}
+	return currency, nil
+}
+
+// RatesFromConfig loads the rates file named in the currency section of the config.
+// Without a file only the base currency is available.
+func RatesFromConfig() (ExchangeRates, error) {
+	conf := config.Config.CurrencyConfig
+	if conf == nil || conf.RatesFile == "" {
+		return ExchangeRates{}, nil
+	}
+	return LoadRatesFile(conf.RatesFile)
+}
+
+// LoadRatesFile reads a YAML file of the form
+//
+//	rates:
+//	  USD: { rate: "0.74", decimals: 2 }
+func LoadRatesFile(path string) (ExchangeRates, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read exchange rates %s: %w", path, err)
+	}
+	var file struct {
+		Rates map[string]struct {
+			Rate     string `mapstructure:"rate"`
+			Decimals int    `mapstructure:"decimals"`
+		} `mapstructure:"rates"`
+	}
+	if err := v.Unmarshal(&file); err != nil {
+		return nil, fmt.Errorf("decode exchange rates %s: %w", path, err)
+	}
+	rates := make(ExchangeRates, len(file.Rates))
+	for code, entry := range file.Rates {
+		currency, err := NewCurrency(code, entry.Rate, entry.Decimals)
+		if err != nil {
+			return nil, fmt.Errorf("exchange rates %s: %w", path, err)
+		}
+		rates[currency.Code] = currency
+	}
+	return rates, nil
+}
@@ -0,0 +1,109 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCurrency_Convert(t *testing.T) {
+	mustCurrency := func(code, rate string, decimals int) *Currency {
+		c, err := NewCurrency(code, rate, decimals)
+		if err != nil {
+			t.Fatalf("Expected a currency, got %v", err)
+		}
+		return c
+	}
+	usd := mustCurrency("usd", "0.74", 2)
+	jpy := mustCurrency("JPY", "112", 0)
+	half := mustCurrency("XTS", "0.5", 2)
+	cases := []struct {
+		currency *Currency
+		amount   int64
+		want     int64
+	}{
+		{usd, 1000, 740},
+		{usd, 1, 1},       // 0.74 cents
+		{jpy, 1050, 1176}, // S$10.50 -> 1176 yen
+		{jpy, 5, 6},       // 5.6 yen
+		{half, 1, 1},      // exactly half rounds away from zero
+		{half, -1, -1},
+		{half, -3, -2},
+	}
+	for _, tc := range cases {
+		if got := tc.currency.Convert(tc.amount); got != tc.want {
+			t.Errorf("%s: expected %d to convert to %d, got %d", tc.currency.Code, tc.amount, tc.want, got)
+		}
+	}
+
+	for _, bad := range [][2]string{{"US", "0.74"}, {"USD", "0"}, {"USD", "abc"}, {"USD", "-1"}} {
+		if _, err := NewCurrency(bad[0], bad[1], 2); err == nil {
+			t.Errorf("Expected an error for %v", bad)
+		}
+	}
+}
+
+func TestExchangeRates_Lookup(t *testing.T) {
+	usd, _ := NewCurrency("USD", "0.74", 2)
+	rates := ExchangeRates{"USD": usd}
+	if c, err := rates.Lookup(""); err != nil || c != Base() {
+		t.Errorf("Expected the base currency, got %+v, %v", c, err)
+	}
+	if c, err := rates.Lookup(" usd "); err != nil || c != usd {
+		t.Errorf("Expected USD, got %+v, %v", c, err)
+	}
+	if _, err := rates.Lookup("GBP"); !errors.Is(err, ErrUnknownCurrency) {
+		t.Errorf("Expected ErrUnknownCurrency, got %v", err)
+	}
+}
+
+func TestLoadRatesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.yml")
+	content := "rates:\n  USD: { rate: \"0.74\", decimals: 2 }\n  jpy: { rate: 112, decimals: 0 }\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	rates, err := LoadRatesFile(path)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(rates) != 2 || rates["USD"].Rate != "0.74" || rates["JPY"].Decimals != 0 {
+		t.Errorf("Unexpected rates: %+v", rates)
+	}
+}
+
+func TestQuote_Convert(t *testing.T) {
+	p := NewPipeline(testRules(), SubtotalStage(), DiscountStage(&fixedDiscounter{lines: []*Line{
+		{Code: "A", Amount: 333},
+		{Code: "B", Amount: 333},
+	}}), ShippingStage(), TaxStage())
+	q, err := p.Quote(context.Background(), &Cart{Items: []*Item{{ProductID: 1, UnitPrice: 1999, Quantity: 3}}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	usd, _ := NewCurrency("USD", "0.74", 2)
+	converted := q.Convert(usd)
+	if converted.Currency != "USD" || q.Currency != Base().Code {
+		t.Errorf("Expected a USD copy of the %s quote, got %s", q.Currency, converted.Currency)
+	}
+	// 1999 -> 1479.26 rounds to 1479 per unit, and the subtotal is the sum of converted units
+	if converted.Items[0].UnitPrice != 1479 || converted.Subtotal != 4437 || q.Items[0].UnitPrice != 1999 {
+		t.Errorf("Unexpected converted items: %+v, subtotal %d", converted.Items[0], converted.Subtotal)
+	}
+	// each 333 discount becomes 246.42 -> 246
+	if converted.Discount != 492 {
+		t.Errorf("Expected discount 492, got %d", converted.Discount)
+	}
+	sum := 0
+	for _, line := range converted.Lines {
+		sum += line.Amount
+	}
+	if sum != converted.Total() {
+		t.Errorf("Expected the lines to add up to the total %d, got %d", converted.Total(), sum)
+	}
+	if q.Convert(Base()) != q {
+		t.Error("Expected converting to the quote's own currency to return the quote")
+	}
+}
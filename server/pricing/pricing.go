@@ -0,0 +1,207 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrUnknownRegion is returned when a quote is requested for a region without pricing rules.
+var ErrUnknownRegion = errors.New("unknown pricing region")
+
+const (
+	LineSubtotal = "subtotal"
+	LineDiscount = "discount"
+	LineShipping = "shipping"
+	LineTax      = "tax"
+
+	// LineFreeShipping is returned by discounters to waive shipping; it never appears in a quote.
+	LineFreeShipping = "free_shipping"
+)
+
+// Item is a priced cart line. Amounts are in cents.
+type Item struct {
+	ProductID int
+	Category  string
+	UnitPrice int
+	Quantity  int
+}
+
+// Cart is the input of a quote.
+type Cart struct {
+	UserID     int
+	Region     string
+	CouponCode string
+	Items      []*Item
+}
+
+// Line is one entry of the itemised breakdown. Discount lines carry negative amounts.
+type Line struct {
+	Type        string
+	Code        string
+	Description string
+	Amount      int
+}
+
+// Rejection explains why a code offered with the cart was not applied.
+type Rejection struct {
+	Code   string
+	Reason string
+}
+
+// Quote accumulates the result of every stage of a pipeline.
+type Quote struct {
+	Region     string
+	Currency   string // amounts are in minor units of this currency
+	Rules      *RegionRules
+	UserID     int
+	CouponCode string
+	Items      []*Item
+
+	Subtotal     int
+	Discount     int
+	FreeShipping *Line // set when a discounter waives shipping
+	Shipping     int
+	Tax          int
+	Lines        []*Line
+	Rejections   []*Rejection
+}
+
+// Total is the amount the customer pays.
+func (q *Quote) Total() int {
+	return q.Subtotal - q.Discount + q.Shipping + q.Tax
+}
+
+// Taxable is the merchandise amount after discounts.
+func (q *Quote) Taxable() int {
+	return q.Subtotal - q.Discount
+}
+
+// Reject records that code could not be applied to the quote.
+func (q *Quote) Reject(code, reason string) {
+	q.Rejections = append(q.Rejections, &Rejection{Code: code, Reason: reason})
+}
+
+// Convert returns the quote in display currency c. Unit prices, discount lines, shipping and tax
+// are each converted from their base amount, while the subtotal and the discount are summed from
+// the converted parts so that the breakdown still adds up to Total.
+func (q *Quote) Convert(c *Currency) *Quote {
+	if c == nil || c.Code == q.Currency {
+		return q
+	}
+	ret := *q
+	ret.Currency = c.Code
+	ret.Items = make([]*Item, 0, len(q.Items))
+	ret.Subtotal = 0
+	for _, item := range q.Items {
+		converted := *item
+		converted.UnitPrice = int(c.Convert(int64(item.UnitPrice)))
+		ret.Items = append(ret.Items, &converted)
+		ret.Subtotal += converted.UnitPrice * converted.Quantity
+	}
+	ret.Discount = 0
+	ret.Lines = make([]*Line, 0, len(q.Lines))
+	for _, line := range q.Lines {
+		converted := *line
+		converted.Amount = int(c.Convert(int64(line.Amount)))
+		switch line.Type {
+		case LineSubtotal:
+			converted.Amount = ret.Subtotal
+		case LineDiscount:
+			// rounding must not push the discount past the subtotal
+			amount := -converted.Amount
+			if remaining := ret.Subtotal - ret.Discount; amount > remaining {
+				amount = remaining
+			}
+			ret.Discount += amount
+			converted.Amount = -amount
+		case LineShipping:
+			ret.Shipping = converted.Amount
+		case LineTax:
+			ret.Tax = converted.Amount
+		}
+		ret.Lines = append(ret.Lines, &converted)
+	}
+	return &ret
+}
+
+func (q *Quote) addLine(lineType, code, description string, amount int) {
+	q.Lines = append(q.Lines, &Line{Type: lineType, Code: code, Description: description, Amount: amount})
+}
+
+// Stage is one step of the pricing pipeline.
+type Stage interface {
+	Apply(ctx context.Context, q *Quote) error
+}
+
+// StageFunc adapts a function to Stage.
+type StageFunc func(ctx context.Context, q *Quote) error
+
+func (f StageFunc) Apply(ctx context.Context, q *Quote) error {
+	return f(ctx, q)
+}
+
+// Pipeline prices a cart by running its stages in order.
+type Pipeline struct {
+	rules  *RuleSet
+	stages []Stage
+}
+
+func NewPipeline(rules *RuleSet, stages ...Stage) *Pipeline {
+	return &Pipeline{rules: rules, stages: stages}
+}
+
+// NewDefaultPipeline builds subtotal -> discounts -> shipping -> tax using the rules in config.
+func NewDefaultPipeline(discounters ...Discounter) *Pipeline {
+	return NewPipeline(RulesFromConfig(), SubtotalStage(), DiscountStage(discounters...), ShippingStage(), TaxStage())
+}
+
+// Quote prices the cart. An empty region falls back to the default region.
+func (p *Pipeline) Quote(ctx context.Context, cart *Cart) (*Quote, error) {
+	region, rules, err := p.rules.Lookup(cart.Region)
+	if err != nil {
+		return nil, err
+	}
+	q := &Quote{
+		Region:     region,
+		Currency:   Base().Code,
+		Rules:      rules,
+		UserID:     cart.UserID,
+		CouponCode: strings.TrimSpace(cart.CouponCode),
+		Items:      cart.Items,
+		Lines:      make([]*Line, 0),
+		Rejections: make([]*Rejection, 0),
+	}
+	for _, stage := range p.stages {
+		if err := stage.Apply(ctx, q); err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+// RegionRules holds the tax and shipping rules of one region.
+type RegionRules struct {
+	TaxRateBps            int
+	TaxShipping           bool
+	ShippingFee           int
+	FreeShippingThreshold int
+}
+
+// RuleSet maps lower-case region codes to their rules.
+type RuleSet struct {
+	DefaultRegion string
+	Regions       map[string]*RegionRules
+}
+
+func (r *RuleSet) Lookup(region string) (string, *RegionRules, error) {
+	region = strings.ToLower(strings.TrimSpace(region))
+	if region == "" {
+		region = r.DefaultRegion
+	}
+	rules, ok := r.Regions[region]
+	if !ok {
+		return "", nil, ErrUnknownRegion
+	}
+	return region, rules, nil
+}
@@ -0,0 +1,101 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type fixedDiscounter struct {
+	lines []*Line
+}
+
+func (f *fixedDiscounter) Discounts(ctx context.Context, q *Quote) ([]*Line, error) {
+	return f.lines, nil
+}
+
+func testRules() *RuleSet {
+	return &RuleSet{
+		DefaultRegion: "sg",
+		Regions: map[string]*RegionRules{
+			"sg": {TaxRateBps: 900, ShippingFee: 800, FreeShippingThreshold: 30000},
+			"my": {TaxRateBps: 1000, ShippingFee: 1500, TaxShipping: true},
+		},
+	}
+}
+
+func TestPipeline_Quote(t *testing.T) {
+	ctx := context.Background()
+	items := []*Item{
+		{ProductID: 1, UnitPrice: 1000, Quantity: 2},
+		{ProductID: 2, UnitPrice: 500, Quantity: 1},
+	}
+
+	t.Run("default region", func(t *testing.T) {
+		p := NewPipeline(testRules(), SubtotalStage(), DiscountStage(), ShippingStage(), TaxStage())
+		q, err := p.Quote(ctx, &Cart{Items: items})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if q.Region != "sg" || q.Subtotal != 2500 || q.Shipping != 800 || q.Tax != 225 || q.Total() != 3525 {
+			t.Errorf("Unexpected quote: %+v", q)
+		}
+		if len(q.Lines) != 3 || q.Lines[0].Type != LineSubtotal || q.Lines[2].Type != LineTax {
+			t.Errorf("Unexpected lines: %+v", q.Lines)
+		}
+	})
+
+	t.Run("region taxes shipping", func(t *testing.T) {
+		p := NewPipeline(testRules(), SubtotalStage(), ShippingStage(), TaxStage())
+		q, err := p.Quote(ctx, &Cart{Region: "MY", Items: items})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		// (2500 + 1500) * 10%
+		if q.Tax != 400 || q.Total() != 4400 {
+			t.Errorf("Unexpected quote: %+v", q)
+		}
+	})
+
+	t.Run("discounts are capped and decide free shipping", func(t *testing.T) {
+		discounter := &fixedDiscounter{lines: []*Line{
+			{Code: "TENOFF", Description: "10 off", Amount: 1000},
+			{Code: "HUGE", Description: "too much", Amount: 5000},
+		}}
+		p := NewPipeline(testRules(), SubtotalStage(), DiscountStage(discounter), ShippingStage(), TaxStage())
+		q, err := p.Quote(ctx, &Cart{Region: "sg", Items: []*Item{{ProductID: 1, UnitPrice: 31000, Quantity: 1}}})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if q.Discount != 6000 || q.Shipping != 800 || q.Tax != 2250 {
+			t.Errorf("Unexpected quote: %+v", q)
+		}
+		if q.Lines[1].Amount != -1000 || q.Lines[2].Amount != -5000 {
+			t.Errorf("Expected negative discount lines, got %+v, %+v", q.Lines[1], q.Lines[2])
+		}
+	})
+
+	t.Run("free shipping line waives the fee", func(t *testing.T) {
+		discounter := &fixedDiscounter{lines: []*Line{
+			{Type: LineFreeShipping, Code: "SHIPFREE", Description: "Free shipping coupon"},
+		}}
+		p := NewPipeline(testRules(), SubtotalStage(), DiscountStage(discounter), ShippingStage(), TaxStage())
+		q, err := p.Quote(ctx, &Cart{Items: items})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if q.Discount != 0 || q.Shipping != 0 || q.Total() != 2725 {
+			t.Errorf("Unexpected quote: %+v", q)
+		}
+		if q.Lines[1].Type != LineShipping || q.Lines[1].Code != "SHIPFREE" {
+			t.Errorf("Expected the coupon on the shipping line, got %+v", q.Lines[1])
+		}
+	})
+
+	t.Run("unknown region", func(t *testing.T) {
+		p := NewPipeline(testRules(), SubtotalStage())
+		if _, err := p.Quote(ctx, &Cart{Region: "us", Items: items}); !errors.Is(err, ErrUnknownRegion) {
+			t.Errorf("Expected ErrUnknownRegion, got %v", err)
+		}
+	})
+}
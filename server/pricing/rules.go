@@ -0,0 +1,43 @@
+package pricing
+
+import (
+	"strings"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
+)
+
+const defaultRegion = "default"
+
+// defaultRules keeps the historical 9% tax and $8 shipping, free over $300, when pricing is not configured.
+var defaultRules = &RegionRules{
+	TaxRateBps:            900,
+	ShippingFee:           800,
+	FreeShippingThreshold: 30000,
+}
+
+// RulesFromConfig builds the rule set from the pricing section of the config.
+func RulesFromConfig() *RuleSet {
+	conf := config.Config.PricingConfig
+	if conf == nil || len(conf.Regions) == 0 {
+		return &RuleSet{
+			DefaultRegion: defaultRegion,
+			Regions:       map[string]*RegionRules{defaultRegion: defaultRules},
+		}
+	}
+	rules := &RuleSet{
+		DefaultRegion: strings.ToLower(conf.DefaultRegion),
+		Regions:       make(map[string]*RegionRules, len(conf.Regions)),
+	}
+	for region, r := range conf.Regions {
+		if r == nil {
+			continue
+		}
+		rules.Regions[strings.ToLower(region)] = &RegionRules{
+			TaxRateBps:            r.TaxRateBps,
+			TaxShipping:           r.TaxShipping,
+			ShippingFee:           r.ShippingFee,
+			FreeShippingThreshold: r.FreeShippingThreshold,
+		}
+	}
+	return rules
+}
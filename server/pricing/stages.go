@@ -0,0 +1,88 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+)
+
+// SubtotalStage sums unit price times quantity over all items.
+func SubtotalStage() Stage {
+	return StageFunc(func(ctx context.Context, q *Quote) error {
+		q.Subtotal = 0
+		for _, item := range q.Items {
+			q.Subtotal += item.UnitPrice * item.Quantity
+		}
+		q.addLine(LineSubtotal, "", "Items", q.Subtotal)
+		return nil
+	})
+}
+
+// Discounter contributes discounts to a quote, e.g. coupons or promotions.
+// Returned lines carry positive amounts; the discount stage records them as negative.
+// A line of type LineFreeShipping waives shipping instead of reducing the subtotal.
+type Discounter interface {
+	Discounts(ctx context.Context, q *Quote) ([]*Line, error)
+}
+
+// DiscountStage applies every discounter in order. The total discount never exceeds the subtotal.
+func DiscountStage(discounters ...Discounter) Stage {
+	return StageFunc(func(ctx context.Context, q *Quote) error {
+		for _, d := range discounters {
+			lines, err := d.Discounts(ctx, q)
+			if err != nil {
+				return err
+			}
+			for _, line := range lines {
+				if line.Type == LineFreeShipping {
+					if q.FreeShipping == nil {
+						q.FreeShipping = line
+					}
+					continue
+				}
+				amount := line.Amount
+				if remaining := q.Subtotal - q.Discount; amount > remaining {
+					amount = remaining
+				}
+				if amount <= 0 {
+					continue
+				}
+				q.Discount += amount
+				q.addLine(LineDiscount, line.Code, line.Description, -amount)
+			}
+		}
+		return nil
+	})
+}
+
+// ShippingStage charges the region's flat fee unless the discounted amount exceeds the free shipping threshold
+// or a discounter waived it.
+func ShippingStage() Stage {
+	return StageFunc(func(ctx context.Context, q *Quote) error {
+		q.Shipping = q.Rules.ShippingFee
+		code, description := q.Region, "Shipping"
+		switch {
+		case q.Rules.FreeShippingThreshold > 0 && q.Taxable() > q.Rules.FreeShippingThreshold:
+			q.Shipping = 0
+			description = "Free shipping"
+		case q.FreeShipping != nil && q.Shipping > 0:
+			q.Shipping = 0
+			code, description = q.FreeShipping.Code, q.FreeShipping.Description
+		}
+		q.addLine(LineShipping, code, description, q.Shipping)
+		return nil
+	})
+}
+
+// TaxStage applies the region's tax rate, rounding down to the cent.
+func TaxStage() Stage {
+	return StageFunc(func(ctx context.Context, q *Quote) error {
+		base := q.Taxable()
+		if q.Rules.TaxShipping {
+			base += q.Shipping
+		}
+		q.Tax = base * q.Rules.TaxRateBps / 10000
+		rate := fmt.Sprintf("%d.%02d%%", q.Rules.TaxRateBps/100, q.Rules.TaxRateBps%100)
+		q.addLine(LineTax, q.Region, "Tax "+rate, q.Tax)
+		return nil
+	})
+}
@@ -0,0 +1,79 @@
+package proxy
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/segmentio/kafka-go"
+)
+
+var (
+	EventProducerInst EventProducer
+	kafkaInitSyncOnce sync.Once
+)
+
+// EventProducer 向 Kafka 发送业务事件
+type EventProducer interface {
+	Publish(ctx context.Context, topic string, key string, value []byte) error
+	// PublishSync 同步发送，所有副本确认写入后才返回，用于发送失败后需要保留状态的事件
+	PublishSync(ctx context.Context, topic string, key string, value []byte) error
+}
+
+type KafkaProducerImpl struct {
+	writer     *kafka.Writer
+	syncWriter *kafka.Writer
+}
+
+// GetEventProducer 返回 Kafka 生产者，Publish 异步写入，发送失败只记录日志，不影响调用方
+func GetEventProducer() EventProducer {
+	kafkaInitSyncOnce.Do(func() {
+		EventProducerInst = &KafkaProducerImpl{
+			syncWriter: &kafka.Writer{
+				Addr:                   kafka.TCP(config.Config.KafkaConfig.Brokers...),
+				Balancer:               &kafka.Hash{},
+				RequiredAcks:           kafka.RequireAll,
+				AllowAutoTopicCreation: true,
+			},
+			writer: &kafka.Writer{
+				Addr:                   kafka.TCP(config.Config.KafkaConfig.Brokers...),
+				Balancer:               &kafka.Hash{},
+				Async:                  true,
+				AllowAutoTopicCreation: true,
+				Completion: func(messages []kafka.Message, err error) {
+					if err != nil {
+						log.Logger.Errorf("Failed to publish %d messages to kafka: %v", len(messages), err)
+					}
+				},
+			},
+		}
+	})
+	return EventProducerInst
+}
+
+// Publish 以 key 作为分区键发送消息，同一 key 的消息保持顺序
+func (k *KafkaProducerImpl) Publish(ctx context.Context, topic string, key string, value []byte) error {
+	err := k.writer.WriteMessages(ctx, kafka.Message{
+		Topic: topic,
+		Key:   []byte(key),
+		Value: value,
+	})
+	if err != nil {
+		log.Logger.Errorf("Failed to publish message to topic %s: %v", topic, err)
+	}
+	return err
+}
+
+// PublishSync 与 Publish 相同，但等待 Kafka 确认，返回的错误表示消息没有送达
+func (k *KafkaProducerImpl) PublishSync(ctx context.Context, topic string, key string, value []byte) error {
+	err := k.syncWriter.WriteMessages(ctx, kafka.Message{
+		Topic: topic,
+		Key:   []byte(key),
+		Value: value,
+	})
+	if err != nil {
+		log.Logger.Errorf("Failed to publish message to topic %s: %v", topic, err)
+	}
+	return err
+}
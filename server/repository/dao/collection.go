@@ -0,0 +1,149 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"gorm.io/gorm"
+)
+
+type CollectionDao interface {
+	CreateCollection(ctx context.Context, collection *model.Collection) (collectionId int, err error)
+	UpdateCollection(ctx context.Context, collection *model.Collection) error
+	DeleteCollection(ctx context.Context, id int) error
+	GetCollectionByID(ctx context.Context, id int) (*model.Collection, error)
+	ListCollections(ctx context.Context, onlyVisible bool) ([]*model.Collection, error)
+	ReplaceItems(ctx context.Context, collectionId int, productIds []int) error
+	GetItems(ctx context.Context, collectionId int) ([]*model.CollectionItem, error)
+}
+
+var (
+	collectionDaoInstance CollectionDao
+	collectionDaoSyncOnce sync.Once
+)
+
+func GetCollectionDao() CollectionDao {
+	collectionDaoSyncOnce.Do(func() {
+		collectionDaoInstance = &CollectionDaoImpl{
+			db: repository.DB,
+		}
+	})
+	return collectionDaoInstance
+}
+
+type CollectionDaoImpl struct {
+	db *gorm.DB
+}
+
+// CreateCollection 创建专题并返回ID
+func (c *CollectionDaoImpl) CreateCollection(ctx context.Context, collection *model.Collection) (int, error) {
+	ret := c.db.WithContext(ctx).Create(collection)
+	if ret.Error != nil {
+		log.Logger.Errorf("CollectionDao: CreateCollection: Failed to create collection: %v", ret.Error)
+		return 0, ret.Error
+	}
+	return int(collection.ID), nil
+}
+
+// UpdateCollection 更新专题信息，status 等零值字段同样会被写入
+func (c *CollectionDaoImpl) UpdateCollection(ctx context.Context, collection *model.Collection) error {
+	ret := c.db.WithContext(ctx).Model(&model.Collection{}).Where("id = ?", collection.ID).
+		Select("name", "desc", "pic_info", "status").Updates(collection)
+	if ret.Error != nil {
+		log.Logger.Errorf("CollectionDao: UpdateCollection: Failed to update collection %d: %v", collection.ID, ret.Error)
+		return ret.Error
+	}
+	if ret.RowsAffected == 0 {
+		err := fmt.Errorf("collection not found with ID: %d", collection.ID)
+		log.Logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+// DeleteCollection 删除专题及其商品关联
+func (c *CollectionDaoImpl) DeleteCollection(ctx context.Context, id int) error {
+	defer InvalidateProductListCache(ctx)
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", id).Delete(&model.CollectionItem{}).Error; err != nil {
+			log.Logger.Errorf("CollectionDao: DeleteCollection: Failed to delete items of collection %d: %v", id, err)
+			return err
+		}
+		if err := tx.Delete(&model.Collection{}, id).Error; err != nil {
+			log.Logger.Errorf("CollectionDao: DeleteCollection: Failed to delete collection %d: %v", id, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// GetCollectionByID 根据ID获取专题，不存在时返回 nil
+func (c *CollectionDaoImpl) GetCollectionByID(ctx context.Context, id int) (*model.Collection, error) {
+	var collection model.Collection
+	ret := c.db.WithContext(ctx).Where("id = ?", id).First(&collection)
+	if ret.Error != nil {
+		if errors.Is(ret.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Errorf("CollectionDao: GetCollectionByID: Failed to get collection %d: %v", id, ret.Error)
+		return nil, ret.Error
+	}
+	return &collection, nil
+}
+
+// ListCollections 查询专题列表，用户侧只返回可见专题
+func (c *CollectionDaoImpl) ListCollections(ctx context.Context, onlyVisible bool) ([]*model.Collection, error) {
+	var collections []*model.Collection
+	query := c.db.WithContext(ctx).Model(&model.Collection{})
+	if onlyVisible {
+		query = query.Where("status = ?", model.CollectionStatusVisible)
+	}
+	if err := query.Order("updated_at DESC").Find(&collections).Error; err != nil {
+		log.Logger.Errorf("CollectionDao: ListCollections: Failed to list collections: %v", err)
+		return nil, err
+	}
+	return collections, nil
+}
+
+// ReplaceItems 按给定顺序覆盖专题内的商品
+func (c *CollectionDaoImpl) ReplaceItems(ctx context.Context, collectionId int, productIds []int) error {
+	defer InvalidateProductListCache(ctx)
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", collectionId).Delete(&model.CollectionItem{}).Error; err != nil {
+			log.Logger.Errorf("CollectionDao: ReplaceItems: Failed to delete items of collection %d: %v", collectionId, err)
+			return err
+		}
+		if len(productIds) == 0 {
+			return nil
+		}
+		items := make([]*model.CollectionItem, 0, len(productIds))
+		for position, productId := range productIds {
+			items = append(items, &model.CollectionItem{
+				CollectionID: collectionId,
+				ProductID:    productId,
+				Position:     position,
+			})
+		}
+		if err := tx.Create(&items).Error; err != nil {
+			log.Logger.Errorf("CollectionDao: ReplaceItems: Failed to create items of collection %d: %v", collectionId, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// GetItems 按顺序返回专题内的商品关联
+func (c *CollectionDaoImpl) GetItems(ctx context.Context, collectionId int) ([]*model.CollectionItem, error) {
+	var items []*model.CollectionItem
+	ret := c.db.WithContext(ctx).Where("collection_id = ?", collectionId).Order("position").Find(&items)
+	if ret.Error != nil {
+		log.Logger.Errorf("CollectionDao: GetItems: Failed to get items of collection %d: %v", collectionId, ret.Error)
+		return nil, ret.Error
+	}
+	return items, nil
+}
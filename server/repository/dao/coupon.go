@@ -0,0 +1,257 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrCouponExhausted is returned when a redemption would exceed the coupon's usage limit.
+	ErrCouponExhausted = errors.New("coupon usage limit reached")
+	// ErrCouponUserLimitReached is returned when a redemption would exceed the coupon's per-user limit.
+	ErrCouponUserLimitReached = errors.New("coupon per-user limit reached")
+)
+
+type CouponDao interface {
+	CreateCoupon(ctx context.Context, coupon *model.Coupon) (couponId int, err error)
+	UpdateCouponActive(ctx context.Context, id int, active bool) error
+	GetCouponByID(ctx context.Context, id int) (*model.Coupon, error)
+	GetCouponByCode(ctx context.Context, code string) (*model.Coupon, error)
+	ListCoupons(ctx context.Context, offset, limit int) ([]*model.Coupon, int64, error)
+	CountUserRedemptions(ctx context.Context, couponId int, userId int) (int64, error)
+	GetRedemptionByOrder(ctx context.Context, orderId string) (*model.CouponRedemption, error)
+	Redeem(ctx context.Context, coupon *model.Coupon, userId int, orderId string) error
+	ReleaseRedemption(ctx context.Context, orderId string) error
+
+	GetCartCoupon(ctx context.Context, userId int) (code string, err error)
+	SetCartCoupon(ctx context.Context, userId int, code string) error
+	DeleteCartCoupon(ctx context.Context, userId int) error
+}
+
+var (
+	couponDaoInstance CouponDao
+	couponDaoSyncOnce sync.Once
+)
+
+func GetCouponDao() CouponDao {
+	couponDaoSyncOnce.Do(func() {
+		couponDaoInstance = &CouponDaoImpl{
+			db: repository.DB,
+		}
+	})
+	return couponDaoInstance
+}
+
+type CouponDaoImpl struct {
+	db *gorm.DB
+}
+
+// CreateCoupon implements CouponDao.
+func (c *CouponDaoImpl) CreateCoupon(ctx context.Context, coupon *model.Coupon) (int, error) {
+	ret := c.db.WithContext(ctx).Create(coupon)
+	if ret.Error != nil {
+		log.Logger.Errorf("CouponDao: CreateCoupon: Failed to create coupon %s: %v", coupon.Code, ret.Error)
+		return 0, ret.Error
+	}
+	return coupon.ID, nil
+}
+
+// UpdateCouponActive enables or disables a coupon.
+func (c *CouponDaoImpl) UpdateCouponActive(ctx context.Context, id int, active bool) error {
+	ret := c.db.WithContext(ctx).Model(&model.Coupon{}).Where("id = ?", id).Update("active", active)
+	if ret.Error != nil {
+		log.Logger.Errorf("CouponDao: UpdateCouponActive: Failed to update coupon %d: %v", id, ret.Error)
+		return ret.Error
+	}
+	return nil
+}
+
+// GetCouponByID returns nil when the coupon does not exist.
+func (c *CouponDaoImpl) GetCouponByID(ctx context.Context, id int) (*model.Coupon, error) {
+	return c.getCoupon(ctx, "id = ?", id)
+}
+
+// GetCouponByCode returns nil when no coupon has the code.
+func (c *CouponDaoImpl) GetCouponByCode(ctx context.Context, code string) (*model.Coupon, error) {
+	return c.getCoupon(ctx, "code = ?", code)
+}
+
+func (c *CouponDaoImpl) getCoupon(ctx context.Context, query string, arg interface{}) (*model.Coupon, error) {
+	var coupon model.Coupon
+	err := c.db.WithContext(ctx).Where(query, arg).First(&coupon).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Errorf("CouponDao: getCoupon: Failed to get coupon by %s %v: %v", query, arg, err)
+		return nil, err
+	}
+	return &coupon, nil
+}
+
+// ListCoupons returns coupons newest first together with the total count.
+func (c *CouponDaoImpl) ListCoupons(ctx context.Context, offset, limit int) ([]*model.Coupon, int64, error) {
+	var total int64
+	if err := c.db.WithContext(ctx).Model(&model.Coupon{}).Count(&total).Error; err != nil {
+		log.Logger.Errorf("CouponDao: ListCoupons: Failed to count coupons: %v", err)
+		return nil, 0, err
+	}
+	coupons := make([]*model.Coupon, 0)
+	err := c.db.WithContext(ctx).Order("id desc").Offset(offset).Limit(limit).Find(&coupons).Error
+	if err != nil {
+		log.Logger.Errorf("CouponDao: ListCoupons: Failed to list coupons: %v", err)
+		return nil, 0, err
+	}
+	return coupons, total, nil
+}
+
+// CountUserRedemptions reads the per-user counter maintained by Redeem.
+func (c *CouponDaoImpl) CountUserRedemptions(ctx context.Context, couponId int, userId int) (int64, error) {
+	var usage model.CouponUserUsage
+	err := c.db.WithContext(ctx).Where("coupon_id = ? AND user_id = ?", couponId, userId).First(&usage).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		log.Logger.Errorf("CouponDao: CountUserRedemptions: coupon %d, user %d: %v", couponId, userId, err)
+		return 0, err
+	}
+	return int64(usage.UsedCount), nil
+}
+
+// GetRedemptionByOrder returns nil when the order has not redeemed a coupon.
+func (c *CouponDaoImpl) GetRedemptionByOrder(ctx context.Context, orderId string) (*model.CouponRedemption, error) {
+	var redemption model.CouponRedemption
+	err := c.db.WithContext(ctx).Where("order_id = ?", orderId).First(&redemption).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Errorf("CouponDao: GetRedemptionByOrder: order %s: %v", orderId, err)
+		return nil, err
+	}
+	return &redemption, nil
+}
+
+// Redeem records the redemption of coupon by the order and bumps the global and per-user counters
+// in one transaction. Redeeming an order that already has a redemption is a no-op. It returns
+// ErrCouponExhausted or ErrCouponUserLimitReached when a limit has been reached.
+func (c *CouponDaoImpl) Redeem(ctx context.Context, coupon *model.Coupon, userId int, orderId string) error {
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.CouponRedemption{CouponID: coupon.ID, UserID: userId, OrderID: orderId})
+		if ret.Error != nil {
+			log.Logger.Errorf("CouponDao: Redeem: Failed to record redemption of coupon %d by order %s: %v", coupon.ID, orderId, ret.Error)
+			return ret.Error
+		}
+		if ret.RowsAffected == 0 {
+			log.Logger.Infof("CouponDao: Redeem: order %s has already redeemed a coupon", orderId)
+			return nil
+		}
+
+		ret = tx.Model(&model.Coupon{}).
+			Where("id = ? AND (usage_limit = 0 OR used_count < usage_limit)", coupon.ID).
+			Update("used_count", gorm.Expr("used_count + 1"))
+		if ret.Error != nil {
+			log.Logger.Errorf("CouponDao: Redeem: Failed to update coupon %d: %v", coupon.ID, ret.Error)
+			return ret.Error
+		}
+		if ret.RowsAffected == 0 {
+			return ErrCouponExhausted
+		}
+
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&model.CouponUserUsage{CouponID: coupon.ID, UserID: userId}).Error
+		if err != nil {
+			log.Logger.Errorf("CouponDao: Redeem: Failed to create usage of coupon %d by user %d: %v", coupon.ID, userId, err)
+			return err
+		}
+		ret = tx.Model(&model.CouponUserUsage{}).
+			Where("coupon_id = ? AND user_id = ? AND (? = 0 OR used_count < ?)", coupon.ID, userId, coupon.PerUserLimit, coupon.PerUserLimit).
+			Update("used_count", gorm.Expr("used_count + 1"))
+		if ret.Error != nil {
+			log.Logger.Errorf("CouponDao: Redeem: Failed to update usage of coupon %d by user %d: %v", coupon.ID, userId, ret.Error)
+			return ret.Error
+		}
+		if ret.RowsAffected == 0 {
+			return ErrCouponUserLimitReached
+		}
+		return nil
+	})
+}
+
+// ReleaseRedemption gives back the coupon redeemed by a cancelled order. It is a no-op when the
+// order has no redemption, so releasing twice is safe.
+func (c *CouponDaoImpl) ReleaseRedemption(ctx context.Context, orderId string) error {
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var redemption model.CouponRedemption
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("order_id = ?", orderId).First(&redemption).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			log.Logger.Errorf("CouponDao: ReleaseRedemption: Failed to get redemption of order %s: %v", orderId, err)
+			return err
+		}
+		if err := tx.Delete(&redemption).Error; err != nil {
+			log.Logger.Errorf("CouponDao: ReleaseRedemption: Failed to delete redemption of order %s: %v", orderId, err)
+			return err
+		}
+		err = tx.Model(&model.Coupon{}).Where("id = ? AND used_count > 0", redemption.CouponID).
+			Update("used_count", gorm.Expr("used_count - 1")).Error
+		if err != nil {
+			log.Logger.Errorf("CouponDao: ReleaseRedemption: Failed to update coupon %d: %v", redemption.CouponID, err)
+			return err
+		}
+		err = tx.Model(&model.CouponUserUsage{}).
+			Where("coupon_id = ? AND user_id = ? AND used_count > 0", redemption.CouponID, redemption.UserID).
+			Update("used_count", gorm.Expr("used_count - 1")).Error
+		if err != nil {
+			log.Logger.Errorf("CouponDao: ReleaseRedemption: Failed to update usage of coupon %d by user %d: %v", redemption.CouponID, redemption.UserID, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// GetCartCoupon returns the code applied to the user's cart, or an empty string.
+func (c *CouponDaoImpl) GetCartCoupon(ctx context.Context, userId int) (string, error) {
+	var cartCoupon model.CartCoupon
+	err := c.db.WithContext(ctx).Where("user_id = ?", userId).First(&cartCoupon).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", nil
+		}
+		log.Logger.Errorf("CouponDao: GetCartCoupon: user %d: %v", userId, err)
+		return "", err
+	}
+	return cartCoupon.Code, nil
+}
+
+// SetCartCoupon replaces the code applied to the user's cart.
+func (c *CouponDaoImpl) SetCartCoupon(ctx context.Context, userId int, code string) error {
+	err := c.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"code", "updated_at"}),
+	}).Create(&model.CartCoupon{UserID: userId, Code: code}).Error
+	if err != nil {
+		log.Logger.Errorf("CouponDao: SetCartCoupon: user %d: %v", userId, err)
+	}
+	return err
+}
+
+// DeleteCartCoupon removes the code from the user's cart; it is a no-op when none is applied.
+func (c *CouponDaoImpl) DeleteCartCoupon(ctx context.Context, userId int) error {
+	err := c.db.WithContext(ctx).Where("user_id = ?", userId).Delete(&model.CartCoupon{}).Error
+	if err != nil {
+		log.Logger.Errorf("CouponDao: DeleteCartCoupon: user %d: %v", userId, err)
+	}
+	return err
+}
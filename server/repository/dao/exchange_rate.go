@@ -0,0 +1,75 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRateDao interface {
+	GetExchangeRate(ctx context.Context, currency string) (*model.ExchangeRate, error)
+	ListExchangeRates(ctx context.Context) ([]*model.ExchangeRate, error)
+	SaveExchangeRate(ctx context.Context, rate *model.ExchangeRate) error
+}
+
+var (
+	exchangeRateDaoInstance ExchangeRateDao
+	exchangeRateDaoSyncOnce sync.Once
+)
+
+func GetExchangeRateDao() ExchangeRateDao {
+	exchangeRateDaoSyncOnce.Do(func() {
+		exchangeRateDaoInstance = &ExchangeRateDaoImpl{
+			db: repository.DB,
+		}
+	})
+	return exchangeRateDaoInstance
+}
+
+type ExchangeRateDaoImpl struct {
+	db *gorm.DB
+}
+
+// GetExchangeRate returns nil when the currency has no override.
+func (e *ExchangeRateDaoImpl) GetExchangeRate(ctx context.Context, currency string) (*model.ExchangeRate, error) {
+	var rate model.ExchangeRate
+	ret := e.db.WithContext(ctx).Where("currency = ?", currency).Take(&rate)
+	if errors.Is(ret.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if ret.Error != nil {
+		log.Logger.Errorf("ExchangeRateDao: GetExchangeRate: Failed to get rate of %s: %v", currency, ret.Error)
+		return nil, ret.Error
+	}
+	return &rate, nil
+}
+
+// ListExchangeRates implements ExchangeRateDao.
+func (e *ExchangeRateDaoImpl) ListExchangeRates(ctx context.Context) ([]*model.ExchangeRate, error) {
+	var rates []*model.ExchangeRate
+	ret := e.db.WithContext(ctx).Order("currency").Find(&rates)
+	if ret.Error != nil {
+		log.Logger.Errorf("ExchangeRateDao: ListExchangeRates: Failed to list rates: %v", ret.Error)
+		return nil, ret.Error
+	}
+	return rates, nil
+}
+
+// SaveExchangeRate creates or replaces the override of rate.Currency.
+func (e *ExchangeRateDaoImpl) SaveExchangeRate(ctx context.Context, rate *model.ExchangeRate) error {
+	ret := e.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "decimals", "operator_id", "updated_at"}),
+	}).Create(rate)
+	if ret.Error != nil {
+		log.Logger.Errorf("ExchangeRateDao: SaveExchangeRate: Failed to save rate of %s: %v", rate.Currency, ret.Error)
+		return ret.Error
+	}
+	return nil
+}
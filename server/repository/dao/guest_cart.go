@@ -0,0 +1,133 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"gorm.io/gorm"
+)
+
+// ErrGuestCartGone is returned when the guest cart was merged or removed by another request.
+var ErrGuestCartGone = errors.New("guest cart was merged or removed")
+
+type GuestCartDao interface {
+	CreateGuestCart(ctx context.Context, cart *model.GuestCart) error
+	GetGuestCartByTokenHash(ctx context.Context, tokenHash string) (*model.GuestCart, error)
+	MergeGuestCart(ctx context.Context, cart *model.GuestCart, items []*model.ShoppingCartItem) error
+	DeleteExpiredGuestCarts(ctx context.Context, before time.Time, limit int) (deleted int, err error)
+}
+
+var (
+	guestCartDaoInstance GuestCartDao
+	guestCartDaoSyncOnce sync.Once
+)
+
+func GetGuestCartDao() GuestCartDao {
+	guestCartDaoSyncOnce.Do(func() {
+		guestCartDaoInstance = &GuestCartDaoImpl{
+			db: repository.DB,
+		}
+	})
+	return guestCartDaoInstance
+}
+
+type GuestCartDaoImpl struct {
+	db *gorm.DB
+}
+
+// CreateGuestCart implements GuestCartDao.
+func (g *GuestCartDaoImpl) CreateGuestCart(ctx context.Context, cart *model.GuestCart) error {
+	ret := g.db.WithContext(ctx).Create(cart)
+	if ret.Error != nil {
+		log.Logger.Errorf("GuestCartDao: CreateGuestCart: Failed to create guest cart: %v", ret.Error)
+		return ret.Error
+	}
+	return nil
+}
+
+// GetGuestCartByTokenHash returns nil when no cart has the token.
+func (g *GuestCartDaoImpl) GetGuestCartByTokenHash(ctx context.Context, tokenHash string) (*model.GuestCart, error) {
+	var cart model.GuestCart
+	ret := g.db.WithContext(ctx).Where("token_hash = ?", tokenHash).Take(&cart)
+	if errors.Is(ret.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if ret.Error != nil {
+		log.Logger.Errorf("GuestCartDao: GetGuestCartByTokenHash: Failed to get guest cart: %v", ret.Error)
+		return nil, ret.Error
+	}
+	return &cart, nil
+}
+
+// MergeGuestCart saves the user's merged cart items and removes the guest cart with its items in
+// one transaction, so a retried merge can never add the guest quantities twice. The guest cart is
+// deleted first: when another merge got there first it returns ErrGuestCartGone and nothing is saved.
+// Items with an ID are updated only while they still belong to the user, otherwise ErrCartItemChanged
+// rolls the merge back.
+func (g *GuestCartDaoImpl) MergeGuestCart(ctx context.Context, cart *model.GuestCart, items []*model.ShoppingCartItem) error {
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Delete(&model.GuestCart{}, cart.ID)
+		if ret.Error != nil {
+			return ret.Error
+		}
+		if ret.RowsAffected == 0 {
+			return ErrGuestCartGone
+		}
+		for _, item := range items {
+			if item.ID == 0 {
+				if err := tx.Create(item).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := updateItemColumns(tx, item); err != nil {
+				return err
+			}
+		}
+		return tx.Where("user_id = ?", cart.OwnerID()).Delete(&model.ShoppingCartItem{}).Error
+	})
+	if err != nil {
+		log.Logger.Errorf("GuestCartDao: MergeGuestCart: Failed to merge guest cart %d: %v", cart.ID, err)
+		return err
+	}
+	log.Logger.Infof("GuestCartDao: MergeGuestCart: Merged guest cart %d, saved %d items", cart.ID, len(items))
+	return nil
+}
+
+// DeleteExpiredGuestCarts removes up to limit guest carts that expired before the given time,
+// together with their items.
+func (g *GuestCartDaoImpl) DeleteExpiredGuestCarts(ctx context.Context, before time.Time, limit int) (int, error) {
+	var ids []int
+	err := g.db.WithContext(ctx).Model(&model.GuestCart{}).Where("expires_at < ?", before).
+		Order("expires_at").Limit(limit).Pluck("id", &ids).Error
+	if err != nil {
+		log.Logger.Errorf("GuestCartDao: DeleteExpiredGuestCarts: Failed to list expired guest carts: %v", err)
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	ownerIds := make([]int, 0, len(ids))
+	for _, id := range ids {
+		ownerIds = append(ownerIds, (&model.GuestCart{ID: id}).OwnerID())
+	}
+	var deleted int64
+	err = g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id IN ?", ownerIds).Delete(&model.ShoppingCartItem{}).Error; err != nil {
+			return err
+		}
+		ret := tx.Where("id IN ? AND expires_at < ?", ids, before).Delete(&model.GuestCart{})
+		deleted = ret.RowsAffected
+		return ret.Error
+	})
+	if err != nil {
+		log.Logger.Errorf("GuestCartDao: DeleteExpiredGuestCarts: Failed to delete expired guest carts: %v", err)
+		return 0, err
+	}
+	return int(deleted), nil
+}
@@ -0,0 +1,138 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/collection.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCollectionDao is a mock of CollectionDao interface.
+type MockCollectionDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockCollectionDaoMockRecorder
+}
+
+// MockCollectionDaoMockRecorder is the mock recorder for MockCollectionDao.
+type MockCollectionDaoMockRecorder struct {
+	mock *MockCollectionDao
+}
+
+// NewMockCollectionDao creates a new mock instance.
+func NewMockCollectionDao(ctrl *gomock.Controller) *MockCollectionDao {
+	mock := &MockCollectionDao{ctrl: ctrl}
+	mock.recorder = &MockCollectionDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCollectionDao) EXPECT() *MockCollectionDaoMockRecorder {
+	return m.recorder
+}
+
+// CreateCollection mocks base method.
+func (m *MockCollectionDao) CreateCollection(ctx context.Context, collection *model.Collection) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCollection", ctx, collection)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCollection indicates an expected call of CreateCollection.
+func (mr *MockCollectionDaoMockRecorder) CreateCollection(ctx, collection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCollection", reflect.TypeOf((*MockCollectionDao)(nil).CreateCollection), ctx, collection)
+}
+
+// DeleteCollection mocks base method.
+func (m *MockCollectionDao) DeleteCollection(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollection", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCollection indicates an expected call of DeleteCollection.
+func (mr *MockCollectionDaoMockRecorder) DeleteCollection(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollection", reflect.TypeOf((*MockCollectionDao)(nil).DeleteCollection), ctx, id)
+}
+
+// GetCollectionByID mocks base method.
+func (m *MockCollectionDao) GetCollectionByID(ctx context.Context, id int) (*model.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCollectionByID", ctx, id)
+	ret0, _ := ret[0].(*model.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCollectionByID indicates an expected call of GetCollectionByID.
+func (mr *MockCollectionDaoMockRecorder) GetCollectionByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCollectionByID", reflect.TypeOf((*MockCollectionDao)(nil).GetCollectionByID), ctx, id)
+}
+
+// GetItems mocks base method.
+func (m *MockCollectionDao) GetItems(ctx context.Context, collectionId int) ([]*model.CollectionItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItems", ctx, collectionId)
+	ret0, _ := ret[0].([]*model.CollectionItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItems indicates an expected call of GetItems.
+func (mr *MockCollectionDaoMockRecorder) GetItems(ctx, collectionId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItems", reflect.TypeOf((*MockCollectionDao)(nil).GetItems), ctx, collectionId)
+}
+
+// ListCollections mocks base method.
+func (m *MockCollectionDao) ListCollections(ctx context.Context, onlyVisible bool) ([]*model.Collection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCollections", ctx, onlyVisible)
+	ret0, _ := ret[0].([]*model.Collection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCollections indicates an expected call of ListCollections.
+func (mr *MockCollectionDaoMockRecorder) ListCollections(ctx, onlyVisible interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCollections", reflect.TypeOf((*MockCollectionDao)(nil).ListCollections), ctx, onlyVisible)
+}
+
+// ReplaceItems mocks base method.
+func (m *MockCollectionDao) ReplaceItems(ctx context.Context, collectionId int, productIds []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceItems", ctx, collectionId, productIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceItems indicates an expected call of ReplaceItems.
+func (mr *MockCollectionDaoMockRecorder) ReplaceItems(ctx, collectionId, productIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceItems", reflect.TypeOf((*MockCollectionDao)(nil).ReplaceItems), ctx, collectionId, productIds)
+}
+
+// UpdateCollection mocks base method.
+func (m *MockCollectionDao) UpdateCollection(ctx context.Context, collection *model.Collection) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCollection", ctx, collection)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCollection indicates an expected call of UpdateCollection.
+func (mr *MockCollectionDaoMockRecorder) UpdateCollection(ctx, collection interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCollection", reflect.TypeOf((*MockCollectionDao)(nil).UpdateCollection), ctx, collection)
+}
@@ -0,0 +1,212 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/coupon.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockCouponDao is a mock of CouponDao interface.
+type MockCouponDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockCouponDaoMockRecorder
+}
+
+// MockCouponDaoMockRecorder is the mock recorder for MockCouponDao.
+type MockCouponDaoMockRecorder struct {
+	mock *MockCouponDao
+}
+
+// NewMockCouponDao creates a new mock instance.
+func NewMockCouponDao(ctrl *gomock.Controller) *MockCouponDao {
+	mock := &MockCouponDao{ctrl: ctrl}
+	mock.recorder = &MockCouponDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCouponDao) EXPECT() *MockCouponDaoMockRecorder {
+	return m.recorder
+}
+
+// CountUserRedemptions mocks base method.
+func (m *MockCouponDao) CountUserRedemptions(ctx context.Context, couponId, userId int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserRedemptions", ctx, couponId, userId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserRedemptions indicates an expected call of CountUserRedemptions.
+func (mr *MockCouponDaoMockRecorder) CountUserRedemptions(ctx, couponId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserRedemptions", reflect.TypeOf((*MockCouponDao)(nil).CountUserRedemptions), ctx, couponId, userId)
+}
+
+// CreateCoupon mocks base method.
+func (m *MockCouponDao) CreateCoupon(ctx context.Context, coupon *model.Coupon) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCoupon", ctx, coupon)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCoupon indicates an expected call of CreateCoupon.
+func (mr *MockCouponDaoMockRecorder) CreateCoupon(ctx, coupon interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCoupon", reflect.TypeOf((*MockCouponDao)(nil).CreateCoupon), ctx, coupon)
+}
+
+// DeleteCartCoupon mocks base method.
+func (m *MockCouponDao) DeleteCartCoupon(ctx context.Context, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCartCoupon", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCartCoupon indicates an expected call of DeleteCartCoupon.
+func (mr *MockCouponDaoMockRecorder) DeleteCartCoupon(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCartCoupon", reflect.TypeOf((*MockCouponDao)(nil).DeleteCartCoupon), ctx, userId)
+}
+
+// GetCartCoupon mocks base method.
+func (m *MockCouponDao) GetCartCoupon(ctx context.Context, userId int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCartCoupon", ctx, userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCartCoupon indicates an expected call of GetCartCoupon.
+func (mr *MockCouponDaoMockRecorder) GetCartCoupon(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCartCoupon", reflect.TypeOf((*MockCouponDao)(nil).GetCartCoupon), ctx, userId)
+}
+
+// GetCouponByCode mocks base method.
+func (m *MockCouponDao) GetCouponByCode(ctx context.Context, code string) (*model.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCouponByCode", ctx, code)
+	ret0, _ := ret[0].(*model.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCouponByCode indicates an expected call of GetCouponByCode.
+func (mr *MockCouponDaoMockRecorder) GetCouponByCode(ctx, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCouponByCode", reflect.TypeOf((*MockCouponDao)(nil).GetCouponByCode), ctx, code)
+}
+
+// GetCouponByID mocks base method.
+func (m *MockCouponDao) GetCouponByID(ctx context.Context, id int) (*model.Coupon, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCouponByID", ctx, id)
+	ret0, _ := ret[0].(*model.Coupon)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCouponByID indicates an expected call of GetCouponByID.
+func (mr *MockCouponDaoMockRecorder) GetCouponByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCouponByID", reflect.TypeOf((*MockCouponDao)(nil).GetCouponByID), ctx, id)
+}
+
+// GetRedemptionByOrder mocks base method.
+func (m *MockCouponDao) GetRedemptionByOrder(ctx context.Context, orderId string) (*model.CouponRedemption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRedemptionByOrder", ctx, orderId)
+	ret0, _ := ret[0].(*model.CouponRedemption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRedemptionByOrder indicates an expected call of GetRedemptionByOrder.
+func (mr *MockCouponDaoMockRecorder) GetRedemptionByOrder(ctx, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRedemptionByOrder", reflect.TypeOf((*MockCouponDao)(nil).GetRedemptionByOrder), ctx, orderId)
+}
+
+// ListCoupons mocks base method.
+func (m *MockCouponDao) ListCoupons(ctx context.Context, offset, limit int) ([]*model.Coupon, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoupons", ctx, offset, limit)
+	ret0, _ := ret[0].([]*model.Coupon)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListCoupons indicates an expected call of ListCoupons.
+func (mr *MockCouponDaoMockRecorder) ListCoupons(ctx, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoupons", reflect.TypeOf((*MockCouponDao)(nil).ListCoupons), ctx, offset, limit)
+}
+
+// Redeem mocks base method.
+func (m *MockCouponDao) Redeem(ctx context.Context, coupon *model.Coupon, userId int, orderId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Redeem", ctx, coupon, userId, orderId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Redeem indicates an expected call of Redeem.
+func (mr *MockCouponDaoMockRecorder) Redeem(ctx, coupon, userId, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Redeem", reflect.TypeOf((*MockCouponDao)(nil).Redeem), ctx, coupon, userId, orderId)
+}
+
+// ReleaseRedemption mocks base method.
+func (m *MockCouponDao) ReleaseRedemption(ctx context.Context, orderId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseRedemption", ctx, orderId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseRedemption indicates an expected call of ReleaseRedemption.
+func (mr *MockCouponDaoMockRecorder) ReleaseRedemption(ctx, orderId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseRedemption", reflect.TypeOf((*MockCouponDao)(nil).ReleaseRedemption), ctx, orderId)
+}
+
+// SetCartCoupon mocks base method.
+func (m *MockCouponDao) SetCartCoupon(ctx context.Context, userId int, code string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCartCoupon", ctx, userId, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCartCoupon indicates an expected call of SetCartCoupon.
+func (mr *MockCouponDaoMockRecorder) SetCartCoupon(ctx, userId, code interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCartCoupon", reflect.TypeOf((*MockCouponDao)(nil).SetCartCoupon), ctx, userId, code)
+}
+
+// UpdateCouponActive mocks base method.
+func (m *MockCouponDao) UpdateCouponActive(ctx context.Context, id int, active bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCouponActive", ctx, id, active)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCouponActive indicates an expected call of UpdateCouponActive.
+func (mr *MockCouponDaoMockRecorder) UpdateCouponActive(ctx, id, active interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCouponActive", reflect.TypeOf((*MockCouponDao)(nil).UpdateCouponActive), ctx, id, active)
+}
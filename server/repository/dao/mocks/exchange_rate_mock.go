@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/exchange_rate.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockExchangeRateDao is a mock of ExchangeRateDao interface.
+type MockExchangeRateDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockExchangeRateDaoMockRecorder
+}
+
+// MockExchangeRateDaoMockRecorder is the mock recorder for MockExchangeRateDao.
+type MockExchangeRateDaoMockRecorder struct {
+	mock *MockExchangeRateDao
+}
+
+// NewMockExchangeRateDao creates a new mock instance.
+func NewMockExchangeRateDao(ctrl *gomock.Controller) *MockExchangeRateDao {
+	mock := &MockExchangeRateDao{ctrl: ctrl}
+	mock.recorder = &MockExchangeRateDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExchangeRateDao) EXPECT() *MockExchangeRateDaoMockRecorder {
+	return m.recorder
+}
+
+// GetExchangeRate mocks base method.
+func (m *MockExchangeRateDao) GetExchangeRate(ctx context.Context, currency string) (*model.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExchangeRate", ctx, currency)
+	ret0, _ := ret[0].(*model.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExchangeRate indicates an expected call of GetExchangeRate.
+func (mr *MockExchangeRateDaoMockRecorder) GetExchangeRate(ctx, currency interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExchangeRate", reflect.TypeOf((*MockExchangeRateDao)(nil).GetExchangeRate), ctx, currency)
+}
+
+// ListExchangeRates mocks base method.
+func (m *MockExchangeRateDao) ListExchangeRates(ctx context.Context) ([]*model.ExchangeRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExchangeRates", ctx)
+	ret0, _ := ret[0].([]*model.ExchangeRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExchangeRates indicates an expected call of ListExchangeRates.
+func (mr *MockExchangeRateDaoMockRecorder) ListExchangeRates(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExchangeRates", reflect.TypeOf((*MockExchangeRateDao)(nil).ListExchangeRates), ctx)
+}
+
+// SaveExchangeRate mocks base method.
+func (m *MockExchangeRateDao) SaveExchangeRate(ctx context.Context, rate *model.ExchangeRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveExchangeRate", ctx, rate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveExchangeRate indicates an expected call of SaveExchangeRate.
+func (mr *MockExchangeRateDaoMockRecorder) SaveExchangeRate(ctx, rate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveExchangeRate", reflect.TypeOf((*MockExchangeRateDao)(nil).SaveExchangeRate), ctx, rate)
+}
@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/guest_cart.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockGuestCartDao is a mock of GuestCartDao interface.
+type MockGuestCartDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockGuestCartDaoMockRecorder
+}
+
+// MockGuestCartDaoMockRecorder is the mock recorder for MockGuestCartDao.
+type MockGuestCartDaoMockRecorder struct {
+	mock *MockGuestCartDao
+}
+
+// NewMockGuestCartDao creates a new mock instance.
+func NewMockGuestCartDao(ctrl *gomock.Controller) *MockGuestCartDao {
+	mock := &MockGuestCartDao{ctrl: ctrl}
+	mock.recorder = &MockGuestCartDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockGuestCartDao) EXPECT() *MockGuestCartDaoMockRecorder {
+	return m.recorder
+}
+
+// CreateGuestCart mocks base method.
+func (m *MockGuestCartDao) CreateGuestCart(ctx context.Context, cart *model.GuestCart) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateGuestCart", ctx, cart)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateGuestCart indicates an expected call of CreateGuestCart.
+func (mr *MockGuestCartDaoMockRecorder) CreateGuestCart(ctx, cart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateGuestCart", reflect.TypeOf((*MockGuestCartDao)(nil).CreateGuestCart), ctx, cart)
+}
+
+// DeleteExpiredGuestCarts mocks base method.
+func (m *MockGuestCartDao) DeleteExpiredGuestCarts(ctx context.Context, before time.Time, limit int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredGuestCarts", ctx, before, limit)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredGuestCarts indicates an expected call of DeleteExpiredGuestCarts.
+func (mr *MockGuestCartDaoMockRecorder) DeleteExpiredGuestCarts(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredGuestCarts", reflect.TypeOf((*MockGuestCartDao)(nil).DeleteExpiredGuestCarts), ctx, before, limit)
+}
+
+// GetGuestCartByTokenHash mocks base method.
+func (m *MockGuestCartDao) GetGuestCartByTokenHash(ctx context.Context, tokenHash string) (*model.GuestCart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGuestCartByTokenHash", ctx, tokenHash)
+	ret0, _ := ret[0].(*model.GuestCart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGuestCartByTokenHash indicates an expected call of GetGuestCartByTokenHash.
+func (mr *MockGuestCartDaoMockRecorder) GetGuestCartByTokenHash(ctx, tokenHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGuestCartByTokenHash", reflect.TypeOf((*MockGuestCartDao)(nil).GetGuestCartByTokenHash), ctx, tokenHash)
+}
+
+// MergeGuestCart mocks base method.
+func (m *MockGuestCartDao) MergeGuestCart(ctx context.Context, cart *model.GuestCart, items []*model.ShoppingCartItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeGuestCart", ctx, cart, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeGuestCart indicates an expected call of MergeGuestCart.
+func (mr *MockGuestCartDaoMockRecorder) MergeGuestCart(ctx, cart, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeGuestCart", reflect.TypeOf((*MockGuestCartDao)(nil).MergeGuestCart), ctx, cart, items)
+}
@@ -7,6 +7,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	dao "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
 	model "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
@@ -36,19 +37,63 @@ func (m *MockProductDao) EXPECT() *MockProductDaoMockRecorder {
 	return m.recorder
 }
 
+// AdjustStockWithCAS mocks base method.
+func (m *MockProductDao) AdjustStockWithCAS(ctx context.Context, version int, adjustment *model.StockAdjustment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustStockWithCAS", ctx, version, adjustment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdjustStockWithCAS indicates an expected call of AdjustStockWithCAS.
+func (mr *MockProductDaoMockRecorder) AdjustStockWithCAS(ctx, version, adjustment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustStockWithCAS", reflect.TypeOf((*MockProductDao)(nil).AdjustStockWithCAS), ctx, version, adjustment)
+}
+
 // CreateProduct mocks base method.
-func (m *MockProductDao) CreateProduct(ctx context.Context, product *model.Product) (int, error) {
+func (m *MockProductDao) CreateProduct(ctx context.Context, product *model.Product, entry *model.InventoryLedgerEntry) (int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateProduct", ctx, product)
+	ret := m.ctrl.Call(m, "CreateProduct", ctx, product, entry)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateProduct indicates an expected call of CreateProduct.
-func (mr *MockProductDaoMockRecorder) CreateProduct(ctx, product interface{}) *gomock.Call {
+func (mr *MockProductDaoMockRecorder) CreateProduct(ctx, product, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockProductDao)(nil).CreateProduct), ctx, product, entry)
+}
+
+// GetDeletedProductByID mocks base method.
+func (m *MockProductDao) GetDeletedProductByID(ctx context.Context, id int) (*model.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeletedProductByID", ctx, id)
+	ret0, _ := ret[0].(*model.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeletedProductByID indicates an expected call of GetDeletedProductByID.
+func (mr *MockProductDaoMockRecorder) GetDeletedProductByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeletedProductByID", reflect.TypeOf((*MockProductDao)(nil).GetDeletedProductByID), ctx, id)
+}
+
+// GetLowestPriceSince mocks base method.
+func (m *MockProductDao) GetLowestPriceSince(ctx context.Context, productID int, since time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLowestPriceSince", ctx, productID, since)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLowestPriceSince indicates an expected call of GetLowestPriceSince.
+func (mr *MockProductDaoMockRecorder) GetLowestPriceSince(ctx, productID, since interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockProductDao)(nil).CreateProduct), ctx, product)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLowestPriceSince", reflect.TypeOf((*MockProductDao)(nil).GetLowestPriceSince), ctx, productID, since)
 }
 
 // GetProductByID mocks base method.
@@ -81,6 +126,54 @@ func (mr *MockProductDaoMockRecorder) GetProductByIDs(ctx, ids interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductByIDs", reflect.TypeOf((*MockProductDao)(nil).GetProductByIDs), ctx, ids)
 }
 
+// ListLedgerEntries mocks base method.
+func (m *MockProductDao) ListLedgerEntries(ctx context.Context, productID, offset, limit int) ([]*model.InventoryLedgerEntry, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLedgerEntries", ctx, productID, offset, limit)
+	ret0, _ := ret[0].([]*model.InventoryLedgerEntry)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListLedgerEntries indicates an expected call of ListLedgerEntries.
+func (mr *MockProductDaoMockRecorder) ListLedgerEntries(ctx, productID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLedgerEntries", reflect.TypeOf((*MockProductDao)(nil).ListLedgerEntries), ctx, productID, offset, limit)
+}
+
+// ListLowStockProducts mocks base method.
+func (m *MockProductDao) ListLowStockProducts(ctx context.Context, offset, limit int) ([]*model.Product, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLowStockProducts", ctx, offset, limit)
+	ret0, _ := ret[0].([]*model.Product)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListLowStockProducts indicates an expected call of ListLowStockProducts.
+func (mr *MockProductDaoMockRecorder) ListLowStockProducts(ctx, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLowStockProducts", reflect.TypeOf((*MockProductDao)(nil).ListLowStockProducts), ctx, offset, limit)
+}
+
+// ListPriceChanges mocks base method.
+func (m *MockProductDao) ListPriceChanges(ctx context.Context, productID, offset, limit int) ([]*model.ProductPriceChange, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPriceChanges", ctx, productID, offset, limit)
+	ret0, _ := ret[0].([]*model.ProductPriceChange)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPriceChanges indicates an expected call of ListPriceChanges.
+func (mr *MockProductDaoMockRecorder) ListPriceChanges(ctx, productID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPriceChanges", reflect.TypeOf((*MockProductDao)(nil).ListPriceChanges), ctx, productID, offset, limit)
+}
+
 // ListProduct mocks base method.
 func (m *MockProductDao) ListProduct(ctx context.Context, q dao.ListProductQuery) ([]*model.Product, int, error) {
 	m.ctrl.T.Helper()
@@ -97,58 +190,219 @@ func (mr *MockProductDaoMockRecorder) ListProduct(ctx, q interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProduct", reflect.TypeOf((*MockProductDao)(nil).ListProduct), ctx, q)
 }
 
-// UpdateProduct mocks base method.
-func (m *MockProductDao) UpdateProduct(ctx context.Context, product *model.Product) error {
+// ListStatusTransitions mocks base method.
+func (m *MockProductDao) ListStatusTransitions(ctx context.Context, productID int) ([]*model.ProductStatusTransition, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateProduct", ctx, product)
+	ret := m.ctrl.Call(m, "ListStatusTransitions", ctx, productID)
+	ret0, _ := ret[0].([]*model.ProductStatusTransition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStatusTransitions indicates an expected call of ListStatusTransitions.
+func (mr *MockProductDaoMockRecorder) ListStatusTransitions(ctx, productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStatusTransitions", reflect.TypeOf((*MockProductDao)(nil).ListStatusTransitions), ctx, productID)
+}
+
+// ListStockAdjustments mocks base method.
+func (m *MockProductDao) ListStockAdjustments(ctx context.Context, productID, offset, limit int) ([]*model.StockAdjustment, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockAdjustments", ctx, productID, offset, limit)
+	ret0, _ := ret[0].([]*model.StockAdjustment)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListStockAdjustments indicates an expected call of ListStockAdjustments.
+func (mr *MockProductDaoMockRecorder) ListStockAdjustments(ctx, productID, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockAdjustments", reflect.TypeOf((*MockProductDao)(nil).ListStockAdjustments), ctx, productID, offset, limit)
+}
+
+// PatchProduct mocks base method.
+func (m *MockProductDao) PatchProduct(ctx context.Context, id int, version int64, fields map[string]interface{}, priceChange *model.ProductPriceChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchProduct", ctx, id, version, fields, priceChange)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateProduct indicates an expected call of UpdateProduct.
-func (mr *MockProductDaoMockRecorder) UpdateProduct(ctx, product interface{}) *gomock.Call {
+// PatchProduct indicates an expected call of PatchProduct.
+func (mr *MockProductDaoMockRecorder) PatchProduct(ctx, id, version, fields, priceChange interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProduct", reflect.TypeOf((*MockProductDao)(nil).UpdateProduct), ctx, product)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchProduct", reflect.TypeOf((*MockProductDao)(nil).PatchProduct), ctx, id, version, fields, priceChange)
 }
 
-// UpdateProductStatus mocks base method.
-func (m *MockProductDao) UpdateProductStatus(ctx context.Context, id, status int) error {
+// PurgeDeletedProducts mocks base method.
+func (m *MockProductDao) PurgeDeletedProducts(ctx context.Context, deletedBefore time.Time, limit int) ([]int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateProductStatus", ctx, id, status)
+	ret := m.ctrl.Call(m, "PurgeDeletedProducts", ctx, deletedBefore, limit)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedProducts indicates an expected call of PurgeDeletedProducts.
+func (mr *MockProductDaoMockRecorder) PurgeDeletedProducts(ctx, deletedBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedProducts", reflect.TypeOf((*MockProductDao)(nil).PurgeDeletedProducts), ctx, deletedBefore, limit)
+}
+
+// RestoreDeletedProduct mocks base method.
+func (m *MockProductDao) RestoreDeletedProduct(ctx context.Context, transition *model.ProductStatusTransition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreDeletedProduct", ctx, transition)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreDeletedProduct indicates an expected call of RestoreDeletedProduct.
+func (mr *MockProductDaoMockRecorder) RestoreDeletedProduct(ctx, transition interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreDeletedProduct", reflect.TypeOf((*MockProductDao)(nil).RestoreDeletedProduct), ctx, transition)
+}
+
+// SoftDeleteProduct mocks base method.
+func (m *MockProductDao) SoftDeleteProduct(ctx context.Context, transition *model.ProductStatusTransition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SoftDeleteProduct", ctx, transition)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SoftDeleteProduct indicates an expected call of SoftDeleteProduct.
+func (mr *MockProductDaoMockRecorder) SoftDeleteProduct(ctx, transition interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SoftDeleteProduct", reflect.TypeOf((*MockProductDao)(nil).SoftDeleteProduct), ctx, transition)
+}
+
+// SumCustomerOrderQuantity mocks base method.
+func (m *MockProductDao) SumCustomerOrderQuantity(ctx context.Context, productID, customerID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumCustomerOrderQuantity", ctx, productID, customerID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumCustomerOrderQuantity indicates an expected call of SumCustomerOrderQuantity.
+func (mr *MockProductDaoMockRecorder) SumCustomerOrderQuantity(ctx, productID, customerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumCustomerOrderQuantity", reflect.TypeOf((*MockProductDao)(nil).SumCustomerOrderQuantity), ctx, productID, customerID)
+}
+
+// SumLedger mocks base method.
+func (m *MockProductDao) SumLedger(ctx context.Context, productID int) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumLedger", ctx, productID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SumLedger indicates an expected call of SumLedger.
+func (mr *MockProductDaoMockRecorder) SumLedger(ctx, productID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumLedger", reflect.TypeOf((*MockProductDao)(nil).SumLedger), ctx, productID)
+}
+
+// TransitionProductStatus mocks base method.
+func (m *MockProductDao) TransitionProductStatus(ctx context.Context, transition *model.ProductStatusTransition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionProductStatus", ctx, transition)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateProductStatus indicates an expected call of UpdateProductStatus.
-func (mr *MockProductDaoMockRecorder) UpdateProductStatus(ctx, id, status interface{}) *gomock.Call {
+// TransitionProductStatus indicates an expected call of TransitionProductStatus.
+func (mr *MockProductDaoMockRecorder) TransitionProductStatus(ctx, transition interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProductStatus", reflect.TypeOf((*MockProductDao)(nil).UpdateProductStatus), ctx, id, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionProductStatus", reflect.TypeOf((*MockProductDao)(nil).TransitionProductStatus), ctx, transition)
+}
+
+// UpdateLowStockThreshold mocks base method.
+func (m *MockProductDao) UpdateLowStockThreshold(ctx context.Context, id, threshold int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLowStockThreshold", ctx, id, threshold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLowStockThreshold indicates an expected call of UpdateLowStockThreshold.
+func (mr *MockProductDaoMockRecorder) UpdateLowStockThreshold(ctx, id, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLowStockThreshold", reflect.TypeOf((*MockProductDao)(nil).UpdateLowStockThreshold), ctx, id, threshold)
+}
+
+// UpdateProduct mocks base method.
+func (m *MockProductDao) UpdateProduct(ctx context.Context, product *model.Product, priceChange *model.ProductPriceChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProduct", ctx, product, priceChange)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateProduct indicates an expected call of UpdateProduct.
+func (mr *MockProductDaoMockRecorder) UpdateProduct(ctx, product, priceChange interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProduct", reflect.TypeOf((*MockProductDao)(nil).UpdateProduct), ctx, product, priceChange)
 }
 
 // UpdateProductStock mocks base method.
-func (m *MockProductDao) UpdateProductStock(ctx context.Context, id, stock int) error {
+func (m *MockProductDao) UpdateProductStock(ctx context.Context, entry *model.InventoryLedgerEntry) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateProductStock", ctx, id, stock)
+	ret := m.ctrl.Call(m, "UpdateProductStock", ctx, entry)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateProductStock indicates an expected call of UpdateProductStock.
-func (mr *MockProductDaoMockRecorder) UpdateProductStock(ctx, id, stock interface{}) *gomock.Call {
+func (mr *MockProductDaoMockRecorder) UpdateProductStock(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProductStock", reflect.TypeOf((*MockProductDao)(nil).UpdateProductStock), ctx, entry)
+}
+
+// UpdatePurchaseLimit mocks base method.
+func (m *MockProductDao) UpdatePurchaseLimit(ctx context.Context, id, maxPerCustomer int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePurchaseLimit", ctx, id, maxPerCustomer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePurchaseLimit indicates an expected call of UpdatePurchaseLimit.
+func (mr *MockProductDaoMockRecorder) UpdatePurchaseLimit(ctx, id, maxPerCustomer interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePurchaseLimit", reflect.TypeOf((*MockProductDao)(nil).UpdatePurchaseLimit), ctx, id, maxPerCustomer)
+}
+
+// UpdateStockMode mocks base method.
+func (m *MockProductDao) UpdateStockMode(ctx context.Context, id int, mode string, maxBackorder int64, expectedShipDate *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStockMode", ctx, id, mode, maxBackorder, expectedShipDate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStockMode indicates an expected call of UpdateStockMode.
+func (mr *MockProductDaoMockRecorder) UpdateStockMode(ctx, id, mode, maxBackorder, expectedShipDate interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProductStock", reflect.TypeOf((*MockProductDao)(nil).UpdateProductStock), ctx, id, stock)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStockMode", reflect.TypeOf((*MockProductDao)(nil).UpdateStockMode), ctx, id, mode, maxBackorder, expectedShipDate)
 }
 
 // UpdateStockWithCAS mocks base method.
-func (m *MockProductDao) UpdateStockWithCAS(ctx context.Context, id, version, newStock int) error {
+func (m *MockProductDao) UpdateStockWithCAS(ctx context.Context, version int, entry *model.InventoryLedgerEntry) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStockWithCAS", ctx, id, version, newStock)
+	ret := m.ctrl.Call(m, "UpdateStockWithCAS", ctx, version, entry)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateStockWithCAS indicates an expected call of UpdateStockWithCAS.
-func (mr *MockProductDaoMockRecorder) UpdateStockWithCAS(ctx, id, version, newStock interface{}) *gomock.Call {
+func (mr *MockProductDaoMockRecorder) UpdateStockWithCAS(ctx, version, entry interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStockWithCAS", reflect.TypeOf((*MockProductDao)(nil).UpdateStockWithCAS), ctx, id, version, newStock)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStockWithCAS", reflect.TypeOf((*MockProductDao)(nil).UpdateStockWithCAS), ctx, version, entry)
 }
@@ -0,0 +1,139 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/product_schedule.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProductScheduleDao is a mock of ProductScheduleDao interface.
+type MockProductScheduleDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductScheduleDaoMockRecorder
+}
+
+// MockProductScheduleDaoMockRecorder is the mock recorder for MockProductScheduleDao.
+type MockProductScheduleDaoMockRecorder struct {
+	mock *MockProductScheduleDao
+}
+
+// NewMockProductScheduleDao creates a new mock instance.
+func NewMockProductScheduleDao(ctrl *gomock.Controller) *MockProductScheduleDao {
+	mock := &MockProductScheduleDao{ctrl: ctrl}
+	mock.recorder = &MockProductScheduleDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductScheduleDao) EXPECT() *MockProductScheduleDaoMockRecorder {
+	return m.recorder
+}
+
+// CancelSchedule mocks base method.
+func (m *MockProductScheduleDao) CancelSchedule(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelSchedule", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelSchedule indicates an expected call of CancelSchedule.
+func (mr *MockProductScheduleDaoMockRecorder) CancelSchedule(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelSchedule", reflect.TypeOf((*MockProductScheduleDao)(nil).CancelSchedule), ctx, id)
+}
+
+// ClaimDueSchedules mocks base method.
+func (m *MockProductScheduleDao) ClaimDueSchedules(ctx context.Context, now time.Time, token string, lease time.Duration, limit int) ([]*model.ProductSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDueSchedules", ctx, now, token, lease, limit)
+	ret0, _ := ret[0].([]*model.ProductSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDueSchedules indicates an expected call of ClaimDueSchedules.
+func (mr *MockProductScheduleDaoMockRecorder) ClaimDueSchedules(ctx, now, token, lease, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDueSchedules", reflect.TypeOf((*MockProductScheduleDao)(nil).ClaimDueSchedules), ctx, now, token, lease, limit)
+}
+
+// CreateSchedule mocks base method.
+func (m *MockProductScheduleDao) CreateSchedule(ctx context.Context, schedule *model.ProductSchedule) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSchedule", ctx, schedule)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSchedule indicates an expected call of CreateSchedule.
+func (mr *MockProductScheduleDaoMockRecorder) CreateSchedule(ctx, schedule interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSchedule", reflect.TypeOf((*MockProductScheduleDao)(nil).CreateSchedule), ctx, schedule)
+}
+
+// FinishSchedule mocks base method.
+func (m *MockProductScheduleDao) FinishSchedule(ctx context.Context, id int, token string, status int, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishSchedule", ctx, id, token, status, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishSchedule indicates an expected call of FinishSchedule.
+func (mr *MockProductScheduleDaoMockRecorder) FinishSchedule(ctx, id, token, status, lastError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishSchedule", reflect.TypeOf((*MockProductScheduleDao)(nil).FinishSchedule), ctx, id, token, status, lastError)
+}
+
+// GetScheduleByID mocks base method.
+func (m *MockProductScheduleDao) GetScheduleByID(ctx context.Context, id int) (*model.ProductSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduleByID", ctx, id)
+	ret0, _ := ret[0].(*model.ProductSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduleByID indicates an expected call of GetScheduleByID.
+func (mr *MockProductScheduleDaoMockRecorder) GetScheduleByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduleByID", reflect.TypeOf((*MockProductScheduleDao)(nil).GetScheduleByID), ctx, id)
+}
+
+// ListSchedules mocks base method.
+func (m *MockProductScheduleDao) ListSchedules(ctx context.Context, productId int, onlyPending bool) ([]*model.ProductSchedule, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSchedules", ctx, productId, onlyPending)
+	ret0, _ := ret[0].([]*model.ProductSchedule)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSchedules indicates an expected call of ListSchedules.
+func (mr *MockProductScheduleDaoMockRecorder) ListSchedules(ctx, productId, onlyPending interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSchedules", reflect.TypeOf((*MockProductScheduleDao)(nil).ListSchedules), ctx, productId, onlyPending)
+}
+
+// ReleaseSchedule mocks base method.
+func (m *MockProductScheduleDao) ReleaseSchedule(ctx context.Context, id int, token, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseSchedule", ctx, id, token, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseSchedule indicates an expected call of ReleaseSchedule.
+func (mr *MockProductScheduleDaoMockRecorder) ReleaseSchedule(ctx, id, token, lastError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseSchedule", reflect.TypeOf((*MockProductScheduleDao)(nil).ReleaseSchedule), ctx, id, token, lastError)
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/product_tag.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProductTagDao is a mock of ProductTagDao interface.
+type MockProductTagDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductTagDaoMockRecorder
+}
+
+// MockProductTagDaoMockRecorder is the mock recorder for MockProductTagDao.
+type MockProductTagDaoMockRecorder struct {
+	mock *MockProductTagDao
+}
+
+// NewMockProductTagDao creates a new mock instance.
+func NewMockProductTagDao(ctrl *gomock.Controller) *MockProductTagDao {
+	mock := &MockProductTagDao{ctrl: ctrl}
+	mock.recorder = &MockProductTagDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductTagDao) EXPECT() *MockProductTagDaoMockRecorder {
+	return m.recorder
+}
+
+// GetTagsByProductID mocks base method.
+func (m *MockProductTagDao) GetTagsByProductID(ctx context.Context, productId int) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagsByProductID", ctx, productId)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagsByProductID indicates an expected call of GetTagsByProductID.
+func (mr *MockProductTagDaoMockRecorder) GetTagsByProductID(ctx, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagsByProductID", reflect.TypeOf((*MockProductTagDao)(nil).GetTagsByProductID), ctx, productId)
+}
+
+// ReplaceTags mocks base method.
+func (m *MockProductTagDao) ReplaceTags(ctx context.Context, productId int, tags []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceTags", ctx, productId, tags)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceTags indicates an expected call of ReplaceTags.
+func (mr *MockProductTagDaoMockRecorder) ReplaceTags(ctx, productId, tags interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceTags", reflect.TypeOf((*MockProductTagDao)(nil).ReplaceTags), ctx, productId, tags)
+}
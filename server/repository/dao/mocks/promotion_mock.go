@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/promotion.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPromotionDao is a mock of PromotionDao interface.
+type MockPromotionDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockPromotionDaoMockRecorder
+}
+
+// MockPromotionDaoMockRecorder is the mock recorder for MockPromotionDao.
+type MockPromotionDaoMockRecorder struct {
+	mock *MockPromotionDao
+}
+
+// NewMockPromotionDao creates a new mock instance.
+func NewMockPromotionDao(ctrl *gomock.Controller) *MockPromotionDao {
+	mock := &MockPromotionDao{ctrl: ctrl}
+	mock.recorder = &MockPromotionDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPromotionDao) EXPECT() *MockPromotionDaoMockRecorder {
+	return m.recorder
+}
+
+// CreatePromotion mocks base method.
+func (m *MockPromotionDao) CreatePromotion(ctx context.Context, promotion *model.Promotion) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePromotion", ctx, promotion)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePromotion indicates an expected call of CreatePromotion.
+func (mr *MockPromotionDaoMockRecorder) CreatePromotion(ctx, promotion interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePromotion", reflect.TypeOf((*MockPromotionDao)(nil).CreatePromotion), ctx, promotion)
+}
+
+// GetPromotionByID mocks base method.
+func (m *MockPromotionDao) GetPromotionByID(ctx context.Context, id int) (*model.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromotionByID", ctx, id)
+	ret0, _ := ret[0].(*model.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromotionByID indicates an expected call of GetPromotionByID.
+func (mr *MockPromotionDaoMockRecorder) GetPromotionByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromotionByID", reflect.TypeOf((*MockPromotionDao)(nil).GetPromotionByID), ctx, id)
+}
+
+// ListActivePromotions mocks base method.
+func (m *MockPromotionDao) ListActivePromotions(ctx context.Context, now time.Time) ([]*model.Promotion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActivePromotions", ctx, now)
+	ret0, _ := ret[0].([]*model.Promotion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActivePromotions indicates an expected call of ListActivePromotions.
+func (mr *MockPromotionDaoMockRecorder) ListActivePromotions(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActivePromotions", reflect.TypeOf((*MockPromotionDao)(nil).ListActivePromotions), ctx, now)
+}
+
+// ListPromotions mocks base method.
+func (m *MockPromotionDao) ListPromotions(ctx context.Context, offset, limit int) ([]*model.Promotion, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPromotions", ctx, offset, limit)
+	ret0, _ := ret[0].([]*model.Promotion)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPromotions indicates an expected call of ListPromotions.
+func (mr *MockPromotionDaoMockRecorder) ListPromotions(ctx, offset, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPromotions", reflect.TypeOf((*MockPromotionDao)(nil).ListPromotions), ctx, offset, limit)
+}
+
+// UpdatePromotionActive mocks base method.
+func (m *MockPromotionDao) UpdatePromotionActive(ctx context.Context, id int, active bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePromotionActive", ctx, id, active)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePromotionActive indicates an expected call of UpdatePromotionActive.
+func (mr *MockPromotionDaoMockRecorder) UpdatePromotionActive(ctx, id, active interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePromotionActive", reflect.TypeOf((*MockPromotionDao)(nil).UpdatePromotionActive), ctx, id, active)
+}
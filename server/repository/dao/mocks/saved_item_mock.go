@@ -0,0 +1,137 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/saved_item.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSavedItemDao is a mock of SavedItemDao interface.
+type MockSavedItemDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockSavedItemDaoMockRecorder
+}
+
+// MockSavedItemDaoMockRecorder is the mock recorder for MockSavedItemDao.
+type MockSavedItemDaoMockRecorder struct {
+	mock *MockSavedItemDao
+}
+
+// NewMockSavedItemDao creates a new mock instance.
+func NewMockSavedItemDao(ctrl *gomock.Controller) *MockSavedItemDao {
+	mock := &MockSavedItemDao{ctrl: ctrl}
+	mock.recorder = &MockSavedItemDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSavedItemDao) EXPECT() *MockSavedItemDaoMockRecorder {
+	return m.recorder
+}
+
+// CreateSavedItem mocks base method.
+func (m *MockSavedItemDao) CreateSavedItem(ctx context.Context, item *model.SavedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSavedItem", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSavedItem indicates an expected call of CreateSavedItem.
+func (mr *MockSavedItemDaoMockRecorder) CreateSavedItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSavedItem", reflect.TypeOf((*MockSavedItemDao)(nil).CreateSavedItem), ctx, item)
+}
+
+// DeleteSavedItem mocks base method.
+func (m *MockSavedItemDao) DeleteSavedItem(ctx context.Context, userId, id int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSavedItem", ctx, userId, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSavedItem indicates an expected call of DeleteSavedItem.
+func (mr *MockSavedItemDaoMockRecorder) DeleteSavedItem(ctx, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSavedItem", reflect.TypeOf((*MockSavedItemDao)(nil).DeleteSavedItem), ctx, userId, id)
+}
+
+// GetSavedItem mocks base method.
+func (m *MockSavedItemDao) GetSavedItem(ctx context.Context, id int) (*model.SavedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSavedItem", ctx, id)
+	ret0, _ := ret[0].(*model.SavedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSavedItem indicates an expected call of GetSavedItem.
+func (mr *MockSavedItemDaoMockRecorder) GetSavedItem(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSavedItem", reflect.TypeOf((*MockSavedItemDao)(nil).GetSavedItem), ctx, id)
+}
+
+// MoveFromCart mocks base method.
+func (m *MockSavedItemDao) MoveFromCart(ctx context.Context, cartItem *model.ShoppingCartItem, saved *model.SavedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveFromCart", ctx, cartItem, saved)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveFromCart indicates an expected call of MoveFromCart.
+func (mr *MockSavedItemDaoMockRecorder) MoveFromCart(ctx, cartItem, saved interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveFromCart", reflect.TypeOf((*MockSavedItemDao)(nil).MoveFromCart), ctx, cartItem, saved)
+}
+
+// MoveToCart mocks base method.
+func (m *MockSavedItemDao) MoveToCart(ctx context.Context, saved *model.SavedItem, cartItem *model.ShoppingCartItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveToCart", ctx, saved, cartItem)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveToCart indicates an expected call of MoveToCart.
+func (mr *MockSavedItemDaoMockRecorder) MoveToCart(ctx, saved, cartItem interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToCart", reflect.TypeOf((*MockSavedItemDao)(nil).MoveToCart), ctx, saved, cartItem)
+}
+
+// QuerySavedItems mocks base method.
+func (m *MockSavedItemDao) QuerySavedItems(ctx context.Context, query *model.SavedItem) ([]*model.SavedItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuerySavedItems", ctx, query)
+	ret0, _ := ret[0].([]*model.SavedItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuerySavedItems indicates an expected call of QuerySavedItems.
+func (mr *MockSavedItemDaoMockRecorder) QuerySavedItems(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuerySavedItems", reflect.TypeOf((*MockSavedItemDao)(nil).QuerySavedItems), ctx, query)
+}
+
+// UpdateSavedItem mocks base method.
+func (m *MockSavedItemDao) UpdateSavedItem(ctx context.Context, item *model.SavedItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSavedItem", ctx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSavedItem indicates an expected call of UpdateSavedItem.
+func (mr *MockSavedItemDaoMockRecorder) UpdateSavedItem(ctx, item interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSavedItem", reflect.TypeOf((*MockSavedItemDao)(nil).UpdateSavedItem), ctx, item)
+}
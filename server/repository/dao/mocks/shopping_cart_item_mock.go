@@ -50,6 +50,21 @@ func (mr *MockShoppingCartItemDaoMockRecorder) CreateItem(ctx, item interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockShoppingCartItemDao)(nil).CreateItem), ctx, item)
 }
 
+// DeleteAllByProductId mocks base method.
+func (m *MockShoppingCartItemDao) DeleteAllByProductId(ctx context.Context, productId int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAllByProductId", ctx, productId)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAllByProductId indicates an expected call of DeleteAllByProductId.
+func (mr *MockShoppingCartItemDaoMockRecorder) DeleteAllByProductId(ctx, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllByProductId", reflect.TypeOf((*MockShoppingCartItemDao)(nil).DeleteAllByProductId), ctx, productId)
+}
+
 // DeleteByProductIds mocks base method.
 func (m *MockShoppingCartItemDao) DeleteByProductIds(ctx context.Context, userId int, productIds []int) error {
 	m.ctrl.T.Helper()
@@ -78,6 +93,21 @@ func (mr *MockShoppingCartItemDaoMockRecorder) DeleteItemById(ctx, id, userId in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItemById", reflect.TypeOf((*MockShoppingCartItemDao)(nil).DeleteItemById), ctx, id, userId)
 }
 
+// DeleteItemsByIds mocks base method.
+func (m *MockShoppingCartItemDao) DeleteItemsByIds(ctx context.Context, userId int, ids []int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItemsByIds", ctx, userId, ids)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteItemsByIds indicates an expected call of DeleteItemsByIds.
+func (mr *MockShoppingCartItemDaoMockRecorder) DeleteItemsByIds(ctx, userId, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItemsByIds", reflect.TypeOf((*MockShoppingCartItemDao)(nil).DeleteItemsByIds), ctx, userId, ids)
+}
+
 // GetItemById mocks base method.
 func (m *MockShoppingCartItemDao) GetItemById(ctx context.Context, id int) (*model.ShoppingCartItem, error) {
 	m.ctrl.T.Helper()
@@ -108,6 +138,21 @@ func (mr *MockShoppingCartItemDaoMockRecorder) QueryItems(ctx, query interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryItems", reflect.TypeOf((*MockShoppingCartItemDao)(nil).QueryItems), ctx, query)
 }
 
+// ReconcileItem mocks base method.
+func (m *MockShoppingCartItemDao) ReconcileItem(ctx context.Context, item *model.ShoppingCartItem, quantityBefore int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileItem", ctx, item, quantityBefore)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReconcileItem indicates an expected call of ReconcileItem.
+func (mr *MockShoppingCartItemDaoMockRecorder) ReconcileItem(ctx, item, quantityBefore interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileItem", reflect.TypeOf((*MockShoppingCartItemDao)(nil).ReconcileItem), ctx, item, quantityBefore)
+}
+
 // UpdateItem mocks base method.
 func (m *MockShoppingCartItemDao) UpdateItem(ctx context.Context, item *model.ShoppingCartItem) error {
 	m.ctrl.T.Helper()
@@ -121,3 +166,32 @@ func (mr *MockShoppingCartItemDaoMockRecorder) UpdateItem(ctx, item interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItem", reflect.TypeOf((*MockShoppingCartItemDao)(nil).UpdateItem), ctx, item)
 }
+
+// UpdateItems mocks base method.
+func (m *MockShoppingCartItemDao) UpdateItems(ctx context.Context, items []*model.ShoppingCartItem) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItems", ctx, items)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItems indicates an expected call of UpdateItems.
+func (mr *MockShoppingCartItemDaoMockRecorder) UpdateItems(ctx, items interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItems", reflect.TypeOf((*MockShoppingCartItemDao)(nil).UpdateItems), ctx, items)
+}
+
+// UpdateSelectStatus mocks base method.
+func (m *MockShoppingCartItemDao) UpdateSelectStatus(ctx context.Context, userId int, ids []int, status int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSelectStatus", ctx, userId, ids, status)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSelectStatus indicates an expected call of UpdateSelectStatus.
+func (mr *MockShoppingCartItemDaoMockRecorder) UpdateSelectStatus(ctx, userId, ids, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSelectStatus", reflect.TypeOf((*MockShoppingCartItemDao)(nil).UpdateSelectStatus), ctx, userId, ids, status)
+}
@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: dao/stock_subscription.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockStockSubscriptionDao is a mock of StockSubscriptionDao interface.
+type MockStockSubscriptionDao struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockSubscriptionDaoMockRecorder
+}
+
+// MockStockSubscriptionDaoMockRecorder is the mock recorder for MockStockSubscriptionDao.
+type MockStockSubscriptionDaoMockRecorder struct {
+	mock *MockStockSubscriptionDao
+}
+
+// NewMockStockSubscriptionDao creates a new mock instance.
+func NewMockStockSubscriptionDao(ctrl *gomock.Controller) *MockStockSubscriptionDao {
+	mock := &MockStockSubscriptionDao{ctrl: ctrl}
+	mock.recorder = &MockStockSubscriptionDaoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockSubscriptionDao) EXPECT() *MockStockSubscriptionDaoMockRecorder {
+	return m.recorder
+}
+
+// DeleteSubscriptions mocks base method.
+func (m *MockStockSubscriptionDao) DeleteSubscriptions(ctx context.Context, productId int, userIds []int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscriptions", ctx, productId, userIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSubscriptions indicates an expected call of DeleteSubscriptions.
+func (mr *MockStockSubscriptionDaoMockRecorder) DeleteSubscriptions(ctx, productId, userIds interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscriptions", reflect.TypeOf((*MockStockSubscriptionDao)(nil).DeleteSubscriptions), ctx, productId, userIds)
+}
+
+// ListSubscriberIDs mocks base method.
+func (m *MockStockSubscriptionDao) ListSubscriberIDs(ctx context.Context, productId int) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriberIDs", ctx, productId)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriberIDs indicates an expected call of ListSubscriberIDs.
+func (mr *MockStockSubscriptionDaoMockRecorder) ListSubscriberIDs(ctx, productId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriberIDs", reflect.TypeOf((*MockStockSubscriptionDao)(nil).ListSubscriberIDs), ctx, productId)
+}
+
+// Subscribe mocks base method.
+func (m *MockStockSubscriptionDao) Subscribe(ctx context.Context, productId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, productId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockStockSubscriptionDaoMockRecorder) Subscribe(ctx, productId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockStockSubscriptionDao)(nil).Subscribe), ctx, productId, userId)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockStockSubscriptionDao) Unsubscribe(ctx context.Context, productId, userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", ctx, productId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockStockSubscriptionDaoMockRecorder) Unsubscribe(ctx, productId, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockStockSubscriptionDao)(nil).Unsubscribe), ctx, productId, userId)
+}
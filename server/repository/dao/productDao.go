@@ -2,25 +2,63 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/cache"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ErrVersionConflict 乐观锁冲突，商品已被其他请求修改
+var ErrVersionConflict = errors.New("product version conflict")
+
 type ProductDao interface {
-	CreateProduct(ctx context.Context, product *model.Product) (productId int, err error)
-	UpdateProduct(ctx context.Context, product *model.Product) error
-	UpdateStockWithCAS(ctx context.Context, id int, version int, newStock int) error
+	CreateProduct(ctx context.Context, product *model.Product, entry *model.InventoryLedgerEntry) (productId int, err error)
+	UpdateProduct(ctx context.Context, product *model.Product, priceChange *model.ProductPriceChange) error
+	PatchProduct(ctx context.Context, id int, version int64, fields map[string]interface{}, priceChange *model.ProductPriceChange) error
+	UpdateStockWithCAS(ctx context.Context, version int, entry *model.InventoryLedgerEntry) error
+	AdjustStockWithCAS(ctx context.Context, version int, adjustment *model.StockAdjustment) error
+	ListStockAdjustments(ctx context.Context, productID int, offset int, limit int) ([]*model.StockAdjustment, int, error)
 	GetProductByID(ctx context.Context, id int) (*model.Product, error)
 	GetProductByIDs(ctx context.Context, ids []int) ([]*model.Product, error)
-	UpdateProductStatus(ctx context.Context, id int, status int) error
-	UpdateProductStock(ctx context.Context, id int, stock int) error
+	TransitionProductStatus(ctx context.Context, transition *model.ProductStatusTransition) error
+	ListStatusTransitions(ctx context.Context, productID int) ([]*model.ProductStatusTransition, error)
+	UpdateProductStock(ctx context.Context, entry *model.InventoryLedgerEntry) error
 	ListProduct(ctx context.Context, q ListProductQuery) ([]*model.Product, int, error)
+
+	// 库存流水，库存变化与流水在同一事务中写入
+	ListLedgerEntries(ctx context.Context, productID int, offset int, limit int) ([]*model.InventoryLedgerEntry, int, error)
+	SumLedger(ctx context.Context, productID int) (total int, count int, err error)
+	SumCustomerOrderQuantity(ctx context.Context, productID int, customerID int) (int, error)
+
+	// 库存预警
+	UpdateLowStockThreshold(ctx context.Context, id int, threshold int) error
+	ListLowStockProducts(ctx context.Context, offset int, limit int) ([]*model.Product, int, error)
+
+	// 价格历史，改价与变更记录在同一事务中写入
+	ListPriceChanges(ctx context.Context, productID int, offset int, limit int) ([]*model.ProductPriceChange, int, error)
+	GetLowestPriceSince(ctx context.Context, productID int, since time.Time) (int64, error)
+
+	// 限购
+	UpdatePurchaseLimit(ctx context.Context, id int, maxPerCustomer int) error
+
+	// 预售与缺货下单
+	UpdateStockMode(ctx context.Context, id int, mode string, maxBackorder int64, expectedShipDate *time.Time) error
+
+	// 软删除与恢复，软删除的商品对普通查询不可见
+	SoftDeleteProduct(ctx context.Context, transition *model.ProductStatusTransition) error
+	GetDeletedProductByID(ctx context.Context, id int) (*model.Product, error)
+	RestoreDeletedProduct(ctx context.Context, transition *model.ProductStatusTransition) error
+	PurgeDeletedProducts(ctx context.Context, deletedBefore time.Time, limit int) ([]int, error)
 }
 
 type ProductDaoImpl struct {
@@ -29,53 +67,254 @@ type ProductDaoImpl struct {
 
 var (
 	productOnce sync.Once
-	productDao  *ProductDaoImpl
+	productDao  ProductDao
 )
 
-func GetProductDao() *ProductDaoImpl {
+// GetProductDao 返回商品 DAO，启用缓存时返回带读穿透缓存的实现
+func GetProductDao() ProductDao {
 	productOnce.Do(func() {
 		if productDao == nil {
 			productDao = &ProductDaoImpl{db: repository.DB}
+			if cache.Instance != nil {
+				productDao = NewCachedProductDao(productDao, cache.Instance, cache.TTL)
+			}
 		}
 	})
 	return productDao
 }
 
-// CreateProduct 创建产品并返回ID
-func (p *ProductDaoImpl) CreateProduct(ctx context.Context, product *model.Product) (int, error) {
-	result := p.db.WithContext(ctx).Create(product)
-	if result.Error != nil {
-		log.Logger.Errorf("Failed to create product: %v", result.Error)
-		return 0, result.Error
+// CreateProduct 创建产品并写入初始库存流水与初始价格，返回ID
+func (p *ProductDaoImpl) CreateProduct(ctx context.Context, product *model.Product, entry *model.InventoryLedgerEntry) (int, error) {
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(product).Error; err != nil {
+			return err
+		}
+		entry.ProductID = int(product.ID)
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.ProductPriceChange{
+			ProductID:   int(product.ID),
+			NewPrice:    product.Price,
+			OperatorID:  entry.OperatorID,
+			EffectiveAt: product.CreatedAt,
+		}).Error
+	})
+	if err != nil {
+		log.Logger.Errorf("Failed to create product: %v", err)
+		return 0, err
 	}
 	return int(product.ID), nil
 }
 
 // UpdateProduct 更新产品信息
-func (p *ProductDaoImpl) UpdateProduct(ctx context.Context, product *model.Product) error {
-	result := p.db.WithContext(ctx).Model(&model.Product{}).Where("id = ?", product.ID).Updates(product)
-	if result.Error != nil {
-		log.Logger.Errorf("Failed to update product ID %d: %v", product.ID, result.Error)
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		err := fmt.Errorf("product not found with ID: %d", product.ID)
-		log.Logger.Error(err)
+// product.Version 为调用方读取到的版本号，仅当数据库中版本一致时才更新，并递增版本号。
+// priceChange 不为 nil 时在同一事务中写入价格变更记录
+func (p *ProductDaoImpl) UpdateProduct(ctx context.Context, product *model.Product, priceChange *model.ProductPriceChange) error {
+	expectedVersion := product.Version
+	product.Version = expectedVersion + 1
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Product{}).Where("id = ? AND version = ?", product.ID, expectedVersion).Updates(product)
+		if result.Error != nil {
+			log.Logger.Errorf("Failed to update product ID %d: %v", product.ID, result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			log.Logger.Warnf("UpdateProduct: version conflict or product not found, ID: %d, version: %d", product.ID, expectedVersion)
+			return versionConflictOrNotFound(tx, int(product.ID))
+		}
+		return recordPriceChange(tx, priceChange)
+	})
+	if err != nil {
+		product.Version = expectedVersion
 		return err
 	}
 	return nil
 }
 
-// UpdateStockWithCAS
-func (p *ProductDaoImpl) UpdateStockWithCAS(ctx context.Context, id, version, newStock int) error {
-	ret := p.db.WithContext(ctx).Model(&model.Product{}).Where("id = ? AND version = ?", id, version).Update("stock", newStock)
-	if ret.Error != nil {
-		log.Logger.Errorf("Failed to update product ID %d: %v", id, ret.Error)
-		return ret.Error
+// versionConflictOrNotFound 在条件更新未命中时区分商品不存在 (gorm.ErrRecordNotFound) 与版本冲突
+func versionConflictOrNotFound(tx *gorm.DB, id int) error {
+	var count int64
+	if err := tx.Model(&model.Product{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return ErrVersionConflict
+}
+
+// PatchProduct 只更新 fields 中给出的列 (包括零值)，版本号一致时才更新并递增版本号。
+// priceChange 不为 nil 时在同一事务中写入价格变更记录
+func (p *ProductDaoImpl) PatchProduct(ctx context.Context, id int, version int64, fields map[string]interface{}, priceChange *model.ProductPriceChange) error {
+	updates := make(map[string]interface{}, len(fields)+1)
+	for column, value := range fields {
+		updates[column] = value
+	}
+	updates["version"] = gorm.Expr("version + 1")
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Product{}).Where("id = ? AND version = ?", id, version).Updates(updates)
+		if result.Error != nil {
+			log.Logger.Errorf("Failed to patch product ID %d: %v", id, result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			log.Logger.Warnf("PatchProduct: version conflict or product not found, ID: %d, version: %d", id, version)
+			return versionConflictOrNotFound(tx, id)
+		}
+		return recordPriceChange(tx, priceChange)
+	})
+}
+
+func recordPriceChange(tx *gorm.DB, priceChange *model.ProductPriceChange) error {
+	if priceChange == nil {
+		return nil
+	}
+	if err := tx.Create(priceChange).Error; err != nil {
+		log.Logger.Errorf("Failed to record price change of product ID %d: %v", priceChange.ProductID, err)
+		return err
 	}
 	return nil
 }
 
+// ListPriceChanges 按生效时间倒序分页返回价格变更记录及总数
+func (p *ProductDaoImpl) ListPriceChanges(ctx context.Context, productID int, offset int, limit int) ([]*model.ProductPriceChange, int, error) {
+	var changes []*model.ProductPriceChange
+	var total int64
+	query := p.db.WithContext(ctx).Model(&model.ProductPriceChange{}).Where("product_id = ?", productID)
+	if err := query.Count(&total).Error; err != nil {
+		log.Logger.Errorf("Failed to count price changes of product ID %d: %v", productID, err)
+		return nil, 0, err
+	}
+	err := query.Order("effective_at desc, id desc").Offset(offset).Limit(limit).Find(&changes).Error
+	if err != nil {
+		log.Logger.Errorf("Failed to list price changes of product ID %d: %v", productID, err)
+		return nil, 0, err
+	}
+	return changes, int(total), nil
+}
+
+// GetLowestPriceSince 返回 since 至今生效过的最低价格，包括 since 时刻正在生效的价格。
+// since 之前没有记录时，since 时刻的价格取窗口内第一条记录的 OldPrice (初始价格记录的 OldPrice 为 0，不计入)。
+// 没有任何价格记录时返回 0
+func (p *ProductDaoImpl) GetLowestPriceSince(ctx context.Context, productID int, since time.Time) (int64, error) {
+	db := p.db.WithContext(ctx)
+	var lowest struct {
+		Price *int64
+	}
+	err := db.Model(&model.ProductPriceChange{}).Select("MIN(new_price) AS price").
+		Where("product_id = ? AND effective_at >= ?", productID, since).Scan(&lowest).Error
+	if err != nil {
+		log.Logger.Errorf("Failed to get lowest price of product ID %d: %v", productID, err)
+		return 0, err
+	}
+	var before model.ProductPriceChange
+	err = db.Where("product_id = ? AND effective_at < ?", productID, since).
+		Order("effective_at desc, id desc").Take(&before).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("Failed to get price of product ID %d at %v: %v", productID, since, err)
+		return 0, err
+	}
+	if err == nil && (lowest.Price == nil || before.NewPrice < *lowest.Price) {
+		return before.NewPrice, nil
+	}
+	if err != nil && lowest.Price != nil {
+		var first model.ProductPriceChange
+		err = db.Where("product_id = ? AND effective_at >= ?", productID, since).
+			Order("effective_at asc, id asc").Take(&first).Error
+		if err != nil {
+			log.Logger.Errorf("Failed to get price of product ID %d at %v: %v", productID, since, err)
+			return 0, err
+		}
+		if first.OldPrice > 0 && first.OldPrice < *lowest.Price {
+			return first.OldPrice, nil
+		}
+	}
+	if lowest.Price == nil {
+		return 0, nil
+	}
+	return *lowest.Price, nil
+}
+
+// UpdateStockWithCAS 仅当版本号未变化时将库存更新为 entry.StockAfter，递增版本号并写入库存流水
+func (p *ProductDaoImpl) UpdateStockWithCAS(ctx context.Context, version int, entry *model.InventoryLedgerEntry) error {
+	id := entry.ProductID
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Model(&model.Product{}).Where("id = ? AND version = ?", id, version).
+			Updates(map[string]interface{}{
+				"stock":   entry.StockAfter,
+				"version": gorm.Expr("version + 1"),
+			})
+		if ret.Error != nil {
+			log.Logger.Errorf("Failed to update product ID %d: %v", id, ret.Error)
+			return ret.Error
+		}
+		if ret.RowsAffected == 0 {
+			log.Logger.Warnf("UpdateStockWithCAS: version conflict, product ID: %d, version: %d", id, version)
+			return ErrVersionConflict
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			log.Logger.Errorf("Failed to record inventory ledger of product ID %d: %v", id, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// AdjustStockWithCAS 在事务中按版本号将库存更新为 adjustment.StockAfter 并写入调整记录和库存流水，
+// 版本号不一致时返回 ErrVersionConflict
+func (p *ProductDaoImpl) AdjustStockWithCAS(ctx context.Context, version int, adjustment *model.StockAdjustment) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Model(&model.Product{}).Where("id = ? AND version = ?", adjustment.ProductID, version).
+			Updates(map[string]interface{}{
+				"stock":   adjustment.StockAfter,
+				"version": gorm.Expr("version + 1"),
+			})
+		if ret.Error != nil {
+			log.Logger.Errorf("Failed to adjust stock of product ID %d: %v", adjustment.ProductID, ret.Error)
+			return ret.Error
+		}
+		if ret.RowsAffected == 0 {
+			log.Logger.Warnf("AdjustStockWithCAS: version conflict, product ID: %d, version: %d", adjustment.ProductID, version)
+			return ErrVersionConflict
+		}
+		if err := tx.Create(adjustment).Error; err != nil {
+			log.Logger.Errorf("Failed to record stock adjustment of product ID %d: %v", adjustment.ProductID, err)
+			return err
+		}
+		entry := &model.InventoryLedgerEntry{
+			ProductID:   adjustment.ProductID,
+			Source:      model.InventorySourceAdjustment,
+			ReferenceID: strconv.Itoa(adjustment.ID),
+			Delta:       adjustment.Delta,
+			StockAfter:  adjustment.StockAfter,
+			OperatorID:  adjustment.OperatorID,
+		}
+		if err := tx.Create(entry).Error; err != nil {
+			log.Logger.Errorf("Failed to record inventory ledger of product ID %d: %v", adjustment.ProductID, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// ListStockAdjustments 按时间倒序分页返回库存调整记录及总数
+func (p *ProductDaoImpl) ListStockAdjustments(ctx context.Context, productID int, offset int, limit int) ([]*model.StockAdjustment, int, error) {
+	var adjustments []*model.StockAdjustment
+	var total int64
+	query := p.db.WithContext(ctx).Model(&model.StockAdjustment{}).Where("product_id = ?", productID)
+	if err := query.Count(&total).Error; err != nil {
+		log.Logger.Errorf("Failed to count stock adjustments of product ID %d: %v", productID, err)
+		return nil, 0, err
+	}
+	err := query.Order("id desc").Offset(offset).Limit(limit).Find(&adjustments).Error
+	if err != nil {
+		log.Logger.Errorf("Failed to list stock adjustments of product ID %d: %v", productID, err)
+		return nil, 0, err
+	}
+	return adjustments, int(total), nil
+}
+
 // GetProductByID 根据ID获取产品信息
 func (p *ProductDaoImpl) GetProductByID(ctx context.Context, id int) (*model.Product, error) {
 	var product model.Product
@@ -97,21 +336,148 @@ func (p *ProductDaoImpl) GetProductByIDs(ctx context.Context, ids []int) ([]*mod
 	return products, nil
 }
 
-// UpdateProductStock 更新商品库存
-func (p *ProductDaoImpl) UpdateProductStock(ctx context.Context, id int, stock int) error {
-	result := p.db.WithContext(ctx).Model(&model.Product{}).Where("id = ?", id).Update("stock", stock)
+// UpdateProductStock 将商品库存设置为 entry.StockAfter，
+// 事务内锁定商品行读取原库存，按差值写入库存流水
+func (p *ProductDaoImpl) UpdateProductStock(ctx context.Context, entry *model.InventoryLedgerEntry) error {
+	id, stock := entry.ProductID, entry.StockAfter
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var product model.Product
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "stock").Where("id = ?", id).Take(&product).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = fmt.Errorf("product not found with ID: %d", id)
+			log.Logger.Error(err)
+			return err
+		}
+		if err != nil {
+			log.Logger.Errorf("Failed to lock product stock, ID: %d, error: %v", id, err)
+			return err
+		}
+		result := tx.Model(&model.Product{}).Where("id = ?", id).
+			Updates(map[string]interface{}{
+				"stock":   stock,
+				"version": gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			log.Logger.Errorf("Failed to update product stock, ID: %d, stock: %d, error: %v", id, stock, result.Error)
+			return result.Error
+		}
+		entry.Delta = stock - int(product.Stock)
+		if err := tx.Create(entry).Error; err != nil {
+			log.Logger.Errorf("Failed to record inventory ledger of product ID %d: %v", id, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// ListLedgerEntries 按时间倒序分页返回库存流水及总数
+func (p *ProductDaoImpl) ListLedgerEntries(ctx context.Context, productID int, offset int, limit int) ([]*model.InventoryLedgerEntry, int, error) {
+	var entries []*model.InventoryLedgerEntry
+	var total int64
+	query := p.db.WithContext(ctx).Model(&model.InventoryLedgerEntry{}).Where("product_id = ?", productID)
+	if err := query.Count(&total).Error; err != nil {
+		log.Logger.Errorf("Failed to count inventory ledger of product ID %d: %v", productID, err)
+		return nil, 0, err
+	}
+	err := query.Order("id desc").Offset(offset).Limit(limit).Find(&entries).Error
+	if err != nil {
+		log.Logger.Errorf("Failed to list inventory ledger of product ID %d: %v", productID, err)
+		return nil, 0, err
+	}
+	return entries, int(total), nil
+}
+
+// SumLedger 返回商品所有库存流水的增减合计与流水条数
+func (p *ProductDaoImpl) SumLedger(ctx context.Context, productID int) (int, int, error) {
+	var ret struct {
+		Total int
+		Count int
+	}
+	err := p.db.WithContext(ctx).Model(&model.InventoryLedgerEntry{}).
+		Select("COALESCE(SUM(delta), 0) AS total, COUNT(*) AS count").
+		Where("product_id = ?", productID).Scan(&ret).Error
+	if err != nil {
+		log.Logger.Errorf("Failed to sum inventory ledger of product ID %d: %v", productID, err)
+		return 0, 0, err
+	}
+	return ret.Total, ret.Count, nil
+}
+
+// SumCustomerOrderQuantity 返回顾客在订单中累计购买的商品数量，取消订单回补的库存会抵消对应的扣减
+func (p *ProductDaoImpl) SumCustomerOrderQuantity(ctx context.Context, productID int, customerID int) (int, error) {
+	var total int
+	err := p.db.WithContext(ctx).Model(&model.InventoryLedgerEntry{}).
+		Select("COALESCE(-SUM(delta), 0)").
+		Where("customer_id = ? AND product_id = ? AND source = ?", customerID, productID, model.InventorySourceOrder).
+		Scan(&total).Error
+	if err != nil {
+		log.Logger.Errorf("Failed to sum order quantity of product ID %d for customer %d: %v", productID, customerID, err)
+		return 0, err
+	}
+	return total, nil
+}
+
+// UpdateLowStockThreshold 更新库存预警阈值，阈值不属于商品信息，不递增版本号
+func (p *ProductDaoImpl) UpdateLowStockThreshold(ctx context.Context, id int, threshold int) error {
+	result := p.db.WithContext(ctx).Model(&model.Product{}).Where("id = ?", id).
+		Update("low_stock_threshold", threshold)
+	if result.Error != nil {
+		log.Logger.Errorf("Failed to update low stock threshold, ID: %d, error: %v", id, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// UpdatePurchaseLimit 更新每位顾客的限购数量，与预警阈值一样不递增版本号
+func (p *ProductDaoImpl) UpdatePurchaseLimit(ctx context.Context, id int, maxPerCustomer int) error {
+	result := p.db.WithContext(ctx).Model(&model.Product{}).Where("id = ?", id).
+		Update("max_per_customer", maxPerCustomer)
+	if result.Error != nil {
+		log.Logger.Errorf("Failed to update purchase limit, ID: %d, error: %v", id, result.Error)
+		return result.Error
+	}
+	return nil
+}
+
+// UpdateStockMode 更新预售/缺货下单设置，仅当当前库存不低于 -maxBackorder 时更新，
+// 否则说明已售出的预售数量超过新的上限，返回 ErrVersionConflict
+func (p *ProductDaoImpl) UpdateStockMode(ctx context.Context, id int, mode string, maxBackorder int64, expectedShipDate *time.Time) error {
+	result := p.db.WithContext(ctx).Model(&model.Product{}).Where("id = ? AND stock >= ?", id, -maxBackorder).
+		Updates(map[string]interface{}{
+			"stock_mode":         mode,
+			"max_backorder":      maxBackorder,
+			"expected_ship_date": expectedShipDate,
+			"version":            gorm.Expr("version + 1"),
+		})
 	if result.Error != nil {
-		log.Logger.Errorf("Failed to update product stock, ID: %d, stock: %d, error: %v", id, stock, result.Error)
+		log.Logger.Errorf("Failed to update stock mode, ID: %d, error: %v", id, result.Error)
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
-		err := fmt.Errorf("product not found with ID: %d", id)
-		log.Logger.Error(err)
-		return err
+		log.Logger.Warnf("UpdateStockMode: stock of product ID %d is below -%d", id, maxBackorder)
+		return ErrVersionConflict
 	}
 	return nil
 }
 
+// ListLowStockProducts 分页返回库存小于等于预警阈值的商品，库存少的在前
+func (p *ProductDaoImpl) ListLowStockProducts(ctx context.Context, offset int, limit int) ([]*model.Product, int, error) {
+	var products []*model.Product
+	var total int64
+	query := p.db.WithContext(ctx).Model(&model.Product{}).
+		Where("low_stock_threshold > 0 AND stock <= low_stock_threshold")
+	if err := query.Count(&total).Error; err != nil {
+		log.Logger.Errorf("Failed to count low stock products: %v", err)
+		return nil, 0, err
+	}
+	err := query.Order("stock asc, id asc").Offset(offset).Limit(limit).Find(&products).Error
+	if err != nil {
+		log.Logger.Errorf("Failed to list low stock products: %v", err)
+		return nil, 0, err
+	}
+	return products, int(total), nil
+}
+
 // ListProduct 查询商品列表
 func (p *ProductDaoImpl) ListProduct(ctx context.Context, q ListProductQuery) ([]*model.Product, int, error) {
 	var products []*model.Product
@@ -120,22 +486,32 @@ func (p *ProductDaoImpl) ListProduct(ctx context.Context, q ListProductQuery) ([
 	query := p.db.WithContext(ctx).Model(&model.Product{})
 
 	if q.Keyword != "" {
-		query = query.Where("name LIKE ?", "%"+q.Keyword+"%")
+		query = query.Where("products.name LIKE ?", "%"+q.Keyword+"%")
 	}
 
 	if q.Category != "" {
-		query = query.Where("category = ?", q.Category)
+		query = query.Where("products.category = ?", q.Category)
+	}
+
+	// 标签以小写存储，查询时同样规范化
+	if tag := strings.ToLower(strings.TrimSpace(q.Tag)); tag != "" {
+		query = query.Where("products.id IN (?)",
+			p.db.WithContext(ctx).Model(&model.ProductTag{}).Select("product_id").Where("tag = ?", tag))
 	}
 
 	// 用户侧只能看到上架的商品
 	if q.IsCustomer {
-		query = query.Where("status = ?", 1)
+		query = query.Where("products.status = ?", 1)
 	}
 
-	if q.OrderBy == 0 {
-		query = query.Order("updated_at DESC")
+	if q.CollectionID != 0 {
+		// 专题内按商家设置的顺序展示
+		query = query.Joins("JOIN collection_items ON collection_items.product_id = products.id AND collection_items.collection_id = ?", q.CollectionID).
+			Order("collection_items.position")
+	} else if q.OrderBy == 0 {
+		query = query.Order("products.updated_at DESC")
 	} else {
-		query = query.Order("updated_at")
+		query = query.Order("products.updated_at")
 	}
 
 	if q.Limit == 0 {
@@ -158,17 +534,137 @@ func (p *ProductDaoImpl) ListProduct(ctx context.Context, q ListProductQuery) ([
 	return products, int(total), nil
 }
 
-// UpdateProductStatus 更新商品状态
-func (p *ProductDaoImpl) UpdateProductStatus(ctx context.Context, id int, status int) error {
-	result := p.db.WithContext(ctx).Model(&model.Product{}).Where("id = ?", id).Update("status", status)
+// TransitionProductStatus 在事务中将商品从 FromStatus 变更为 ToStatus 并写入变更记录，
+// 商品当前状态已不是 FromStatus 时返回 ErrVersionConflict
+func (p *ProductDaoImpl) TransitionProductStatus(ctx context.Context, transition *model.ProductStatusTransition) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Product{}).
+			Where("id = ? AND status = ?", transition.ProductID, transition.FromStatus).
+			Updates(map[string]interface{}{
+				"status":  transition.ToStatus,
+				"version": gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			log.Logger.Errorf("Failed to update product status, ID: %d, status: %d, error: %v",
+				transition.ProductID, transition.ToStatus, result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		if err := tx.Create(transition).Error; err != nil {
+			log.Logger.Errorf("Failed to record status transition, ID: %d, error: %v", transition.ProductID, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// ListStatusTransitions 按时间倒序返回商品的状态变更记录
+func (p *ProductDaoImpl) ListStatusTransitions(ctx context.Context, productID int) ([]*model.ProductStatusTransition, error) {
+	var transitions []*model.ProductStatusTransition
+	err := p.db.WithContext(ctx).Where("product_id = ?", productID).Order("id desc").Find(&transitions).Error
+	if err != nil {
+		log.Logger.Errorf("Failed to list status transitions, ID: %d, error: %v", productID, err)
+		return nil, err
+	}
+	return transitions, nil
+}
+
+// SoftDeleteProduct 在事务中将商品状态改为已删除、写入 deleted_at 并记录状态变更
+func (p *ProductDaoImpl) SoftDeleteProduct(ctx context.Context, transition *model.ProductStatusTransition) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Product{}).
+			Where("id = ? AND status = ?", transition.ProductID, transition.FromStatus).
+			Updates(map[string]interface{}{
+				"status":     transition.ToStatus,
+				"version":    gorm.Expr("version + 1"),
+				"deleted_at": time.Now(),
+			})
+		if result.Error != nil {
+			log.Logger.Errorf("Failed to soft delete product, ID: %d, error: %v", transition.ProductID, result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		if err := tx.Create(transition).Error; err != nil {
+			log.Logger.Errorf("Failed to record status transition, ID: %d, error: %v", transition.ProductID, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// GetDeletedProductByID 查询已软删除的商品，不存在或未删除时返回 nil, nil
+func (p *ProductDaoImpl) GetDeletedProductByID(ctx context.Context, id int) (*model.Product, error) {
+	var product model.Product
+	result := p.db.WithContext(ctx).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&product)
 	if result.Error != nil {
-		log.Logger.Errorf("Failed to update product status, ID: %d, status: %d, error: %v", id, status, result.Error)
-		return result.Error
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Errorf("Failed to get deleted product by ID %d: %v", id, result.Error)
+		return nil, result.Error
 	}
-	if result.RowsAffected == 0 {
-		err := fmt.Errorf("product not found with ID: %d", id)
-		log.Logger.Error(err)
-		return err
+	return &product, nil
+}
+
+// RestoreDeletedProduct 在事务中清除 deleted_at、将状态改为 ToStatus 并记录状态变更
+func (p *ProductDaoImpl) RestoreDeletedProduct(ctx context.Context, transition *model.ProductStatusTransition) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&model.Product{}).
+			Where("id = ? AND status = ? AND deleted_at IS NOT NULL", transition.ProductID, transition.FromStatus).
+			Updates(map[string]interface{}{
+				"status":     transition.ToStatus,
+				"version":    gorm.Expr("version + 1"),
+				"deleted_at": nil,
+			})
+		if result.Error != nil {
+			log.Logger.Errorf("Failed to restore product, ID: %d, error: %v", transition.ProductID, result.Error)
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		if err := tx.Create(transition).Error; err != nil {
+			log.Logger.Errorf("Failed to record status transition, ID: %d, error: %v", transition.ProductID, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// PurgeDeletedProducts 永久删除 deletedBefore 之前软删除的商品及其标签、专题关联、状态记录、定时任务、购物车条目、稍后购买条目和到货订阅，
+// 每次最多处理 limit 个商品，返回被删除的商品ID
+func (p *ProductDaoImpl) PurgeDeletedProducts(ctx context.Context, deletedBefore time.Time, limit int) ([]int, error) {
+	var ids []int
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&model.Product{}).
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+			Order("id asc").Limit(limit).Pluck("id", &ids).Error
+		if err != nil || len(ids) == 0 {
+			return err
+		}
+		related := []interface{}{
+			&model.ProductTag{},
+			&model.CollectionItem{},
+			&model.ProductStatusTransition{},
+			&model.ProductSchedule{},
+			&model.ShoppingCartItem{},
+			&model.SavedItem{},
+			&model.StockSubscription{},
+		}
+		for _, m := range related {
+			if err := tx.Where("product_id IN ?", ids).Delete(m).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Where("id IN ?", ids).Delete(&model.Product{}).Error
+	})
+	if err != nil {
+		log.Logger.Errorf("Failed to purge deleted products: %v", err)
+		return nil, err
 	}
-	return nil
+	return ids, nil
 }
@@ -0,0 +1,120 @@
+package dao
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"go.uber.org/zap"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// newSQLMockProductDao 返回使用 sqlmock 的 ProductDaoImpl，只校验 SQL 与结果处理，不连接数据库
+func newSQLMockProductDao(t *testing.T) (*ProductDaoImpl, sqlmock.Sqlmock) {
+	if log.Logger == nil {
+		logger, _ := zap.NewDevelopment()
+		log.Logger = logger.Sugar()
+	}
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to open gorm: %v", err)
+	}
+	return &ProductDaoImpl{db: db}, mock
+}
+
+func TestProductDaoImpl_GetLowestPriceSince(t *testing.T) {
+	ctx := context.Background()
+	since := time.Now().Add(-30 * 24 * time.Hour)
+	changeColumns := []string{"id", "product_id", "old_price", "new_price", "operator_id", "effective_at", "created_at"}
+
+	t.Run("窗口开始时的价格来自窗口前的最后一条记录", func(t *testing.T) {
+		p, mock := newSQLMockProductDao(t)
+		mock.ExpectQuery("SELECT MIN\\(new_price\\)").WillReturnRows(sqlmock.NewRows([]string{"price"}).AddRow(900))
+		mock.ExpectQuery("effective_at < \\?").WillReturnRows(sqlmock.NewRows(changeColumns).
+			AddRow(1, 1, 0, 800, 0, since.Add(-time.Hour), since.Add(-time.Hour)))
+
+		if lowest, err := p.GetLowestPriceSince(ctx, 1, since); err != nil || lowest != 800 {
+			t.Errorf("Expected 800, got %d, %v", lowest, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("窗口前没有记录时取窗口内第一次改价前的价格", func(t *testing.T) {
+		p, mock := newSQLMockProductDao(t)
+		mock.ExpectQuery("SELECT MIN\\(new_price\\)").WillReturnRows(sqlmock.NewRows([]string{"price"}).AddRow(900))
+		mock.ExpectQuery("effective_at < \\?").WillReturnRows(sqlmock.NewRows(changeColumns))
+		mock.ExpectQuery("effective_at >= \\?.*ORDER BY effective_at asc").WillReturnRows(sqlmock.NewRows(changeColumns).
+			AddRow(2, 1, 700, 1000, 0, since.Add(time.Hour), since.Add(time.Hour)))
+
+		if lowest, err := p.GetLowestPriceSince(ctx, 1, since); err != nil || lowest != 700 {
+			t.Errorf("Expected 700, got %d, %v", lowest, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("窗口内创建的商品不计入初始价格记录的 OldPrice", func(t *testing.T) {
+		p, mock := newSQLMockProductDao(t)
+		mock.ExpectQuery("SELECT MIN\\(new_price\\)").WillReturnRows(sqlmock.NewRows([]string{"price"}).AddRow(900))
+		mock.ExpectQuery("effective_at < \\?").WillReturnRows(sqlmock.NewRows(changeColumns))
+		mock.ExpectQuery("effective_at >= \\?.*ORDER BY effective_at asc").WillReturnRows(sqlmock.NewRows(changeColumns).
+			AddRow(3, 1, 0, 1000, 0, since.Add(time.Hour), since.Add(time.Hour)))
+
+		if lowest, err := p.GetLowestPriceSince(ctx, 1, since); err != nil || lowest != 900 {
+			t.Errorf("Expected 900, got %d, %v", lowest, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("没有价格记录时返回 0", func(t *testing.T) {
+		p, mock := newSQLMockProductDao(t)
+		mock.ExpectQuery("SELECT MIN\\(new_price\\)").WillReturnRows(sqlmock.NewRows([]string{"price"}).AddRow(nil))
+		mock.ExpectQuery("effective_at < \\?").WillReturnRows(sqlmock.NewRows(changeColumns))
+
+		if lowest, err := p.GetLowestPriceSince(ctx, 1, since); err != nil || lowest != 0 {
+			t.Errorf("Expected 0, got %d, %v", lowest, err)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func TestProductDaoImpl_PurgeDeletedProducts(t *testing.T) {
+	ctx := context.Background()
+	p, mock := newSQLMockProductDao(t)
+	before := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT `id` FROM `products` WHERE deleted_at IS NOT NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2))
+	// 关联数据全部删除后才删除商品本身
+	for _, table := range []string{"product_tags", "collection_items", "product_status_transitions", "product_schedules",
+		"shopping_cart_items", "saved_items", "stock_subscriptions"} {
+		mock.ExpectExec("DELETE FROM `"+table+"` WHERE product_id IN \\(\\?,\\?\\)").
+			WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectExec("DELETE FROM `products` WHERE id IN").WithArgs(1, 2).WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	ids, err := p.PurgeDeletedProducts(ctx, before, 10)
+	if err != nil || len(ids) != 2 {
+		t.Errorf("Expected 2 purged products, got %v, %v", ids, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
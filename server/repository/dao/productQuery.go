@@ -1,10 +1,12 @@
 package dao
 
 type ListProductQuery struct {
-	Keyword string
-	Category string
-	Offset int
-	Limit int
-	IsCustomer bool
-	OrderBy int // 0-updateTime desc, 1-updateTime inc
-}
\ No newline at end of file
+	Keyword      string
+	Category     string
+	Tag          string // 只返回带有该标签的商品
+	CollectionID int    // 只返回该专题内的商品，并按专题内顺序排序
+	Offset       int
+	Limit        int
+	IsCustomer   bool
+	OrderBy      int // 0-updateTime desc, 1-updateTime inc
+}
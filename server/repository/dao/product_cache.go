@@ -0,0 +1,281 @@
+package dao
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/cache"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+)
+
+const (
+	productCacheKeyPrefix = "product:detail:"
+	productListKeyPrefix  = "product:list:"
+	// 列表缓存的代际号，任何写操作都会更换代际号使旧的列表缓存整体失效
+	productListGenKey = "product:list:gen"
+
+	cacheNameProductDetail = "product_detail"
+	cacheNameProductList   = "product_list"
+)
+
+// CachedProductDao 在 ProductDao 之上提供读穿透缓存：
+// 详情按商品ID缓存，列表按查询条件缓存；所有写路径都会清除对应商品缓存并使列表缓存失效。
+// 不嵌入 ProductDao，每个方法都显式实现，新增写方法时不会漏掉缓存失效
+type CachedProductDao struct {
+	next  ProductDao
+	cache cache.Cache
+	ttl   time.Duration
+}
+
+var _ ProductDao = (*CachedProductDao)(nil)
+
+func NewCachedProductDao(productDao ProductDao, c cache.Cache, ttl time.Duration) *CachedProductDao {
+	return &CachedProductDao{
+		next:  productDao,
+		cache: c,
+		ttl:   ttl,
+	}
+}
+
+type cachedProductList struct {
+	Products []*model.Product `json:"products"`
+	Total    int              `json:"total"`
+}
+
+func (c *CachedProductDao) GetProductByID(ctx context.Context, id int) (*model.Product, error) {
+	if product, ok := c.getCachedProduct(ctx, id); ok {
+		return product, nil
+	}
+	product, err := c.next.GetProductByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	c.setCachedProduct(ctx, product)
+	return product, nil
+}
+
+func (c *CachedProductDao) GetProductByIDs(ctx context.Context, ids []int) ([]*model.Product, error) {
+	products := make([]*model.Product, 0, len(ids))
+	missed := make([]int, 0)
+	for _, id := range ids {
+		if product, ok := c.getCachedProduct(ctx, id); ok {
+			products = append(products, product)
+		} else {
+			missed = append(missed, id)
+		}
+	}
+	if len(missed) == 0 {
+		return products, nil
+	}
+	loaded, err := c.next.GetProductByIDs(ctx, missed)
+	if err != nil {
+		return nil, err
+	}
+	for _, product := range loaded {
+		c.setCachedProduct(ctx, product)
+	}
+	return append(products, loaded...), nil
+}
+
+func (c *CachedProductDao) ListProduct(ctx context.Context, q ListProductQuery) ([]*model.Product, int, error) {
+	key := c.listKey(ctx, q)
+	if value, ok := c.cache.Get(ctx, key); ok {
+		var cached cachedProductList
+		if err := json.Unmarshal(value, &cached); err == nil {
+			metrics.CacheRequestsTotal.WithLabelValues(cacheNameProductList, "hit").Inc()
+			return cached.Products, cached.Total, nil
+		}
+		log.Logger.Warnf("CachedProductDao: ListProduct: Failed to decode cached list %s", key)
+	}
+	metrics.CacheRequestsTotal.WithLabelValues(cacheNameProductList, "miss").Inc()
+	products, total, err := c.next.ListProduct(ctx, q)
+	if err != nil {
+		return nil, 0, err
+	}
+	if value, err := json.Marshal(cachedProductList{Products: products, Total: total}); err == nil {
+		c.cache.Set(ctx, key, value, c.ttl)
+	}
+	return products, total, nil
+}
+
+func (c *CachedProductDao) CreateProduct(ctx context.Context, product *model.Product, entry *model.InventoryLedgerEntry) (int, error) {
+	id, err := c.next.CreateProduct(ctx, product, entry)
+	if err == nil {
+		invalidateProductLists(ctx, c.cache)
+	}
+	return id, err
+}
+
+func (c *CachedProductDao) UpdateProduct(ctx context.Context, product *model.Product, priceChange *model.ProductPriceChange) error {
+	defer c.invalidate(ctx, int(product.ID))
+	return c.next.UpdateProduct(ctx, product, priceChange)
+}
+
+func (c *CachedProductDao) PatchProduct(ctx context.Context, id int, version int64, fields map[string]interface{}, priceChange *model.ProductPriceChange) error {
+	defer c.invalidate(ctx, id)
+	return c.next.PatchProduct(ctx, id, version, fields, priceChange)
+}
+
+// UpdateStockWithCAS 无论成功与否都清除缓存，CAS 冲突后重试时可以读到最新版本
+func (c *CachedProductDao) UpdateStockWithCAS(ctx context.Context, version int, entry *model.InventoryLedgerEntry) error {
+	defer c.invalidate(ctx, entry.ProductID)
+	return c.next.UpdateStockWithCAS(ctx, version, entry)
+}
+
+func (c *CachedProductDao) TransitionProductStatus(ctx context.Context, transition *model.ProductStatusTransition) error {
+	defer c.invalidate(ctx, transition.ProductID)
+	return c.next.TransitionProductStatus(ctx, transition)
+}
+
+func (c *CachedProductDao) SoftDeleteProduct(ctx context.Context, transition *model.ProductStatusTransition) error {
+	defer c.invalidate(ctx, transition.ProductID)
+	return c.next.SoftDeleteProduct(ctx, transition)
+}
+
+func (c *CachedProductDao) RestoreDeletedProduct(ctx context.Context, transition *model.ProductStatusTransition) error {
+	defer c.invalidate(ctx, transition.ProductID)
+	return c.next.RestoreDeletedProduct(ctx, transition)
+}
+
+func (c *CachedProductDao) PurgeDeletedProducts(ctx context.Context, deletedBefore time.Time, limit int) ([]int, error) {
+	ids, err := c.next.PurgeDeletedProducts(ctx, deletedBefore, limit)
+	for _, id := range ids {
+		c.cache.Delete(ctx, productCacheKey(id))
+	}
+	if len(ids) > 0 {
+		invalidateProductLists(ctx, c.cache)
+	}
+	return ids, err
+}
+
+func (c *CachedProductDao) AdjustStockWithCAS(ctx context.Context, version int, adjustment *model.StockAdjustment) error {
+	defer c.invalidate(ctx, adjustment.ProductID)
+	return c.next.AdjustStockWithCAS(ctx, version, adjustment)
+}
+
+func (c *CachedProductDao) UpdateProductStock(ctx context.Context, entry *model.InventoryLedgerEntry) error {
+	defer c.invalidate(ctx, entry.ProductID)
+	return c.next.UpdateProductStock(ctx, entry)
+}
+
+func (c *CachedProductDao) UpdateLowStockThreshold(ctx context.Context, id int, threshold int) error {
+	defer c.invalidate(ctx, id)
+	return c.next.UpdateLowStockThreshold(ctx, id, threshold)
+}
+
+func (c *CachedProductDao) UpdatePurchaseLimit(ctx context.Context, id int, maxPerCustomer int) error {
+	defer c.invalidate(ctx, id)
+	return c.next.UpdatePurchaseLimit(ctx, id, maxPerCustomer)
+}
+
+func (c *CachedProductDao) UpdateStockMode(ctx context.Context, id int, mode string, maxBackorder int64, expectedShipDate *time.Time) error {
+	defer c.invalidate(ctx, id)
+	return c.next.UpdateStockMode(ctx, id, mode, maxBackorder, expectedShipDate)
+}
+
+// 以下读方法不缓存，直接查询
+
+func (c *CachedProductDao) ListStockAdjustments(ctx context.Context, productID int, offset int, limit int) ([]*model.StockAdjustment, int, error) {
+	return c.next.ListStockAdjustments(ctx, productID, offset, limit)
+}
+
+func (c *CachedProductDao) ListStatusTransitions(ctx context.Context, productID int) ([]*model.ProductStatusTransition, error) {
+	return c.next.ListStatusTransitions(ctx, productID)
+}
+
+func (c *CachedProductDao) ListLedgerEntries(ctx context.Context, productID int, offset int, limit int) ([]*model.InventoryLedgerEntry, int, error) {
+	return c.next.ListLedgerEntries(ctx, productID, offset, limit)
+}
+
+func (c *CachedProductDao) SumLedger(ctx context.Context, productID int) (int, int, error) {
+	return c.next.SumLedger(ctx, productID)
+}
+
+func (c *CachedProductDao) SumCustomerOrderQuantity(ctx context.Context, productID int, customerID int) (int, error) {
+	return c.next.SumCustomerOrderQuantity(ctx, productID, customerID)
+}
+
+func (c *CachedProductDao) ListLowStockProducts(ctx context.Context, offset int, limit int) ([]*model.Product, int, error) {
+	return c.next.ListLowStockProducts(ctx, offset, limit)
+}
+
+func (c *CachedProductDao) ListPriceChanges(ctx context.Context, productID int, offset int, limit int) ([]*model.ProductPriceChange, int, error) {
+	return c.next.ListPriceChanges(ctx, productID, offset, limit)
+}
+
+func (c *CachedProductDao) GetLowestPriceSince(ctx context.Context, productID int, since time.Time) (int64, error) {
+	return c.next.GetLowestPriceSince(ctx, productID, since)
+}
+
+// GetDeletedProductByID 软删除的商品不进入缓存
+func (c *CachedProductDao) GetDeletedProductByID(ctx context.Context, id int) (*model.Product, error) {
+	return c.next.GetDeletedProductByID(ctx, id)
+}
+
+func (c *CachedProductDao) invalidate(ctx context.Context, id int) {
+	c.cache.Delete(ctx, productCacheKey(id))
+	invalidateProductLists(ctx, c.cache)
+}
+
+func (c *CachedProductDao) getCachedProduct(ctx context.Context, id int) (*model.Product, bool) {
+	value, ok := c.cache.Get(ctx, productCacheKey(id))
+	if ok {
+		var product model.Product
+		if err := json.Unmarshal(value, &product); err == nil {
+			metrics.CacheRequestsTotal.WithLabelValues(cacheNameProductDetail, "hit").Inc()
+			return &product, true
+		}
+		log.Logger.Warnf("CachedProductDao: Failed to decode cached product %d", id)
+	}
+	metrics.CacheRequestsTotal.WithLabelValues(cacheNameProductDetail, "miss").Inc()
+	return nil, false
+}
+
+func (c *CachedProductDao) setCachedProduct(ctx context.Context, product *model.Product) {
+	value, err := json.Marshal(product)
+	if err != nil {
+		log.Logger.Warnf("CachedProductDao: Failed to encode product %d: %v", product.ID, err)
+		return
+	}
+	c.cache.Set(ctx, productCacheKey(int(product.ID)), value, c.ttl)
+}
+
+func (c *CachedProductDao) listKey(ctx context.Context, q ListProductQuery) string {
+	gen, ok := c.cache.Get(ctx, productListGenKey)
+	if !ok {
+		// 代际号丢失 (被淘汰或从未写入) 时生成新的代际号，保证不会读到失效前的列表
+		gen = newProductListGen()
+		c.cache.Set(ctx, productListGenKey, gen, 0)
+	}
+	raw, _ := json.Marshal(q)
+	sum := sha1.Sum(raw)
+	return fmt.Sprintf("%s%s:%s", productListKeyPrefix, gen, hex.EncodeToString(sum[:]))
+}
+
+func productCacheKey(id int) string {
+	return productCacheKeyPrefix + strconv.Itoa(id)
+}
+
+func newProductListGen() []byte {
+	return []byte(strconv.FormatInt(time.Now().UnixNano(), 36))
+}
+
+// InvalidateProductListCache 使全部商品列表缓存失效，
+// 影响列表结果的非商品表写操作 (如标签、专题) 也需要调用
+func InvalidateProductListCache(ctx context.Context) {
+	if cache.Instance == nil {
+		return
+	}
+	invalidateProductLists(ctx, cache.Instance)
+}
+
+func invalidateProductLists(ctx context.Context, c cache.Cache) {
+	c.Set(ctx, productListGenKey, newProductListGen(), 0)
+}
@@ -0,0 +1,171 @@
+package dao_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/cache"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func init() {
+	logger, _ := zap.NewDevelopment()
+	log.Logger = logger.Sugar()
+}
+
+func TestCachedProductDao_GetProductByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	m := mocks.NewMockProductDao(ctrl)
+	cachedDao := dao.NewCachedProductDao(m, cache.NewMemoryCache(10), time.Minute)
+	product := &model.Product{Model: gorm.Model{ID: 1}, Name: "Mug", Stock: 5, Version: 2}
+
+	// 第二次读取命中缓存，只查询一次数据库
+	m.EXPECT().GetProductByID(ctx, 1).Return(product, nil).Times(1)
+	for i := 0; i < 2; i++ {
+		got, err := cachedDao.GetProductByID(ctx, 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got.Name != "Mug" || got.Stock != 5 || got.Version != 2 {
+			t.Errorf("Unexpected product: %+v", got)
+		}
+	}
+
+	// 写操作后缓存失效，重新读取数据库
+	entry := &model.InventoryLedgerEntry{ProductID: 1, Source: model.InventorySourceMerchantSet, StockAfter: 8}
+	m.EXPECT().UpdateProductStock(ctx, entry).Return(nil)
+	if err := cachedDao.UpdateProductStock(ctx, entry); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Name: "Mug", Stock: 8, Version: 3}, nil)
+	got, _ := cachedDao.GetProductByID(ctx, 1)
+	if got.Stock != 8 {
+		t.Errorf("Expected refreshed stock 8, got %d", got.Stock)
+	}
+}
+
+func TestCachedProductDao_GetProductByIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	m := mocks.NewMockProductDao(ctrl)
+	cachedDao := dao.NewCachedProductDao(m, cache.NewMemoryCache(10), time.Minute)
+
+	m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}}, nil)
+	_, _ = cachedDao.GetProductByID(ctx, 1)
+
+	// 只有未命中缓存的商品才会查询数据库
+	m.EXPECT().GetProductByIDs(ctx, []int{2}).Return([]*model.Product{{Model: gorm.Model{ID: 2}}}, nil)
+	products, err := cachedDao.GetProductByIDs(ctx, []int{1, 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(products) != 2 {
+		t.Errorf("Expected 2 products, got %d", len(products))
+	}
+}
+
+func TestCachedProductDao_ListProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	m := mocks.NewMockProductDao(ctrl)
+	cachedDao := dao.NewCachedProductDao(m, cache.NewMemoryCache(10), time.Minute)
+	q := dao.ListProductQuery{Category: "mug", Limit: 10, IsCustomer: true}
+
+	m.EXPECT().ListProduct(ctx, q).Return([]*model.Product{{Model: gorm.Model{ID: 1}}}, 1, nil).Times(1)
+	for i := 0; i < 2; i++ {
+		list, total, err := cachedDao.ListProduct(ctx, q)
+		if err != nil || total != 1 || len(list) != 1 {
+			t.Errorf("Unexpected list result: %v, %d, %v", list, total, err)
+		}
+	}
+
+	// 任一商品状态变化都会使列表缓存失效
+	transition := &model.ProductStatusTransition{ProductID: 3, FromStatus: 1, ToStatus: 0}
+	m.EXPECT().TransitionProductStatus(ctx, transition).Return(nil)
+	_ = cachedDao.TransitionProductStatus(ctx, transition)
+	m.EXPECT().ListProduct(ctx, q).Return([]*model.Product{}, 0, nil)
+	_, total, _ := cachedDao.ListProduct(ctx, q)
+	if total != 0 {
+		t.Errorf("Expected refreshed total 0, got %d", total)
+	}
+}
+
+func TestCachedProductDao_WritesInvalidate(t *testing.T) {
+	ctx := context.Background()
+	transition := &model.ProductStatusTransition{ProductID: 1}
+	writes := map[string]func(m *mocks.MockProductDao, d *dao.CachedProductDao) error{
+		"UpdateProduct": func(m *mocks.MockProductDao, d *dao.CachedProductDao) error {
+			m.EXPECT().UpdateProduct(ctx, gomock.Any(), nil).Return(nil)
+			return d.UpdateProduct(ctx, &model.Product{Model: gorm.Model{ID: 1}}, nil)
+		},
+		"PatchProduct": func(m *mocks.MockProductDao, d *dao.CachedProductDao) error {
+			m.EXPECT().PatchProduct(ctx, 1, int64(1), gomock.Any(), nil).Return(nil)
+			return d.PatchProduct(ctx, 1, 1, map[string]interface{}{"name": "Cup"}, nil)
+		},
+		"UpdateStockWithCAS": func(m *mocks.MockProductDao, d *dao.CachedProductDao) error {
+			m.EXPECT().UpdateStockWithCAS(ctx, 1, gomock.Any()).Return(nil)
+			return d.UpdateStockWithCAS(ctx, 1, &model.InventoryLedgerEntry{ProductID: 1})
+		},
+		"AdjustStockWithCAS": func(m *mocks.MockProductDao, d *dao.CachedProductDao) error {
+			m.EXPECT().AdjustStockWithCAS(ctx, 1, gomock.Any()).Return(nil)
+			return d.AdjustStockWithCAS(ctx, 1, &model.StockAdjustment{ProductID: 1})
+		},
+		"TransitionProductStatus": func(m *mocks.MockProductDao, d *dao.CachedProductDao) error {
+			m.EXPECT().TransitionProductStatus(ctx, transition).Return(nil)
+			return d.TransitionProductStatus(ctx, transition)
+		},
+		"SoftDeleteProduct": func(m *mocks.MockProductDao, d *dao.CachedProductDao) error {
+			m.EXPECT().SoftDeleteProduct(ctx, transition).Return(nil)
+			return d.SoftDeleteProduct(ctx, transition)
+		},
+		"RestoreDeletedProduct": func(m *mocks.MockProductDao, d *dao.CachedProductDao) error {
+			m.EXPECT().RestoreDeletedProduct(ctx, transition).Return(nil)
+			return d.RestoreDeletedProduct(ctx, transition)
+		},
+		"UpdateLowStockThreshold": func(m *mocks.MockProductDao, d *dao.CachedProductDao) error {
+			m.EXPECT().UpdateLowStockThreshold(ctx, 1, 3).Return(nil)
+			return d.UpdateLowStockThreshold(ctx, 1, 3)
+		},
+		"UpdatePurchaseLimit": func(m *mocks.MockProductDao, d *dao.CachedProductDao) error {
+			m.EXPECT().UpdatePurchaseLimit(ctx, 1, 3).Return(nil)
+			return d.UpdatePurchaseLimit(ctx, 1, 3)
+		},
+		"UpdateStockMode": func(m *mocks.MockProductDao, d *dao.CachedProductDao) error {
+			m.EXPECT().UpdateStockMode(ctx, 1, "backorder", int64(2), nil).Return(nil)
+			return d.UpdateStockMode(ctx, 1, "backorder", 2, nil)
+		},
+	}
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			m := mocks.NewMockProductDao(ctrl)
+			cachedDao := dao.NewCachedProductDao(m, cache.NewMemoryCache(10), time.Minute)
+
+			// 写操作前后各读一次，两次都查询数据库
+			m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}}, nil).Times(2)
+			if _, err := cachedDao.GetProductByID(ctx, 1); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if err := write(m, cachedDao); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if _, err := cachedDao.GetProductByID(ctx, 1); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
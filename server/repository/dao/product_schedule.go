@@ -0,0 +1,174 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"gorm.io/gorm"
+)
+
+// ErrScheduleNotPending 任务已执行、已取消或已被其他副本领取
+var ErrScheduleNotPending = errors.New("product schedule is not pending")
+
+type ProductScheduleDao interface {
+	CreateSchedule(ctx context.Context, schedule *model.ProductSchedule) (scheduleId int, err error)
+	GetScheduleByID(ctx context.Context, id int) (*model.ProductSchedule, error)
+	ListSchedules(ctx context.Context, productId int, onlyPending bool) ([]*model.ProductSchedule, error)
+	CancelSchedule(ctx context.Context, id int) error
+	ClaimDueSchedules(ctx context.Context, now time.Time, token string, lease time.Duration, limit int) ([]*model.ProductSchedule, error)
+	FinishSchedule(ctx context.Context, id int, token string, status int, lastError string) error
+	ReleaseSchedule(ctx context.Context, id int, token string, lastError string) error
+}
+
+var (
+	productScheduleDaoInstance ProductScheduleDao
+	productScheduleDaoSyncOnce sync.Once
+)
+
+func GetProductScheduleDao() ProductScheduleDao {
+	productScheduleDaoSyncOnce.Do(func() {
+		productScheduleDaoInstance = &ProductScheduleDaoImpl{
+			db: repository.DB,
+		}
+	})
+	return productScheduleDaoInstance
+}
+
+type ProductScheduleDaoImpl struct {
+	db *gorm.DB
+}
+
+// CreateSchedule 创建定时任务并返回ID
+func (p *ProductScheduleDaoImpl) CreateSchedule(ctx context.Context, schedule *model.ProductSchedule) (int, error) {
+	ret := p.db.WithContext(ctx).Create(schedule)
+	if ret.Error != nil {
+		log.Logger.Errorf("ProductScheduleDao: CreateSchedule: Failed to create schedule: %v", ret.Error)
+		return 0, ret.Error
+	}
+	return schedule.ID, nil
+}
+
+// GetScheduleByID 不存在时返回 nil, nil
+func (p *ProductScheduleDaoImpl) GetScheduleByID(ctx context.Context, id int) (*model.ProductSchedule, error) {
+	var schedule model.ProductSchedule
+	ret := p.db.WithContext(ctx).Where("id = ?", id).First(&schedule)
+	if ret.Error != nil {
+		if errors.Is(ret.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Errorf("ProductScheduleDao: GetScheduleByID: Failed to get schedule %d: %v", id, ret.Error)
+		return nil, ret.Error
+	}
+	return &schedule, nil
+}
+
+// ListSchedules 按执行时间升序返回定时任务，productId 为 0 时返回所有商品的任务
+func (p *ProductScheduleDaoImpl) ListSchedules(ctx context.Context, productId int, onlyPending bool) ([]*model.ProductSchedule, error) {
+	var schedules []*model.ProductSchedule
+	query := p.db.WithContext(ctx).Model(&model.ProductSchedule{})
+	if productId > 0 {
+		query = query.Where("product_id = ?", productId)
+	}
+	if onlyPending {
+		query = query.Where("status = ?", model.ProductScheduleStatusPending)
+	}
+	ret := query.Order("scheduled_at asc, id asc").Find(&schedules)
+	if ret.Error != nil {
+		log.Logger.Errorf("ProductScheduleDao: ListSchedules: Failed to list schedules: %v", ret.Error)
+		return nil, ret.Error
+	}
+	return schedules, nil
+}
+
+// CancelSchedule 取消等待执行的任务，已被副本领取执行中的任务不能取消
+func (p *ProductScheduleDaoImpl) CancelSchedule(ctx context.Context, id int) error {
+	now := time.Now()
+	ret := p.db.WithContext(ctx).Model(&model.ProductSchedule{}).
+		Where("id = ? AND status = ?", id, model.ProductScheduleStatusPending).
+		Where("claimed_until IS NULL OR claimed_until < ?", now).
+		Update("status", model.ProductScheduleStatusCanceled)
+	if ret.Error != nil {
+		log.Logger.Errorf("ProductScheduleDao: CancelSchedule: Failed to cancel schedule %d: %v", id, ret.Error)
+		return ret.Error
+	}
+	if ret.RowsAffected == 0 {
+		return ErrScheduleNotPending
+	}
+	return nil
+}
+
+// ClaimDueSchedules 领取到期的任务。每条任务通过条件更新抢占，同一时刻只有一个副本能领取成功
+func (p *ProductScheduleDaoImpl) ClaimDueSchedules(ctx context.Context, now time.Time, token string, lease time.Duration, limit int) ([]*model.ProductSchedule, error) {
+	var candidates []*model.ProductSchedule
+	ret := p.db.WithContext(ctx).
+		Where("status = ? AND scheduled_at <= ?", model.ProductScheduleStatusPending, now).
+		Where("claimed_until IS NULL OR claimed_until < ?", now).
+		Order("scheduled_at asc, id asc").Limit(limit).Find(&candidates)
+	if ret.Error != nil {
+		log.Logger.Errorf("ProductScheduleDao: ClaimDueSchedules: Failed to find due schedules: %v", ret.Error)
+		return nil, ret.Error
+	}
+
+	claimedUntil := now.Add(lease)
+	claimed := make([]*model.ProductSchedule, 0, len(candidates))
+	for _, schedule := range candidates {
+		ret = p.db.WithContext(ctx).Model(&model.ProductSchedule{}).
+			Where("id = ? AND status = ?", schedule.ID, model.ProductScheduleStatusPending).
+			Where("claimed_until IS NULL OR claimed_until < ?", now).
+			Updates(map[string]interface{}{
+				"claim_token":   token,
+				"claimed_until": claimedUntil,
+			})
+		if ret.Error != nil {
+			log.Logger.Errorf("ProductScheduleDao: ClaimDueSchedules: Failed to claim schedule %d: %v", schedule.ID, ret.Error)
+			return claimed, ret.Error
+		}
+		if ret.RowsAffected == 1 {
+			schedule.ClaimToken = token
+			schedule.ClaimedUntil = &claimedUntil
+			claimed = append(claimed, schedule)
+		}
+	}
+	return claimed, nil
+}
+
+// FinishSchedule 记录任务的执行结果，只有持有领取凭证的副本可以写入
+func (p *ProductScheduleDaoImpl) FinishSchedule(ctx context.Context, id int, token string, status int, lastError string) error {
+	ret := p.db.WithContext(ctx).Model(&model.ProductSchedule{}).
+		Where("id = ? AND claim_token = ? AND status = ?", id, token, model.ProductScheduleStatusPending).
+		Updates(map[string]interface{}{
+			"status":        status,
+			"last_error":    lastError,
+			"executed_at":   time.Now(),
+			"claimed_until": nil,
+		})
+	if ret.Error != nil {
+		log.Logger.Errorf("ProductScheduleDao: FinishSchedule: Failed to finish schedule %d: %v", id, ret.Error)
+		return ret.Error
+	}
+	if ret.RowsAffected == 0 {
+		return ErrScheduleNotPending
+	}
+	return nil
+}
+
+// ReleaseSchedule 放弃已领取的任务并累计重试次数，下一轮调度时重新执行
+func (p *ProductScheduleDaoImpl) ReleaseSchedule(ctx context.Context, id int, token string, lastError string) error {
+	ret := p.db.WithContext(ctx).Model(&model.ProductSchedule{}).
+		Where("id = ? AND claim_token = ? AND status = ?", id, token, model.ProductScheduleStatusPending).
+		Updates(map[string]interface{}{
+			"last_error":    lastError,
+			"attempts":      gorm.Expr("attempts + 1"),
+			"claimed_until": nil,
+		})
+	if ret.Error != nil {
+		log.Logger.Errorf("ProductScheduleDao: ReleaseSchedule: Failed to release schedule %d: %v", id, ret.Error)
+		return ret.Error
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package dao
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"gorm.io/gorm"
+)
+
+type ProductTagDao interface {
+	ReplaceTags(ctx context.Context, productId int, tags []string) error
+	GetTagsByProductID(ctx context.Context, productId int) ([]string, error)
+}
+
+var (
+	productTagDaoInstance ProductTagDao
+	productTagDaoSyncOnce sync.Once
+)
+
+func GetProductTagDao() ProductTagDao {
+	productTagDaoSyncOnce.Do(func() {
+		productTagDaoInstance = &ProductTagDaoImpl{
+			db: repository.DB,
+		}
+	})
+	return productTagDaoInstance
+}
+
+type ProductTagDaoImpl struct {
+	db *gorm.DB
+}
+
+// ReplaceTags 用新的标签集合覆盖商品原有标签
+func (p *ProductTagDaoImpl) ReplaceTags(ctx context.Context, productId int, tags []string) error {
+	defer InvalidateProductListCache(ctx)
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productId).Delete(&model.ProductTag{}).Error; err != nil {
+			log.Logger.Errorf("ProductTagDao: ReplaceTags: Failed to delete tags of product %d: %v", productId, err)
+			return err
+		}
+		if len(tags) == 0 {
+			return nil
+		}
+		rows := make([]*model.ProductTag, 0, len(tags))
+		for _, tag := range tags {
+			rows = append(rows, &model.ProductTag{ProductID: productId, Tag: tag})
+		}
+		if err := tx.Create(&rows).Error; err != nil {
+			log.Logger.Errorf("ProductTagDao: ReplaceTags: Failed to create tags of product %d: %v", productId, err)
+			return err
+		}
+		return nil
+	})
+}
+
+// GetTagsByProductID 查询商品的全部标签
+func (p *ProductTagDaoImpl) GetTagsByProductID(ctx context.Context, productId int) ([]string, error) {
+	tags := make([]string, 0)
+	ret := p.db.WithContext(ctx).Model(&model.ProductTag{}).Where("product_id = ?", productId).Order("tag").Pluck("tag", &tags)
+	if ret.Error != nil {
+		log.Logger.Errorf("ProductTagDao: GetTagsByProductID: Failed to get tags of product %d: %v", productId, ret.Error)
+		return nil, ret.Error
+	}
+	return tags, nil
+}
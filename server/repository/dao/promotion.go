@@ -0,0 +1,101 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"gorm.io/gorm"
+)
+
+type PromotionDao interface {
+	CreatePromotion(ctx context.Context, promotion *model.Promotion) (promotionId int, err error)
+	UpdatePromotionActive(ctx context.Context, id int, active bool) error
+	GetPromotionByID(ctx context.Context, id int) (*model.Promotion, error)
+	ListPromotions(ctx context.Context, offset, limit int) ([]*model.Promotion, int64, error)
+	ListActivePromotions(ctx context.Context, now time.Time) ([]*model.Promotion, error)
+}
+
+var (
+	promotionDaoInstance PromotionDao
+	promotionDaoSyncOnce sync.Once
+)
+
+func GetPromotionDao() PromotionDao {
+	promotionDaoSyncOnce.Do(func() {
+		promotionDaoInstance = &PromotionDaoImpl{
+			db: repository.DB,
+		}
+	})
+	return promotionDaoInstance
+}
+
+type PromotionDaoImpl struct {
+	db *gorm.DB
+}
+
+// CreatePromotion implements PromotionDao.
+func (p *PromotionDaoImpl) CreatePromotion(ctx context.Context, promotion *model.Promotion) (int, error) {
+	ret := p.db.WithContext(ctx).Create(promotion)
+	if ret.Error != nil {
+		log.Logger.Errorf("PromotionDao: CreatePromotion: Failed to create promotion %s: %v", promotion.Name, ret.Error)
+		return 0, ret.Error
+	}
+	return promotion.ID, nil
+}
+
+// UpdatePromotionActive enables or disables a promotion.
+func (p *PromotionDaoImpl) UpdatePromotionActive(ctx context.Context, id int, active bool) error {
+	ret := p.db.WithContext(ctx).Model(&model.Promotion{}).Where("id = ?", id).Update("active", active)
+	if ret.Error != nil {
+		log.Logger.Errorf("PromotionDao: UpdatePromotionActive: Failed to update promotion %d: %v", id, ret.Error)
+		return ret.Error
+	}
+	return nil
+}
+
+// GetPromotionByID returns nil when the promotion does not exist.
+func (p *PromotionDaoImpl) GetPromotionByID(ctx context.Context, id int) (*model.Promotion, error) {
+	var promotion model.Promotion
+	err := p.db.WithContext(ctx).First(&promotion, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		log.Logger.Errorf("PromotionDao: GetPromotionByID: Failed to get promotion %d: %v", id, err)
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// ListPromotions returns promotions newest first together with the total count.
+func (p *PromotionDaoImpl) ListPromotions(ctx context.Context, offset, limit int) ([]*model.Promotion, int64, error) {
+	var total int64
+	if err := p.db.WithContext(ctx).Model(&model.Promotion{}).Count(&total).Error; err != nil {
+		log.Logger.Errorf("PromotionDao: ListPromotions: Failed to count promotions: %v", err)
+		return nil, 0, err
+	}
+	promotions := make([]*model.Promotion, 0)
+	err := p.db.WithContext(ctx).Order("id desc").Offset(offset).Limit(limit).Find(&promotions).Error
+	if err != nil {
+		log.Logger.Errorf("PromotionDao: ListPromotions: Failed to list promotions: %v", err)
+		return nil, 0, err
+	}
+	return promotions, total, nil
+}
+
+// ListActivePromotions returns the enabled promotions whose window contains now.
+func (p *PromotionDaoImpl) ListActivePromotions(ctx context.Context, now time.Time) ([]*model.Promotion, error) {
+	promotions := make([]*model.Promotion, 0)
+	err := p.db.WithContext(ctx).Where("active = ? AND starts_at <= ? AND ends_at > ?", true, now, now).
+		Order("id asc").Find(&promotions).Error
+	if err != nil {
+		log.Logger.Errorf("PromotionDao: ListActivePromotions: Failed to list promotions: %v", err)
+		return nil, err
+	}
+	return promotions, nil
+}
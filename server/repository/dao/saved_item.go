@@ -0,0 +1,159 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"gorm.io/gorm"
+)
+
+// ErrSavedItemGone is returned when a saved item was removed by another request.
+var ErrSavedItemGone = errors.New("saved item was removed")
+
+type SavedItemDao interface {
+	CreateSavedItem(ctx context.Context, item *model.SavedItem) error
+	UpdateSavedItem(ctx context.Context, item *model.SavedItem) error
+	GetSavedItem(ctx context.Context, id int) (*model.SavedItem, error)
+	QuerySavedItems(ctx context.Context, query *model.SavedItem) ([]*model.SavedItem, error)
+	DeleteSavedItem(ctx context.Context, userId int, id int) (deleted int64, err error)
+	MoveToCart(ctx context.Context, saved *model.SavedItem, cartItem *model.ShoppingCartItem) error
+	MoveFromCart(ctx context.Context, cartItem *model.ShoppingCartItem, saved *model.SavedItem) error
+}
+
+var (
+	savedItemDaoInstance SavedItemDao
+	savedItemDaoSyncOnce sync.Once
+)
+
+func GetSavedItemDao() SavedItemDao {
+	savedItemDaoSyncOnce.Do(func() {
+		savedItemDaoInstance = &SavedItemDaoImpl{
+			db: repository.DB,
+		}
+	})
+	return savedItemDaoInstance
+}
+
+type SavedItemDaoImpl struct {
+	db *gorm.DB
+}
+
+// CreateSavedItem implements SavedItemDao.
+func (s *SavedItemDaoImpl) CreateSavedItem(ctx context.Context, item *model.SavedItem) error {
+	ret := s.db.WithContext(ctx).Create(item)
+	if ret.Error != nil {
+		log.Logger.Errorf("SavedItemDao: CreateSavedItem: Failed to create saved item: %v", ret.Error)
+		return ret.Error
+	}
+	return nil
+}
+
+// UpdateSavedItem writes the item's quantity. It returns ErrSavedItemGone when the item is no longer
+// on the user's list.
+func (s *SavedItemDaoImpl) UpdateSavedItem(ctx context.Context, item *model.SavedItem) error {
+	if err := updateSavedItemColumns(s.db.WithContext(ctx), item); err != nil {
+		log.Logger.Errorf("SavedItemDao: UpdateSavedItem: Failed to update saved item %d: %v", item.ID, err)
+		return err
+	}
+	return nil
+}
+
+// updateSavedItemColumns updates an item that is still on the user's list; like updateItemColumns it
+// does not use Save, which would insert a deleted item again.
+func updateSavedItemColumns(tx *gorm.DB, item *model.SavedItem) error {
+	ret := tx.Model(&model.SavedItem{}).Where("id = ? AND user_id = ?", item.ID, item.UserID).
+		UpdateColumns(map[string]interface{}{"quantity": item.Quantity, "updated_at": item.UpdatedAt})
+	if ret.Error != nil {
+		return ret.Error
+	}
+	if ret.RowsAffected == 0 {
+		return ErrSavedItemGone
+	}
+	return nil
+}
+
+// GetSavedItem returns nil when the item does not exist.
+func (s *SavedItemDaoImpl) GetSavedItem(ctx context.Context, id int) (*model.SavedItem, error) {
+	var item model.SavedItem
+	ret := s.db.WithContext(ctx).Take(&item, id)
+	if errors.Is(ret.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if ret.Error != nil {
+		log.Logger.Errorf("SavedItemDao: GetSavedItem: Failed to get saved item %d: %v", id, ret.Error)
+		return nil, ret.Error
+	}
+	return &item, nil
+}
+
+// QuerySavedItems returns the items matching the non-zero fields of query, most recently saved first.
+func (s *SavedItemDaoImpl) QuerySavedItems(ctx context.Context, query *model.SavedItem) ([]*model.SavedItem, error) {
+	var items []*model.SavedItem
+	ret := s.db.WithContext(ctx).Where(query).Order("updated_at DESC, id DESC").Find(&items)
+	if ret.Error != nil {
+		log.Logger.Errorf("SavedItemDao: QuerySavedItems: Failed to query saved items: %v", ret.Error)
+		return nil, ret.Error
+	}
+	return items, nil
+}
+
+// DeleteSavedItem deletes the item only if it belongs to the user.
+func (s *SavedItemDaoImpl) DeleteSavedItem(ctx context.Context, userId int, id int) (int64, error) {
+	ret := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userId).Delete(&model.SavedItem{})
+	if ret.Error != nil {
+		log.Logger.Errorf("SavedItemDao: DeleteSavedItem: Failed to delete saved item %d: %v", id, ret.Error)
+		return 0, ret.Error
+	}
+	return ret.RowsAffected, nil
+}
+
+// MoveToCart removes the saved item and writes the cart item in one transaction. The saved item is
+// removed first, so of two concurrent moves only one adds to the cart; the other gets ErrSavedItemGone.
+// An existing cart item that was removed meanwhile fails the move with ErrCartItemChanged.
+func (s *SavedItemDaoImpl) MoveToCart(ctx context.Context, saved *model.SavedItem, cartItem *model.ShoppingCartItem) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Where("id = ? AND user_id = ?", saved.ID, saved.UserID).Delete(&model.SavedItem{})
+		if ret.Error != nil {
+			return ret.Error
+		}
+		if ret.RowsAffected == 0 {
+			return ErrSavedItemGone
+		}
+		if cartItem.ID == 0 {
+			return tx.Create(cartItem).Error
+		}
+		return updateItemColumns(tx, cartItem)
+	})
+	if err != nil {
+		log.Logger.Errorf("SavedItemDao: MoveToCart: Failed to move saved item %d to cart: %v", saved.ID, err)
+		return err
+	}
+	return nil
+}
+
+// MoveFromCart removes the cart item and writes the saved item in one transaction, the same way
+// MoveToCart does in the other direction.
+func (s *SavedItemDaoImpl) MoveFromCart(ctx context.Context, cartItem *model.ShoppingCartItem, saved *model.SavedItem) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ret := tx.Where("id = ? AND user_id = ?", cartItem.ID, cartItem.UserID).Delete(&model.ShoppingCartItem{})
+		if ret.Error != nil {
+			return ret.Error
+		}
+		if ret.RowsAffected == 0 {
+			return ErrCartItemChanged
+		}
+		if saved.ID == 0 {
+			return tx.Create(saved).Error
+		}
+		return updateSavedItemColumns(tx, saved)
+	})
+	if err != nil {
+		log.Logger.Errorf("SavedItemDao: MoveFromCart: Failed to move cart item %d to %s: %v", cartItem.ID, saved.List, err)
+		return err
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository"
@@ -11,13 +12,22 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrCartItemChanged is returned when a cart item was removed or changed by another request between
+// being read and being written.
+var ErrCartItemChanged = errors.New("cart item was changed or removed")
+
 type ShoppingCartItemDao interface {
 	CreateItem(ctx context.Context, item *model.ShoppingCartItem) (itemId int, err error)
 	UpdateItem(ctx context.Context, item *model.ShoppingCartItem) error
 	DeleteItemById(ctx context.Context, id int, userId int) error
 	DeleteByProductIds(ctx context.Context, userId int, productIds []int) error
+	DeleteAllByProductId(ctx context.Context, productId int) (deleted int64, err error)
 	GetItemById(ctx context.Context, id int) (item *model.ShoppingCartItem, err error)
 	QueryItems(ctx context.Context, query *model.ShoppingCartItem) (item []*model.ShoppingCartItem, err error)
+	UpdateItems(ctx context.Context, items []*model.ShoppingCartItem) error
+	UpdateSelectStatus(ctx context.Context, userId int, ids []int, status int) (updated int64, err error)
+	ReconcileItem(ctx context.Context, item *model.ShoppingCartItem, quantityBefore int) (updated bool, err error)
+	DeleteItemsByIds(ctx context.Context, userId int, ids []int) (deleted int64, err error)
 }
 
 var (
@@ -49,6 +59,17 @@ func (s *ShoppingCartItemDaoImpl) DeleteByProductIds(ctx context.Context, userId
 	return nil
 }
 
+// DeleteAllByProductId removes the product from every user's cart.
+func (s *ShoppingCartItemDaoImpl) DeleteAllByProductId(ctx context.Context, productId int) (int64, error) {
+	ret := s.db.WithContext(ctx).Where("product_id = ?", productId).Delete(&model.ShoppingCartItem{})
+	if ret.Error != nil {
+		log.Logger.Errorf("ShoppingCartItemDao: DeleteAllByProductId: Failed to delete items: %v", ret.Error)
+		return 0, ret.Error
+	}
+	log.Logger.Infof("ShoppingCartItemDao: DeleteAllByProductId: Deleted %d items for product ID %d", ret.RowsAffected, productId)
+	return ret.RowsAffected, nil
+}
+
 // CreateItem implements ShoppingCartItemDao.
 func (s *ShoppingCartItemDaoImpl) CreateItem(ctx context.Context, item *model.ShoppingCartItem) (itemId int, err error) {
 	ret := s.db.WithContext(ctx).Create(item)
@@ -62,7 +83,8 @@ func (s *ShoppingCartItemDaoImpl) CreateItem(ctx context.Context, item *model.Sh
 
 // DeleteItem implements ShoppingCartItemDao.
 func (s *ShoppingCartItemDaoImpl) DeleteItemById(ctx context.Context, id int, userId int) error {
-	ret := s.db.WithContext(ctx).Delete(&model.ShoppingCartItem{ID: id, UserID: userId})
+	// Delete only applies the primary key of a model value, so the owner has to be a condition
+	ret := s.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userId).Delete(&model.ShoppingCartItem{})
 	if ret.Error != nil {
 		log.Logger.Errorf("ShoppingCartItemDao: DeleteItem: Failed to delete item: %v", ret.Error)
 		return ret.Error
@@ -100,16 +122,87 @@ func (s *ShoppingCartItemDaoImpl) QueryItems(ctx context.Context, query *model.S
 	return item, nil
 }
 
-// UpdateItem implements ShoppingCartItemDao.
+// UpdateItem writes the item's quantity, selection and price snapshot. It returns ErrCartItemChanged
+// when the item is no longer in the user's cart.
 func (s *ShoppingCartItemDaoImpl) UpdateItem(ctx context.Context, item *model.ShoppingCartItem) error {
-	ret := s.db.WithContext(ctx).Save(item)
+	if err := updateItemColumns(s.db.WithContext(ctx), item); err != nil {
+		log.Logger.Errorf("ShoppingCartItemDao: UpdateItem: Failed to update item %d: %v", item.ID, err)
+		return err
+	}
+	return nil
+}
+
+// UpdateItems updates all items in one transaction like UpdateItem, either every item is updated or none is.
+func (s *ShoppingCartItemDaoImpl) UpdateItems(ctx context.Context, items []*model.ShoppingCartItem) error {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range items {
+			if err := updateItemColumns(tx, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Logger.Errorf("ShoppingCartItemDao: UpdateItems: Failed to update %d items: %v", len(items), err)
+		return err
+	}
+	return nil
+}
+
+// updateItemColumns updates the columns a customer can change on an item that is still in their cart.
+// Save is not used: when nothing matches it inserts the row again, bringing back a deleted item.
+func updateItemColumns(tx *gorm.DB, item *model.ShoppingCartItem) error {
+	ret := tx.Model(&model.ShoppingCartItem{}).Where("id = ? AND user_id = ?", item.ID, item.UserID).
+		UpdateColumns(map[string]interface{}{
+			"quantity":       item.Quantity,
+			"select_status":  item.SelectStatus,
+			"price_snapshot": item.PriceSnapshot,
+			"updated_at":     item.UpdatedAt,
+		})
 	if ret.Error != nil {
-		log.Logger.Errorf("ShoppingCartItemDao: UpdateItem: Failed to update item: %v", ret.Error)
 		return ret.Error
 	}
 	if ret.RowsAffected == 0 {
-		log.Logger.Warnf("ShoppingCartItemDao: UpdateItem: No item found with ID %d to update", item.ID)
-		return nil
+		return ErrCartItemChanged
 	}
 	return nil
 }
+
+// UpdateSelectStatus selects or unselects the user's items among ids in one statement.
+func (s *ShoppingCartItemDaoImpl) UpdateSelectStatus(ctx context.Context, userId int, ids []int, status int) (int64, error) {
+	ret := s.db.WithContext(ctx).Model(&model.ShoppingCartItem{}).Where("user_id = ? AND id IN ?", userId, ids).
+		UpdateColumns(map[string]interface{}{"select_status": status, "updated_at": time.Now()})
+	if ret.Error != nil {
+		log.Logger.Errorf("ShoppingCartItemDao: UpdateSelectStatus: Failed to update items: %v", ret.Error)
+		return 0, ret.Error
+	}
+	return ret.RowsAffected, nil
+}
+
+// ReconcileItem writes the price snapshot of an item and, when it was reduced to the sellable stock,
+// its quantity. Nothing is written when the customer changed the quantity or removed the item since
+// it was read with quantityBefore, so their change always wins.
+func (s *ShoppingCartItemDaoImpl) ReconcileItem(ctx context.Context, item *model.ShoppingCartItem, quantityBefore int) (bool, error) {
+	columns := map[string]interface{}{"price_snapshot": item.PriceSnapshot}
+	if item.Quantity != quantityBefore {
+		columns["quantity"] = item.Quantity
+	}
+	ret := s.db.WithContext(ctx).Model(&model.ShoppingCartItem{}).
+		Where("id = ? AND user_id = ? AND quantity = ?", item.ID, item.UserID, quantityBefore).UpdateColumns(columns)
+	if ret.Error != nil {
+		log.Logger.Errorf("ShoppingCartItemDao: ReconcileItem: Failed to update item %d: %v", item.ID, ret.Error)
+		return false, ret.Error
+	}
+	return ret.RowsAffected > 0, nil
+}
+
+// DeleteItemsByIds deletes the user's items among ids; items of other users are left alone.
+func (s *ShoppingCartItemDaoImpl) DeleteItemsByIds(ctx context.Context, userId int, ids []int) (int64, error) {
+	ret := s.db.WithContext(ctx).Where("user_id = ? AND id IN ?", userId, ids).Delete(&model.ShoppingCartItem{})
+	if ret.Error != nil {
+		log.Logger.Errorf("ShoppingCartItemDao: DeleteItemsByIds: Failed to delete items: %v", ret.Error)
+		return 0, ret.Error
+	}
+	log.Logger.Infof("ShoppingCartItemDao: DeleteItemsByIds: Deleted %d items for user ID %d", ret.RowsAffected, userId)
+	return ret.RowsAffected, nil
+}
@@ -0,0 +1,82 @@
+package dao
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StockSubscriptionDao interface {
+	Subscribe(ctx context.Context, productId int, userId int) error
+	Unsubscribe(ctx context.Context, productId int, userId int) error
+	ListSubscriberIDs(ctx context.Context, productId int) ([]int, error)
+	DeleteSubscriptions(ctx context.Context, productId int, userIds []int) error
+}
+
+var (
+	stockSubscriptionDaoInstance StockSubscriptionDao
+	stockSubscriptionDaoSyncOnce sync.Once
+)
+
+func GetStockSubscriptionDao() StockSubscriptionDao {
+	stockSubscriptionDaoSyncOnce.Do(func() {
+		stockSubscriptionDaoInstance = &StockSubscriptionDaoImpl{
+			db: repository.DB,
+		}
+	})
+	return stockSubscriptionDaoInstance
+}
+
+type StockSubscriptionDaoImpl struct {
+	db *gorm.DB
+}
+
+// Subscribe 订阅到货通知，重复订阅不报错
+func (s *StockSubscriptionDaoImpl) Subscribe(ctx context.Context, productId int, userId int) error {
+	err := s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.StockSubscription{ProductID: productId, UserID: userId}).Error
+	if err != nil {
+		log.Logger.Errorf("StockSubscriptionDao: Subscribe: product %d, user %d: %v", productId, userId, err)
+	}
+	return err
+}
+
+// Unsubscribe 取消订阅，未订阅时不报错
+func (s *StockSubscriptionDaoImpl) Unsubscribe(ctx context.Context, productId int, userId int) error {
+	err := s.db.WithContext(ctx).Where("product_id = ? AND user_id = ?", productId, userId).
+		Delete(&model.StockSubscription{}).Error
+	if err != nil {
+		log.Logger.Errorf("StockSubscriptionDao: Unsubscribe: product %d, user %d: %v", productId, userId, err)
+	}
+	return err
+}
+
+// ListSubscriberIDs 按订阅先后返回商品的全部订阅用户
+func (s *StockSubscriptionDaoImpl) ListSubscriberIDs(ctx context.Context, productId int) ([]int, error) {
+	var userIds []int
+	err := s.db.WithContext(ctx).Model(&model.StockSubscription{}).Where("product_id = ?", productId).
+		Order("id asc").Pluck("user_id", &userIds).Error
+	if err != nil {
+		log.Logger.Errorf("StockSubscriptionDao: ListSubscriberIDs: product %d: %v", productId, err)
+		return nil, err
+	}
+	return userIds, nil
+}
+
+// DeleteSubscriptions 删除已通知用户的订阅，通知期间新增的订阅保留到下次补货
+func (s *StockSubscriptionDaoImpl) DeleteSubscriptions(ctx context.Context, productId int, userIds []int) error {
+	if len(userIds) == 0 {
+		return nil
+	}
+	err := s.db.WithContext(ctx).Where("product_id = ? AND user_id IN ?", productId, userIds).
+		Delete(&model.StockSubscription{}).Error
+	if err != nil {
+		log.Logger.Errorf("StockSubscriptionDao: DeleteSubscriptions: product %d: %v", productId, err)
+	}
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -33,8 +34,65 @@ func Init() {
 	}
 	err = DB.AutoMigrate(
 		&model.Product{},
+		&model.ProductTag{},
+		&model.Collection{},
+		&model.CollectionItem{},
+		&model.ProductStatusTransition{},
+		&model.ProductSchedule{},
+		&model.StockAdjustment{},
+		&model.InventoryLedgerEntry{},
+		&model.ProductPriceChange{},
+		&model.StockSubscription{},
+		&model.Coupon{},
+		&model.CouponRedemption{},
+		&model.CouponUserUsage{},
+		&model.CartCoupon{},
+		&model.Promotion{},
+		&model.ExchangeRate{},
+		&model.GuestCart{},
+		&model.SavedItem{},
 	)
 	if err != nil {
 		panic(err)
 	}
+	if err = backfillOpeningLedger(DB); err != nil {
+		panic(err)
+	}
+	if err = backfillOpeningPrices(DB); err != nil {
+		panic(err)
+	}
+}
+
+// backfillOpeningLedger 为库存流水上线前创建的商品补写一条初始流水，使流水合计等于当前库存，
+// 否则这些商品对账时永远不一致。只处理没有初始流水的商品，重复执行不会重复写入
+func backfillOpeningLedger(db *gorm.DB) error {
+	result := db.Exec(`INSERT INTO inventory_ledger (product_id, source, reference_id, delta, stock_after, operator_id, customer_id, created_at)
+		SELECT p.id, ?, '', p.stock - COALESCE((SELECT SUM(l.delta) FROM inventory_ledger l WHERE l.product_id = p.id), 0), p.stock, 0, 0, NOW()
+		FROM products p
+		WHERE NOT EXISTS (SELECT 1 FROM inventory_ledger l WHERE l.product_id = p.id AND l.source = ?)`,
+		model.InventorySourceInitial, model.InventorySourceInitial)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Logger.Infof("Backfilled opening inventory ledger entries for %d products", result.RowsAffected)
+	}
+	return nil
+}
+
+// backfillOpeningPrices 为价格记录上线前创建的商品补写一条初始价格记录 (OldPrice 为 0)，
+// 价格取第一次改价前的价格，没有改价时取当前价格，使这些商品在第一次改价前的价格也能查到。
+// 只处理没有初始价格记录的商品，重复执行不会重复写入
+func backfillOpeningPrices(db *gorm.DB) error {
+	result := db.Exec(`INSERT INTO product_price_changes (product_id, old_price, new_price, operator_id, effective_at, created_at)
+		SELECT p.id, 0, COALESCE((SELECT c.old_price FROM product_price_changes c WHERE c.product_id = p.id ORDER BY c.effective_at, c.id LIMIT 1), p.price), 0, p.created_at, NOW()
+		FROM products p
+		WHERE NOT EXISTS (SELECT 1 FROM product_price_changes c WHERE c.product_id = p.id AND c.old_price = 0)`)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Logger.Infof("Backfilled opening price changes for %d products", result.RowsAffected)
+	}
+	return nil
 }
@@ -0,0 +1,34 @@
+package model
+
+import "gorm.io/gorm"
+
+const (
+	CollectionStatusHidden  = 0
+	CollectionStatusVisible = 1
+)
+
+// Collection 商家维护的商品专题，可跨分类组织商品
+type Collection struct {
+	gorm.Model
+
+	Name    string `gorm:"type:varchar(255);not null"`
+	Desc    string `gorm:"type:text"`
+	PicInfo string `gorm:"type:text"`
+	Status  int32  `gorm:"type:int;not null;default:0"` // 0: 隐藏, 1: 用户可见
+}
+
+func (Collection) TableName() string {
+	return "collections"
+}
+
+// CollectionItem 专题与商品的关联关系，Position 越小越靠前
+type CollectionItem struct {
+	ID           int `gorm:"primaryKey;autoIncrement"`
+	CollectionID int `gorm:"not null;index:idx_collection_product,unique"`
+	ProductID    int `gorm:"not null;index:idx_collection_product,unique;index"`
+	Position     int `gorm:"not null;default:0"`
+}
+
+func (CollectionItem) TableName() string {
+	return "collection_items"
+}
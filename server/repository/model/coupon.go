@@ -0,0 +1,71 @@
+package model
+
+import "time"
+
+const (
+	CouponTypePercentage   = "percentage"
+	CouponTypeFixedAmount  = "fixed_amount"
+	CouponTypeFreeShipping = "free_shipping"
+)
+
+// Coupon is a promo code customers can apply to their cart.
+// Value is a percentage (1-100) for percentage coupons and an amount in cents for fixed amount coupons.
+type Coupon struct {
+	ID           int        `gorm:"primaryKey;autoIncrement"`
+	Code         string     `gorm:"type:varchar(64);not null;uniqueIndex"`
+	Type         string     `gorm:"type:varchar(32);not null"`
+	Value        int        `gorm:"not null;default:0"`
+	MaxDiscount  int        `gorm:"not null;default:0"` // cap for percentage coupons, 0 means no cap
+	MinSpend     int        `gorm:"not null;default:0"` // measured on the eligible items
+	StartsAt     *time.Time `gorm:"type:datetime"`
+	EndsAt       *time.Time `gorm:"type:datetime"`
+	UsageLimit   int        `gorm:"not null;default:0"` // total redemptions, 0 means unlimited
+	PerUserLimit int        `gorm:"not null;default:0"` // redemptions per customer, 0 means unlimited
+	UsedCount    int        `gorm:"not null;default:0"`
+	Categories   string     `gorm:"type:text"` // comma separated, empty means every category
+	ProductIDs   string     `gorm:"type:text"` // comma separated, empty means every product
+	Active       bool       `gorm:"not null;default:true"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime"`
+}
+
+func (Coupon) TableName() string {
+	return "coupons"
+}
+
+// CouponRedemption records one use of a coupon by a customer. An order redeems at most one coupon,
+// so the order ID makes redeeming the same order twice a no-op.
+type CouponRedemption struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	CouponID  int       `gorm:"not null;index:idx_coupon_user"`
+	UserID    int       `gorm:"not null;index:idx_coupon_user"`
+	OrderID   string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (CouponRedemption) TableName() string {
+	return "coupon_redemptions"
+}
+
+// CouponUserUsage counts a customer's redemptions of a coupon. Redeem bumps it with a conditional
+// update so the per-user limit holds under concurrent orders.
+type CouponUserUsage struct {
+	CouponID  int `gorm:"primaryKey;autoIncrement:false"`
+	UserID    int `gorm:"primaryKey;autoIncrement:false"`
+	UsedCount int `gorm:"not null;default:0"`
+}
+
+func (CouponUserUsage) TableName() string {
+	return "coupon_user_usages"
+}
+
+// CartCoupon is the code a customer applied to their cart.
+type CartCoupon struct {
+	UserID    int       `gorm:"primaryKey;autoIncrement:false"`
+	Code      string    `gorm:"type:varchar(64);not null"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (CartCoupon) TableName() string {
+	return "cart_coupons"
+}
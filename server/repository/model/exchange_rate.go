@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// ExchangeRate overrides the rate of a display currency from the rates file.
+// Rate is a decimal string to keep it exact, e.g. "0.74" US dollars per unit of the base currency.
+type ExchangeRate struct {
+	Currency   string    `gorm:"type:varchar(3);primaryKey"`
+	Rate       string    `gorm:"type:varchar(32);not null"`
+	Decimals   int       `gorm:"not null;default:2"`
+	OperatorID int       `gorm:"not null;default:0"`
+	UpdatedAt  time.Time `gorm:"not null"`
+}
+
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
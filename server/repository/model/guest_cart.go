@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// GuestCart is the cart of a visitor who has not logged in. Its items are ShoppingCartItem rows
+// whose UserID is the negated cart ID, so they never collide with a real user's rows.
+// Only the SHA-256 of the cart token is stored; the token itself is held by the client.
+type GuestCart struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	TokenHash string    `gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (GuestCart) TableName() string {
+	return "guest_carts"
+}
+
+// OwnerID is the UserID the cart's items are stored under.
+func (g *GuestCart) OwnerID() int {
+	return -g.ID
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	InventorySourceInitial     = "initial"      // 创建商品时的初始库存
+	InventorySourceMerchantSet = "merchant_set" // 商家直接设置库存
+	InventorySourceOrder       = "order"        // 下单扣减或取消订单回补
+	InventorySourceAdjustment  = "adjustment"   // 商家按增量调整库存
+)
+
+// InventoryLedgerEntry 库存流水，每次库存变化追加一条，只增不改。
+// 所有流水的 Delta 之和应等于商品当前库存
+type InventoryLedgerEntry struct {
+	ID          int       `gorm:"primaryKey;autoIncrement"`
+	ProductID   int       `gorm:"not null;index;index:idx_ledger_customer_product,priority:2"`
+	Source      string    `gorm:"type:varchar(32);not null"`
+	ReferenceID string    `gorm:"type:varchar(64);not null;default:''"` // 订单号或库存调整记录ID
+	Delta       int       `gorm:"not null"`
+	StockAfter  int       `gorm:"not null"`
+	OperatorID  int       `gorm:"not null;default:0"`
+	CustomerID  int       `gorm:"not null;default:0;index:idx_ledger_customer_product,priority:1"` // 下单顾客，仅订单流水记录，用于限购
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+func (InventoryLedgerEntry) TableName() string {
+	return "inventory_ledger"
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// ProductPriceChange 商品价格变更记录，每次改价追加一条，只增不改。
+// 创建商品时写入初始价格 (OldPrice 为 0)，某一时刻的生效价格为该时刻之前最后一条记录的 NewPrice
+type ProductPriceChange struct {
+	ID          int       `gorm:"primaryKey;autoIncrement"`
+	ProductID   int       `gorm:"not null;index:idx_price_change_product,priority:1"`
+	OldPrice    int64     `gorm:"type:int;not null"`
+	NewPrice    int64     `gorm:"type:int;not null"`
+	OperatorID  int       `gorm:"not null;default:0"` // 操作人 userID，0 表示系统操作
+	EffectiveAt time.Time `gorm:"not null;index:idx_price_change_product,priority:2"`
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+func (ProductPriceChange) TableName() string {
+	return "product_price_changes"
+}
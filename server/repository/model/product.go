@@ -1,6 +1,10 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Product struct {
 	gorm.Model
@@ -16,8 +20,15 @@ type Product struct {
 	Weight           string `gorm:"type:varchar(255)"`
 	Capacity         string `gorm:"type:varchar(255)"`
 	CareInstructions string `gorm:"type:text"`
-	Status           int32  `gorm:"type:int;not null"`           // 0: 未上架, 1: 已上架
+	Status           int32  `gorm:"type:int;not null"`           // 0: 下架, 1: 上架, 2: 草稿, 3: 待审核, 4: 已归档, 5: 已删除
 	Version          int64  `gorm:"type:int;not null;default:0"` // 用于乐观锁
+
+	LowStockThreshold int64 `gorm:"type:int;not null;default:0"` // 库存预警阈值，0 表示不预警
+	MaxPerCustomer    int64 `gorm:"type:int;not null;default:0"` // 每位顾客累计最多购买的数量，0 表示不限购
+
+	StockMode        string     `gorm:"type:varchar(16);not null;default:'normal'"` // normal, preorder: 预售, backorder: 缺货可下单
+	MaxBackorder     int64      `gorm:"type:int;not null;default:0"`                // 预售/缺货下单时库存最低可以扣到 -MaxBackorder
+	ExpectedShipDate *time.Time // 预售/缺货下单商品的预计发货日期
 }
 
 func (Product) TableName() string {
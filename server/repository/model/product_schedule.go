@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+const (
+	ProductScheduleActionPublish   = "publish"
+	ProductScheduleActionUnpublish = "unpublish"
+
+	ProductScheduleStatusPending  = 0 // 等待执行
+	ProductScheduleStatusDone     = 1 // 已执行
+	ProductScheduleStatusCanceled = 2 // 已取消
+	ProductScheduleStatusFailed   = 3 // 执行失败，不再重试
+)
+
+// ProductSchedule 定时上下架任务。副本领取任务时写入 ClaimToken 并在 ClaimedUntil 之前独占，
+// 副本异常退出后租约过期，任务会被其他副本重新领取
+type ProductSchedule struct {
+	ID           int       `gorm:"primaryKey;autoIncrement"`
+	ProductID    int       `gorm:"not null;index"`
+	Action       string    `gorm:"type:varchar(16);not null"`
+	ScheduledAt  time.Time `gorm:"not null;index:idx_schedule_due,priority:2"`
+	Status       int       `gorm:"not null;default:0;index:idx_schedule_due,priority:1"`
+	OperatorID   int       `gorm:"not null;default:0"`
+	ClaimToken   string    `gorm:"type:varchar(64);not null;default:''"`
+	ClaimedUntil *time.Time
+	LastError    string `gorm:"type:varchar(512);not null;default:''"`
+	Attempts     int    `gorm:"not null;default:0"` // 执行出错后释放重试的次数
+	ExecutedAt   *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (ProductSchedule) TableName() string {
+	return "product_schedules"
+}
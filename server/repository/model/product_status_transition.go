@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// ProductStatusTransition 商品状态变更记录
+type ProductStatusTransition struct {
+	ID         int       `gorm:"primaryKey;autoIncrement"`
+	ProductID  int       `gorm:"not null;index"`
+	FromStatus int32     `gorm:"type:int;not null"`
+	ToStatus   int32     `gorm:"type:int;not null"`
+	OperatorID int       `gorm:"not null;default:0"` // 操作人 userID，0 表示系统操作
+	CreatedAt  time.Time `gorm:"not null"`
+}
+
+func (ProductStatusTransition) TableName() string {
+	return "product_status_transitions"
+}
@@ -0,0 +1,11 @@
+package model
+
+type ProductTag struct {
+	ID        int    `gorm:"primaryKey;autoIncrement"`
+	ProductID int    `gorm:"not null;index:idx_product_tag,unique"`
+	Tag       string `gorm:"type:varchar(64);not null;index:idx_product_tag,unique;index"`
+}
+
+func (ProductTag) TableName() string {
+	return "product_tags"
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+const (
+	PromotionTypeSale   = "sale"   // percentage off the unit price, shown as the sale price
+	PromotionTypeBundle = "bundle" // buy BuyQuantity, get FreeQuantity of the cheapest items free
+	PromotionTypeTiered = "tiered" // percentage off once enough items are bought
+)
+
+// PromotionTier is one step of a tiered promotion.
+type PromotionTier struct {
+	MinQuantity int `json:"min_quantity"`
+	Percentage  int `json:"percentage"`
+}
+
+// Promotion is an automatic discount applied without a code while it is active.
+type Promotion struct {
+	ID           int             `gorm:"primaryKey;autoIncrement"`
+	Name         string          `gorm:"type:varchar(255);not null"`
+	Type         string          `gorm:"type:varchar(32);not null"`
+	Percentage   int             `gorm:"not null;default:0"` // sale promotions
+	BuyQuantity  int             `gorm:"not null;default:0"` // bundle promotions
+	FreeQuantity int             `gorm:"not null;default:0"` // bundle promotions
+	Tiers        []PromotionTier `gorm:"type:text;serializer:json"`
+	Categories   string          `gorm:"type:text"` // comma separated, empty means every category
+	ProductIDs   string          `gorm:"type:text"` // comma separated, empty means every product
+	StartsAt     time.Time       `gorm:"type:datetime;not null;index:idx_promotion_window"`
+	EndsAt       time.Time       `gorm:"type:datetime;not null;index:idx_promotion_window"`
+	Active       bool            `gorm:"not null;default:true"`
+	CreatedAt    time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time       `gorm:"autoUpdateTime"`
+}
+
+func (Promotion) TableName() string {
+	return "promotions"
+}
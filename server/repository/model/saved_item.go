@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	SavedListWishlist     = "wishlist"
+	SavedListSaveForLater = "save_for_later"
+)
+
+// SavedItem is a product a customer keeps outside the cart, either on the wishlist or saved for later.
+// A product appears at most once per list.
+type SavedItem struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	UserID    int       `gorm:"not null;index:idx_saved_user_list_product,unique"`
+	List      string    `gorm:"type:varchar(16);not null;index:idx_saved_user_list_product,unique"`
+	ProductID int       `gorm:"not null;index:idx_saved_user_list_product,unique"`
+	Quantity  int       `gorm:"not null;default:1"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+func (SavedItem) TableName() string {
+	return "saved_items"
+}
@@ -8,13 +8,15 @@ const (
 )
 
 type ShoppingCartItem struct {
-	ID           int       `gorm:"primaryKey;autoIncrement"`
-	UserID       int       `gorm:"not null;index:idx_user_product,unique"`
-	ProductID    int       `gorm:"not null;index:idx_user_product,unique"`
-	Quantity     int       `gorm:"not null;default:1"`
-	SelectStatus int       `gorm:"not null;default:0"`
-	CreatedAt    time.Time `gorm:"autoCreateTime"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+	ID           int `gorm:"primaryKey;autoIncrement"`
+	UserID       int `gorm:"not null;index:idx_user_product,unique"`
+	ProductID    int `gorm:"not null;index:idx_user_product,unique"`
+	Quantity     int `gorm:"not null;default:1"`
+	SelectStatus int `gorm:"not null;default:0"`
+	// unit price in the base currency when the customer last set the quantity, 0 when not known
+	PriceSnapshot int64     `gorm:"not null;default:0"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
 }
 
 func (ShoppingCartItem) TableName() string {
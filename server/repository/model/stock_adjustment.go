@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+const (
+	StockAdjustReasonRestock    = "restock"    // 补货，只能增加库存
+	StockAdjustReasonDamage     = "damage"     // 损耗，只能减少库存
+	StockAdjustReasonCorrection = "correction" // 盘点修正
+)
+
+// StockAdjustment 商家对库存的一次增减调整
+type StockAdjustment struct {
+	ID          int       `gorm:"primaryKey;autoIncrement"`
+	ProductID   int       `gorm:"not null;index"`
+	Delta       int       `gorm:"not null"`
+	StockBefore int       `gorm:"not null"`
+	StockAfter  int       `gorm:"not null"`
+	Reason      string    `gorm:"type:varchar(32);not null"`
+	Note        string    `gorm:"type:varchar(255);not null;default:''"`
+	OperatorID  int       `gorm:"not null;default:0"`
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+func (StockAdjustment) TableName() string {
+	return "stock_adjustments"
+}
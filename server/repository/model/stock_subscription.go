@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// StockSubscription 用户订阅的到货通知，商品补货后发送通知并删除
+type StockSubscription struct {
+	ID        int       `gorm:"primaryKey;autoIncrement"`
+	ProductID int       `gorm:"not null;index:idx_product_user,unique"`
+	UserID    int       `gorm:"not null;index:idx_product_user,unique;index"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (StockSubscription) TableName() string {
+	return "stock_subscriptions"
+}
@@ -0,0 +1,59 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/service"
+)
+
+const (
+	defaultInterval               = 10 * time.Second
+	defaultGuestCartPurgeInterval = time.Minute
+)
+
+// Init 启动后台定时任务，每个副本都会运行，任务通过数据库领取保证只执行一次。
+// 每个任务使用独立的 goroutine 和 ticker，一个任务出错或执行缓慢不会推迟其他任务
+func Init() {
+	interval, purgeInterval := defaultInterval, defaultGuestCartPurgeInterval
+	if conf := config.Config.SchedulerConfig; conf != nil {
+		if conf.IntervalSeconds > 0 {
+			interval = time.Duration(conf.IntervalSeconds) * time.Second
+		}
+		if conf.GuestCartPurgeIntervalSeconds > 0 {
+			purgeInterval = time.Duration(conf.GuestCartPurgeIntervalSeconds) * time.Second
+		}
+	}
+	go every(interval, runDueSchedules)
+	go every(purgeInterval, purgeExpiredGuestCarts)
+	log.Logger.Infof("Product scheduler started, interval: %v, guest cart purge interval: %v", interval, purgeInterval)
+}
+
+func every(interval time.Duration, job func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		job()
+	}
+}
+
+func runDueSchedules() {
+	finished := service.GetProductScheduleService().RunDueSchedules(context.Background())
+	if finished > 0 {
+		log.Logger.Infof("Product scheduler: %d schedules finished", finished)
+	}
+}
+
+// purgeExpiredGuestCarts 清理过期的游客购物车，每次最多一批，剩余的留到下一次
+func purgeExpiredGuestCarts() {
+	purged, err := service.GetCartService().PurgeExpiredGuestCarts(context.Background())
+	if err != nil {
+		log.Logger.Warnf("Guest cart purge: failed to purge expired guest carts: %v", err)
+		return
+	}
+	if purged > 0 {
+		log.Logger.Infof("Guest cart purge: %d expired guest carts purged", purged)
+	}
+}
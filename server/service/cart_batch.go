@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+)
+
+// The batch operations check every item first and report the ones that cannot be changed, then
+// write the rest in a single statement or transaction. A database error fails the whole batch.
+
+func newCartBatchResult(size int) *data.CartBatchResult {
+	return &data.CartBatchResult{Results: make([]data.CartItemResultVO, 0, size)}
+}
+
+func addBatchSuccess(ret *data.CartBatchResult, itemId int) {
+	ret.Succeeded += 1
+	ret.Results = append(ret.Results, data.CartItemResultVO{ItemID: itemId, Success: true})
+}
+
+func addBatchFailure(ret *data.CartBatchResult, itemId int, bizErr *types.BizError) {
+	ret.Results = append(ret.Results, data.CartItemResultVO{ItemID: itemId, Code: bizErr.Code, Message: bizErr.Message})
+}
+
+func cartItemNotFound(itemId int) *types.BizError {
+	return types.NewBizError(CartItemStatus_NotExist, fmt.Sprintf("cart item %d not found", itemId))
+}
+
+// userCartItems returns the user's cart items by ID.
+func (c *CartServiceImpl) userCartItems(ctx context.Context, userId int) (map[int]*model.ShoppingCartItem, error) {
+	items, err := c.cartItemDao.QueryItems(ctx, &model.ShoppingCartItem{UserID: userId})
+	if err != nil {
+		return nil, err
+	}
+	ret := make(map[int]*model.ShoppingCartItem, len(items))
+	for _, item := range items {
+		ret[item.ID] = item
+	}
+	return ret, nil
+}
+
+// SelectItems selects or unselects the given items, or every item in the cart when itemIds is empty.
+func (c *CartServiceImpl) SelectItems(ctx context.Context, userId int, itemIds []int, selected bool) (*data.CartBatchResult, error) {
+	items, err := c.userCartItems(ctx, userId)
+	if err != nil {
+		log.Logger.Errorf("CartService: SelectItems: Failed to query cart items: %v", err)
+		return nil, err
+	}
+	if len(itemIds) == 0 {
+		for id := range items {
+			itemIds = append(itemIds, id)
+		}
+		sort.Ints(itemIds)
+	}
+	status := model.CartItemStatusUnselected
+	if selected {
+		status = model.CartItemStatusSelected
+	}
+	ret := newCartBatchResult(len(itemIds))
+	toUpdate := make([]int, 0, len(itemIds))
+	for _, id := range itemIds {
+		if _, exists := items[id]; !exists {
+			addBatchFailure(ret, id, cartItemNotFound(id))
+			continue
+		}
+		toUpdate = append(toUpdate, id)
+		addBatchSuccess(ret, id)
+	}
+	if len(toUpdate) > 0 {
+		if _, err := c.cartItemDao.UpdateSelectStatus(ctx, userId, toUpdate, status); err != nil {
+			log.Logger.Errorf("CartService: SelectItems: Failed to update cart items: %v", err)
+			return nil, err
+		}
+	}
+	log.Logger.Infof("CartService: SelectItems: Set %d items of user %d selected=%v", len(toUpdate), userId, selected)
+	return ret, nil
+}
+
+// DeleteItems removes the given items from the user's cart.
+func (c *CartServiceImpl) DeleteItems(ctx context.Context, userId int, itemIds []int) (*data.CartBatchResult, error) {
+	items, err := c.userCartItems(ctx, userId)
+	if err != nil {
+		log.Logger.Errorf("CartService: DeleteItems: Failed to query cart items: %v", err)
+		return nil, err
+	}
+	ret := newCartBatchResult(len(itemIds))
+	toDelete := make([]int, 0, len(itemIds))
+	for _, id := range itemIds {
+		if _, exists := items[id]; !exists {
+			addBatchFailure(ret, id, cartItemNotFound(id))
+			continue
+		}
+		toDelete = append(toDelete, id)
+		addBatchSuccess(ret, id)
+	}
+	if len(toDelete) > 0 {
+		if _, err := c.cartItemDao.DeleteItemsByIds(ctx, userId, toDelete); err != nil {
+			log.Logger.Errorf("CartService: DeleteItems: Failed to delete cart items: %v", err)
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// UpdateQuantities sets the quantity of several items. Each quantity is checked against the sellable
+// stock and the limits like UpdateItem does.
+func (c *CartServiceImpl) UpdateQuantities(ctx context.Context, userId int, quantities []data.CartQuantityVO) (*data.CartBatchResult, error) {
+	items, err := c.userCartItems(ctx, userId)
+	if err != nil {
+		log.Logger.Errorf("CartService: UpdateQuantities: Failed to query cart items: %v", err)
+		return nil, err
+	}
+	productIds := make([]int, 0, len(quantities))
+	for _, q := range quantities {
+		if item, exists := items[q.ItemID]; exists {
+			productIds = append(productIds, item.ProductID)
+		}
+	}
+	productsById := make(map[int]*model.Product, len(productIds))
+	if len(productIds) > 0 {
+		products, err := c.productDao.GetProductByIDs(ctx, productIds)
+		if err != nil {
+			log.Logger.Errorf("CartService: UpdateQuantities: Failed to get products by IDs: %v", err)
+			return nil, err
+		}
+		for _, product := range products {
+			productsById[int(product.ID)] = product
+		}
+	}
+	// the new quantities are snapshotted at the price the customer sees now
+	promotions, promotionsErr := activePromotions(ctx, c.promotionDao)
+	if promotionsErr != nil {
+		log.Logger.Warnf("CartService: UpdateQuantities: Failed to load promotions: %v", promotionsErr)
+	}
+	ret := newCartBatchResult(len(quantities))
+	toUpdate := make([]*model.ShoppingCartItem, 0, len(quantities))
+	now := time.Now()
+	for _, q := range quantities {
+		item, exists := items[q.ItemID]
+		if !exists {
+			addBatchFailure(ret, q.ItemID, cartItemNotFound(q.ItemID))
+			continue
+		}
+		product := productsById[item.ProductID]
+		if product == nil || product.Status != ProductStatu_Online {
+			addBatchFailure(ret, q.ItemID, types.NewBizError(ProductCheckStatus_NotExist, "product not found or not available"))
+			continue
+		}
+		if sellableQuantity(product) < int64(q.Quantity) {
+			addBatchFailure(ret, q.ItemID, types.NewBizError(ProductCheckStatus_InsufficientStock, fmt.Sprintf("insufficient stock for product ID %d", item.ProductID)))
+			continue
+		}
+		if bizErr := c.checkQuantityLimits(ctx, userId, product, q.Quantity); bizErr != nil {
+			if bizErr.Code == ProductCheckStatus_DBError {
+				return nil, bizErr
+			}
+			addBatchFailure(ret, q.ItemID, bizErr)
+			continue
+		}
+		item.Quantity = q.Quantity
+		item.PriceSnapshot = 0
+		if promotionsErr == nil {
+			item.PriceSnapshot, _ = salePrice(product, promotions)
+		}
+		item.UpdatedAt = now
+		toUpdate = append(toUpdate, item)
+		addBatchSuccess(ret, q.ItemID)
+	}
+	if len(toUpdate) > 0 {
+		err := c.cartItemDao.UpdateItems(ctx, toUpdate)
+		if errors.Is(err, dao.ErrCartItemChanged) {
+			// an item was removed while the batch was checked, nothing has been written
+			return nil, types.NewBizError(CartItemStatus_NotExist, "cart items were changed, please reload the cart")
+		}
+		if err != nil {
+			log.Logger.Errorf("CartService: UpdateQuantities: Failed to update cart items: %v", err)
+			return nil, err
+		}
+	}
+	log.Logger.Infof("CartService: UpdateQuantities: Updated %d of %d items for user %d", len(toUpdate), len(quantities), userId)
+	return ret, nil
+}
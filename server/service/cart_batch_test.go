@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func batchTestCartItems() []*model.ShoppingCartItem {
+	return []*model.ShoppingCartItem{
+		{ID: 1, UserID: 1, ProductID: 1, Quantity: 1, SelectStatus: model.CartItemStatusUnselected},
+		{ID: 2, UserID: 1, ProductID: 2, Quantity: 1, SelectStatus: model.CartItemStatusSelected},
+		{ID: 3, UserID: 1, ProductID: 3, Quantity: 1, SelectStatus: model.CartItemStatusUnselected},
+	}
+}
+
+func expectBatchResults(t *testing.T, ret *data.CartBatchResult, expected map[int]int) {
+	t.Helper()
+	if len(ret.Results) != len(expected) {
+		t.Fatalf("Expected %d results, got %+v", len(expected), ret.Results)
+	}
+	succeeded := 0
+	for _, r := range ret.Results {
+		code, exists := expected[r.ItemID]
+		if !exists || r.Success != (code == 0) || r.Code != code {
+			t.Errorf("Unexpected result for item %d: %+v", r.ItemID, r)
+		}
+		if r.Success {
+			succeeded += 1
+		}
+	}
+	if ret.Succeeded != succeeded {
+		t.Errorf("Expected %d succeeded, got %d", succeeded, ret.Succeeded)
+	}
+}
+
+func TestCartService_SelectItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	cartService := &CartServiceImpl{cartItemDao: cartItemDao}
+
+	t.Run("Selects the whole cart when no IDs are given", func(t *testing.T) {
+		cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 1}).Return(batchTestCartItems(), nil)
+		cartItemDao.EXPECT().UpdateSelectStatus(ctx, 1, []int{1, 2, 3}, model.CartItemStatusSelected).Return(int64(3), nil)
+		ret, err := cartService.SelectItems(ctx, 1, nil, true)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expectBatchResults(t, ret, map[int]int{1: 0, 2: 0, 3: 0})
+	})
+
+	t.Run("Reports items that are not in the cart", func(t *testing.T) {
+		cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 1}).Return(batchTestCartItems(), nil)
+		cartItemDao.EXPECT().UpdateSelectStatus(ctx, 1, []int{2}, model.CartItemStatusUnselected).Return(int64(1), nil)
+		ret, err := cartService.SelectItems(ctx, 1, []int{2, 9}, false)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		expectBatchResults(t, ret, map[int]int{2: 0, 9: CartItemStatus_NotExist})
+	})
+
+	t.Run("Fails the batch on a database error", func(t *testing.T) {
+		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return(batchTestCartItems(), nil)
+		cartItemDao.EXPECT().UpdateSelectStatus(ctx, 1, []int{1, 3}, model.CartItemStatusSelected).Return(int64(0), errors.New("database error"))
+		if _, err := cartService.SelectItems(ctx, 1, []int{1, 3}, true); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+func TestCartService_DeleteItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	cartService := &CartServiceImpl{cartItemDao: cartItemDao}
+
+	cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 1}).Return(batchTestCartItems(), nil)
+	cartItemDao.EXPECT().DeleteItemsByIds(ctx, 1, []int{1, 3}).Return(int64(2), nil)
+	ret, err := cartService.DeleteItems(ctx, 1, []int{1, 5, 3})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expectBatchResults(t, ret, map[int]int{1: 0, 3: 0, 5: CartItemStatus_NotExist})
+}
+
+func TestCartService_UpdateQuantities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	cartService := &CartServiceImpl{cartItemDao: cartItemDao, productDao: productDao}
+
+	cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 1}).Return(batchTestCartItems(), nil)
+	productDao.EXPECT().GetProductByIDs(ctx, []int{1, 2, 3}).Return([]*model.Product{
+		{Model: gorm.Model{ID: 1}, Stock: 10, Status: ProductStatu_Online},
+		{Model: gorm.Model{ID: 2}, Stock: 2, Status: ProductStatu_Online},
+		{Model: gorm.Model{ID: 3}, Stock: 10, Status: ProductStatusUnpublished},
+	}, nil)
+	cartItemDao.EXPECT().UpdateItems(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, items []*model.ShoppingCartItem) error {
+		if len(items) != 1 || items[0].ID != 1 || items[0].Quantity != 5 {
+			t.Errorf("Expected only item 1 to be updated to 5, got %+v", items)
+		}
+		return nil
+	})
+	ret, err := cartService.UpdateQuantities(ctx, 1, []data.CartQuantityVO{
+		{ItemID: 1, Quantity: 5},
+		{ItemID: 2, Quantity: 3},
+		{ItemID: 3, Quantity: 1},
+		{ItemID: 4, Quantity: 1},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expectBatchResults(t, ret, map[int]int{
+		1: 0,
+		2: ProductCheckStatus_InsufficientStock,
+		3: ProductCheckStatus_NotExist,
+		4: CartItemStatus_NotExist,
+	})
+	if ret.Results[1].ItemID != 2 {
+		t.Errorf("Expected results in request order, got %+v", ret.Results)
+	}
+}
+
+func TestCartService_UpdateQuantities_ItemRemoved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	cartService := &CartServiceImpl{cartItemDao: cartItemDao, productDao: productDao}
+
+	cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 1}).Return(batchTestCartItems(), nil)
+	productDao.EXPECT().GetProductByIDs(ctx, []int{1}).Return([]*model.Product{
+		{Model: gorm.Model{ID: 1}, Stock: 10, Status: ProductStatu_Online},
+	}, nil)
+	// the item was deleted after it was read, the update must not bring it back
+	cartItemDao.EXPECT().UpdateItems(ctx, gomock.Any()).Return(dao.ErrCartItemChanged)
+	_, err := cartService.UpdateQuantities(ctx, 1, []data.CartQuantityVO{{ItemID: 1, Quantity: 5}})
+	var bizErr *types.BizError
+	if !errors.As(err, &bizErr) || bizErr.Code != CartItemStatus_NotExist {
+		t.Errorf("Expected cart item not exist error, got %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+)
+
+// Going over a product's per-customer limit is reported with ProductCheckStatus_PurchaseLimitExceeded,
+// the code stock deductions use for it.
+const (
+	CartLimitStatus_TooManyItems     = -13
+	CartLimitStatus_QuantityExceeded = -14
+)
+
+// defaultCartConfig applies when the cart section is missing from the configuration.
+var defaultCartConfig = &config.CartConfig{
+	MaxDistinctItems:   100,
+	MaxQuantityPerItem: 99,
+}
+
+func cartConfig() *config.CartConfig {
+	if config.Config.CartConfig != nil {
+		return config.Config.CartConfig
+	}
+	return defaultCartConfig
+}
+
+// customerAllowance returns how many units of product userId may still have in the cart: the
+// product's per-customer limit less what the customer has already ordered, or -1 when the product is
+// not limited. Guest carts have no orders, so they get the whole limit.
+func (c *CartServiceImpl) customerAllowance(ctx context.Context, userId int, product *model.Product) (int, error) {
+	if product.MaxPerCustomer <= 0 {
+		return -1, nil
+	}
+	if userId <= 0 {
+		return int(product.MaxPerCustomer), nil
+	}
+	purchased, err := c.productDao.SumCustomerOrderQuantity(ctx, int(product.ID), userId)
+	if err != nil {
+		return 0, err
+	}
+	return max(int(product.MaxPerCustomer)-purchased, 0), nil
+}
+
+// checkQuantityLimits checks the quantity an item would have against the configured per-item limit
+// and the product's per-customer limit.
+func (c *CartServiceImpl) checkQuantityLimits(ctx context.Context, userId int, product *model.Product, quantity int) *types.BizError {
+	if limit := cartConfig().MaxQuantityPerItem; limit > 0 && quantity > limit {
+		return types.NewBizError(CartLimitStatus_QuantityExceeded, fmt.Sprintf("at most %d of each product can be in the cart", limit))
+	}
+	allowance, err := c.customerAllowance(ctx, userId, product)
+	if err != nil {
+		log.Logger.Errorf("CartService: checkQuantityLimits: Failed to sum order quantity: %v", err)
+		return types.NewBizError(ProductCheckStatus_DBError, fmt.Sprintf("database error: %v", err))
+	}
+	if allowance >= 0 && quantity > allowance {
+		return types.NewBizError(ProductCheckStatus_PurchaseLimitExceeded,
+			fmt.Sprintf("product ID %d is limited to %d per customer, %d more can be bought", product.ID, product.MaxPerCustomer, allowance))
+	}
+	return nil
+}
+
+// checkCartSize is called before a product is added to the cart as a new item.
+func (c *CartServiceImpl) checkCartSize(ctx context.Context, userId int) *types.BizError {
+	limit := cartConfig().MaxDistinctItems
+	if limit <= 0 {
+		return nil
+	}
+	items, err := c.cartItemDao.QueryItems(ctx, &model.ShoppingCartItem{UserID: userId})
+	if err != nil {
+		log.Logger.Errorf("CartService: checkCartSize: Failed to query cart items: %v", err)
+		return types.NewBizError(ProductCheckStatus_DBError, fmt.Sprintf("database error: %v", err))
+	}
+	if len(items) >= limit {
+		return types.NewBizError(CartLimitStatus_TooManyItems, fmt.Sprintf("the cart can hold at most %d different products", limit))
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func withCartConfig(t *testing.T, conf *config.CartConfig) {
+	config.Config.CartConfig = conf
+	t.Cleanup(func() { config.Config.CartConfig = nil })
+}
+
+func TestCartService_AddItem_Limits(t *testing.T) {
+	ctx := context.Background()
+	onSale := func(id uint, maxPerCustomer int64) *model.Product {
+		return &model.Product{Model: gorm.Model{ID: id}, Stock: 500, Status: ProductStatu_Online, MaxPerCustomer: maxPerCustomer}
+	}
+
+	t.Run("rejects a new product when the cart is full", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+		cartService := &CartServiceImpl{cartItemDao: cartItemDao}
+		withCartConfig(t, &config.CartConfig{MaxDistinctItems: 2})
+
+		cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 1, ProductID: 3}).Return(nil, nil)
+		cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 1}).Return([]*model.ShoppingCartItem{{ID: 1}, {ID: 2}}, nil)
+
+		err := cartService.AddItem(ctx, &data.CartItemBasicVO{UserID: 1, ProductID: 3, Quantity: 1})
+		if err == nil || err.Code != CartLimitStatus_TooManyItems {
+			t.Errorf("Expected too many items error, got %v", err)
+		}
+	})
+
+	t.Run("rejects more than the configured quantity per item", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+		productDao := mocks.NewMockProductDao(ctrl)
+		cartService := &CartServiceImpl{cartItemDao: cartItemDao, productDao: productDao}
+
+		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return(nil, nil).Times(2)
+		productDao.EXPECT().GetProductByID(ctx, 1).Return(onSale(1, 0), nil)
+
+		err := cartService.AddItem(ctx, &data.CartItemBasicVO{UserID: 1, ProductID: 1, Quantity: defaultCartConfig.MaxQuantityPerItem + 1})
+		if err == nil || err.Code != CartLimitStatus_QuantityExceeded {
+			t.Errorf("Expected quantity exceeded error, got %v", err)
+		}
+	})
+
+	t.Run("counts what the customer has already ordered", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+		productDao := mocks.NewMockProductDao(ctrl)
+		cartService := &CartServiceImpl{cartItemDao: cartItemDao, productDao: productDao}
+
+		cartItemDao.EXPECT().GetItemById(ctx, 7).Return(&model.ShoppingCartItem{ID: 7, UserID: 1, ProductID: 1, Quantity: 1}, nil).Times(2)
+		productDao.EXPECT().GetProductByID(ctx, 1).Return(onSale(1, 3), nil).Times(2)
+		productDao.EXPECT().SumCustomerOrderQuantity(ctx, 1, 1).Return(2, nil).Times(2)
+
+		err := cartService.UpdateItem(ctx, &data.CartItemBasicVO{ID: 7, UserID: 1, ProductID: 1, Quantity: 2})
+		if err == nil || err.Code != ProductCheckStatus_PurchaseLimitExceeded {
+			t.Errorf("Expected purchase limit error, got %v", err)
+		}
+		cartItemDao.EXPECT().UpdateItem(ctx, gomock.Any()).Return(nil)
+		if err := cartService.UpdateItem(ctx, &data.CartItemBasicVO{ID: 7, UserID: 1, ProductID: 1, Quantity: 1}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("guest carts get the whole per-customer limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+		productDao := mocks.NewMockProductDao(ctrl)
+		cartService := &CartServiceImpl{cartItemDao: cartItemDao, productDao: productDao}
+
+		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return(nil, nil).Times(2)
+		productDao.EXPECT().GetProductByID(ctx, 1).Return(onSale(1, 3), nil)
+
+		err := cartService.AddItem(ctx, &data.CartItemBasicVO{UserID: -4, ProductID: 1, Quantity: 4})
+		if err == nil || err.Code != ProductCheckStatus_PurchaseLimitExceeded {
+			t.Errorf("Expected purchase limit error, got %v", err)
+		}
+	})
+}
+
+func TestCartService_UpdateQuantities_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	cartService := &CartServiceImpl{cartItemDao: cartItemDao, productDao: productDao}
+
+	cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 1}).Return([]*model.ShoppingCartItem{
+		{ID: 1, UserID: 1, ProductID: 1, Quantity: 1},
+		{ID: 2, UserID: 1, ProductID: 2, Quantity: 1},
+	}, nil)
+	productDao.EXPECT().GetProductByIDs(ctx, []int{1, 2}).Return([]*model.Product{
+		{Model: gorm.Model{ID: 1}, Price: 100, Stock: 10, Status: ProductStatu_Online, MaxPerCustomer: 2},
+		{Model: gorm.Model{ID: 2}, Price: 100, Stock: 10, Status: ProductStatu_Online},
+	}, nil)
+	productDao.EXPECT().SumCustomerOrderQuantity(ctx, 1, 1).Return(0, nil)
+	cartItemDao.EXPECT().UpdateItems(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, items []*model.ShoppingCartItem) error {
+		if len(items) != 1 || items[0].ID != 2 || items[0].Quantity != 5 {
+			t.Errorf("Expected only item 2 updated, got %+v", items)
+		}
+		return nil
+	})
+
+	ret, err := cartService.UpdateQuantities(ctx, 1, []data.CartQuantityVO{{ItemID: 1, Quantity: 3}, {ItemID: 2, Quantity: 5}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ret.Succeeded != 1 || ret.Results[0].Code != ProductCheckStatus_PurchaseLimitExceeded {
+		t.Errorf("Expected item 1 to fail on the purchase limit, got %+v", ret)
+	}
+}
+
+func TestCartService_MergeGuestCart_Limits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	guestCartDao := mocks.NewMockGuestCartDao(ctrl)
+	cartService := &CartServiceImpl{cartItemDao: cartItemDao, productDao: productDao, guestCartDao: guestCartDao}
+	withCartConfig(t, &config.CartConfig{MaxDistinctItems: 2, MaxQuantityPerItem: 5})
+
+	guestCart := &model.GuestCart{ID: 3, ExpiresAt: time.Now().Add(time.Hour)}
+	guestCartDao.EXPECT().GetGuestCartByTokenHash(ctx, hashCartToken("token")).Return(guestCart, nil)
+	cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: -3}).Return([]*model.ShoppingCartItem{
+		{ID: 1, UserID: -3, ProductID: 1, Quantity: 4}, // capped by the quantity per item
+		{ID: 2, UserID: -3, ProductID: 2, Quantity: 2}, // capped by the per-customer limit
+		{ID: 3, UserID: -3, ProductID: 3, Quantity: 1}, // no room in the cart
+	}, nil)
+	cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 5}).Return([]*model.ShoppingCartItem{
+		{ID: 10, UserID: 5, ProductID: 1, Quantity: 3},
+	}, nil)
+	productDao.EXPECT().GetProductByIDs(ctx, []int{1, 2, 3}).Return([]*model.Product{
+		{Model: gorm.Model{ID: 1}, Stock: 10, Status: ProductStatu_Online},
+		{Model: gorm.Model{ID: 2}, Stock: 10, Status: ProductStatu_Online, MaxPerCustomer: 3},
+		{Model: gorm.Model{ID: 3}, Stock: 10, Status: ProductStatu_Online},
+	}, nil)
+	productDao.EXPECT().SumCustomerOrderQuantity(ctx, 2, 5).Return(2, nil)
+	guestCartDao.EXPECT().MergeGuestCart(ctx, guestCart, gomock.Any()).DoAndReturn(func(ctx context.Context, cart *model.GuestCart, items []*model.ShoppingCartItem) error {
+		if len(items) != 2 {
+			t.Fatalf("Expected 2 items to save, got %d", len(items))
+		}
+		if items[0].Quantity != 5 || items[1].ProductID != 2 || items[1].Quantity != 1 {
+			t.Errorf("Expected product 1 at 5 and product 2 at 1, got %+v, %+v", items[0], items[1])
+		}
+		return nil
+	})
+
+	ret, err := cartService.MergeGuestCart(ctx, 5, "token")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := []data.CartMergeIssueVO{
+		{ProductID: 1, Requested: 4, Merged: 2, Reason: mergeReasonLimitReached},
+		{ProductID: 2, Requested: 2, Merged: 1, Reason: mergeReasonLimitReached},
+		{ProductID: 3, Requested: 1, Reason: mergeReasonCartFull},
+	}
+	if len(ret.Unmerged) != len(expected) {
+		t.Fatalf("Expected %d unmerged items, got %+v", len(expected), ret.Unmerged)
+	}
+	for i := range expected {
+		if ret.Unmerged[i] != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], ret.Unmerged[i])
+		}
+	}
+}
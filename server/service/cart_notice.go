@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+)
+
+// currentUnitPrice is what one unit of product costs now in the base currency, the price snapshotted
+// when the customer sets a quantity. It returns 0, no snapshot, when the running sales cannot be loaded,
+// rather than snapshot the regular price and report a false price change later.
+func (c *CartServiceImpl) currentUnitPrice(ctx context.Context, product *model.Product) int64 {
+	promotions, err := activePromotions(ctx, c.promotionDao)
+	if err != nil {
+		log.Logger.Warnf("CartService: currentUnitPrice: Failed to load promotions: %v", err)
+		return 0
+	}
+	price, _ := salePrice(product, promotions)
+	return price
+}
+
+func removedItemNotice(item *model.ShoppingCartItem, product *model.Product) data.CartNoticeVO {
+	notice := data.CartNoticeVO{
+		ItemID:      item.ID,
+		ProductID:   item.ProductID,
+		Type:        data.CartNotice_RemovedUnpublished,
+		OldQuantity: item.Quantity,
+	}
+	if product != nil {
+		notice.ProductName = product.Name
+	}
+	return notice
+}
+
+// reconcileCartItem brings item up to date with the product's current unit price and sellable stock,
+// and returns what changed as notices with prices in currency. It reports whether item was modified
+// and needs saving, which includes taking a first snapshot without a notice.
+// An item of a sold out product keeps its quantity; the cart shows it as out of stock instead.
+func reconcileCartItem(item *model.ShoppingCartItem, product *model.Product, price int64, currency *pricing.Currency) ([]data.CartNoticeVO, bool) {
+	var notices []data.CartNoticeVO
+	changed := false
+	if item.PriceSnapshot != price {
+		oldPrice, newPrice := currency.Convert(item.PriceSnapshot), currency.Convert(price)
+		if item.PriceSnapshot != 0 && oldPrice != newPrice {
+			noticeType := data.CartNotice_PriceIncreased
+			if newPrice < oldPrice {
+				noticeType = data.CartNotice_PriceDecreased
+			}
+			notices = append(notices, data.CartNoticeVO{
+				ItemID:      item.ID,
+				ProductID:   item.ProductID,
+				ProductName: product.Name,
+				Type:        noticeType,
+				OldPrice:    int(oldPrice),
+				NewPrice:    int(newPrice),
+			})
+		}
+		item.PriceSnapshot = price
+		changed = true
+	}
+	if sellable := sellableQuantity(product); sellable > 0 && int64(item.Quantity) > sellable {
+		notices = append(notices, data.CartNoticeVO{
+			ItemID:      item.ID,
+			ProductID:   item.ProductID,
+			ProductName: product.Name,
+			Type:        data.CartNotice_QuantityReduced,
+			OldQuantity: item.Quantity,
+			NewQuantity: int(sellable),
+		})
+		item.Quantity = int(sellable)
+		changed = true
+	}
+	return notices, changed
+}
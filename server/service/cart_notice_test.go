@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestReconcileCartItem(t *testing.T) {
+	product := &model.Product{Model: gorm.Model{ID: 1}, Name: "mug", Price: 1200, Stock: 3, Status: ProductStatu_Online}
+	testCases := []struct {
+		name     string
+		item     model.ShoppingCartItem
+		price    int64
+		notices  []string
+		changed  bool
+		quantity int
+	}{
+		{"unchanged", model.ShoppingCartItem{Quantity: 2, PriceSnapshot: 1200}, 1200, nil, false, 2},
+		{"first snapshot is silent", model.ShoppingCartItem{Quantity: 2}, 1200, nil, true, 2},
+		{"price increased", model.ShoppingCartItem{Quantity: 2, PriceSnapshot: 1000}, 1200, []string{data.CartNotice_PriceIncreased}, true, 2},
+		{"price decreased by a sale", model.ShoppingCartItem{Quantity: 2, PriceSnapshot: 1200}, 960, []string{data.CartNotice_PriceDecreased}, true, 2},
+		{"quantity reduced to stock", model.ShoppingCartItem{Quantity: 5, PriceSnapshot: 1000}, 1200,
+			[]string{data.CartNotice_PriceIncreased, data.CartNotice_QuantityReduced}, true, 3},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			item := tc.item
+			notices, changed := reconcileCartItem(&item, product, tc.price, pricing.Base())
+			if changed != tc.changed || item.Quantity != tc.quantity || item.PriceSnapshot != tc.price {
+				t.Errorf("Expected changed=%v quantity %d snapshot %d, got %v %+v", tc.changed, tc.quantity, tc.price, changed, item)
+			}
+			if len(notices) != len(tc.notices) {
+				t.Fatalf("Expected notices %v, got %+v", tc.notices, notices)
+			}
+			for i, notice := range notices {
+				if notice.Type != tc.notices[i] || notice.ProductName != "mug" {
+					t.Errorf("Expected a %s notice, got %+v", tc.notices[i], notice)
+				}
+			}
+		})
+	}
+
+	t.Run("sold out items keep their quantity", func(t *testing.T) {
+		soldOut := *product
+		soldOut.Stock = 0
+		item := model.ShoppingCartItem{Quantity: 2, PriceSnapshot: 1200}
+		if notices, changed := reconcileCartItem(&item, &soldOut, 1200, pricing.Base()); len(notices) != 0 || changed || item.Quantity != 2 {
+			t.Errorf("Expected no change, got %+v, %v", notices, changed)
+		}
+	})
+}
+
+func TestGetCartItems_Notices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	exchangeRateDao := mocks.NewMockExchangeRateDao(ctrl)
+	cartService := &CartServiceImpl{cartItemDao: cartItemDao, productDao: productDao, exchangeRateDao: exchangeRateDao}
+
+	exchangeRateDao.EXPECT().GetExchangeRate(ctx, "USD").Return(&model.ExchangeRate{Currency: "USD", Rate: "0.5", Decimals: 2}, nil)
+	cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 1}).Return([]*model.ShoppingCartItem{
+		{ID: 1, UserID: 1, ProductID: 1, Quantity: 4, SelectStatus: model.CartItemStatusSelected, PriceSnapshot: 1000},
+		{ID: 2, UserID: 1, ProductID: 2, Quantity: 1, SelectStatus: model.CartItemStatusSelected, PriceSnapshot: 500},
+		{ID: 3, UserID: 1, ProductID: 3, Quantity: 1, SelectStatus: model.CartItemStatusSelected, PriceSnapshot: 700},
+	}, nil)
+	// product 3 has been deleted
+	productDao.EXPECT().GetProductByIDs(ctx, []int{1, 2, 3}).Return([]*model.Product{
+		{Model: gorm.Model{ID: 1}, Name: "mug", Price: 1200, Stock: 2, Status: ProductStatu_Online},
+		{Model: gorm.Model{ID: 2}, Name: "vase", Price: 500, Stock: 2, Status: ProductStatusUnpublished},
+	}, nil)
+	// both writes happen before the cart is returned, so a second read does not report the notices again
+	cartItemDao.EXPECT().DeleteByProductIds(ctx, 1, []int{2, 3}).Return(nil)
+	// the write is conditional on the quantity the customer had when the cart was read
+	cartItemDao.EXPECT().ReconcileItem(ctx, gomock.Any(), 4).DoAndReturn(func(ctx context.Context, item *model.ShoppingCartItem, quantityBefore int) (bool, error) {
+		if item.ID != 1 || item.Quantity != 2 || item.PriceSnapshot != 1200 {
+			t.Errorf("Expected item 1 saved with the new price and quantity, got %+v", item)
+		}
+		return true, nil
+	})
+
+	result, err := cartService.GetCartItems(ctx, 1, "USD")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.CartItems) != 1 || result.CartItems[0].Quantity != 2 || result.CartItems[0].TotalPrice != 1200 {
+		t.Errorf("Expected 2 mugs at US$6, got %+v", result.CartItems)
+	}
+	expected := []data.CartNoticeVO{
+		{ItemID: 1, ProductID: 1, ProductName: "mug", Type: data.CartNotice_PriceIncreased, OldPrice: 500, NewPrice: 600},
+		{ItemID: 1, ProductID: 1, ProductName: "mug", Type: data.CartNotice_QuantityReduced, OldQuantity: 4, NewQuantity: 2},
+		{ItemID: 2, ProductID: 2, ProductName: "vase", Type: data.CartNotice_RemovedUnpublished, OldQuantity: 1},
+		{ItemID: 3, ProductID: 3, Type: data.CartNotice_RemovedUnpublished, OldQuantity: 1},
+	}
+	if len(result.Notices) != len(expected) {
+		t.Fatalf("Expected %d notices, got %+v", len(expected), result.Notices)
+	}
+	for i := range expected {
+		if result.Notices[i] != expected[i] {
+			t.Errorf("Expected notice %+v, got %+v", expected[i], result.Notices[i])
+		}
+	}
+}
+
+func TestGetCartItems_ReconcileFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	cartService := &CartServiceImpl{cartItemDao: cartItemDao, productDao: productDao}
+
+	cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 1}).Return([]*model.ShoppingCartItem{
+		{ID: 1, UserID: 1, ProductID: 1, Quantity: 1, PriceSnapshot: 1000},
+		{ID: 2, UserID: 1, ProductID: 2, Quantity: 1, PriceSnapshot: 500},
+	}, nil)
+	productDao.EXPECT().GetProductByIDs(ctx, []int{1, 2}).Return([]*model.Product{
+		{Model: gorm.Model{ID: 1}, Name: "mug", Price: 1200, Stock: 2, Status: ProductStatu_Online},
+		{Model: gorm.Model{ID: 2}, Name: "vase", Price: 600, Stock: 2, Status: ProductStatu_Online},
+	}, nil)
+	// a failed write does not stop the other items from being saved
+	gomock.InOrder(
+		cartItemDao.EXPECT().ReconcileItem(ctx, gomock.Any(), 1).Return(false, errors.New("database error")),
+		cartItemDao.EXPECT().ReconcileItem(ctx, gomock.Any(), 1).DoAndReturn(func(ctx context.Context, item *model.ShoppingCartItem, quantityBefore int) (bool, error) {
+			if item.ID != 2 || item.PriceSnapshot != 600 {
+				t.Errorf("Expected item 2 saved with the new price, got %+v", item)
+			}
+			return true, nil
+		}),
+	)
+
+	result, err := cartService.GetCartItems(ctx, 1, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Notices) != 2 {
+		t.Errorf("Expected 2 price notices, got %+v", result.Notices)
+	}
+}
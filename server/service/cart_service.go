@@ -10,6 +10,7 @@ import (
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
@@ -20,10 +21,26 @@ type CartService interface {
 	AddItem(ctx context.Context, item *data.CartItemBasicVO) *types.BizError
 	UpdateItem(ctx context.Context, item *data.CartItemBasicVO) *types.BizError
 	DeleteItem(ctx context.Context, itemId int, userId int) error
+	SelectItems(ctx context.Context, userId int, itemIds []int, selected bool) (*data.CartBatchResult, error)
+	DeleteItems(ctx context.Context, userId int, itemIds []int) (*data.CartBatchResult, error)
+	UpdateQuantities(ctx context.Context, userId int, quantities []data.CartQuantityVO) (*data.CartBatchResult, error)
 	GetCartSelectedItemCnt(ctx context.Context, userId int) (int, error)
-	GetCartItems(ctx context.Context, userId int) (*data.CartListVO, error)
+	GetCartItems(ctx context.Context, userId int, currency string) (*data.CartListVO, error)
 	DeleteItemByProductIds(ctx context.Context, userId int, productIds []int) error
-	EstimatePrice(ctx context.Context, userId int) (*data.CartPriceEstimateResult, error)
+	EstimatePrice(ctx context.Context, userId int, region string, currency string) (*data.CartPriceEstimateResult, error)
+	ApplyCoupon(ctx context.Context, userId int, code string) error
+	RemoveCoupon(ctx context.Context, userId int) error
+	RedeemOrderCoupon(ctx context.Context, userId int, orderId string, code string, lines []OrderLine) error
+	ReleaseRedeemedCoupon(ctx context.Context, orderId string) error
+	CreateGuestCart(ctx context.Context) (*data.GuestCartVO, error)
+	ResolveGuestCart(ctx context.Context, token string) (int, error)
+	MergeGuestCart(ctx context.Context, userId int, token string) (*data.CartMergeResult, error)
+	PurgeExpiredGuestCarts(ctx context.Context) (int, error)
+	ListSavedItems(ctx context.Context, userId int, list string, currency string) (*data.SavedListVO, error)
+	SaveItem(ctx context.Context, userId int, req *data.SaveItemRequest) (int, error)
+	DeleteSavedItem(ctx context.Context, userId int, id int) error
+	MoveToCart(ctx context.Context, userId int, savedItemId int) error
+	MoveFromCart(ctx context.Context, userId int, cartItemId int, list string) error
 }
 
 var (
@@ -33,17 +50,35 @@ var (
 
 func GetCartService() CartService {
 	cartServiceSyncOnce.Do(func() {
+		couponDao := dao.GetCouponDao()
+		promotionDao := dao.GetPromotionDao()
 		cartServiceInstance = &CartServiceImpl{
-			cartItemDao: dao.GetShoppingCartItemDao(),
-			productDao:  dao.GetProductDao(),
+			cartItemDao:     dao.GetShoppingCartItemDao(),
+			productDao:      dao.GetProductDao(),
+			couponDao:       couponDao,
+			promotionDao:    promotionDao,
+			exchangeRateDao: dao.GetExchangeRateDao(),
+			guestCartDao:    dao.GetGuestCartDao(),
+			savedItemDao:    dao.GetSavedItemDao(),
+			// promotions go first so coupons discount the promoted amount
+			pricer: pricing.NewDefaultPipeline(
+				&promotionDiscounter{promotionDao: promotionDao},
+				&couponDiscounter{couponDao: couponDao},
+			),
 		}
 	})
 	return cartServiceInstance
 }
 
 type CartServiceImpl struct {
-	cartItemDao dao.ShoppingCartItemDao
-	productDao  dao.ProductDao
+	cartItemDao     dao.ShoppingCartItemDao
+	productDao      dao.ProductDao
+	couponDao       dao.CouponDao
+	promotionDao    dao.PromotionDao
+	exchangeRateDao dao.ExchangeRateDao
+	guestCartDao    dao.GuestCartDao
+	savedItemDao    dao.SavedItemDao
+	pricer          *pricing.Pipeline
 }
 
 const (
@@ -71,18 +106,22 @@ func (c *CartServiceImpl) AddItem(ctx context.Context, item *data.CartItemBasicV
 		item.Selected = true
 		return c.UpdateItem(ctx, item)
 	}
-	bizErr := c.checkProductWithItem(ctx, item)
+	if bizErr := c.checkCartSize(ctx, item.UserID); bizErr != nil {
+		return bizErr
+	}
+	product, bizErr := c.checkProductWithItem(ctx, item)
 	if bizErr != nil {
-		log.Logger.Errorf("CartService: AddItem: Failed to check product with item: %v", err)
+		log.Logger.Errorf("CartService: AddItem: Failed to check product with item: %v", bizErr)
 		return bizErr
 	}
 	itemId, err := c.cartItemDao.CreateItem(ctx, &model.ShoppingCartItem{
-		UserID:       item.UserID,
-		ProductID:    item.ProductID,
-		Quantity:     item.Quantity,
-		SelectStatus: model.CartItemStatusSelected,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		UserID:        item.UserID,
+		ProductID:     item.ProductID,
+		Quantity:      item.Quantity,
+		SelectStatus:  model.CartItemStatusSelected,
+		PriceSnapshot: c.currentUnitPrice(ctx, product),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
 	})
 	if err != nil {
 		log.Logger.Errorf("CartService: AddItem: Failed to create cart item: %v", err)
@@ -119,8 +158,14 @@ func (c *CartServiceImpl) DeleteItemByProductIds(ctx context.Context, userId int
 	return nil
 }
 
-// GetCartItems implements CartService.
-func (c *CartServiceImpl) GetCartItems(ctx context.Context, userId int) (*data.CartListVO, error) {
+// GetCartItems lists the cart with prices in the display currency; an empty currency is the base currency.
+// Items of products no longer on sale are removed and quantities above the sellable stock are reduced.
+// Those changes, and price changes since the customer last set the quantity, are returned as notices.
+func (c *CartServiceImpl) GetCartItems(ctx context.Context, userId int, currency string) (*data.CartListVO, error) {
+	displayIn, err := displayCurrency(ctx, c.exchangeRateDao, currency)
+	if err != nil {
+		return nil, err
+	}
 	items, err := c.cartItemDao.QueryItems(ctx, &model.ShoppingCartItem{
 		UserID: userId,
 	})
@@ -128,71 +173,120 @@ func (c *CartServiceImpl) GetCartItems(ctx context.Context, userId int) (*data.C
 		log.Logger.Errorf("CartService: GetCartItems: Failed to query cart items: %v", err)
 		return nil, err
 	}
+	ret := &data.CartListVO{
+		CartItems: make([]data.CartItemDetailVO, 0),
+		Currency:  displayIn.Code,
+		Notices:   make([]data.CartNoticeVO, 0),
+	}
 	if len(items) == 0 {
 		log.Logger.Infof("CartService: GetCartItems: No items found for user ID %d", userId)
-		return &data.CartListVO{
-			CartItems: []data.CartItemDetailVO{},
-		}, nil
+		return ret, nil
 	}
 	productIds := make([]int, 0, len(items))
-	productId2Item := make(map[int]*model.ShoppingCartItem)
 	for _, item := range items {
 		productIds = append(productIds, item.ProductID)
-		productId2Item[item.ProductID] = item
 	}
 	products, err := c.productDao.GetProductByIDs(ctx, productIds)
 	if err != nil {
 		log.Logger.Errorf("CartService: GetCartItems: Failed to get products by IDs: %v", err)
 		return nil, err
 	}
-	ret := &data.CartListVO{
-		CartItems: make([]data.CartItemDetailVO, 0),
+	productsById := make(map[int]*model.Product, len(products))
+	for _, product := range products {
+		productsById[int(product.ID)] = product
 	}
+	promotions, err := activePromotions(ctx, c.promotionDao)
+	if err != nil {
+		log.Logger.Errorf("CartService: GetCartItems: Failed to load promotions: %v", err)
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
 	toDeleteProductIds := make([]int, 0)
-	for _, product := range products {
-		if product.Status != ProductStatu_Online {
-			toDeleteProductIds = append(toDeleteProductIds, int(product.ID))
+	toUpdate := make([]*model.ShoppingCartItem, 0)
+	quantitiesBefore := make([]int, 0)
+	for _, item := range items {
+		product := productsById[item.ProductID]
+		if product == nil || product.Status != ProductStatu_Online {
+			toDeleteProductIds = append(toDeleteProductIds, item.ProductID)
+			ret.Notices = append(ret.Notices, removedItemNotice(item, product))
 			continue
 		}
-		if item, exists := productId2Item[int(product.ID)]; exists {
-			cartItemDetail := buildCartItemDetail(product, item)
-			ret.CartItems = append(ret.CartItems, cartItemDetail)
-			if item.SelectStatus == model.CartItemStatusSelected {
-				ret.SelectedItemCount += 1
-				ret.SelectedPrice += cartItemDetail.TotalPrice
-			}
+		price, _ := salePrice(product, promotions)
+		quantityBefore := item.Quantity
+		notices, changed := reconcileCartItem(item, product, price, displayIn)
+		ret.Notices = append(ret.Notices, notices...)
+		if changed {
+			toUpdate = append(toUpdate, item)
+			quantitiesBefore = append(quantitiesBefore, quantityBefore)
+		}
+		cartItemDetail := buildCartItemDetail(product, item, promotions, displayIn)
+		ret.CartItems = append(ret.CartItems, cartItemDetail)
+		if item.SelectStatus == model.CartItemStatusSelected {
+			ret.SelectedItemCount += 1
+			ret.SelectedPrice += cartItemDetail.TotalPrice
 		}
 	}
+	// Persisted before returning so that a second read does not report the same notices again.
+	// Failures are only logged: the notices are reported again on the next read.
 	if len(toDeleteProductIds) > 0 {
-		go func() {
-			err := c.cartItemDao.DeleteByProductIds(context.Background(), userId, toDeleteProductIds)
-			log.Logger.Infof("CartService: GetCartItems: Deleted cart items with invalid products for user ID %d, err: %v", userId, err)
-		}()
+		err := c.cartItemDao.DeleteByProductIds(ctx, userId, toDeleteProductIds)
+		log.Logger.Infof("CartService: GetCartItems: Deleted cart items with invalid products for user ID %d, err: %v", userId, err)
+	}
+	// items the customer changed in the meantime are left as they are
+	updated := 0
+	for i, item := range toUpdate {
+		ok, err := c.cartItemDao.ReconcileItem(ctx, item, quantitiesBefore[i])
+		if err != nil {
+			log.Logger.Errorf("CartService: GetCartItems: Failed to update reconciled cart item %d for user ID %d: %v", item.ID, userId, err)
+			continue
+		}
+		if ok {
+			updated += 1
+		}
+	}
+	if len(toUpdate) > 0 {
+		log.Logger.Infof("CartService: GetCartItems: Updated %d of %d reconciled cart items for user ID %d", updated, len(toUpdate), userId)
 	}
-	sort.Slice(ret.CartItems, func(i, j int) bool {
-		return ret.CartItems[i].ID < ret.CartItems[j].ID
-	})
 	return ret, nil
 }
 
-func buildCartItemDetail(product *model.Product, item *model.ShoppingCartItem) data.CartItemDetailVO {
+// buildCartItemDetail prices the item at the sale price when a promotion is running. The unit price is
+// converted to the display currency before it is multiplied, so the total matches what the item shows.
+func buildCartItemDetail(product *model.Product, item *model.ShoppingCartItem, promotions []*model.Promotion, currency *pricing.Currency) data.CartItemDetailVO {
+	price, promotion := salePrice(product, promotions)
+	price = currency.Convert(price)
 	ret := data.CartItemDetailVO{
 		ID: item.ID,
 		ProductInfo: types.ProductSimplifiedInfo{
 			ID:       int(product.ID),
 			Name:     product.Name,
 			Category: product.Category,
-			Price:    product.Price,
+			Price:    currency.Convert(product.Price),
 			Stock:    product.Stock,
 			PicInfo:  product.PicInfo,
+			Currency: currency.Code,
+
+			Availability:     productAvailability(product),
+			ExpectedShipDate: expectedShipDate(product),
 		},
 		Quantity:   item.Quantity,
-		TotalPrice: int(product.Price) * item.Quantity,
+		TotalPrice: int(price) * item.Quantity,
 		Selected:   item.SelectStatus == model.CartItemStatusSelected,
 	}
-	ret.Status = data.CartItemStatus_Normal
-	if item.Quantity > int(product.Stock) {
+	if promotion != nil {
+		ret.ProductInfo.SalePrice = price
+		ret.ProductInfo.PromotionName = promotion.Name
+	}
+	// items beyond the stock on hand ship on the expected ship date
+	switch {
+	case int64(item.Quantity) > sellableQuantity(product):
 		ret.Status = data.CartItemStatus_OutOfStock
+	case int64(item.Quantity) > product.Stock || product.StockMode == StockModePreorder:
+		ret.Status = data.CartItemStatus_Backorder
+	default:
+		ret.Status = data.CartItemStatus_Normal
 	}
 	return ret
 }
@@ -224,11 +318,12 @@ func (c *CartServiceImpl) UpdateItem(ctx context.Context, item *data.CartItemBas
 		log.Logger.Errorf("CartService: UpdateItem: Item not found or does not belong to user")
 		return types.NewBizError(CartItemStatus_NotExist, "cart item not found or does not belong to user")
 	}
-	bizErr := c.checkProductWithItem(ctx, item)
+	product, bizErr := c.checkProductWithItem(ctx, item)
 	if bizErr != nil {
 		return bizErr
 	}
 	existingItems.Quantity = item.Quantity
+	existingItems.PriceSnapshot = c.currentUnitPrice(ctx, product)
 	if item.Selected {
 		existingItems.SelectStatus = model.CartItemStatusSelected
 	} else {
@@ -236,6 +331,9 @@ func (c *CartServiceImpl) UpdateItem(ctx context.Context, item *data.CartItemBas
 	}
 	existingItems.UpdatedAt = time.Now()
 	err = c.cartItemDao.UpdateItem(ctx, existingItems)
+	if errors.Is(err, dao.ErrCartItemChanged) {
+		return types.NewBizError(CartItemStatus_NotExist, "cart item not found or does not belong to user")
+	}
 	if err != nil {
 		log.Logger.Errorf("CartService: UpdateItem: Failed to update cart item: %v", err)
 		return types.NewBizError(ProductCheckStatus_DBError, fmt.Sprintf("database error: %v", err))
@@ -244,25 +342,37 @@ func (c *CartServiceImpl) UpdateItem(ctx context.Context, item *data.CartItemBas
 	return nil
 }
 
-func (c *CartServiceImpl) checkProductWithItem(ctx context.Context, item *data.CartItemBasicVO) *types.BizError {
+// checkProductWithItem returns the product when it is on sale and can be bought in the item's quantity,
+// which is also checked against the cart and purchase limits.
+func (c *CartServiceImpl) checkProductWithItem(ctx context.Context, item *data.CartItemBasicVO) (*model.Product, *types.BizError) {
 	product, err := c.productDao.GetProductByID(ctx, item.ProductID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return types.NewBizError(ProductCheckStatus_NotExist, fmt.Sprintf("product not found with ID: %d", item.ProductID))
+			return nil, types.NewBizError(ProductCheckStatus_NotExist, fmt.Sprintf("product not found with ID: %d", item.ProductID))
 		}
 		log.Logger.Errorf("CartService: UpdateItem: Failed to get product by ID: %v", err)
-		return types.NewBizError(ProductCheckStatus_DBError, fmt.Sprintf("database error: %v", err))
+		return nil, types.NewBizError(ProductCheckStatus_DBError, fmt.Sprintf("database error: %v", err))
 	}
 	if product == nil || product.Status != ProductStatu_Online {
-		return types.NewBizError(ProductCheckStatus_NotExist, "product not found or not available")
+		return nil, types.NewBizError(ProductCheckStatus_NotExist, "product not found or not available")
 	}
-	if product.Stock < int64(item.Quantity) {
-		return types.NewBizError(ProductCheckStatus_InsufficientStock, fmt.Sprintf("insufficient stock for product ID %d", item.ProductID))
+	if sellableQuantity(product) < int64(item.Quantity) {
+		return nil, types.NewBizError(ProductCheckStatus_InsufficientStock, fmt.Sprintf("insufficient stock for product ID %d", item.ProductID))
 	}
-	return nil
+	if bizErr := c.checkQuantityLimits(ctx, item.UserID, product, item.Quantity); bizErr != nil {
+		return nil, bizErr
+	}
+	return product, nil
 }
 
-func (c *CartServiceImpl) EstimatePrice(ctx context.Context, userId int) (*data.CartPriceEstimateResult, error) {
+// EstimatePrice prices the selected items through the pricing pipeline for region and shows the
+// result in the display currency. Empty region and currency use the configured defaults. The result
+// reports whether the coupon applied to the cart took effect.
+func (c *CartServiceImpl) EstimatePrice(ctx context.Context, userId int, region string, currency string) (*data.CartPriceEstimateResult, error) {
+	displayIn, err := displayCurrency(ctx, c.exchangeRateDao, currency)
+	if err != nil {
+		return nil, err
+	}
 	items, err := c.cartItemDao.QueryItems(ctx, &model.ShoppingCartItem{
 		UserID:       userId,
 		SelectStatus: model.CartItemStatusSelected,
@@ -271,7 +381,7 @@ func (c *CartServiceImpl) EstimatePrice(ctx context.Context, userId int) (*data.
 		log.Logger.Errorf("CartService: EstimatePrice: Failed to query cart items: %v", err)
 		return nil, err
 	}
-	ret := &data.CartPriceEstimateResult{}
+	ret := &data.CartPriceEstimateResult{Currency: displayIn.Code, Lines: []data.PriceLine{}}
 	if len(items) == 0 {
 		log.Logger.Infof("CartService: EstimatePrice: No items found for user ID %d", userId)
 		return ret, nil
@@ -287,35 +397,81 @@ func (c *CartServiceImpl) EstimatePrice(ctx context.Context, userId int) (*data.
 		log.Logger.Errorf("CartService: EstimatePrice: Failed to get products by IDs: %v", err)
 		return nil, err
 	}
+	promotions, err := activePromotions(ctx, c.promotionDao)
+	if err != nil {
+		log.Logger.Errorf("CartService: EstimatePrice: Failed to load promotions: %v", err)
+		return nil, err
+	}
+	pricingItems := make([]*pricing.Item, 0, len(products))
 	for _, product := range products {
 		if product.Status != ProductStatu_Online {
 			continue
 		}
 		if item, exists := productId2Item[int(product.ID)]; exists {
 			if item.SelectStatus == model.CartItemStatusSelected {
-				ret.ProductPrice += int(product.Price) * item.Quantity
+				price, _ := salePrice(product, promotions)
+				pricingItems = append(pricingItems, &pricing.Item{
+					ProductID: int(product.ID),
+					Category:  product.Category,
+					UnitPrice: int(price),
+					Quantity:  item.Quantity,
+				})
 			}
 		}
 	}
-	ret.ShippingPrice = getShipmentPrice(ret.ProductPrice)
-	ret.Tax = getTaxPrice(ret.ProductPrice)
-	ret.Total = ret.ShippingPrice + ret.Tax + ret.ProductPrice
-	return ret, nil
-}
-
-const (
-	freeShipPrice = 30000 // $300.00 in cents
-	shipPrice     = 800    // $8.00 in cents
-)
-
-func getTaxPrice(productPrice int) int {
-	return productPrice * 9 / 100
+	couponCode := ""
+	if c.couponDao != nil {
+		couponCode, err = c.couponDao.GetCartCoupon(ctx, userId)
+		if err != nil {
+			log.Logger.Errorf("CartService: EstimatePrice: Failed to get coupon for user ID %d: %v", userId, err)
+			return nil, err
+		}
+	}
+	pricer := c.pricer
+	if pricer == nil {
+		pricer = pricing.NewDefaultPipeline()
+	}
+	quote, err := pricer.Quote(ctx, &pricing.Cart{
+		UserID:     userId,
+		Region:     region,
+		CouponCode: couponCode,
+		Items:      pricingItems,
+	})
+	if err != nil {
+		log.Logger.Warnf("CartService: EstimatePrice: Failed to price cart for user ID %d: %v", userId, err)
+		return nil, err
+	}
+	return buildPriceEstimateResult(quote.Convert(displayIn)), nil
 }
 
-func getShipmentPrice(productPrice int) int {
-	if productPrice > freeShipPrice {
-		return 0
-	} else {
-		return shipPrice
+func buildPriceEstimateResult(quote *pricing.Quote) *data.CartPriceEstimateResult {
+	ret := &data.CartPriceEstimateResult{
+		Region:        quote.Region,
+		Currency:      quote.Currency,
+		ProductPrice:  quote.Subtotal,
+		Discount:      quote.Discount,
+		ShippingPrice: quote.Shipping,
+		Tax:           quote.Tax,
+		Total:         quote.Total(),
+		Lines:         make([]data.PriceLine, 0, len(quote.Lines)),
+	}
+	for _, line := range quote.Lines {
+		ret.Lines = append(ret.Lines, data.PriceLine{
+			Type:        line.Type,
+			Code:        line.Code,
+			Description: line.Description,
+			Amount:      line.Amount,
+		})
+	}
+	if quote.CouponCode != "" {
+		ret.Coupon = &data.AppliedCouponVO{Code: quote.CouponCode, Applied: true}
+		for _, rejection := range quote.Rejections {
+			if rejection.Code == quote.CouponCode {
+				ret.Coupon.Applied = false
+				ret.Coupon.RejectReason = rejection.Reason
+				break
+			}
+		}
 	}
+	return ret
 }
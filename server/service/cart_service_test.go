@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
 	"github.com/golang/mock/gomock"
@@ -48,7 +49,7 @@ func TestAddItem(t *testing.T) {
 			Quantity:  2,
 		}
 		ctx := context.Background()
-		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return([]*model.ShoppingCartItem{}, nil)
+		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return([]*model.ShoppingCartItem{}, nil).Times(2) // existing item, then cart size
 		product := &model.Product{
 			Model:  gorm.Model{ID: uint(item.ProductID)},
 			Stock:  10,
@@ -119,7 +120,7 @@ func TestAddItem(t *testing.T) {
 			ProductID: 9999, // Assuming this product does not exist
 			Quantity:  1,
 		}
-		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return([]*model.ShoppingCartItem{}, nil)
+		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return([]*model.ShoppingCartItem{}, nil).Times(2)
 		product := &model.Product{
 			Model: gorm.Model{ID: uint(item.ProductID)},
 			Stock: int64(item.Quantity) + 10,
@@ -145,7 +146,7 @@ func TestAddItem(t *testing.T) {
 			Quantity:  100, // Assuming the stock is less than 100
 		}
 
-		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return([]*model.ShoppingCartItem{}, nil)
+		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return([]*model.ShoppingCartItem{}, nil).Times(2)
 		product := &model.Product{
 			Model:  gorm.Model{ID: uint(item.ProductID)},
 			Stock:  int64(item.Quantity) - 10,
@@ -264,8 +265,8 @@ func TestGetCartItems(t *testing.T) {
 		userId := 1
 
 		cartItems := []*model.ShoppingCartItem{
-			{ID: 10, UserID: userId, ProductID: 1, Quantity: 2, SelectStatus: model.CartItemStatusSelected},
-			{ID: 2, UserID: userId, ProductID: 2, Quantity: 1, SelectStatus: model.CartItemStatusUnselected},
+			{ID: 10, UserID: userId, ProductID: 1, Quantity: 2, SelectStatus: model.CartItemStatusSelected, PriceSnapshot: 100},
+			{ID: 2, UserID: userId, ProductID: 2, Quantity: 1, SelectStatus: model.CartItemStatusUnselected, PriceSnapshot: 200},
 		}
 		cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: userId}).Return(cartItems, nil)
 
@@ -275,7 +276,7 @@ func TestGetCartItems(t *testing.T) {
 		}
 		productDao.EXPECT().GetProductByIDs(ctx, []int{1, 2}).Return(products, nil)
 
-		result, err := cartService.GetCartItems(ctx, userId)
+		result, err := cartService.GetCartItems(ctx, userId, "")
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
 		}
@@ -308,7 +309,7 @@ func TestGetCartItems(t *testing.T) {
 
 		cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: userId}).Return(nil, errors.New("database error"))
 
-		_, err := cartService.GetCartItems(ctx, userId)
+		_, err := cartService.GetCartItems(ctx, userId, "")
 		if err == nil {
 			t.Errorf("Expected error, got none")
 		}
@@ -326,7 +327,7 @@ func TestGetCartItems(t *testing.T) {
 
 		cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: userId}).Return([]*model.ShoppingCartItem{}, nil)
 
-		result, err := cartService.GetCartItems(ctx, userId)
+		result, err := cartService.GetCartItems(ctx, userId, "")
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
 		}
@@ -361,7 +362,7 @@ func TestGetCartItems(t *testing.T) {
 			return nil
 		})
 
-		result, err := cartService.GetCartItems(ctx, userId)
+		result, err := cartService.GetCartItems(ctx, userId, "")
 		wg.Wait()
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
@@ -369,6 +370,9 @@ func TestGetCartItems(t *testing.T) {
 		if len(result.CartItems) != 0 {
 			t.Errorf("Expected 0 cart items, got %d", len(result.CartItems))
 		}
+		if len(result.Notices) != 1 || result.Notices[0].Type != data.CartNotice_RemovedUnpublished || result.Notices[0].ItemID != 1 {
+			t.Errorf("Expected a removed notice for item 1, got %+v", result.Notices)
+		}
 	})
 }
 func TestGetCartSelectedItemCnt(t *testing.T) {
@@ -620,11 +624,12 @@ func TestEstimatePrice(t *testing.T) {
 		}
 		productDao.EXPECT().GetProductByIDs(ctx, []int{1, 2}).Return(products, nil)
 
-		result, err := cartService.EstimatePrice(ctx, userId)
+		result, err := cartService.EstimatePrice(ctx, userId, "", "")
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
 		}
-		expectedTotal := 100*2 + 200*1 + getTaxPrice(100*2+200*1) + getShipmentPrice(100*2+200*1)
+		// 9% tax and $8 shipping under the default pricing rules
+		expectedTotal := 100*2 + 200*1 + 36 + 800
 		if result.Total != expectedTotal {
 			t.Errorf("Expected total %d, got %d", expectedTotal, result.Total)
 		}
@@ -645,7 +650,7 @@ func TestEstimatePrice(t *testing.T) {
 			SelectStatus: model.CartItemStatusSelected,
 		}).Return([]*model.ShoppingCartItem{}, nil)
 
-		result, err := cartService.EstimatePrice(ctx, userId)
+		result, err := cartService.EstimatePrice(ctx, userId, "", "")
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
 		}
@@ -669,7 +674,7 @@ func TestEstimatePrice(t *testing.T) {
 			SelectStatus: model.CartItemStatusSelected,
 		}).Return(nil, errors.New("database error"))
 
-		_, err := cartService.EstimatePrice(ctx, userId)
+		_, err := cartService.EstimatePrice(ctx, userId, "", "")
 		if err == nil {
 			t.Errorf("Expected error, got none")
 		}
@@ -700,13 +705,80 @@ func TestEstimatePrice(t *testing.T) {
 		}
 		productDao.EXPECT().GetProductByIDs(ctx, []int{1, 2}).Return(products, nil)
 
-		result, err := cartService.EstimatePrice(ctx, userId)
+		result, err := cartService.EstimatePrice(ctx, userId, "", "")
 		if err != nil {
 			t.Errorf("Expected no error, got %v", err)
 		}
-		expectedTotal := 100*2 + getTaxPrice(100*2) + getShipmentPrice(100*2)
+		expectedTotal := 100*2 + 18 + 800
 		if result.Total != expectedTotal {
 			t.Errorf("Expected total %d, got %d", expectedTotal, result.Total)
 		}
 	})
 }
+
+func TestAddItem_Backorder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	cartService := &CartServiceImpl{
+		cartItemDao: cartItemDao,
+		productDao:  productDao,
+	}
+	ctx := context.Background()
+
+	t.Run("AddItem allows quantity within the backorder allowance", func(t *testing.T) {
+		item := &data.CartItemBasicVO{UserID: 1, ProductID: 1, Quantity: 5}
+		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return([]*model.ShoppingCartItem{}, nil).Times(2)
+		productDao.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{
+			Model:        gorm.Model{ID: 1},
+			Stock:        2,
+			Status:       ProductStatu_Online,
+			StockMode:    StockModeBackorder,
+			MaxBackorder: 3,
+		}, nil)
+		cartItemDao.EXPECT().CreateItem(ctx, gomock.Any()).Return(1, nil)
+
+		if err := cartService.AddItem(ctx, item); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("AddItem rejects quantity beyond the backorder allowance", func(t *testing.T) {
+		item := &data.CartItemBasicVO{UserID: 1, ProductID: 2, Quantity: 6}
+		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return([]*model.ShoppingCartItem{}, nil).Times(2)
+		productDao.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{
+			Model:        gorm.Model{ID: 2},
+			Stock:        -1,
+			Status:       ProductStatu_Online,
+			StockMode:    StockModePreorder,
+			MaxBackorder: 6,
+		}, nil)
+
+		err := cartService.AddItem(ctx, item)
+		if err == nil || err.Code != ProductCheckStatus_InsufficientStock {
+			t.Errorf("Expected insufficient stock error, got %v", err)
+		}
+	})
+}
+
+func TestBuildCartItemDetail_Availability(t *testing.T) {
+	product := &model.Product{Model: gorm.Model{ID: 1}, Stock: 2, StockMode: StockModeBackorder, MaxBackorder: 3}
+	testCases := []struct {
+		quantity int
+		status   int
+	}{
+		{2, data.CartItemStatus_Normal},
+		{4, data.CartItemStatus_Backorder},
+		{6, data.CartItemStatus_OutOfStock},
+	}
+	for _, tc := range testCases {
+		detail := buildCartItemDetail(product, &model.ShoppingCartItem{ProductID: 1, Quantity: tc.quantity}, nil, pricing.Base())
+		if detail.Status != tc.status {
+			t.Errorf("quantity %d: expected status %d, got %d", tc.quantity, tc.status, detail.Status)
+		}
+		if detail.ProductInfo.Availability != AvailabilityInStock {
+			t.Errorf("Expected availability %s, got %s", AvailabilityInStock, detail.ProductInfo.Availability)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"gorm.io/gorm"
+)
+
+// CollectionService 管理商品标签与商家专题
+type CollectionService interface {
+	CreateCollection(ctx context.Context, req *types.SaveCollectionRequest) (collectionId int, err error)
+	UpdateCollection(ctx context.Context, id int, req *types.SaveCollectionRequest) error
+	DeleteCollection(ctx context.Context, id int) error
+	GetCollection(ctx context.Context, id int, isCustomer bool) (*types.CollectionInfo, error)
+	ListCollections(ctx context.Context, isCustomer bool) ([]*types.CollectionInfo, error)
+	SetCollectionProducts(ctx context.Context, id int, productIds []int) error
+	GetCollectionProductIDs(ctx context.Context, id int) ([]int, error)
+
+	SetProductTags(ctx context.Context, productId int, tags []string) ([]string, error)
+	GetProductTags(ctx context.Context, productId int) ([]string, error)
+}
+
+var (
+	collectionServiceInstance CollectionService
+	collectionServiceSyncOnce sync.Once
+)
+
+func GetCollectionService() CollectionService {
+	collectionServiceSyncOnce.Do(func() {
+		collectionServiceInstance = &CollectionServiceImpl{
+			collectionDao: dao.GetCollectionDao(),
+			productTagDao: dao.GetProductTagDao(),
+			productDao:    dao.GetProductDao(),
+		}
+	})
+	return collectionServiceInstance
+}
+
+type CollectionServiceImpl struct {
+	collectionDao dao.CollectionDao
+	productTagDao dao.ProductTagDao
+	productDao    dao.ProductDao
+}
+
+const (
+	CollectionCheckStatus_NotExist        = -20
+	CollectionCheckStatus_InvalidParam    = -21
+	CollectionCheckStatus_InvalidProducts = -22
+
+	maxTagsPerProduct = 20
+	maxTagLength      = 64
+)
+
+// CreateCollection implements CollectionService.
+func (c *CollectionServiceImpl) CreateCollection(ctx context.Context, req *types.SaveCollectionRequest) (int, error) {
+	if err := checkCollectionRequest(req); err != nil {
+		return -1, err
+	}
+	id, err := c.collectionDao.CreateCollection(ctx, &model.Collection{
+		Name:    strings.TrimSpace(req.Name),
+		Desc:    req.Desc,
+		PicInfo: req.PicInfo,
+		Status:  req.Status,
+	})
+	if err != nil {
+		log.Logger.Errorf("CollectionService: CreateCollection: Failed to create collection: %v", err)
+		return -1, err
+	}
+	return id, nil
+}
+
+// UpdateCollection implements CollectionService.
+func (c *CollectionServiceImpl) UpdateCollection(ctx context.Context, id int, req *types.SaveCollectionRequest) error {
+	if err := checkCollectionRequest(req); err != nil {
+		return err
+	}
+	collection, err := c.getCollection(ctx, id)
+	if err != nil {
+		return err
+	}
+	collection.Name = strings.TrimSpace(req.Name)
+	collection.Desc = req.Desc
+	collection.PicInfo = req.PicInfo
+	collection.Status = req.Status
+	err = c.collectionDao.UpdateCollection(ctx, collection)
+	if err != nil {
+		log.Logger.Errorf("CollectionService: UpdateCollection: Failed to update collection %d: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+// DeleteCollection implements CollectionService.
+func (c *CollectionServiceImpl) DeleteCollection(ctx context.Context, id int) error {
+	if _, err := c.getCollection(ctx, id); err != nil {
+		return err
+	}
+	err := c.collectionDao.DeleteCollection(ctx, id)
+	if err != nil {
+		log.Logger.Errorf("CollectionService: DeleteCollection: Failed to delete collection %d: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+// GetCollection implements CollectionService.
+// 用户侧只能看到可见的专题
+func (c *CollectionServiceImpl) GetCollection(ctx context.Context, id int, isCustomer bool) (*types.CollectionInfo, error) {
+	collection, err := c.getCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if isCustomer && collection.Status != model.CollectionStatusVisible {
+		return nil, types.NewBizError(CollectionCheckStatus_NotExist, fmt.Sprintf("collection not found with ID: %d", id))
+	}
+	return buildCollectionInfo(collection), nil
+}
+
+// ListCollections implements CollectionService.
+func (c *CollectionServiceImpl) ListCollections(ctx context.Context, isCustomer bool) ([]*types.CollectionInfo, error) {
+	collections, err := c.collectionDao.ListCollections(ctx, isCustomer)
+	if err != nil {
+		log.Logger.Errorf("CollectionService: ListCollections: Failed to list collections: %v", err)
+		return nil, err
+	}
+	ret := make([]*types.CollectionInfo, 0, len(collections))
+	for _, collection := range collections {
+		ret = append(ret, buildCollectionInfo(collection))
+	}
+	return ret, nil
+}
+
+// SetCollectionProducts implements CollectionService.
+// productIds 的顺序即专题内的展示顺序
+func (c *CollectionServiceImpl) SetCollectionProducts(ctx context.Context, id int, productIds []int) error {
+	if _, err := c.getCollection(ctx, id); err != nil {
+		return err
+	}
+	seen := make(map[int]bool, len(productIds))
+	for _, productId := range productIds {
+		if seen[productId] {
+			return types.NewBizError(CollectionCheckStatus_InvalidProducts, fmt.Sprintf("duplicate product ID: %d", productId))
+		}
+		seen[productId] = true
+	}
+	if len(productIds) > 0 {
+		products, err := c.productDao.GetProductByIDs(ctx, productIds)
+		if err != nil {
+			log.Logger.Errorf("CollectionService: SetCollectionProducts: Failed to get products by IDs: %v", err)
+			return err
+		}
+		for _, product := range products {
+			delete(seen, int(product.ID))
+		}
+		if len(seen) > 0 {
+			missing := make([]int, 0, len(seen))
+			for _, productId := range productIds {
+				if seen[productId] {
+					missing = append(missing, productId)
+				}
+			}
+			return types.NewBizError(CollectionCheckStatus_InvalidProducts, fmt.Sprintf("products not found: %v", missing))
+		}
+	}
+	err := c.collectionDao.ReplaceItems(ctx, id, productIds)
+	if err != nil {
+		log.Logger.Errorf("CollectionService: SetCollectionProducts: Failed to replace items of collection %d: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+// GetCollectionProductIDs implements CollectionService.
+func (c *CollectionServiceImpl) GetCollectionProductIDs(ctx context.Context, id int) ([]int, error) {
+	if _, err := c.getCollection(ctx, id); err != nil {
+		return nil, err
+	}
+	items, err := c.collectionDao.GetItems(ctx, id)
+	if err != nil {
+		log.Logger.Errorf("CollectionService: GetCollectionProductIDs: Failed to get items of collection %d: %v", id, err)
+		return nil, err
+	}
+	ret := make([]int, 0, len(items))
+	for _, item := range items {
+		ret = append(ret, item.ProductID)
+	}
+	return ret, nil
+}
+
+// SetProductTags implements CollectionService.
+// 标签统一去除首尾空格并转为小写，重复标签只保留一个
+func (c *CollectionServiceImpl) SetProductTags(ctx context.Context, productId int, tags []string) ([]string, error) {
+	normalized, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.productDao.GetProductByID(ctx, productId); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, types.NewBizError(ProductCheckStatus_NotExist, fmt.Sprintf("product not found with ID: %d", productId))
+		}
+		log.Logger.Errorf("CollectionService: SetProductTags: Failed to get product by ID: %v", err)
+		return nil, err
+	}
+	err = c.productTagDao.ReplaceTags(ctx, productId, normalized)
+	if err != nil {
+		log.Logger.Errorf("CollectionService: SetProductTags: Failed to replace tags of product %d: %v", productId, err)
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// GetProductTags implements CollectionService.
+func (c *CollectionServiceImpl) GetProductTags(ctx context.Context, productId int) ([]string, error) {
+	tags, err := c.productTagDao.GetTagsByProductID(ctx, productId)
+	if err != nil {
+		log.Logger.Errorf("CollectionService: GetProductTags: Failed to get tags of product %d: %v", productId, err)
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (c *CollectionServiceImpl) getCollection(ctx context.Context, id int) (*model.Collection, error) {
+	collection, err := c.collectionDao.GetCollectionByID(ctx, id)
+	if err != nil {
+		log.Logger.Errorf("CollectionService: Failed to get collection %d: %v", id, err)
+		return nil, err
+	}
+	if collection == nil {
+		return nil, types.NewBizError(CollectionCheckStatus_NotExist, fmt.Sprintf("collection not found with ID: %d", id))
+	}
+	return collection, nil
+}
+
+func checkCollectionRequest(req *types.SaveCollectionRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return types.NewBizError(CollectionCheckStatus_InvalidParam, "collection name cannot be empty")
+	}
+	if req.Status != model.CollectionStatusHidden && req.Status != model.CollectionStatusVisible {
+		return types.NewBizError(CollectionCheckStatus_InvalidParam, fmt.Sprintf("invalid collection status: %d", req.Status))
+	}
+	return nil
+}
+
+func normalizeTags(tags []string) ([]string, error) {
+	ret := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			return nil, types.NewBizError(CollectionCheckStatus_InvalidParam, "tag cannot be empty")
+		}
+		if len([]rune(tag)) > maxTagLength {
+			return nil, types.NewBizError(CollectionCheckStatus_InvalidParam, fmt.Sprintf("tag %q exceeds %d characters", tag, maxTagLength))
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		ret = append(ret, tag)
+	}
+	if len(ret) > maxTagsPerProduct {
+		return nil, types.NewBizError(CollectionCheckStatus_InvalidParam, fmt.Sprintf("a product can have at most %d tags", maxTagsPerProduct))
+	}
+	return ret, nil
+}
+
+func buildCollectionInfo(collection *model.Collection) *types.CollectionInfo {
+	return &types.CollectionInfo{
+		ID:      int(collection.ID),
+		Name:    collection.Name,
+		Desc:    collection.Desc,
+		PicInfo: collection.PicInfo,
+		Status:  collection.Status,
+	}
+}
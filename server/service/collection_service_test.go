@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestCollectionService_CreateCollection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("CreateCollection creates a visible collection", func(t *testing.T) {
+		ctx := context.Background()
+		collectionDao := mocks.NewMockCollectionDao(ctrl)
+		collectionService := &CollectionServiceImpl{collectionDao: collectionDao}
+
+		collectionDao.EXPECT().CreateCollection(ctx, &model.Collection{
+			Name:   "Autumn Glazes",
+			Status: model.CollectionStatusVisible,
+		}).Return(3, nil)
+
+		id, err := collectionService.CreateCollection(ctx, &types.SaveCollectionRequest{
+			Name:   "  Autumn Glazes ",
+			Status: model.CollectionStatusVisible,
+		})
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if id != 3 {
+			t.Errorf("Expected collection ID 3, got %d", id)
+		}
+	})
+
+	t.Run("CreateCollection rejects empty name and unknown status", func(t *testing.T) {
+		collectionService := &CollectionServiceImpl{}
+		reqs := []*types.SaveCollectionRequest{
+			{Name: "  ", Status: model.CollectionStatusVisible},
+			{Name: "Handmade Mugs", Status: 5},
+		}
+		for _, req := range reqs {
+			_, err := collectionService.CreateCollection(context.Background(), req)
+			var bizErr *types.BizError
+			if !errors.As(err, &bizErr) || bizErr.Code != CollectionCheckStatus_InvalidParam {
+				t.Errorf("Expected invalid param error, got %v", err)
+			}
+		}
+	})
+}
+
+func TestCollectionService_GetCollection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	hidden := &model.Collection{Model: gorm.Model{ID: 1}, Name: "Hidden", Status: model.CollectionStatusHidden}
+
+	t.Run("Merchant can see hidden collection", func(t *testing.T) {
+		ctx := context.Background()
+		collectionDao := mocks.NewMockCollectionDao(ctrl)
+		collectionService := &CollectionServiceImpl{collectionDao: collectionDao}
+		collectionDao.EXPECT().GetCollectionByID(ctx, 1).Return(hidden, nil)
+
+		info, err := collectionService.GetCollection(ctx, 1, false)
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if info == nil || info.ID != 1 || info.Name != "Hidden" {
+			t.Errorf("Unexpected collection info: %+v", info)
+		}
+	})
+
+	t.Run("Customer cannot see hidden collection", func(t *testing.T) {
+		ctx := context.Background()
+		collectionDao := mocks.NewMockCollectionDao(ctrl)
+		collectionService := &CollectionServiceImpl{collectionDao: collectionDao}
+		collectionDao.EXPECT().GetCollectionByID(ctx, 1).Return(hidden, nil)
+
+		_, err := collectionService.GetCollection(ctx, 1, true)
+		var bizErr *types.BizError
+		if !errors.As(err, &bizErr) || bizErr.Code != CollectionCheckStatus_NotExist {
+			t.Errorf("Expected not exist error, got %v", err)
+		}
+	})
+
+	t.Run("GetCollection returns not exist for missing collection", func(t *testing.T) {
+		ctx := context.Background()
+		collectionDao := mocks.NewMockCollectionDao(ctrl)
+		collectionService := &CollectionServiceImpl{collectionDao: collectionDao}
+		collectionDao.EXPECT().GetCollectionByID(ctx, 2).Return(nil, nil)
+
+		_, err := collectionService.GetCollection(ctx, 2, false)
+		var bizErr *types.BizError
+		if !errors.As(err, &bizErr) || bizErr.Code != CollectionCheckStatus_NotExist {
+			t.Errorf("Expected not exist error, got %v", err)
+		}
+	})
+}
+
+func TestCollectionService_SetCollectionProducts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	collection := &model.Collection{Model: gorm.Model{ID: 1}, Name: "Handmade Mugs"}
+
+	t.Run("SetCollectionProducts keeps the given order", func(t *testing.T) {
+		ctx := context.Background()
+		collectionDao := mocks.NewMockCollectionDao(ctrl)
+		productDao := mocks.NewMockProductDao(ctrl)
+		collectionService := &CollectionServiceImpl{collectionDao: collectionDao, productDao: productDao}
+
+		collectionDao.EXPECT().GetCollectionByID(ctx, 1).Return(collection, nil)
+		productDao.EXPECT().GetProductByIDs(ctx, []int{3, 1, 2}).Return([]*model.Product{
+			{Model: gorm.Model{ID: 1}}, {Model: gorm.Model{ID: 2}}, {Model: gorm.Model{ID: 3}},
+		}, nil)
+		collectionDao.EXPECT().ReplaceItems(ctx, 1, []int{3, 1, 2}).Return(nil)
+
+		err := collectionService.SetCollectionProducts(ctx, 1, []int{3, 1, 2})
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("SetCollectionProducts rejects duplicate products", func(t *testing.T) {
+		ctx := context.Background()
+		collectionDao := mocks.NewMockCollectionDao(ctrl)
+		collectionService := &CollectionServiceImpl{collectionDao: collectionDao}
+		collectionDao.EXPECT().GetCollectionByID(ctx, 1).Return(collection, nil)
+
+		err := collectionService.SetCollectionProducts(ctx, 1, []int{1, 1})
+		var bizErr *types.BizError
+		if !errors.As(err, &bizErr) || bizErr.Code != CollectionCheckStatus_InvalidProducts {
+			t.Errorf("Expected invalid products error, got %v", err)
+		}
+	})
+
+	t.Run("SetCollectionProducts rejects missing products", func(t *testing.T) {
+		ctx := context.Background()
+		collectionDao := mocks.NewMockCollectionDao(ctrl)
+		productDao := mocks.NewMockProductDao(ctrl)
+		collectionService := &CollectionServiceImpl{collectionDao: collectionDao, productDao: productDao}
+
+		collectionDao.EXPECT().GetCollectionByID(ctx, 1).Return(collection, nil)
+		productDao.EXPECT().GetProductByIDs(ctx, []int{1, 9}).Return([]*model.Product{{Model: gorm.Model{ID: 1}}}, nil)
+
+		err := collectionService.SetCollectionProducts(ctx, 1, []int{1, 9})
+		var bizErr *types.BizError
+		if !errors.As(err, &bizErr) || bizErr.Code != CollectionCheckStatus_InvalidProducts {
+			t.Errorf("Expected invalid products error, got %v", err)
+		}
+	})
+}
+
+func TestCollectionService_SetProductTags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	t.Run("SetProductTags normalizes and deduplicates tags", func(t *testing.T) {
+		ctx := context.Background()
+		productDao := mocks.NewMockProductDao(ctrl)
+		productTagDao := mocks.NewMockProductTagDao(ctrl)
+		collectionService := &CollectionServiceImpl{productDao: productDao, productTagDao: productTagDao}
+
+		productDao.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}}, nil)
+		productTagDao.EXPECT().ReplaceTags(ctx, 1, []string{"glaze", "autumn"}).Return(nil)
+
+		tags, err := collectionService.SetProductTags(ctx, 1, []string{" Glaze", "autumn", "GLAZE "})
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+		if !reflect.DeepEqual(tags, []string{"glaze", "autumn"}) {
+			t.Errorf("Unexpected tags: %v", tags)
+		}
+	})
+
+	t.Run("SetProductTags rejects empty tag", func(t *testing.T) {
+		collectionService := &CollectionServiceImpl{}
+		_, err := collectionService.SetProductTags(context.Background(), 1, []string{"mug", " "})
+		var bizErr *types.BizError
+		if !errors.As(err, &bizErr) || bizErr.Code != CollectionCheckStatus_InvalidParam {
+			t.Errorf("Expected invalid param error, got %v", err)
+		}
+	})
+
+	t.Run("SetProductTags returns not exist for missing product", func(t *testing.T) {
+		ctx := context.Background()
+		productDao := mocks.NewMockProductDao(ctrl)
+		collectionService := &CollectionServiceImpl{productDao: productDao}
+		productDao.EXPECT().GetProductByID(ctx, 9).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := collectionService.SetProductTags(ctx, 9, []string{"mug"})
+		var bizErr *types.BizError
+		if !errors.As(err, &bizErr) || bizErr.Code != ProductCheckStatus_NotExist {
+			t.Errorf("Expected product not exist error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,451 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+)
+
+// CouponService lets merchants manage promo codes. Customers apply codes through CartService.
+type CouponService interface {
+	CreateCoupon(ctx context.Context, req *data.CreateCouponRequest) (couponId int, err error)
+	ListCoupons(ctx context.Context, offset, limit int) ([]*data.CouponVO, int64, error)
+	SetCouponActive(ctx context.Context, id int, active bool) error
+}
+
+var (
+	couponServiceInstance CouponService
+	couponServiceSyncOnce sync.Once
+)
+
+func GetCouponService() CouponService {
+	couponServiceSyncOnce.Do(func() {
+		couponServiceInstance = &CouponServiceImpl{
+			couponDao: dao.GetCouponDao(),
+		}
+	})
+	return couponServiceInstance
+}
+
+type CouponServiceImpl struct {
+	couponDao dao.CouponDao
+}
+
+const (
+	CouponCheckStatus_NotExist     = -40
+	CouponCheckStatus_InvalidParam = -41
+	CouponCheckStatus_Unusable     = -42
+)
+
+// normalizeCouponCode makes codes case-insensitive.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// CreateCoupon implements CouponService.
+func (s *CouponServiceImpl) CreateCoupon(ctx context.Context, req *data.CreateCouponRequest) (int, error) {
+	code := normalizeCouponCode(req.Code)
+	if err := checkCouponRequest(code, req); err != nil {
+		return -1, err
+	}
+	existing, err := s.couponDao.GetCouponByCode(ctx, code)
+	if err != nil {
+		log.Logger.Errorf("CouponService: CreateCoupon: Failed to check code %s: %v", code, err)
+		return -1, err
+	}
+	if existing != nil {
+		return -1, types.NewBizError(CouponCheckStatus_InvalidParam, fmt.Sprintf("coupon code %s already exists", code))
+	}
+	categories, productIds := joinScope(req.Categories, req.ProductIDs)
+	id, err := s.couponDao.CreateCoupon(ctx, &model.Coupon{
+		Code:         code,
+		Type:         req.Type,
+		Value:        req.Value,
+		MaxDiscount:  req.MaxDiscount,
+		MinSpend:     req.MinSpend,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		UsageLimit:   req.UsageLimit,
+		PerUserLimit: req.PerUserLimit,
+		Categories:   categories,
+		ProductIDs:   productIds,
+		Active:       true,
+	})
+	if err != nil {
+		log.Logger.Errorf("CouponService: CreateCoupon: Failed to create coupon %s: %v", code, err)
+		return -1, err
+	}
+	return id, nil
+}
+
+func checkCouponRequest(code string, req *data.CreateCouponRequest) error {
+	if code == "" {
+		return types.NewBizError(CouponCheckStatus_InvalidParam, "coupon code is required")
+	}
+	switch req.Type {
+	case model.CouponTypePercentage:
+		if req.Value < 1 || req.Value > 100 {
+			return types.NewBizError(CouponCheckStatus_InvalidParam, "percentage must be between 1 and 100")
+		}
+	case model.CouponTypeFixedAmount:
+		if req.Value <= 0 {
+			return types.NewBizError(CouponCheckStatus_InvalidParam, "amount must be greater than 0")
+		}
+	case model.CouponTypeFreeShipping:
+	default:
+		return types.NewBizError(CouponCheckStatus_InvalidParam, fmt.Sprintf("unknown coupon type %q", req.Type))
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return types.NewBizError(CouponCheckStatus_InvalidParam, "ends_at must be after starts_at")
+	}
+	for _, id := range req.ProductIDs {
+		if id <= 0 {
+			return types.NewBizError(CouponCheckStatus_InvalidParam, fmt.Sprintf("invalid product ID %d", id))
+		}
+	}
+	return nil
+}
+
+// ListCoupons implements CouponService.
+func (s *CouponServiceImpl) ListCoupons(ctx context.Context, offset, limit int) ([]*data.CouponVO, int64, error) {
+	coupons, total, err := s.couponDao.ListCoupons(ctx, offset, limit)
+	if err != nil {
+		log.Logger.Errorf("CouponService: ListCoupons: Failed to list coupons: %v", err)
+		return nil, 0, err
+	}
+	ret := make([]*data.CouponVO, 0, len(coupons))
+	for _, coupon := range coupons {
+		ret = append(ret, buildCouponVO(coupon))
+	}
+	return ret, total, nil
+}
+
+func buildCouponVO(coupon *model.Coupon) *data.CouponVO {
+	scope := newDiscountScope(coupon.Categories, coupon.ProductIDs)
+	return &data.CouponVO{
+		ID:           coupon.ID,
+		Code:         coupon.Code,
+		Type:         coupon.Type,
+		Value:        coupon.Value,
+		MaxDiscount:  coupon.MaxDiscount,
+		MinSpend:     coupon.MinSpend,
+		StartsAt:     coupon.StartsAt,
+		EndsAt:       coupon.EndsAt,
+		UsageLimit:   coupon.UsageLimit,
+		PerUserLimit: coupon.PerUserLimit,
+		UsedCount:    coupon.UsedCount,
+		Categories:   scope.categories,
+		ProductIDs:   scope.productIds,
+		Active:       coupon.Active,
+		CreatedAt:    coupon.CreatedAt,
+	}
+}
+
+// SetCouponActive implements CouponService.
+func (s *CouponServiceImpl) SetCouponActive(ctx context.Context, id int, active bool) error {
+	coupon, err := s.couponDao.GetCouponByID(ctx, id)
+	if err != nil {
+		log.Logger.Errorf("CouponService: SetCouponActive: Failed to get coupon %d: %v", id, err)
+		return err
+	}
+	if coupon == nil {
+		return types.NewBizError(CouponCheckStatus_NotExist, fmt.Sprintf("coupon not found with ID: %d", id))
+	}
+	if err := s.couponDao.UpdateCouponActive(ctx, id, active); err != nil {
+		log.Logger.Errorf("CouponService: SetCouponActive: Failed to update coupon %d: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+// discountScope limits a coupon or promotion to some categories and products.
+// An empty list does not restrict that dimension.
+type discountScope struct {
+	categories []string
+	productIds []int
+}
+
+func newDiscountScope(categories string, productIds string) discountScope {
+	scope := discountScope{categories: make([]string, 0), productIds: make([]int, 0)}
+	for _, category := range strings.Split(categories, ",") {
+		if category = strings.TrimSpace(category); category != "" {
+			scope.categories = append(scope.categories, category)
+		}
+	}
+	for _, s := range strings.Split(productIds, ",") {
+		if id, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
+			scope.productIds = append(scope.productIds, id)
+		}
+	}
+	return scope
+}
+
+func (s discountScope) contains(productId int, category string) bool {
+	if len(s.categories) > 0 && !containsString(s.categories, category) {
+		return false
+	}
+	return len(s.productIds) == 0 || containsInt(s.productIds, productId)
+}
+
+// joinScope stores categories and product IDs in the comma separated form read by newDiscountScope.
+func joinScope(categories []string, productIds []int) (string, string) {
+	cleaned := make([]string, 0, len(categories))
+	for _, category := range categories {
+		if category = strings.TrimSpace(category); category != "" {
+			cleaned = append(cleaned, category)
+		}
+	}
+	ids := make([]string, 0, len(productIds))
+	for _, id := range productIds {
+		ids = append(ids, strconv.Itoa(id))
+	}
+	return strings.Join(cleaned, ","), strings.Join(ids, ",")
+}
+
+// couponDiscounter prices the code applied to the cart. Codes that cannot be used are
+// recorded on the quote as rejections instead of failing the estimate.
+type couponDiscounter struct {
+	couponDao dao.CouponDao
+}
+
+func (d *couponDiscounter) Discounts(ctx context.Context, q *pricing.Quote) ([]*pricing.Line, error) {
+	if q.CouponCode == "" {
+		return nil, nil
+	}
+	code := normalizeCouponCode(q.CouponCode)
+	coupon, err := d.couponDao.GetCouponByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	reason, err := d.rejectReason(ctx, coupon, q.UserID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if reason != "" {
+		q.Reject(q.CouponCode, reason)
+		return nil, nil
+	}
+
+	eligible := eligibleSubtotal(newDiscountScope(coupon.Categories, coupon.ProductIDs), q.Items)
+	if eligible == 0 {
+		q.Reject(q.CouponCode, "no items in the cart are eligible for this coupon")
+		return nil, nil
+	}
+	if eligible < coupon.MinSpend {
+		q.Reject(q.CouponCode, fmt.Sprintf("spend at least %s on eligible items to use this coupon", formatCents(coupon.MinSpend)))
+		return nil, nil
+	}
+	line := &pricing.Line{Code: coupon.Code}
+	switch coupon.Type {
+	case model.CouponTypePercentage:
+		line.Amount = eligible * coupon.Value / 100
+		if coupon.MaxDiscount > 0 && line.Amount > coupon.MaxDiscount {
+			line.Amount = coupon.MaxDiscount
+		}
+		line.Description = fmt.Sprintf("Coupon %s: %d%% off", coupon.Code, coupon.Value)
+	case model.CouponTypeFixedAmount:
+		line.Amount = min(coupon.Value, eligible)
+		line.Description = fmt.Sprintf("Coupon %s: %s off", coupon.Code, formatCents(coupon.Value))
+	case model.CouponTypeFreeShipping:
+		line.Type = pricing.LineFreeShipping
+		line.Description = fmt.Sprintf("Coupon %s: free shipping", coupon.Code)
+	default:
+		log.Logger.Warnf("couponDiscounter: coupon %s has unknown type %q", coupon.Code, coupon.Type)
+		q.Reject(q.CouponCode, "coupon cannot be used")
+		return nil, nil
+	}
+	return []*pricing.Line{line}, nil
+}
+
+// rejectReason checks everything about a coupon that does not depend on the cart contents.
+func (d *couponDiscounter) rejectReason(ctx context.Context, coupon *model.Coupon, userId int, now time.Time) (string, error) {
+	switch {
+	case coupon == nil:
+		return "coupon code does not exist", nil
+	case !coupon.Active:
+		return "coupon is no longer active", nil
+	case coupon.StartsAt != nil && now.Before(*coupon.StartsAt):
+		return "coupon is not valid yet", nil
+	case coupon.EndsAt != nil && !now.Before(*coupon.EndsAt):
+		return "coupon has expired", nil
+	case coupon.UsageLimit > 0 && coupon.UsedCount >= coupon.UsageLimit:
+		return "coupon has been fully redeemed", nil
+	}
+	if coupon.PerUserLimit > 0 {
+		used, err := d.couponDao.CountUserRedemptions(ctx, coupon.ID, userId)
+		if err != nil {
+			return "", err
+		}
+		if used >= int64(coupon.PerUserLimit) {
+			return "you have already used this coupon the maximum number of times", nil
+		}
+	}
+	return "", nil
+}
+
+// eligibleSubtotal sums the items within scope.
+func eligibleSubtotal(scope discountScope, items []*pricing.Item) int {
+	total := 0
+	for _, item := range items {
+		if scope.contains(item.ProductID, item.Category) {
+			total += item.UnitPrice * item.Quantity
+		}
+	}
+	return total
+}
+
+func containsInt(list []int, target int) bool {
+	for _, v := range list {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+func formatCents(cents int) string {
+	return fmt.Sprintf("$%d.%02d", cents/100, cents%100)
+}
+
+// ApplyCoupon implements CartService. Only unknown codes are refused here; whether the code
+// can be used depends on the cart and is reported by EstimatePrice.
+func (c *CartServiceImpl) ApplyCoupon(ctx context.Context, userId int, code string) error {
+	code = normalizeCouponCode(code)
+	coupon, err := c.couponDao.GetCouponByCode(ctx, code)
+	if err != nil {
+		log.Logger.Errorf("CartService: ApplyCoupon: Failed to get coupon %s: %v", code, err)
+		return err
+	}
+	if coupon == nil {
+		return types.NewBizError(CouponCheckStatus_NotExist, fmt.Sprintf("coupon code %s does not exist", code))
+	}
+	if err := c.couponDao.SetCartCoupon(ctx, userId, code); err != nil {
+		log.Logger.Errorf("CartService: ApplyCoupon: Failed to apply coupon %s for user ID %d: %v", code, userId, err)
+		return err
+	}
+	log.Logger.Infof("CartService: ApplyCoupon: Applied coupon %s to cart of user ID %d", code, userId)
+	return nil
+}
+
+// RemoveCoupon implements CartService.
+func (c *CartServiceImpl) RemoveCoupon(ctx context.Context, userId int) error {
+	if err := c.couponDao.DeleteCartCoupon(ctx, userId); err != nil {
+		log.Logger.Errorf("CartService: RemoveCoupon: Failed to remove coupon for user ID %d: %v", userId, err)
+		return err
+	}
+	return nil
+}
+
+// OrderLine is one product of an order and the quantity ordered.
+type OrderLine struct {
+	ProductID int
+	Quantity  int
+}
+
+// RedeemOrderCoupon implements CartService. It runs when stock is deducted for an order and checks
+// code against the order's own lines rather than the cart, which may have changed since checkout, so a
+// coupon that can no longer be used fails the order instead of being given away. Every deduction of
+// the order calls it; only the first one redeems.
+func (c *CartServiceImpl) RedeemOrderCoupon(ctx context.Context, userId int, orderId string, code string, lines []OrderLine) error {
+	code = normalizeCouponCode(code)
+	if code == "" {
+		return nil
+	}
+	redemption, err := c.couponDao.GetRedemptionByOrder(ctx, orderId)
+	if err != nil {
+		return err
+	}
+	if redemption != nil {
+		return nil
+	}
+	items, err := c.orderPricingItems(ctx, lines)
+	if err != nil {
+		return err
+	}
+	// coupons do not depend on the region, so only the coupon stage is needed
+	quote := &pricing.Quote{UserID: userId, CouponCode: code, Items: items}
+	if _, err := (&couponDiscounter{couponDao: c.couponDao}).Discounts(ctx, quote); err != nil {
+		log.Logger.Errorf("CartService: RedeemOrderCoupon: Failed to check coupon %s for order %s: %v", code, orderId, err)
+		return err
+	}
+	if len(quote.Rejections) > 0 {
+		return types.NewBizError(CouponCheckStatus_Unusable,
+			fmt.Sprintf("coupon %s cannot be used: %s", code, quote.Rejections[0].Reason))
+	}
+	coupon, err := c.couponDao.GetCouponByCode(ctx, code)
+	if err != nil {
+		return err
+	}
+	err = c.couponDao.Redeem(ctx, coupon, userId, orderId)
+	switch {
+	case errors.Is(err, dao.ErrCouponExhausted):
+		return types.NewBizError(CouponCheckStatus_Unusable, fmt.Sprintf("coupon %s has been fully redeemed", coupon.Code))
+	case errors.Is(err, dao.ErrCouponUserLimitReached):
+		return types.NewBizError(CouponCheckStatus_Unusable, fmt.Sprintf("coupon %s has been used the maximum number of times", coupon.Code))
+	case err != nil:
+		log.Logger.Errorf("CartService: RedeemOrderCoupon: Failed to redeem coupon %s for order %s: %v", coupon.Code, orderId, err)
+		return err
+	}
+	log.Logger.Infof("CartService: RedeemOrderCoupon: Redeemed coupon %s for order %s of user ID %d", coupon.Code, orderId, userId)
+	return nil
+}
+
+// orderPricingItems prices order lines the way the order was priced: at the current sale price.
+func (c *CartServiceImpl) orderPricingItems(ctx context.Context, lines []OrderLine) ([]*pricing.Item, error) {
+	if len(lines) == 0 {
+		return nil, types.NewBizError(CouponCheckStatus_InvalidParam, "order lines are required to redeem a coupon")
+	}
+	productIds := make([]int, 0, len(lines))
+	for _, line := range lines {
+		productIds = append(productIds, line.ProductID)
+	}
+	products, err := c.productDao.GetProductByIDs(ctx, productIds)
+	if err != nil {
+		log.Logger.Errorf("CartService: orderPricingItems: Failed to get products by IDs: %v", err)
+		return nil, err
+	}
+	promotions, err := activePromotions(ctx, c.promotionDao)
+	if err != nil {
+		log.Logger.Errorf("CartService: orderPricingItems: Failed to load promotions: %v", err)
+		return nil, err
+	}
+	productsById := make(map[int]*model.Product, len(products))
+	for _, product := range products {
+		productsById[int(product.ID)] = product
+	}
+	items := make([]*pricing.Item, 0, len(lines))
+	for _, line := range lines {
+		product, exists := productsById[line.ProductID]
+		if !exists {
+			return nil, types.NewBizError(CouponCheckStatus_InvalidParam, fmt.Sprintf("order line product not found with ID: %d", line.ProductID))
+		}
+		price, _ := salePrice(product, promotions)
+		items = append(items, &pricing.Item{
+			ProductID: line.ProductID,
+			Category:  product.Category,
+			UnitPrice: int(price),
+			Quantity:  line.Quantity,
+		})
+	}
+	return items, nil
+}
+
+// ReleaseRedeemedCoupon implements CartService. It runs when an order is cancelled and its stock is given back.
+func (c *CartServiceImpl) ReleaseRedeemedCoupon(ctx context.Context, orderId string) error {
+	if err := c.couponDao.ReleaseRedemption(ctx, orderId); err != nil {
+		log.Logger.Errorf("CartService: ReleaseRedeemedCoupon: Failed to release coupon of order %s: %v", orderId, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestCreateCoupon(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	t.Run("creates a normalized coupon", func(t *testing.T) {
+		couponDao := mocks.NewMockCouponDao(ctrl)
+		s := &CouponServiceImpl{couponDao: couponDao}
+		couponDao.EXPECT().GetCouponByCode(ctx, "SAVE10").Return(nil, nil)
+		couponDao.EXPECT().CreateCoupon(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, coupon *model.Coupon) (int, error) {
+			if coupon.Code != "SAVE10" || !coupon.Active || coupon.Categories != "mug,plate" || coupon.ProductIDs != "3,4" {
+				t.Errorf("Unexpected coupon: %+v", coupon)
+			}
+			return 7, nil
+		})
+		id, err := s.CreateCoupon(ctx, &data.CreateCouponRequest{
+			Code:       " save10 ",
+			Type:       model.CouponTypePercentage,
+			Value:      10,
+			Categories: []string{"mug", " ", "plate"},
+			ProductIDs: []int{3, 4},
+		})
+		if err != nil || id != 7 {
+			t.Errorf("Expected coupon 7, got %d, %v", id, err)
+		}
+	})
+
+	t.Run("rejects duplicate codes", func(t *testing.T) {
+		couponDao := mocks.NewMockCouponDao(ctrl)
+		s := &CouponServiceImpl{couponDao: couponDao}
+		couponDao.EXPECT().GetCouponByCode(ctx, "SAVE10").Return(&model.Coupon{ID: 1, Code: "SAVE10"}, nil)
+		_, err := s.CreateCoupon(ctx, &data.CreateCouponRequest{Code: "save10", Type: model.CouponTypeFixedAmount, Value: 500})
+		var bizErr *types.BizError
+		if !errors.As(err, &bizErr) || bizErr.Code != CouponCheckStatus_InvalidParam {
+			t.Errorf("Expected InvalidParam, got %v", err)
+		}
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		s := &CouponServiceImpl{couponDao: mocks.NewMockCouponDao(ctrl)}
+		now := time.Now()
+		reqs := []*data.CreateCouponRequest{
+			{Code: "A", Type: model.CouponTypePercentage, Value: 150},
+			{Code: "B", Type: model.CouponTypeFixedAmount},
+			{Code: "C", Type: model.CouponTypeFreeShipping, StartsAt: &now, EndsAt: &now},
+			{Code: "D", Type: model.CouponTypeFreeShipping, ProductIDs: []int{0}},
+		}
+		for _, req := range reqs {
+			if _, err := s.CreateCoupon(ctx, req); !isCouponBizError(err, CouponCheckStatus_InvalidParam) {
+				t.Errorf("Expected InvalidParam for %s, got %v", req.Code, err)
+			}
+		}
+	})
+}
+
+func isCouponBizError(err error, code int) bool {
+	var bizErr *types.BizError
+	return errors.As(err, &bizErr) && bizErr.Code == code
+}
+
+// couponTestCart sets up a cart with a $200 mug and a $100 plate and returns a service
+// priced with 9% tax and $8 shipping in sg.
+func couponTestCart(ctrl *gomock.Controller, ctx context.Context, couponDao *mocks.MockCouponDao) *CartServiceImpl {
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return([]*model.ShoppingCartItem{
+		{ID: 1, UserID: 1, ProductID: 1, Quantity: 1, SelectStatus: model.CartItemStatusSelected},
+		{ID: 2, UserID: 1, ProductID: 2, Quantity: 1, SelectStatus: model.CartItemStatusSelected},
+	}, nil)
+	productDao.EXPECT().GetProductByIDs(ctx, []int{1, 2}).Return([]*model.Product{
+		{Model: gorm.Model{ID: 1}, Category: "mug", Price: 20000, Status: ProductStatu_Online},
+		{Model: gorm.Model{ID: 2}, Category: "plate", Price: 10000, Status: ProductStatu_Online},
+	}, nil)
+	rules := &pricing.RuleSet{
+		DefaultRegion: "sg",
+		Regions: map[string]*pricing.RegionRules{
+			"sg": {TaxRateBps: 900, ShippingFee: 800},
+		},
+	}
+	return &CartServiceImpl{
+		cartItemDao: cartItemDao,
+		productDao:  productDao,
+		couponDao:   couponDao,
+		pricer: pricing.NewPipeline(rules, pricing.SubtotalStage(),
+			pricing.DiscountStage(&couponDiscounter{couponDao: couponDao}), pricing.ShippingStage(), pricing.TaxStage()),
+	}
+}
+
+func TestEstimatePrice_Coupon(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	t.Run("percentage coupon scoped to a category with a cap", func(t *testing.T) {
+		couponDao := mocks.NewMockCouponDao(ctrl)
+		s := couponTestCart(ctrl, ctx, couponDao)
+		couponDao.EXPECT().GetCartCoupon(ctx, 1).Return("MUGS", nil)
+		couponDao.EXPECT().GetCouponByCode(ctx, "MUGS").Return(&model.Coupon{
+			ID: 1, Code: "MUGS", Type: model.CouponTypePercentage, Value: 50, MaxDiscount: 5000,
+			Categories: "mug", Active: true, StartsAt: &past, EndsAt: &future,
+		}, nil)
+
+		result, err := s.EstimatePrice(ctx, 1, "", "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		// 50% of the $200 mug is capped at $50; tax applies to the discounted $250
+		if result.Discount != 5000 || result.Tax != 2250 || result.Total != 25000+2250+800 {
+			t.Errorf("Unexpected estimate: %+v", result)
+		}
+		if result.Coupon == nil || !result.Coupon.Applied || result.Lines[1].Amount != -5000 {
+			t.Errorf("Expected the coupon to be applied, got %+v, %+v", result.Coupon, result.Lines)
+		}
+	})
+
+	t.Run("free shipping coupon", func(t *testing.T) {
+		couponDao := mocks.NewMockCouponDao(ctrl)
+		s := couponTestCart(ctrl, ctx, couponDao)
+		couponDao.EXPECT().GetCartCoupon(ctx, 1).Return("SHIPFREE", nil)
+		couponDao.EXPECT().GetCouponByCode(ctx, "SHIPFREE").Return(&model.Coupon{
+			ID: 2, Code: "SHIPFREE", Type: model.CouponTypeFreeShipping, PerUserLimit: 1, Active: true,
+		}, nil)
+		couponDao.EXPECT().CountUserRedemptions(ctx, 2, 1).Return(int64(0), nil)
+
+		result, err := s.EstimatePrice(ctx, 1, "", "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.ShippingPrice != 0 || result.Discount != 0 || !result.Coupon.Applied {
+			t.Errorf("Unexpected estimate: %+v", result)
+		}
+	})
+
+	rejections := []struct {
+		name   string
+		coupon *model.Coupon
+		used   int64
+		reason string
+	}{
+		{"unknown code", nil, 0, "coupon code does not exist"},
+		{"disabled", &model.Coupon{Code: "X", Type: model.CouponTypeFixedAmount, Value: 500}, 0, "coupon is no longer active"},
+		{"not started", &model.Coupon{Code: "X", Type: model.CouponTypeFixedAmount, Value: 500, Active: true, StartsAt: &future}, 0, "coupon is not valid yet"},
+		{"expired", &model.Coupon{Code: "X", Type: model.CouponTypeFixedAmount, Value: 500, Active: true, EndsAt: &past}, 0, "coupon has expired"},
+		{"fully redeemed", &model.Coupon{Code: "X", Type: model.CouponTypeFixedAmount, Value: 500, Active: true, UsageLimit: 3, UsedCount: 3}, 0, "coupon has been fully redeemed"},
+		{"per user limit", &model.Coupon{ID: 5, Code: "X", Type: model.CouponTypeFixedAmount, Value: 500, Active: true, PerUserLimit: 1}, 1, "you have already used this coupon the maximum number of times"},
+		{"out of scope", &model.Coupon{Code: "X", Type: model.CouponTypeFixedAmount, Value: 500, Active: true, ProductIDs: "9"}, 0, "no items in the cart are eligible for this coupon"},
+		{"minimum spend", &model.Coupon{Code: "X", Type: model.CouponTypeFixedAmount, Value: 500, Active: true, Categories: "plate", MinSpend: 15000}, 0, "spend at least $150.00 on eligible items to use this coupon"},
+	}
+	for _, tc := range rejections {
+		t.Run("rejects "+tc.name, func(t *testing.T) {
+			couponDao := mocks.NewMockCouponDao(ctrl)
+			s := couponTestCart(ctrl, ctx, couponDao)
+			couponDao.EXPECT().GetCartCoupon(ctx, 1).Return("X", nil)
+			couponDao.EXPECT().GetCouponByCode(ctx, "X").Return(tc.coupon, nil)
+			if tc.coupon != nil && tc.coupon.PerUserLimit > 0 {
+				couponDao.EXPECT().CountUserRedemptions(ctx, tc.coupon.ID, 1).Return(tc.used, nil)
+			}
+
+			result, err := s.EstimatePrice(ctx, 1, "", "")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.Discount != 0 || result.Total != 30000+2700+800 {
+				t.Errorf("Expected no discount, got %+v", result)
+			}
+			if result.Coupon == nil || result.Coupon.Applied || result.Coupon.RejectReason != tc.reason {
+				t.Errorf("Expected rejection %q, got %+v", tc.reason, result.Coupon)
+			}
+		})
+	}
+}
+
+func TestApplyCoupon(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+
+	t.Run("stores a known code", func(t *testing.T) {
+		couponDao := mocks.NewMockCouponDao(ctrl)
+		s := &CartServiceImpl{couponDao: couponDao}
+		couponDao.EXPECT().GetCouponByCode(ctx, "SAVE10").Return(&model.Coupon{ID: 1, Code: "SAVE10"}, nil)
+		couponDao.EXPECT().SetCartCoupon(ctx, 1, "SAVE10").Return(nil)
+		if err := s.ApplyCoupon(ctx, 1, "save10"); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("refuses an unknown code", func(t *testing.T) {
+		couponDao := mocks.NewMockCouponDao(ctrl)
+		s := &CartServiceImpl{couponDao: couponDao}
+		couponDao.EXPECT().GetCouponByCode(ctx, "NOPE").Return(nil, nil)
+		if err := s.ApplyCoupon(ctx, 1, "nope"); !isCouponBizError(err, CouponCheckStatus_NotExist) {
+			t.Errorf("Expected NotExist, got %v", err)
+		}
+	})
+}
+
+func TestRedeemOrderCoupon(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	coupon := &model.Coupon{ID: 3, Code: "TENOFF", Type: model.CouponTypeFixedAmount, Value: 1000, Active: true, MinSpend: 20000}
+	lines := []OrderLine{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}}
+	// the cart is never read: the coupon is checked against the order lines
+	orderCart := func(couponDao *mocks.MockCouponDao) *CartServiceImpl {
+		productDao := mocks.NewMockProductDao(ctrl)
+		productDao.EXPECT().GetProductByIDs(ctx, gomock.Any()).Return([]*model.Product{
+			{Model: gorm.Model{ID: 1}, Category: "mug", Price: 20000, Status: ProductStatu_Online},
+			{Model: gorm.Model{ID: 2}, Category: "plate", Price: 10000, Status: ProductStatu_Online},
+		}, nil).AnyTimes()
+		return &CartServiceImpl{productDao: productDao, couponDao: couponDao}
+	}
+
+	t.Run("redeems a coupon the order lines qualify for", func(t *testing.T) {
+		couponDao := mocks.NewMockCouponDao(ctrl)
+		s := orderCart(couponDao)
+		couponDao.EXPECT().GetRedemptionByOrder(ctx, "order-1").Return(nil, nil)
+		couponDao.EXPECT().GetCouponByCode(ctx, "TENOFF").Return(coupon, nil).Times(2)
+		couponDao.EXPECT().Redeem(ctx, coupon, 1, "order-1").Return(nil)
+		if err := s.RedeemOrderCoupon(ctx, 1, "order-1", "tenoff", lines); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("does nothing for an order without a coupon", func(t *testing.T) {
+		s := &CartServiceImpl{couponDao: mocks.NewMockCouponDao(ctrl)}
+		if err := s.RedeemOrderCoupon(ctx, 1, "order-1", "", lines); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("does nothing for an order that already redeemed", func(t *testing.T) {
+		couponDao := mocks.NewMockCouponDao(ctrl)
+		s := &CartServiceImpl{couponDao: couponDao}
+		couponDao.EXPECT().GetRedemptionByOrder(ctx, "order-1").Return(&model.CouponRedemption{ID: 1, CouponID: 3, UserID: 1, OrderID: "order-1"}, nil)
+		if err := s.RedeemOrderCoupon(ctx, 1, "order-1", "TENOFF", lines); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("requires the order lines", func(t *testing.T) {
+		couponDao := mocks.NewMockCouponDao(ctrl)
+		s := orderCart(couponDao)
+		couponDao.EXPECT().GetRedemptionByOrder(ctx, "order-1").Return(nil, nil)
+		if err := s.RedeemOrderCoupon(ctx, 1, "order-1", "TENOFF", nil); !isCouponBizError(err, CouponCheckStatus_InvalidParam) {
+			t.Errorf("Expected InvalidParam, got %v", err)
+		}
+	})
+
+	t.Run("fails the order when its lines do not reach the minimum spend", func(t *testing.T) {
+		couponDao := mocks.NewMockCouponDao(ctrl)
+		s := orderCart(couponDao)
+		couponDao.EXPECT().GetRedemptionByOrder(ctx, "order-1").Return(nil, nil)
+		couponDao.EXPECT().GetCouponByCode(ctx, "TENOFF").Return(coupon, nil)
+		if err := s.RedeemOrderCoupon(ctx, 1, "order-1", "TENOFF", []OrderLine{{ProductID: 2, Quantity: 1}}); !isCouponBizError(err, CouponCheckStatus_Unusable) {
+			t.Errorf("Expected Unusable, got %v", err)
+		}
+	})
+
+	t.Run("fails the order on a rejected coupon", func(t *testing.T) {
+		couponDao := mocks.NewMockCouponDao(ctrl)
+		s := orderCart(couponDao)
+		disabled := *coupon
+		disabled.Active = false
+		couponDao.EXPECT().GetRedemptionByOrder(ctx, "order-1").Return(nil, nil)
+		couponDao.EXPECT().GetCouponByCode(ctx, "TENOFF").Return(&disabled, nil)
+		if err := s.RedeemOrderCoupon(ctx, 1, "order-1", "TENOFF", lines); !isCouponBizError(err, CouponCheckStatus_Unusable) {
+			t.Errorf("Expected Unusable, got %v", err)
+		}
+	})
+
+	for name, daoErr := range map[string]error{
+		"fails the order when the coupon runs out":           dao.ErrCouponExhausted,
+		"fails the order when the per-user limit is reached": dao.ErrCouponUserLimitReached,
+	} {
+		t.Run(name, func(t *testing.T) {
+			couponDao := mocks.NewMockCouponDao(ctrl)
+			s := orderCart(couponDao)
+			couponDao.EXPECT().GetRedemptionByOrder(ctx, "order-1").Return(nil, nil)
+			couponDao.EXPECT().GetCouponByCode(ctx, "TENOFF").Return(coupon, nil).Times(2)
+			couponDao.EXPECT().Redeem(ctx, coupon, 1, "order-1").Return(daoErr)
+			if err := s.RedeemOrderCoupon(ctx, 1, "order-1", "TENOFF", lines); !isCouponBizError(err, CouponCheckStatus_Unusable) {
+				t.Errorf("Expected Unusable, got %v", err)
+			}
+		})
+	}
+}
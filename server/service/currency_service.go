@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+)
+
+// CurrencyService lists the display currencies customers can pick and lets admins
+// override the exchange rates from the rates file.
+type CurrencyService interface {
+	ListCurrencies(ctx context.Context) ([]*data.CurrencyVO, error)
+	SetExchangeRate(ctx context.Context, code string, req *data.SetExchangeRateRequest) error
+}
+
+var (
+	currencyServiceInstance CurrencyService
+	currencyServiceSyncOnce sync.Once
+)
+
+func GetCurrencyService() CurrencyService {
+	currencyServiceSyncOnce.Do(func() {
+		currencyServiceInstance = &CurrencyServiceImpl{
+			exchangeRateDao: dao.GetExchangeRateDao(),
+		}
+	})
+	return currencyServiceInstance
+}
+
+type CurrencyServiceImpl struct {
+	exchangeRateDao dao.ExchangeRateDao
+}
+
+const (
+	CurrencyCheckStatus_InvalidParam = -60
+
+	currencySourceBase  = "base"
+	currencySourceFile  = "file"
+	currencySourceAdmin = "admin"
+)
+
+var (
+	fileRates     pricing.ExchangeRates
+	fileRatesOnce sync.Once
+)
+
+// configuredRates returns the rates file, loaded once. A broken file leaves only the base
+// currency and admin overrides available rather than failing every request.
+func configuredRates() pricing.ExchangeRates {
+	fileRatesOnce.Do(func() {
+		rates, err := pricing.RatesFromConfig()
+		if err != nil {
+			log.Logger.Errorf("CurrencyService: Failed to load exchange rates: %v", err)
+			rates = pricing.ExchangeRates{}
+		}
+		fileRates = rates
+	})
+	return fileRates
+}
+
+// displayCurrency resolves the currency a customer asked to see prices in; an empty code is the
+// base currency. Admin overrides win over the rates file. Unknown codes return pricing.ErrUnknownCurrency.
+func displayCurrency(ctx context.Context, exchangeRateDao dao.ExchangeRateDao, code string) (*pricing.Currency, error) {
+	code = pricing.NormalizeCurrencyCode(code)
+	if code == "" || code == pricing.Base().Code {
+		return pricing.Base(), nil
+	}
+	if exchangeRateDao != nil {
+		rate, err := exchangeRateDao.GetExchangeRate(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+		if rate != nil {
+			return pricing.NewCurrency(rate.Currency, rate.Rate, rate.Decimals)
+		}
+	}
+	return configuredRates().Lookup(code)
+}
+
+// ListCurrencies implements CurrencyService.
+func (s *CurrencyServiceImpl) ListCurrencies(ctx context.Context) ([]*data.CurrencyVO, error) {
+	overrides, err := s.exchangeRateDao.ListExchangeRates(ctx)
+	if err != nil {
+		log.Logger.Errorf("CurrencyService: ListCurrencies: Failed to list exchange rates: %v", err)
+		return nil, err
+	}
+	base := pricing.Base()
+	currencies := map[string]*data.CurrencyVO{
+		base.Code: {Currency: base.Code, Rate: base.Rate, Decimals: base.Decimals, Source: currencySourceBase},
+	}
+	for code, currency := range configuredRates() {
+		currencies[code] = &data.CurrencyVO{Currency: code, Rate: currency.Rate, Decimals: currency.Decimals, Source: currencySourceFile}
+	}
+	for _, rate := range overrides {
+		updatedAt := rate.UpdatedAt
+		currencies[rate.Currency] = &data.CurrencyVO{
+			Currency:  rate.Currency,
+			Rate:      rate.Rate,
+			Decimals:  rate.Decimals,
+			Source:    currencySourceAdmin,
+			UpdatedAt: &updatedAt,
+		}
+	}
+	ret := make([]*data.CurrencyVO, 0, len(currencies))
+	for _, currency := range currencies {
+		ret = append(ret, currency)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Currency < ret[j].Currency
+	})
+	return ret, nil
+}
+
+// SetExchangeRate implements CurrencyService.
+func (s *CurrencyServiceImpl) SetExchangeRate(ctx context.Context, code string, req *data.SetExchangeRateRequest) error {
+	currency, err := pricing.NewCurrency(code, req.Rate, *req.Decimals)
+	if err != nil {
+		return types.NewBizError(CurrencyCheckStatus_InvalidParam, err.Error())
+	}
+	if currency.Code == pricing.Base().Code {
+		return types.NewBizError(CurrencyCheckStatus_InvalidParam, fmt.Sprintf("%s is the base currency", currency.Code))
+	}
+	err = s.exchangeRateDao.SaveExchangeRate(ctx, &model.ExchangeRate{
+		Currency:   currency.Code,
+		Rate:       currency.Rate,
+		Decimals:   currency.Decimals,
+		OperatorID: types.OperatorFromContext(ctx),
+	})
+	if err != nil {
+		log.Logger.Errorf("CurrencyService: SetExchangeRate: Failed to save rate of %s: %v", currency.Code, err)
+		return err
+	}
+	log.Logger.Infof("CurrencyService: SetExchangeRate: %s set to %s by user %d", currency.Code, currency.Rate, types.OperatorFromContext(ctx))
+	return nil
+}
+
+// convertProductInfo shows a product in display currency c.
+func convertProductInfo(info *types.ProductInfo, c *pricing.Currency) {
+	info.Currency = c.Code
+	info.Price = c.Convert(info.Price)
+	info.SalePrice = c.Convert(info.SalePrice)
+	info.LowestPrice30d = c.Convert(info.LowestPrice30d)
+}
+
+func convertProductSimplifiedInfo(info *types.ProductSimplifiedInfo, c *pricing.Currency) {
+	info.Currency = c.Code
+	info.Price = c.Convert(info.Price)
+	info.SalePrice = c.Convert(info.SalePrice)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestDisplayCurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	exchangeRateDao := mocks.NewMockExchangeRateDao(ctrl)
+
+	if c, err := displayCurrency(ctx, exchangeRateDao, ""); err != nil || c != pricing.Base() {
+		t.Errorf("Expected the base currency, got %+v, %v", c, err)
+	}
+	exchangeRateDao.EXPECT().GetExchangeRate(ctx, "USD").Return(&model.ExchangeRate{Currency: "USD", Rate: "0.75", Decimals: 2}, nil)
+	if c, err := displayCurrency(ctx, exchangeRateDao, "usd"); err != nil || c.Code != "USD" || c.Rate != "0.75" {
+		t.Errorf("Expected the admin rate for USD, got %+v, %v", c, err)
+	}
+	exchangeRateDao.EXPECT().GetExchangeRate(ctx, "XYZ").Return(nil, nil)
+	if _, err := displayCurrency(ctx, exchangeRateDao, "XYZ"); !errors.Is(err, pricing.ErrUnknownCurrency) {
+		t.Errorf("Expected ErrUnknownCurrency, got %v", err)
+	}
+}
+
+func TestCurrencyService_SetExchangeRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := types.WithOperator(context.Background(), 9)
+	exchangeRateDao := mocks.NewMockExchangeRateDao(ctrl)
+	s := &CurrencyServiceImpl{exchangeRateDao: exchangeRateDao}
+	decimals := func(d int) *int { return &d }
+
+	exchangeRateDao.EXPECT().SaveExchangeRate(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, rate *model.ExchangeRate) error {
+		if rate.Currency != "JPY" || rate.Rate != "112.5" || rate.Decimals != 0 || rate.OperatorID != 9 {
+			t.Errorf("Unexpected rate: %+v", rate)
+		}
+		return nil
+	})
+	if err := s.SetExchangeRate(ctx, "jpy", &data.SetExchangeRateRequest{Rate: "112.5", Decimals: decimals(0)}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	reqs := []struct {
+		code string
+		req  *data.SetExchangeRateRequest
+	}{
+		{"SGD", &data.SetExchangeRateRequest{Rate: "1", Decimals: decimals(2)}},
+		{"US", &data.SetExchangeRateRequest{Rate: "0.74", Decimals: decimals(2)}},
+		{"USD", &data.SetExchangeRateRequest{Rate: "0", Decimals: decimals(2)}},
+	}
+	for _, tc := range reqs {
+		err := s.SetExchangeRate(ctx, tc.code, tc.req)
+		var bizErr *types.BizError
+		if !errors.As(err, &bizErr) || bizErr.Code != CurrencyCheckStatus_InvalidParam {
+			t.Errorf("Expected InvalidParam for %s %+v, got %v", tc.code, tc.req, err)
+		}
+	}
+}
+
+func TestCartService_DisplayCurrency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cartItems := []*model.ShoppingCartItem{
+		{ID: 1, UserID: 1, ProductID: 1, Quantity: 3, SelectStatus: model.CartItemStatusSelected, PriceSnapshot: 1999},
+	}
+	products := []*model.Product{
+		{Model: gorm.Model{ID: 1}, Category: "vase", Price: 1999, Stock: 5, Status: ProductStatu_Online},
+	}
+	newService := func() *CartServiceImpl {
+		cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+		productDao := mocks.NewMockProductDao(ctrl)
+		exchangeRateDao := mocks.NewMockExchangeRateDao(ctrl)
+		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return(cartItems, nil)
+		productDao.EXPECT().GetProductByIDs(ctx, []int{1}).Return(products, nil)
+		exchangeRateDao.EXPECT().GetExchangeRate(ctx, "USD").Return(&model.ExchangeRate{Currency: "USD", Rate: "0.74", Decimals: 2}, nil)
+		rules := &pricing.RuleSet{DefaultRegion: "sg", Regions: map[string]*pricing.RegionRules{"sg": {TaxRateBps: 900, ShippingFee: 800}}}
+		return &CartServiceImpl{
+			cartItemDao:     cartItemDao,
+			productDao:      productDao,
+			exchangeRateDao: exchangeRateDao,
+			pricer:          pricing.NewPipeline(rules, pricing.SubtotalStage(), pricing.ShippingStage(), pricing.TaxStage()),
+		}
+	}
+
+	// 1999 cents is US$14.7926, shown as 1479; the cart and the estimate both multiply the shown price
+	cart, err := newService().GetCartItems(ctx, 1, "USD")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cart.Currency != "USD" || cart.CartItems[0].ProductInfo.Price != 1479 || cart.CartItems[0].TotalPrice != 4437 || cart.SelectedPrice != 4437 {
+		t.Errorf("Unexpected cart: %+v", cart)
+	}
+	estimate, err := newService().EstimatePrice(ctx, 1, "", "USD")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// shipping 800 -> 592, tax 9% of 5997 = 539 -> 398.86 -> 399
+	if estimate.Currency != "USD" || estimate.ProductPrice != 4437 || estimate.ShippingPrice != 592 || estimate.Tax != 399 || estimate.Total != 4437+592+399 {
+		t.Errorf("Unexpected estimate: %+v", estimate)
+	}
+}
+
+func TestProductServiceImpl_GetPublishedProductByID_Currency(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	productDao := mocks.NewMockProductDao(ctrl)
+	exchangeRateDao := mocks.NewMockExchangeRateDao(ctrl)
+	p := &ProductServiceImpl{productDao: productDao, exchangeRateDao: exchangeRateDao}
+
+	exchangeRateDao.EXPECT().GetExchangeRate(ctx, "JPY").Return(&model.ExchangeRate{Currency: "JPY", Rate: "112", Decimals: 0}, nil)
+	productDao.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{
+		Model: gorm.Model{ID: 1}, Price: 1050, Stock: 1, Status: ProductStatusPublished,
+	}, nil)
+	productDao.EXPECT().GetLowestPriceSince(ctx, 1, gomock.Any()).Return(int64(1000), nil)
+	info, err := p.GetPublishedProductByID(ctx, 1, "jpy")
+	if err != nil || info.Currency != "JPY" || info.Price != 1176 || info.LowestPrice30d != 1120 {
+		t.Errorf("Expected yen prices, got %+v, %v", info, err)
+	}
+
+	// unknown currencies are rejected before the product is read
+	exchangeRateDao.EXPECT().GetExchangeRate(ctx, "XYZ").Return(nil, nil)
+	if _, err := p.GetPublishedProductByID(ctx, 1, "XYZ"); !errors.Is(err, pricing.ErrUnknownCurrency) {
+		t.Errorf("Expected ErrUnknownCurrency, got %v", err)
+	}
+}
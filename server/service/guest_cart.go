@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+)
+
+const (
+	GuestCartStatus_NotExist = -11
+
+	guestCartTTL = 30 * 24 * time.Hour
+	// guestCartPurgeBatch is how many expired guest carts one purge removes at most
+	guestCartPurgeBatch = 500
+
+	mergeReasonUnavailable       = "product not available"
+	mergeReasonInsufficientStock = "insufficient stock"
+	mergeReasonLimitReached      = "purchase limit reached"
+	mergeReasonCartFull          = "cart is full"
+)
+
+func hashCartToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateGuestCart issues a new cart token. The token is only returned here; it cannot be recovered later.
+func (c *CartServiceImpl) CreateGuestCart(ctx context.Context) (*data.GuestCartVO, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		log.Logger.Errorf("CartService: CreateGuestCart: Failed to generate cart token: %v", err)
+		return nil, err
+	}
+	token := hex.EncodeToString(buf)
+	cart := &model.GuestCart{
+		TokenHash: hashCartToken(token),
+		ExpiresAt: time.Now().Add(guestCartTTL),
+	}
+	if err := c.guestCartDao.CreateGuestCart(ctx, cart); err != nil {
+		log.Logger.Errorf("CartService: CreateGuestCart: Failed to create guest cart: %v", err)
+		return nil, err
+	}
+	log.Logger.Infof("CartService: CreateGuestCart: Created guest cart %d", cart.ID)
+	return &data.GuestCartVO{CartToken: token, ExpiresAt: cart.ExpiresAt}, nil
+}
+
+// ResolveGuestCart returns the user ID the guest cart's items are stored under, which every other
+// CartService method accepts in place of a real user ID.
+func (c *CartServiceImpl) ResolveGuestCart(ctx context.Context, token string) (int, error) {
+	cart, err := c.getGuestCart(ctx, token)
+	if err != nil {
+		return 0, err
+	}
+	return cart.OwnerID(), nil
+}
+
+func (c *CartServiceImpl) getGuestCart(ctx context.Context, token string) (*model.GuestCart, error) {
+	if token == "" {
+		return nil, types.NewBizError(GuestCartStatus_NotExist, "cart token is required")
+	}
+	cart, err := c.guestCartDao.GetGuestCartByTokenHash(ctx, hashCartToken(token))
+	if err != nil {
+		log.Logger.Errorf("CartService: getGuestCart: Failed to get guest cart: %v", err)
+		return nil, err
+	}
+	if cart == nil || time.Now().After(cart.ExpiresAt) {
+		return nil, types.NewBizError(GuestCartStatus_NotExist, "guest cart not found or expired")
+	}
+	return cart, nil
+}
+
+// MergeGuestCart moves the guest cart into the user's cart after login. Quantities of the same product
+// are summed, but never beyond what can be sold or the cart and purchase limits allow; an item already
+// over the limit in the user's cart is left as it is. Items that could not be merged in full are
+// reported. The guest cart is deleted.
+func (c *CartServiceImpl) MergeGuestCart(ctx context.Context, userId int, token string) (*data.CartMergeResult, error) {
+	cart, err := c.getGuestCart(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	guestItems, err := c.cartItemDao.QueryItems(ctx, &model.ShoppingCartItem{UserID: cart.OwnerID()})
+	if err != nil {
+		log.Logger.Errorf("CartService: MergeGuestCart: Failed to query guest cart items: %v", err)
+		return nil, err
+	}
+	ret := &data.CartMergeResult{Unmerged: []data.CartMergeIssueVO{}}
+	toSave := make([]*model.ShoppingCartItem, 0, len(guestItems))
+	if len(guestItems) > 0 {
+		userItems, err := c.cartItemDao.QueryItems(ctx, &model.ShoppingCartItem{UserID: userId})
+		if err != nil {
+			log.Logger.Errorf("CartService: MergeGuestCart: Failed to query cart items of user %d: %v", userId, err)
+			return nil, err
+		}
+		productId2UserItem := make(map[int]*model.ShoppingCartItem, len(userItems))
+		for _, item := range userItems {
+			productId2UserItem[item.ProductID] = item
+		}
+		productIds := make([]int, 0, len(guestItems))
+		for _, item := range guestItems {
+			productIds = append(productIds, item.ProductID)
+		}
+		products, err := c.productDao.GetProductByIDs(ctx, productIds)
+		if err != nil {
+			log.Logger.Errorf("CartService: MergeGuestCart: Failed to get products by IDs: %v", err)
+			return nil, err
+		}
+		productsById := make(map[int]*model.Product, len(products))
+		for _, product := range products {
+			productsById[int(product.ID)] = product
+		}
+		sort.Slice(guestItems, func(i, j int) bool {
+			return guestItems[i].ID < guestItems[j].ID
+		})
+		maxItems, maxQuantity := cartConfig().MaxDistinctItems, cartConfig().MaxQuantityPerItem
+		itemCount := len(userItems)
+		now := time.Now()
+		for _, guestItem := range guestItems {
+			product := productsById[guestItem.ProductID]
+			if product == nil || product.Status != ProductStatu_Online {
+				ret.Unmerged = append(ret.Unmerged, data.CartMergeIssueVO{
+					ProductID: guestItem.ProductID,
+					Requested: guestItem.Quantity,
+					Reason:    mergeReasonUnavailable,
+				})
+				continue
+			}
+			userItem := productId2UserItem[guestItem.ProductID]
+			existing := 0
+			if userItem != nil {
+				existing = userItem.Quantity
+			}
+			if userItem == nil && maxItems > 0 && itemCount >= maxItems {
+				ret.Unmerged = append(ret.Unmerged, data.CartMergeIssueVO{
+					ProductID: guestItem.ProductID,
+					Requested: guestItem.Quantity,
+					Reason:    mergeReasonCartFull,
+				})
+				continue
+			}
+			room, reason := int(sellableQuantity(product)), mergeReasonInsufficientStock
+			if maxQuantity > 0 && maxQuantity < room {
+				room, reason = maxQuantity, mergeReasonLimitReached
+			}
+			allowance, err := c.customerAllowance(ctx, userId, product)
+			if err != nil {
+				log.Logger.Errorf("CartService: MergeGuestCart: Failed to sum order quantity: %v", err)
+				return nil, err
+			}
+			if allowance >= 0 && allowance < room {
+				room, reason = allowance, mergeReasonLimitReached
+			}
+			merged := guestItem.Quantity
+			if room -= existing; merged > room {
+				merged = max(room, 0)
+				ret.Unmerged = append(ret.Unmerged, data.CartMergeIssueVO{
+					ProductID: guestItem.ProductID,
+					Requested: guestItem.Quantity,
+					Merged:    merged,
+					Reason:    reason,
+				})
+			}
+			if merged == 0 {
+				continue
+			}
+			if userItem == nil {
+				itemCount += 1
+				userItem = &model.ShoppingCartItem{
+					UserID:       userId,
+					ProductID:    guestItem.ProductID,
+					SelectStatus: guestItem.SelectStatus,
+					// the guest saw the price when adding the item
+					PriceSnapshot: guestItem.PriceSnapshot,
+					CreatedAt:     now,
+				}
+			} else if guestItem.SelectStatus == model.CartItemStatusSelected {
+				userItem.SelectStatus = model.CartItemStatusSelected
+			}
+			userItem.Quantity = existing + merged
+			userItem.UpdatedAt = now
+			toSave = append(toSave, userItem)
+			ret.MergedItemCount += 1
+		}
+	}
+	err = c.guestCartDao.MergeGuestCart(ctx, cart, toSave)
+	if errors.Is(err, dao.ErrGuestCartGone) {
+		return nil, types.NewBizError(GuestCartStatus_NotExist, "guest cart not found or expired")
+	}
+	if err != nil {
+		log.Logger.Errorf("CartService: MergeGuestCart: Failed to merge guest cart %d into user %d: %v", cart.ID, userId, err)
+		return nil, err
+	}
+	log.Logger.Infof("CartService: MergeGuestCart: Merged %d items of guest cart %d into user %d, %d not merged in full",
+		ret.MergedItemCount, cart.ID, userId, len(ret.Unmerged))
+	return ret, nil
+}
+
+// PurgeExpiredGuestCarts removes guest carts that expired, with their items, and returns how many
+// were removed. Expired carts can no longer be resolved, so their rows are only taking up space.
+func (c *CartServiceImpl) PurgeExpiredGuestCarts(ctx context.Context) (int, error) {
+	deleted, err := c.guestCartDao.DeleteExpiredGuestCarts(ctx, time.Now(), guestCartPurgeBatch)
+	if err != nil {
+		log.Logger.Errorf("CartService: PurgeExpiredGuestCarts: Failed to delete expired guest carts: %v", err)
+		return 0, err
+	}
+	return deleted, nil
+}
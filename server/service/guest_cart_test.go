@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestCartService_GuestCartToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	guestCartDao := mocks.NewMockGuestCartDao(ctrl)
+	cartService := &CartServiceImpl{guestCartDao: guestCartDao}
+
+	var stored *model.GuestCart
+	guestCartDao.EXPECT().CreateGuestCart(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, cart *model.GuestCart) error {
+		cart.ID = 7
+		stored = cart
+		return nil
+	})
+	created, err := cartService.CreateGuestCart(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(created.CartToken) != 64 || stored.TokenHash == created.CartToken {
+		t.Errorf("Expected a random token stored only as a hash, got %s / %s", created.CartToken, stored.TokenHash)
+	}
+
+	t.Run("Resolves the token to the negated cart ID", func(t *testing.T) {
+		guestCartDao.EXPECT().GetGuestCartByTokenHash(ctx, stored.TokenHash).Return(stored, nil)
+		ownerID, err := cartService.ResolveGuestCart(ctx, created.CartToken)
+		if err != nil || ownerID != -7 {
+			t.Errorf("Expected owner -7, got %d, %v", ownerID, err)
+		}
+	})
+
+	t.Run("Rejects unknown and expired tokens", func(t *testing.T) {
+		expired := &model.GuestCart{ID: 8, ExpiresAt: time.Now().Add(-time.Minute)}
+		guestCartDao.EXPECT().GetGuestCartByTokenHash(ctx, hashCartToken("unknown")).Return(nil, nil)
+		guestCartDao.EXPECT().GetGuestCartByTokenHash(ctx, hashCartToken("expired")).Return(expired, nil)
+		for _, token := range []string{"", "unknown", "expired"} {
+			_, err := cartService.ResolveGuestCart(ctx, token)
+			var bizErr *types.BizError
+			if !errors.As(err, &bizErr) || bizErr.Code != GuestCartStatus_NotExist {
+				t.Errorf("Expected NotExist for %q, got %v", token, err)
+			}
+		}
+	})
+}
+
+func TestCartService_MergeGuestCart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	guestCartDao := mocks.NewMockGuestCartDao(ctrl)
+	cartService := &CartServiceImpl{cartItemDao: cartItemDao, productDao: productDao, guestCartDao: guestCartDao}
+
+	guestCart := &model.GuestCart{ID: 3, ExpiresAt: time.Now().Add(time.Hour)}
+	guestCartDao.EXPECT().GetGuestCartByTokenHash(ctx, hashCartToken("token")).Return(guestCart, nil)
+	cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: -3}).Return([]*model.ShoppingCartItem{
+		{ID: 1, UserID: -3, ProductID: 1, Quantity: 2, SelectStatus: model.CartItemStatusSelected}, // summed with the user's item
+		{ID: 2, UserID: -3, ProductID: 2, Quantity: 4, SelectStatus: model.CartItemStatusSelected}, // capped by stock
+		{ID: 3, UserID: -3, ProductID: 3, Quantity: 1, SelectStatus: model.CartItemStatusSelected}, // offline
+		{ID: 4, UserID: -3, ProductID: 4, Quantity: 1, SelectStatus: model.CartItemStatusSelected}, // user already holds all the stock
+	}, nil)
+	cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 5}).Return([]*model.ShoppingCartItem{
+		{ID: 10, UserID: 5, ProductID: 1, Quantity: 1, SelectStatus: model.CartItemStatusUnselected},
+		{ID: 11, UserID: 5, ProductID: 4, Quantity: 2, SelectStatus: model.CartItemStatusSelected},
+	}, nil)
+	productDao.EXPECT().GetProductByIDs(ctx, []int{1, 2, 3, 4}).Return([]*model.Product{
+		{Model: gorm.Model{ID: 1}, Stock: 10, Status: ProductStatu_Online},
+		{Model: gorm.Model{ID: 2}, Stock: 3, Status: ProductStatu_Online},
+		{Model: gorm.Model{ID: 3}, Stock: 10, Status: ProductStatusUnpublished},
+		{Model: gorm.Model{ID: 4}, Stock: 2, Status: ProductStatu_Online},
+	}, nil)
+	guestCartDao.EXPECT().MergeGuestCart(ctx, guestCart, gomock.Any()).DoAndReturn(func(ctx context.Context, cart *model.GuestCart, items []*model.ShoppingCartItem) error {
+		if len(items) != 2 {
+			t.Fatalf("Expected 2 items to save, got %d", len(items))
+		}
+		if items[0].ID != 10 || items[0].Quantity != 3 || items[0].SelectStatus != model.CartItemStatusSelected {
+			t.Errorf("Expected the user's item to be summed to 3, got %+v", items[0])
+		}
+		if items[1].ID != 0 || items[1].UserID != 5 || items[1].ProductID != 2 || items[1].Quantity != 3 {
+			t.Errorf("Expected a new item of 3 for the user, got %+v", items[1])
+		}
+		return nil
+	})
+
+	ret, err := cartService.MergeGuestCart(ctx, 5, "token")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ret.MergedItemCount != 2 || len(ret.Unmerged) != 3 {
+		t.Fatalf("Unexpected result: %+v", ret)
+	}
+	expected := []struct{ productID, merged int }{{2, 3}, {3, 0}, {4, 0}}
+	for i, issue := range ret.Unmerged {
+		if issue.ProductID != expected[i].productID || issue.Merged != expected[i].merged || issue.Reason == "" {
+			t.Errorf("Unexpected unmerged item %d: %+v", i, issue)
+		}
+	}
+}
+
+func TestCartService_MergeGuestCart_AlreadyMerged(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	guestCartDao := mocks.NewMockGuestCartDao(ctrl)
+	cartService := &CartServiceImpl{cartItemDao: cartItemDao, productDao: productDao, guestCartDao: guestCartDao}
+
+	guestCart := &model.GuestCart{ID: 3, ExpiresAt: time.Now().Add(time.Hour)}
+	guestCartDao.EXPECT().GetGuestCartByTokenHash(ctx, hashCartToken("token")).Return(guestCart, nil)
+	cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: -3}).Return([]*model.ShoppingCartItem{
+		{ID: 1, UserID: -3, ProductID: 1, Quantity: 1},
+	}, nil)
+	cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 5}).Return(nil, nil)
+	productDao.EXPECT().GetProductByIDs(ctx, []int{1}).Return([]*model.Product{
+		{Model: gorm.Model{ID: 1}, Stock: 10, Status: ProductStatu_Online},
+	}, nil)
+	// a concurrent merge deleted the guest cart first
+	guestCartDao.EXPECT().MergeGuestCart(ctx, guestCart, gomock.Any()).Return(dao.ErrGuestCartGone)
+
+	_, err := cartService.MergeGuestCart(ctx, 5, "token")
+	var bizErr *types.BizError
+	if !errors.As(err, &bizErr) || bizErr.Code != GuestCartStatus_NotExist {
+		t.Errorf("Expected guest cart not exist error, got %v", err)
+	}
+}
+
+func TestCartService_PurgeExpiredGuestCarts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	guestCartDao := mocks.NewMockGuestCartDao(ctrl)
+	cartService := &CartServiceImpl{guestCartDao: guestCartDao}
+
+	guestCartDao.EXPECT().DeleteExpiredGuestCarts(ctx, gomock.Any(), guestCartPurgeBatch).DoAndReturn(func(ctx context.Context, before time.Time, limit int) (int, error) {
+		if time.Since(before) > time.Minute {
+			t.Errorf("Expected carts expired before now to be purged, got %v", before)
+		}
+		return 2, nil
+	})
+	purged, err := cartService.PurgeExpiredGuestCarts(ctx)
+	if err != nil || purged != 2 {
+		t.Errorf("Expected 2 carts purged, got %d, %v", purged, err)
+	}
+}
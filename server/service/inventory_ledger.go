@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"gorm.io/gorm"
+)
+
+// GetInventoryLedger 按时间倒序分页返回商品的库存流水
+func (p *ProductServiceImpl) GetInventoryLedger(ctx context.Context, id int, offset int, limit int) ([]*types.InventoryLedgerEntryInfo, int, error) {
+	entries, total, err := p.productDao.ListLedgerEntries(ctx, id, offset, limit)
+	if err != nil {
+		log.Logger.Errorf("GetInventoryLedger: Failed to list inventory ledger: %v", err)
+		return nil, 0, err
+	}
+	ret := make([]*types.InventoryLedgerEntryInfo, 0, len(entries))
+	for _, entry := range entries {
+		ret = append(ret, toInventoryLedgerEntryInfo(entry))
+	}
+	return ret, total, nil
+}
+
+// ReconcileInventory 将库存流水的增减合计与商品当前库存对账。
+// 先读库存再汇总流水，对账期间如有新的库存变化可能出现短暂的不一致，重新对账即可
+func (p *ProductServiceImpl) ReconcileInventory(ctx context.Context, id int) (*types.InventoryReconciliation, error) {
+	product, err := p.productDao.GetProductByID(ctx, id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("ReconcileInventory: Failed to get product by ID: %v", err)
+		return nil, err
+	}
+	if product == nil {
+		return nil, newProductNotExistError(id)
+	}
+	total, count, err := p.productDao.SumLedger(ctx, id)
+	if err != nil {
+		log.Logger.Errorf("ReconcileInventory: Failed to sum inventory ledger: %v", err)
+		return nil, err
+	}
+	ret := &types.InventoryReconciliation{
+		ProductID:   id,
+		Stock:       int(product.Stock),
+		LedgerTotal: total,
+		Difference:  int(product.Stock) - total,
+		EntryCount:  count,
+	}
+	ret.Consistent = ret.Difference == 0
+	if !ret.Consistent {
+		log.Logger.Warnf("ReconcileInventory: inventory ledger mismatch, product id: %d, stock: %d, ledger total: %d",
+			id, ret.Stock, total)
+	}
+	return ret, nil
+}
+
+func toInventoryLedgerEntryInfo(entry *model.InventoryLedgerEntry) *types.InventoryLedgerEntryInfo {
+	return &types.InventoryLedgerEntryInfo{
+		ID:          entry.ID,
+		Source:      entry.Source,
+		ReferenceID: entry.ReferenceID,
+		Delta:       entry.Delta,
+		StockAfter:  entry.StockAfter,
+		OperatorID:  entry.OperatorID,
+		CreatedAt:   entry.CreatedAt,
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestProductServiceImpl_UpdateStockWithCAS_LedgerReference(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := types.WithStockReference(context.Background(), "ORD-1001")
+
+	m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Stock: 8, Version: 4}, nil)
+	m.EXPECT().UpdateStockWithCAS(ctx, 4, &model.InventoryLedgerEntry{
+		ProductID:   1,
+		Source:      model.InventorySourceOrder,
+		ReferenceID: "ORD-1001",
+		Delta:       -3,
+		StockAfter:  5,
+	}).Return(nil)
+
+	if err := testProductServiceImpl.UpdateStockWithCAS(ctx, 1, -3); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestProductServiceImpl_ReconcileInventory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := context.Background()
+
+	t.Run("流水与库存一致", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Stock: 12}, nil)
+		m.EXPECT().SumLedger(ctx, 1).Return(12, 4, nil)
+
+		ret, err := testProductServiceImpl.ReconcileInventory(ctx, 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !ret.Consistent || ret.Difference != 0 || ret.EntryCount != 4 {
+			t.Errorf("Unexpected reconciliation: %+v", ret)
+		}
+	})
+
+	t.Run("存在未记录流水的库存变化", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Stock: 20}, nil)
+		m.EXPECT().SumLedger(ctx, 2).Return(15, 3, nil)
+
+		ret, err := testProductServiceImpl.ReconcileInventory(ctx, 2)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if ret.Consistent || ret.Difference != 5 || ret.LedgerTotal != 15 {
+			t.Errorf("Unexpected reconciliation: %+v", ret)
+		}
+	})
+
+	t.Run("商品不存在", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 3).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := testProductServiceImpl.ReconcileInventory(ctx, 3)
+		expectBizErrorCode(t, err, ProductCheckStatus_NotExist)
+	})
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/metrics"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"gorm.io/gorm"
+)
+
+const topicLowStockAlert = "product_low_stock"
+
+// LowStockAlertMessage 库存降到预警阈值及以下时发送的事件
+type LowStockAlertMessage struct {
+	ProductID  int       `json:"product_id"`
+	Name       string    `json:"name"`
+	Stock      int64     `json:"stock"`
+	Threshold  int64     `json:"threshold"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func isLowStock(stock int64, threshold int64) bool {
+	return threshold > 0 && stock <= threshold
+}
+
+// checkLowStock 在库存更新成功后调用，刷新预警指标；
+// 仅在库存从阈值以上降到阈值及以下时发送一次预警事件，持续低库存期间不重复发送
+func (p *ProductServiceImpl) checkLowStock(ctx context.Context, product *model.Product, newStock int64) {
+	id := int(product.ID)
+	threshold := product.LowStockThreshold
+	refreshLowStockGauge(id, newStock, threshold)
+	if !isLowStock(newStock, threshold) || isLowStock(product.Stock, threshold) {
+		return
+	}
+	log.Logger.Infof("Product %d stock %d reached low stock threshold %d", id, newStock, threshold)
+	msg, err := json.Marshal(&LowStockAlertMessage{
+		ProductID:  id,
+		Name:       product.Name,
+		Stock:      newStock,
+		Threshold:  threshold,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		log.Logger.Errorf("checkLowStock: Failed to marshal alert: %v", err)
+		return
+	}
+	if err := p.eventProducer.Publish(ctx, topicLowStockAlert, strconv.Itoa(id), msg); err != nil {
+		log.Logger.Errorf("checkLowStock: Failed to publish low stock alert, product id: %d, err: %v", id, err)
+	}
+}
+
+func refreshLowStockGauge(id int, stock int64, threshold int64) {
+	label := strconv.Itoa(id)
+	if isLowStock(stock, threshold) {
+		metrics.LowStockProducts.WithLabelValues(label).Set(float64(stock))
+		return
+	}
+	metrics.LowStockProducts.DeleteLabelValues(label)
+}
+
+// SetLowStockThreshold 设置库存预警阈值，0 表示关闭预警。
+// 修改阈值只刷新预警指标，不发送预警事件
+func (p *ProductServiceImpl) SetLowStockThreshold(ctx context.Context, id int, threshold int) error {
+	if threshold < 0 {
+		return types.NewBizError(ProductCheckStatus_InvalidParam, "threshold cannot be negative")
+	}
+	product, err := p.productDao.GetProductByID(ctx, id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("SetLowStockThreshold: Failed to get product by ID: %v", err)
+		return err
+	}
+	if product == nil {
+		return newProductNotExistError(id)
+	}
+	if err := p.productDao.UpdateLowStockThreshold(ctx, id, threshold); err != nil {
+		log.Logger.Errorf("SetLowStockThreshold: Failed to update threshold: %v", err)
+		return err
+	}
+	refreshLowStockGauge(id, product.Stock, int64(threshold))
+	return nil
+}
+
+// GetLowStockProducts 分页返回库存小于等于预警阈值的商品
+func (p *ProductServiceImpl) GetLowStockProducts(ctx context.Context, offset int, limit int) ([]*types.LowStockProductInfo, int, error) {
+	products, total, err := p.productDao.ListLowStockProducts(ctx, offset, limit)
+	if err != nil {
+		log.Logger.Errorf("GetLowStockProducts: Failed to list low stock products: %v", err)
+		return nil, 0, err
+	}
+	ret := make([]*types.LowStockProductInfo, 0, len(products))
+	for _, product := range products {
+		ret = append(ret, &types.LowStockProductInfo{
+			ID:                int(product.ID),
+			Name:              product.Name,
+			Category:          product.Category,
+			Stock:             product.Stock,
+			LowStockThreshold: product.LowStockThreshold,
+			Status:            product.Status,
+		})
+	}
+	return ret, total, nil
+}
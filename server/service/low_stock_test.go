@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	proxyMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/proxy/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestProductServiceImpl_UpdateStockWithCAS_LowStockAlert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	producer := proxyMocks.NewEventProducer(t)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m, eventProducer: producer}
+	ctx := context.Background()
+
+	t.Run("库存降到阈值时发送预警", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{
+			Model: gorm.Model{ID: 1}, Name: "Mug", Stock: 6, Version: 1, LowStockThreshold: 5,
+		}, nil)
+		m.EXPECT().UpdateStockWithCAS(ctx, 1, orderLedgerEntry(1, -2, 4)).Return(nil)
+		producer.On("Publish", ctx, topicLowStockAlert, "1", mock.MatchedBy(func(value []byte) bool {
+			var msg LowStockAlertMessage
+			return json.Unmarshal(value, &msg) == nil && msg.ProductID == 1 && msg.Stock == 4 && msg.Threshold == 5
+		})).Return(nil).Once()
+
+		if err := testProductServiceImpl.UpdateStockWithCAS(ctx, 1, -2); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("已处于低库存时不重复预警", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{
+			Model: gorm.Model{ID: 2}, Stock: 4, Version: 1, LowStockThreshold: 5,
+		}, nil)
+		m.EXPECT().UpdateStockWithCAS(ctx, 1, orderLedgerEntry(2, -1, 3)).Return(nil)
+
+		if err := testProductServiceImpl.UpdateStockWithCAS(ctx, 2, -1); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("未设置阈值时不预警", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 3).Return(&model.Product{Model: gorm.Model{ID: 3}, Stock: 1, Version: 1}, nil)
+		m.EXPECT().UpdateStockWithCAS(ctx, 1, orderLedgerEntry(3, -1, 0)).Return(nil)
+
+		if err := testProductServiceImpl.UpdateStockWithCAS(ctx, 3, -1); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestIsLowStock(t *testing.T) {
+	testCases := []struct {
+		stock, threshold int64
+		expected         bool
+	}{
+		{0, 0, false},
+		{3, 5, true},
+		{5, 5, true},
+		{6, 5, false},
+	}
+	for _, tc := range testCases {
+		if got := isLowStock(tc.stock, tc.threshold); got != tc.expected {
+			t.Errorf("isLowStock(%d, %d) = %v, expected %v", tc.stock, tc.threshold, got, tc.expected)
+		}
+	}
+}
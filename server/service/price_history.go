@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+)
+
+// lowestPriceWindow 用户侧展示的最低价统计区间
+const lowestPriceWindow = 30 * 24 * time.Hour
+
+func newPriceChange(ctx context.Context, id int, oldPrice, newPrice int64) *model.ProductPriceChange {
+	return &model.ProductPriceChange{
+		ProductID:   id,
+		OldPrice:    oldPrice,
+		NewPrice:    newPrice,
+		OperatorID:  types.OperatorFromContext(ctx),
+		EffectiveAt: time.Now(),
+	}
+}
+
+// GetPriceHistory 按生效时间倒序分页返回商品的价格变更记录。
+// 每条记录的生效结束时间是下一条 (更新的) 记录的生效时间，因此翻页时多查询上一页的最后一条
+func (p *ProductServiceImpl) GetPriceHistory(ctx context.Context, id int, offset int, limit int) ([]*types.PriceChangeInfo, int, error) {
+	queryOffset, queryLimit := offset, limit
+	if offset > 0 {
+		queryOffset, queryLimit = offset-1, limit+1
+	}
+	changes, total, err := p.productDao.ListPriceChanges(ctx, id, queryOffset, queryLimit)
+	if err != nil {
+		log.Logger.Errorf("GetPriceHistory: Failed to list price changes: %v", err)
+		return nil, 0, err
+	}
+	var effectiveTo *time.Time
+	if offset > 0 && len(changes) > 0 {
+		effectiveTo = &changes[0].EffectiveAt
+		changes = changes[1:]
+	}
+	ret := make([]*types.PriceChangeInfo, 0, len(changes))
+	for _, change := range changes {
+		ret = append(ret, &types.PriceChangeInfo{
+			ID:            change.ID,
+			OldPrice:      change.OldPrice,
+			NewPrice:      change.NewPrice,
+			OperatorID:    change.OperatorID,
+			EffectiveFrom: change.EffectiveAt,
+			EffectiveTo:   effectiveTo,
+		})
+		effectiveTo = &change.EffectiveAt
+	}
+	return ret, total, nil
+}
+
+// lowestRecentPrice 返回近 30 天内的最低原价，查询失败时只记录日志，详情页不展示该字段
+func (p *ProductServiceImpl) lowestRecentPrice(ctx context.Context, id int) int64 {
+	price, err := p.productDao.GetLowestPriceSince(ctx, id, time.Now().Add(-lowestPriceWindow))
+	if err != nil {
+		log.Logger.Warnf("ProductService: Failed to get lowest price of product %d: %v", id, err)
+		return 0
+	}
+	return price
+}
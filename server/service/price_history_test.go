@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestProductServiceImpl_GetPriceHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2026, 9, d, 0, 0, 0, 0, time.UTC) }
+	changes := []*model.ProductPriceChange{
+		{ID: 3, ProductID: 1, OldPrice: 9000, NewPrice: 12000, OperatorID: 7, EffectiveAt: day(20)},
+		{ID: 2, ProductID: 1, OldPrice: 10000, NewPrice: 9000, OperatorID: 7, EffectiveAt: day(10)},
+		{ID: 1, ProductID: 1, OldPrice: 0, NewPrice: 10000, OperatorID: 7, EffectiveAt: day(1)},
+	}
+
+	t.Run("第一页最新的价格没有结束时间", func(t *testing.T) {
+		m.EXPECT().ListPriceChanges(ctx, 1, 0, 2).Return(changes[:2], 3, nil)
+		list, total, err := testProductServiceImpl.GetPriceHistory(ctx, 1, 0, 2)
+		if err != nil || total != 3 || len(list) != 2 {
+			t.Fatalf("Expected 2 of 3 records, got %d of %d, %v", len(list), total, err)
+		}
+		if list[0].EffectiveTo != nil || list[0].NewPrice != 12000 {
+			t.Errorf("Expected the current price first, got %+v", list[0])
+		}
+		if list[1].EffectiveTo == nil || !list[1].EffectiveTo.Equal(day(20)) {
+			t.Errorf("Expected 9000 to be effective until %v, got %+v", day(20), list[1])
+		}
+	})
+
+	t.Run("翻页时结束时间取上一页最后一条", func(t *testing.T) {
+		m.EXPECT().ListPriceChanges(ctx, 1, 1, 3).Return(changes[1:], 3, nil)
+		list, _, err := testProductServiceImpl.GetPriceHistory(ctx, 1, 2, 2)
+		if err != nil || len(list) != 1 {
+			t.Fatalf("Expected 1 record, got %d, %v", len(list), err)
+		}
+		if list[0].ID != 1 || list[0].EffectiveTo == nil || !list[0].EffectiveTo.Equal(day(10)) {
+			t.Errorf("Expected the initial price to be effective until %v, got %+v", day(10), list[0])
+		}
+	})
+}
+
+func TestProductServiceImpl_GetPublishedProductByID_LowestPrice(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := context.Background()
+	m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{
+		Model: gorm.Model{ID: 1}, Price: 12000, Stock: 1, Status: ProductStatusPublished,
+	}, nil).Times(2)
+
+	before := time.Now()
+	m.EXPECT().GetLowestPriceSince(ctx, 1, gomock.Any()).DoAndReturn(func(ctx context.Context, id int, since time.Time) (int64, error) {
+		if window := before.Sub(since); window < lowestPriceWindow-time.Minute || window > lowestPriceWindow+time.Minute {
+			t.Errorf("Expected a 30 day window, got %v", window)
+		}
+		return 9000, nil
+	})
+	info, err := testProductServiceImpl.GetPublishedProductByID(ctx, 1, "")
+	if err != nil || info.LowestPrice30d != 9000 {
+		t.Errorf("Expected the lowest price 9000, got %+v, %v", info, err)
+	}
+
+	// 最低价查询失败不影响详情页
+	m.EXPECT().GetLowestPriceSince(ctx, 1, gomock.Any()).Return(int64(0), errors.New("database error"))
+	info, err = testProductServiceImpl.GetPublishedProductByID(ctx, 1, "")
+	if err != nil || info.LowestPrice30d != 0 {
+		t.Errorf("Expected no lowest price, got %+v, %v", info, err)
+	}
+}
+
+func TestNewPriceChange_RecordsOperator(t *testing.T) {
+	ctx := types.WithOperator(context.Background(), 7)
+	change := newPriceChange(ctx, 1, 10000, 9000)
+	if change.ProductID != 1 || change.OldPrice != 10000 || change.NewPrice != 9000 || change.OperatorID != 7 || change.EffectiveAt.IsZero() {
+		t.Errorf("Unexpected price change: %+v", change)
+	}
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"gorm.io/gorm"
+)
+
+const (
+	StockModeNormal    = "normal"    // 只能售卖现有库存
+	StockModePreorder  = "preorder"  // 预售，商品尚未烧制完成，全部按预计发货日期发货
+	StockModeBackorder = "backorder" // 现货售完后继续接单，超出部分按预计发货日期发货
+
+	AvailabilityInStock    = "in_stock"
+	AvailabilityOutOfStock = "out_of_stock"
+	AvailabilityPreorder   = "preorder"
+	AvailabilityBackorder  = "backorder"
+)
+
+// backorderAllowance 库存最低可以扣减到的负数下限的绝对值，普通商品为 0
+func backorderAllowance(product *model.Product) int64 {
+	if product.StockMode == StockModePreorder || product.StockMode == StockModeBackorder {
+		return product.MaxBackorder
+	}
+	return 0
+}
+
+// sellableQuantity 当前还可以下单的数量
+func sellableQuantity(product *model.Product) int64 {
+	sellable := product.Stock + backorderAllowance(product)
+	if sellable < 0 {
+		return 0
+	}
+	return sellable
+}
+
+func productAvailability(product *model.Product) string {
+	if sellableQuantity(product) <= 0 {
+		return AvailabilityOutOfStock
+	}
+	switch {
+	case product.StockMode == StockModePreorder:
+		return AvailabilityPreorder
+	case product.Stock > 0:
+		return AvailabilityInStock
+	default:
+		return AvailabilityBackorder
+	}
+}
+
+// expectedShipDate 只有预售和缺货下单的商品返回预计发货日期
+func expectedShipDate(product *model.Product) *time.Time {
+	if product.StockMode != StockModePreorder && product.StockMode != StockModeBackorder {
+		return nil
+	}
+	return product.ExpectedShipDate
+}
+
+func checkStockMode(mode string, maxBackorder int64, shipDate *time.Time) error {
+	switch mode {
+	case StockModeNormal:
+		return nil
+	case StockModePreorder:
+		if shipDate == nil {
+			return types.NewBizError(ProductCheckStatus_InvalidParam, "expected_ship_date is required for preorder")
+		}
+	case StockModeBackorder:
+	default:
+		return types.NewBizError(ProductCheckStatus_InvalidParam, fmt.Sprintf("invalid stock mode: %s", mode))
+	}
+	if maxBackorder < 0 {
+		return types.NewBizError(ProductCheckStatus_InvalidParam, "max_backorder cannot be negative")
+	}
+	return nil
+}
+
+// SetStockMode 设置预售/缺货下单，上架中的商品也可以修改。
+// 已超卖的数量 (负库存) 不能超过新的上限，切换为 normal 时要求库存不为负
+func (p *ProductServiceImpl) SetStockMode(ctx context.Context, id int, req *types.UpdateStockModeRequest) error {
+	if err := checkStockMode(req.Mode, req.MaxBackorder, req.ExpectedShipDate); err != nil {
+		return err
+	}
+	maxBackorder, shipDate := req.MaxBackorder, req.ExpectedShipDate
+	if req.Mode == StockModeNormal {
+		maxBackorder, shipDate = 0, nil
+	}
+	product, err := p.productDao.GetProductByID(ctx, id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("SetStockMode: Failed to get product by ID: %v", err)
+		return err
+	}
+	if product == nil {
+		return newProductNotExistError(id)
+	}
+	err = p.productDao.UpdateStockMode(ctx, id, req.Mode, maxBackorder, shipDate)
+	if errors.Is(err, dao.ErrVersionConflict) {
+		return types.NewBizError(ProductCheckStatus_InvalidParam,
+			fmt.Sprintf("product (ID: %d) has more outstanding backorders than %d", id, maxBackorder))
+	}
+	if err != nil {
+		log.Logger.Errorf("SetStockMode: Failed to update stock mode: %v", err)
+		return err
+	}
+	return nil
+}
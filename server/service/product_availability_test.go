@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestProductAvailability(t *testing.T) {
+	testCases := []struct {
+		name     string
+		product  *model.Product
+		expected string
+	}{
+		{"现货", &model.Product{Stock: 3, StockMode: StockModeNormal}, AvailabilityInStock},
+		{"售罄", &model.Product{Stock: 0, StockMode: StockModeNormal}, AvailabilityOutOfStock},
+		{"普通商品忽略预售上限", &model.Product{Stock: 0, StockMode: StockModeNormal, MaxBackorder: 5}, AvailabilityOutOfStock},
+		{"预售", &model.Product{Stock: 3, StockMode: StockModePreorder, MaxBackorder: 5}, AvailabilityPreorder},
+		{"现货售完后缺货下单", &model.Product{Stock: -2, StockMode: StockModeBackorder, MaxBackorder: 5}, AvailabilityBackorder},
+		{"缺货下单额度用完", &model.Product{Stock: -5, StockMode: StockModeBackorder, MaxBackorder: 5}, AvailabilityOutOfStock},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := productAvailability(tc.product); got != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestProductServiceImpl_UpdateStockWithCAS_Backorder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := context.Background()
+
+	// 库存 1，允许超卖 3，扣减 4 后库存为 -3
+	m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{
+		Model: gorm.Model{ID: 1}, Stock: 1, Version: 1, StockMode: StockModeBackorder, MaxBackorder: 3,
+	}, nil)
+	m.EXPECT().UpdateStockWithCAS(ctx, 1, orderLedgerEntry(1, -4, -3)).Return(nil)
+	if err := testProductServiceImpl.UpdateStockWithCAS(ctx, 1, -4); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// 超出允许超卖的数量
+	m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{
+		Model: gorm.Model{ID: 2}, Stock: -3, Version: 2, StockMode: StockModeBackorder, MaxBackorder: 3,
+	}, nil)
+	if err := testProductServiceImpl.UpdateStockWithCAS(ctx, 2, -1); err == nil {
+		t.Error("Expected error when backorder allowance is exceeded, got nil")
+	}
+
+	// 负库存时取消订单回补
+	m.EXPECT().GetProductByID(ctx, 3).Return(&model.Product{
+		Model: gorm.Model{ID: 3}, Stock: -3, Version: 2, StockMode: StockModePreorder, MaxBackorder: 3,
+	}, nil)
+	m.EXPECT().UpdateStockWithCAS(ctx, 2, orderLedgerEntry(3, 1, -2)).Return(nil)
+	if err := testProductServiceImpl.UpdateStockWithCAS(ctx, 3, 1); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestProductServiceImpl_SetStockMode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := context.Background()
+	shipDate := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("预售必须填写发货日期", func(t *testing.T) {
+		err := testProductServiceImpl.SetStockMode(ctx, 1, &types.UpdateStockModeRequest{Mode: StockModePreorder, MaxBackorder: 5})
+		expectBizErrorCode(t, err, ProductCheckStatus_InvalidParam)
+	})
+
+	t.Run("切换为普通模式时清空预售设置", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, StockMode: StockModePreorder}, nil)
+		m.EXPECT().UpdateStockMode(ctx, 1, StockModeNormal, int64(0), nil).Return(nil)
+
+		req := &types.UpdateStockModeRequest{Mode: StockModeNormal, MaxBackorder: 5, ExpectedShipDate: &shipDate}
+		if err := testProductServiceImpl.SetStockMode(ctx, 1, req); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("已超卖数量超过新的上限", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Stock: -4, StockMode: StockModeBackorder}, nil)
+		m.EXPECT().UpdateStockMode(ctx, 2, StockModeBackorder, int64(2), nil).Return(dao.ErrVersionConflict)
+
+		err := testProductServiceImpl.SetStockMode(ctx, 2, &types.UpdateStockModeRequest{Mode: StockModeBackorder, MaxBackorder: 2})
+		expectBizErrorCode(t, err, ProductCheckStatus_InvalidParam)
+	})
+}
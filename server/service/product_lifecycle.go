@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"gorm.io/gorm"
+)
+
+var productStatusNames = map[int32]string{
+	ProductStatusUnpublished:   "unpublished",
+	ProductStatusPublished:     "published",
+	ProductStatusDraft:         "draft",
+	ProductStatusPendingReview: "pending_review",
+	ProductStatusArchived:      "archived",
+	ProductStatusDeleted:       "deleted",
+}
+
+const purgeBatchSize = 500
+
+// productStatusTransitions 商品生命周期中允许的状态变更，key 为当前状态
+var productStatusTransitions = map[int32][]int32{
+	ProductStatusDraft:         {ProductStatusPendingReview, ProductStatusUnpublished, ProductStatusArchived, ProductStatusDeleted},
+	ProductStatusPendingReview: {ProductStatusPublished, ProductStatusDraft, ProductStatusUnpublished},
+	ProductStatusPublished:     {ProductStatusUnpublished, ProductStatusArchived},
+	ProductStatusUnpublished:   {ProductStatusPublished, ProductStatusPendingReview, ProductStatusDraft, ProductStatusArchived, ProductStatusDeleted},
+	ProductStatusArchived:      {ProductStatusUnpublished, ProductStatusDeleted},
+	ProductStatusDeleted:       {},
+}
+
+// IsValidProductStatus 判断是否为合法的商品状态
+func IsValidProductStatus(status int) bool {
+	_, exist := productStatusNames[int32(status)]
+	return exist
+}
+
+func productStatusName(status int32) string {
+	if name, exist := productStatusNames[status]; exist {
+		return name
+	}
+	return fmt.Sprintf("unknown(%d)", status)
+}
+
+// isValidInitialStatus 新建商品只能是草稿、下架或直接上架
+func isValidInitialStatus(status int) bool {
+	return status == ProductStatusDraft || status == ProductStatusUnpublished || status == ProductStatusPublished
+}
+
+// isProductEditable 只有草稿和下架的商品可以修改信息和库存
+func isProductEditable(status int32) bool {
+	return status == ProductStatusDraft || status == ProductStatusUnpublished
+}
+
+func newProductNotExistError(id int) *types.BizError {
+	return types.NewBizError(ProductCheckStatus_NotExist, fmt.Sprintf("product not found with ID: %d", id))
+}
+
+func canTransition(from, to int32) bool {
+	for _, allowed := range productStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionProductStatus 按生命周期规则变更商品状态，并记录操作人
+func (p *ProductServiceImpl) TransitionProductStatus(ctx context.Context, id int, toStatus int) error {
+	if !IsValidProductStatus(toStatus) {
+		return types.NewBizError(ProductCheckStatus_InvalidParam, fmt.Sprintf("invalid product status: %d", toStatus))
+	}
+	product, err := p.productDao.GetProductByID(ctx, id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("TransitionProductStatus: Failed to get product by ID: %v", err)
+		return err
+	}
+	if product == nil {
+		return newProductNotExistError(id)
+	}
+
+	to := int32(toStatus)
+	if product.Status == to {
+		return types.NewBizError(ProductCheckStatus_InvalidTransition,
+			fmt.Sprintf("product (ID: %d) is already %s", id, productStatusName(to)))
+	}
+	if !canTransition(product.Status, to) {
+		return types.NewBizError(ProductCheckStatus_InvalidTransition,
+			fmt.Sprintf("product (ID: %d) cannot change from %s to %s", id, productStatusName(product.Status), productStatusName(to)))
+	}
+
+	if to == ProductStatusPublished {
+		if failed := checkPublishReadiness(product, publishCheckConfig()); len(failed) > 0 {
+			return &PublishNotReadyError{ProductID: id, FailedChecks: failed}
+		}
+	}
+
+	transition := &model.ProductStatusTransition{
+		ProductID:  id,
+		FromStatus: product.Status,
+		ToStatus:   to,
+		OperatorID: types.OperatorFromContext(ctx),
+	}
+	if to == ProductStatusDeleted {
+		err = p.productDao.SoftDeleteProduct(ctx, transition)
+	} else {
+		err = p.productDao.TransitionProductStatus(ctx, transition)
+	}
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionConflict) {
+			return newVersionConflictError(id)
+		}
+		log.Logger.Errorf("TransitionProductStatus: Failed to update product status: %v", err)
+		return err
+	}
+	if to == ProductStatusDeleted {
+		p.removeFromCarts(ctx, id)
+	}
+	// 补货时商品未上架，上架后再通知订阅用户
+	if to == ProductStatusPublished && product.Stock > 0 {
+		p.notifyBackInStock(ctx, product, product.Stock)
+	}
+	return nil
+}
+
+// removeFromCarts 商品删除后从所有用户的购物车中移除，失败只记录日志：
+// 用户查看购物车时也会清理已失效的商品
+func (p *ProductServiceImpl) removeFromCarts(ctx context.Context, id int) {
+	if p.cartItemDao == nil {
+		return
+	}
+	if _, err := p.cartItemDao.DeleteAllByProductId(ctx, id); err != nil {
+		log.Logger.Errorf("removeFromCarts: Failed to remove product %d from carts: %v", id, err)
+	}
+}
+
+// ArchiveProduct 归档商品，已上架的商品会同时下架
+func (p *ProductServiceImpl) ArchiveProduct(ctx context.Context, id int) error {
+	return p.TransitionProductStatus(ctx, id, ProductStatusArchived)
+}
+
+// DeleteProduct 软删除商品，只有草稿、下架和已归档的商品可以删除
+func (p *ProductServiceImpl) DeleteProduct(ctx context.Context, id int) error {
+	return p.TransitionProductStatus(ctx, id, ProductStatusDeleted)
+}
+
+// RestoreProduct 将已归档或已软删除的商品恢复为下架状态
+func (p *ProductServiceImpl) RestoreProduct(ctx context.Context, id int) error {
+	deleted, err := p.productDao.GetDeletedProductByID(ctx, id)
+	if err != nil {
+		log.Logger.Errorf("RestoreProduct: Failed to get deleted product by ID: %v", err)
+		return err
+	}
+	if deleted != nil {
+		err = p.productDao.RestoreDeletedProduct(ctx, &model.ProductStatusTransition{
+			ProductID:  id,
+			FromStatus: deleted.Status,
+			ToStatus:   ProductStatusUnpublished,
+			OperatorID: types.OperatorFromContext(ctx),
+		})
+		if err != nil {
+			if errors.Is(err, dao.ErrVersionConflict) {
+				return newVersionConflictError(id)
+			}
+			log.Logger.Errorf("RestoreProduct: Failed to restore product: %v", err)
+			return err
+		}
+		return nil
+	}
+
+	product, err := p.productDao.GetProductByID(ctx, id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("RestoreProduct: Failed to get product by ID: %v", err)
+		return err
+	}
+	if product != nil && product.Status != ProductStatusArchived {
+		return types.NewBizError(ProductCheckStatus_InvalidTransition,
+			fmt.Sprintf("only archived or deleted products can be restored, product (ID: %d) is %s", id, productStatusName(product.Status)))
+	}
+	return p.TransitionProductStatus(ctx, id, ProductStatusUnpublished)
+}
+
+// PurgeDeletedProducts 永久删除 deletedBefore 之前软删除的商品，分批处理直到没有剩余，返回删除的商品数
+func (p *ProductServiceImpl) PurgeDeletedProducts(ctx context.Context, deletedBefore time.Time) (int, error) {
+	purged := 0
+	for {
+		ids, err := p.productDao.PurgeDeletedProducts(ctx, deletedBefore, purgeBatchSize)
+		if err != nil {
+			log.Logger.Errorf("PurgeDeletedProducts: Failed to purge deleted products: %v", err)
+			return purged, err
+		}
+		purged += len(ids)
+		if len(ids) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// GetStatusHistory 返回商品的状态变更记录，最新的在前
+func (p *ProductServiceImpl) GetStatusHistory(ctx context.Context, id int) ([]*types.ProductStatusTransitionInfo, error) {
+	transitions, err := p.productDao.ListStatusTransitions(ctx, id)
+	if err != nil {
+		log.Logger.Errorf("GetStatusHistory: Failed to list status transitions: %v", err)
+		return nil, err
+	}
+	ret := make([]*types.ProductStatusTransitionInfo, 0, len(transitions))
+	for _, t := range transitions {
+		ret = append(ret, &types.ProductStatusTransitionInfo{
+			FromStatus: t.FromStatus,
+			ToStatus:   t.ToStatus,
+			OperatorID: t.OperatorID,
+			CreatedAt:  t.CreatedAt,
+		})
+	}
+	return ret, nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func expectBizErrorCode(t *testing.T, err error, code int) {
+	t.Helper()
+	var bizErr *types.BizError
+	if !errors.As(err, &bizErr) || bizErr.Code != code {
+		t.Errorf("Expected biz error code %d, got %v", code, err)
+	}
+}
+
+func TestProductServiceImpl_TransitionProductStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := types.WithOperator(context.Background(), 42)
+
+	t.Run("允许的状态变更会记录操作人", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Status: ProductStatusDraft}, nil)
+		m.EXPECT().TransitionProductStatus(ctx, &model.ProductStatusTransition{
+			ProductID:  1,
+			FromStatus: ProductStatusDraft,
+			ToStatus:   ProductStatusPendingReview,
+			OperatorID: 42,
+		}).Return(nil)
+
+		if err := testProductServiceImpl.TransitionProductStatus(ctx, 1, ProductStatusPendingReview); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("不允许的状态变更", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Status: ProductStatusArchived}, nil)
+		err := testProductServiceImpl.TransitionProductStatus(ctx, 2, ProductStatusPublished)
+		expectBizErrorCode(t, err, ProductCheckStatus_InvalidTransition)
+
+		m.EXPECT().GetProductByID(ctx, 3).Return(&model.Product{Model: gorm.Model{ID: 3}, Status: ProductStatusDeleted}, nil)
+		err = testProductServiceImpl.TransitionProductStatus(ctx, 3, ProductStatusUnpublished)
+		expectBizErrorCode(t, err, ProductCheckStatus_InvalidTransition)
+	})
+
+	t.Run("非法状态和不存在的商品", func(t *testing.T) {
+		err := testProductServiceImpl.TransitionProductStatus(ctx, 1, 99)
+		expectBizErrorCode(t, err, ProductCheckStatus_InvalidParam)
+
+		m.EXPECT().GetProductByID(ctx, 4).Return(nil, gorm.ErrRecordNotFound)
+		err = testProductServiceImpl.TransitionProductStatus(ctx, 4, ProductStatusPublished)
+		expectBizErrorCode(t, err, ProductCheckStatus_NotExist)
+	})
+
+	t.Run("状态已被并发修改", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 5).Return(&model.Product{Model: gorm.Model{ID: 5}, Status: ProductStatusPublished}, nil)
+		m.EXPECT().TransitionProductStatus(ctx, gomock.Any()).Return(dao.ErrVersionConflict)
+		err := testProductServiceImpl.TransitionProductStatus(ctx, 5, ProductStatusUnpublished)
+		expectBizErrorCode(t, err, ProductCheckStatus_VersionConflict)
+	})
+}
+
+func TestProductServiceImpl_ArchiveAndRestoreProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := context.Background()
+
+	m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Status: ProductStatusPublished}, nil)
+	m.EXPECT().TransitionProductStatus(ctx, &model.ProductStatusTransition{
+		ProductID:  1,
+		FromStatus: ProductStatusPublished,
+		ToStatus:   ProductStatusArchived,
+	}).Return(nil)
+	if err := testProductServiceImpl.ArchiveProduct(ctx, 1); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	archived := &model.Product{Model: gorm.Model{ID: 1}, Status: ProductStatusArchived}
+	m.EXPECT().GetDeletedProductByID(ctx, 1).Return(nil, nil)
+	m.EXPECT().GetProductByID(ctx, 1).Return(archived, nil).Times(2)
+	m.EXPECT().TransitionProductStatus(ctx, &model.ProductStatusTransition{
+		ProductID:  1,
+		FromStatus: ProductStatusArchived,
+		ToStatus:   ProductStatusUnpublished,
+	}).Return(nil)
+	if err := testProductServiceImpl.RestoreProduct(ctx, 1); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// 只有已归档或已删除的商品可以恢复
+	m.EXPECT().GetDeletedProductByID(ctx, 2).Return(nil, nil)
+	m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Status: ProductStatusDraft}, nil)
+	err := testProductServiceImpl.RestoreProduct(ctx, 2)
+	expectBizErrorCode(t, err, ProductCheckStatus_InvalidTransition)
+
+	// 商品不存在
+	m.EXPECT().GetDeletedProductByID(ctx, 3).Return(nil, nil)
+	m.EXPECT().GetProductByID(ctx, 3).Return(nil, gorm.ErrRecordNotFound).Times(2)
+	err = testProductServiceImpl.RestoreProduct(ctx, 3)
+	expectBizErrorCode(t, err, ProductCheckStatus_NotExist)
+}
+
+func TestProductServiceImpl_EditRequiresEditableStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := context.Background()
+
+	for _, status := range []int32{ProductStatusPendingReview, ProductStatusArchived, ProductStatusDeleted} {
+		m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Status: status}, nil)
+		if err := testProductServiceImpl.UpdateProductStock(ctx, 1, 10); err == nil {
+			t.Errorf("Expected error when updating stock of %s product, got nil", productStatusName(status))
+		}
+	}
+
+	_, err := testProductServiceImpl.Create(ctx, &types.ProductInfo{Name: "Mug", Status: ProductStatusArchived})
+	expectBizErrorCode(t, err, ProductCheckStatus_InvalidParam)
+}
+
+func TestProductServiceImpl_DeleteAndRestoreProduct(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	cartDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m, cartItemDao: cartDao}
+	ctx := types.WithOperator(context.Background(), 9)
+
+	t.Run("删除后从购物车移除", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Status: ProductStatusUnpublished}, nil)
+		m.EXPECT().SoftDeleteProduct(ctx, &model.ProductStatusTransition{
+			ProductID:  1,
+			FromStatus: ProductStatusUnpublished,
+			ToStatus:   ProductStatusDeleted,
+			OperatorID: 9,
+		}).Return(nil)
+		cartDao.EXPECT().DeleteAllByProductId(ctx, 1).Return(int64(3), nil)
+
+		if err := testProductServiceImpl.DeleteProduct(ctx, 1); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("上架中的商品不能直接删除", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Status: ProductStatusPublished}, nil)
+		expectBizErrorCode(t, testProductServiceImpl.DeleteProduct(ctx, 2), ProductCheckStatus_InvalidTransition)
+	})
+
+	t.Run("不存在的商品", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 3).Return(nil, gorm.ErrRecordNotFound)
+		expectBizErrorCode(t, testProductServiceImpl.DeleteProduct(ctx, 3), ProductCheckStatus_NotExist)
+	})
+
+	t.Run("恢复已删除的商品", func(t *testing.T) {
+		m.EXPECT().GetDeletedProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Status: ProductStatusDeleted}, nil)
+		m.EXPECT().RestoreDeletedProduct(ctx, &model.ProductStatusTransition{
+			ProductID:  1,
+			FromStatus: ProductStatusDeleted,
+			ToStatus:   ProductStatusUnpublished,
+			OperatorID: 9,
+		}).Return(nil)
+		if err := testProductServiceImpl.RestoreProduct(ctx, 1); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestProductServiceImpl_PurgeDeletedProducts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := context.Background()
+	before := time.Now()
+
+	fullBatch := make([]int, purgeBatchSize)
+	gomock.InOrder(
+		m.EXPECT().PurgeDeletedProducts(ctx, before, purgeBatchSize).Return(fullBatch, nil),
+		m.EXPECT().PurgeDeletedProducts(ctx, before, purgeBatchSize).Return([]int{1, 2}, nil),
+	)
+	purged, err := testProductServiceImpl.PurgeDeletedProducts(ctx, before)
+	if err != nil || purged != purgeBatchSize+2 {
+		t.Errorf("Expected %d purged products, got %d, %v", purgeBatchSize+2, purged, err)
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"gorm.io/gorm"
+)
+
+// defaultPublishCheckConfig 未配置 publish_checks 时使用的检查项
+var defaultPublishCheckConfig = &config.PublishCheckConfig{
+	RequiredFields:       []string{"name", "category"},
+	RequireImage:         true,
+	RequirePositivePrice: true,
+	RequirePositiveStock: true,
+}
+
+// PublishNotReadyError 商品未通过上架检查，包含所有未通过的检查项
+type PublishNotReadyError struct {
+	ProductID    int
+	FailedChecks []types.ReadinessCheck
+}
+
+func (e *PublishNotReadyError) Error() string {
+	messages := make([]string, 0, len(e.FailedChecks))
+	for _, check := range e.FailedChecks {
+		messages = append(messages, check.Message)
+	}
+	return fmt.Sprintf("product (ID: %d) is not ready to publish: %s", e.ProductID, strings.Join(messages, "; "))
+}
+
+// Unwrap 使上架检查失败可以按 BizError 处理
+func (e *PublishNotReadyError) Unwrap() error {
+	return types.NewBizError(ProductCheckStatus_NotReady, e.Error())
+}
+
+// CheckPublishReadiness 检查商品是否满足上架条件，不修改商品状态
+func (p *ProductServiceImpl) CheckPublishReadiness(ctx context.Context, id int) (*types.PublishReadiness, error) {
+	product, err := p.productDao.GetProductByID(ctx, id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("CheckPublishReadiness: Failed to get product by ID: %v", err)
+		return nil, err
+	}
+	if product == nil {
+		return nil, newProductNotExistError(id)
+	}
+	failed := checkPublishReadiness(product, publishCheckConfig())
+	return &types.PublishReadiness{
+		Ready:        len(failed) == 0,
+		FailedChecks: failed,
+	}, nil
+}
+
+func publishCheckConfig() *config.PublishCheckConfig {
+	if config.Config.PublishCheckConfig != nil {
+		return config.Config.PublishCheckConfig
+	}
+	return defaultPublishCheckConfig
+}
+
+// checkPublishReadiness 返回所有未通过的检查项，全部通过时返回空列表
+func checkPublishReadiness(product *model.Product, conf *config.PublishCheckConfig) []types.ReadinessCheck {
+	failed := make([]types.ReadinessCheck, 0)
+	fail := func(check, field, message string) {
+		failed = append(failed, types.ReadinessCheck{Check: check, Field: field, Message: message})
+	}
+
+	fieldValues := map[string]string{
+		"name":              product.Name,
+		"category":          product.Category,
+		"desc":              product.Desc,
+		"dimensions":        product.Dimensions,
+		"material":          product.Material,
+		"weight":            product.Weight,
+		"capacity":          product.Capacity,
+		"care_instructions": product.CareInstructions,
+	}
+	for _, field := range conf.RequiredFields {
+		value, known := fieldValues[field]
+		if !known {
+			log.Logger.Warnf("checkPublishReadiness: unknown required field %q in config", field)
+			continue
+		}
+		if strings.TrimSpace(value) == "" {
+			fail("required_field", field, fmt.Sprintf("%s is required", field))
+		}
+	}
+	if conf.RequireImage && !hasImage(product.PicInfo) {
+		fail("image", "pic_info", "at least one image is required")
+	}
+	if conf.RequirePositivePrice && product.Price <= 0 {
+		fail("price", "price", "price must be greater than 0")
+	}
+	// 预售商品可以在没有现货时上架
+	if conf.RequirePositiveStock && sellableQuantity(product) <= 0 {
+		fail("stock", "stock", "stock must be greater than 0")
+	}
+	if len(conf.Categories) > 0 && product.Category != "" && !containsString(conf.Categories, product.Category) {
+		fail("category", "category", fmt.Sprintf("category %q is not one of %s", product.Category, strings.Join(conf.Categories, ", ")))
+	}
+	return failed
+}
+
+func hasImage(picInfo string) bool {
+	for _, id := range strings.Split(picInfo, ",") {
+		if strings.TrimSpace(id) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(list []string, target string) bool {
+	for _, s := range list {
+		if s == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestCheckPublishReadiness(t *testing.T) {
+	conf := &config.PublishCheckConfig{
+		RequiredFields:       []string{"name", "category", "desc"},
+		RequireImage:         true,
+		RequirePositivePrice: true,
+		RequirePositiveStock: true,
+		Categories:           []string{"Mugs", "Bowls"},
+	}
+
+	ready := &model.Product{Name: "Mug", Category: "Mugs", Desc: "Glazed", PicInfo: "18a2b3c.jpg", Price: 100, Stock: 5}
+	if failed := checkPublishReadiness(ready, conf); len(failed) != 0 {
+		t.Errorf("Expected product to be ready, got %v", failed)
+	}
+
+	notReady := &model.Product{Name: "Mug", Category: "Vases", PicInfo: " , "}
+	failed := checkPublishReadiness(notReady, conf)
+	got := map[string]bool{}
+	for _, check := range failed {
+		got[check.Check+":"+check.Field] = true
+	}
+	for _, expect := range []string{"required_field:desc", "image:pic_info", "price:price", "stock:stock", "category:category"} {
+		if !got[expect] {
+			t.Errorf("Expected failed check %s, got %v", expect, failed)
+		}
+	}
+	if len(failed) != 5 {
+		t.Errorf("Expected 5 failed checks, got %d: %v", len(failed), failed)
+	}
+
+	// 关闭的检查项不会执行
+	if failed := checkPublishReadiness(notReady, &config.PublishCheckConfig{}); len(failed) != 0 {
+		t.Errorf("Expected no checks, got %v", failed)
+	}
+}
+
+func TestProductServiceImpl_PublishProduct_NotReady(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := context.Background()
+
+	product := &model.Product{Model: gorm.Model{ID: 1}, Name: "Mug", Category: "Mugs", Status: ProductStatusUnpublished}
+	m.EXPECT().GetProductByID(ctx, 1).Return(product, nil).Times(2)
+
+	err := testProductServiceImpl.PublishProduct(ctx, 1)
+	var notReady *PublishNotReadyError
+	if !errors.As(err, &notReady) || len(notReady.FailedChecks) != 3 {
+		t.Fatalf("Expected not ready error with 3 failed checks, got %v", err)
+	}
+	expectBizErrorCode(t, err, ProductCheckStatus_NotReady)
+
+	readiness, err := testProductServiceImpl.CheckPublishReadiness(ctx, 1)
+	if err != nil || readiness.Ready || len(readiness.FailedChecks) != 3 {
+		t.Errorf("Unexpected readiness result: %+v, %v", readiness, err)
+	}
+}
+
+func TestProductServiceImpl_Create_Published(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := context.Background()
+
+	// 未通过上架检查时不会创建商品
+	_, err := testProductServiceImpl.Create(ctx, &types.ProductInfo{Name: "Mug", Category: "Mugs", Status: ProductStatusPublished})
+	var notReady *PublishNotReadyError
+	if !errors.As(err, &notReady) || len(notReady.FailedChecks) != 3 {
+		t.Fatalf("Expected not ready error with 3 failed checks, got %v", err)
+	}
+	expectBizErrorCode(t, err, ProductCheckStatus_NotReady)
+
+	m.EXPECT().CreateProduct(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, product *model.Product, _ *model.InventoryLedgerEntry) (int, error) {
+			if product.Status != ProductStatusPublished {
+				t.Errorf("Expected published product, got status %d", product.Status)
+			}
+			return 1, nil
+		})
+	id, err := testProductServiceImpl.Create(ctx, &types.ProductInfo{
+		Name: "Mug", Category: "Mugs", PicInfo: "18a2b3c.jpg", Price: 100, Stock: 5, Status: ProductStatusPublished,
+	})
+	if err != nil || id != 1 {
+		t.Errorf("Expected product to be created, got %d, %v", id, err)
+	}
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/config"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"gorm.io/gorm"
+)
+
+// ProductScheduleService 管理商品定时上下架
+type ProductScheduleService interface {
+	CreateSchedule(ctx context.Context, productId int, req *types.CreateProductScheduleRequest) (scheduleId int, err error)
+	ListSchedules(ctx context.Context, productId int, onlyPending bool) ([]*types.ProductScheduleInfo, error)
+	CancelSchedule(ctx context.Context, productId int, scheduleId int) error
+
+	// RunDueSchedules 领取并执行到期的任务，返回本轮执行完成的任务数
+	RunDueSchedules(ctx context.Context) int
+}
+
+// productStatusChanger 定时任务执行时使用的上下架操作
+type productStatusChanger interface {
+	PublishProduct(ctx context.Context, id int) error
+	UnpublishProduct(ctx context.Context, id int) error
+}
+
+var (
+	productScheduleServiceInstance ProductScheduleService
+	productScheduleServiceSyncOnce sync.Once
+)
+
+func GetProductScheduleService() ProductScheduleService {
+	productScheduleServiceSyncOnce.Do(func() {
+		impl := &ProductScheduleServiceImpl{
+			scheduleDao:   dao.GetProductScheduleDao(),
+			productDao:    dao.GetProductDao(),
+			statusChanger: GetProductServiceInstance(),
+			lease:         defaultScheduleLease,
+			batchSize:     defaultScheduleBatchSize,
+			maxAttempts:   defaultScheduleMaxAttempts,
+			now:           time.Now,
+		}
+		if conf := config.Config.SchedulerConfig; conf != nil {
+			if conf.LeaseSeconds > 0 {
+				impl.lease = time.Duration(conf.LeaseSeconds) * time.Second
+			}
+			if conf.BatchSize > 0 {
+				impl.batchSize = conf.BatchSize
+			}
+			if conf.MaxAttempts > 0 {
+				impl.maxAttempts = conf.MaxAttempts
+			}
+		}
+		productScheduleServiceInstance = impl
+	})
+	return productScheduleServiceInstance
+}
+
+type ProductScheduleServiceImpl struct {
+	scheduleDao   dao.ProductScheduleDao
+	productDao    dao.ProductDao
+	statusChanger productStatusChanger
+	lease         time.Duration
+	batchSize     int
+	maxAttempts   int
+	now           func() time.Time
+}
+
+const (
+	ScheduleCheckStatus_NotExist     = -30
+	ScheduleCheckStatus_InvalidParam = -31
+	ScheduleCheckStatus_NotPending   = -32
+
+	defaultScheduleLease     = time.Minute
+	defaultScheduleBatchSize = 50
+	// 按默认 10 秒的扫描间隔，持续出错约 1 分钟后放弃
+	defaultScheduleMaxAttempts = 6
+)
+
+// CreateSchedule implements ProductScheduleService.
+func (s *ProductScheduleServiceImpl) CreateSchedule(ctx context.Context, productId int, req *types.CreateProductScheduleRequest) (int, error) {
+	if req.Action != model.ProductScheduleActionPublish && req.Action != model.ProductScheduleActionUnpublish {
+		return -1, types.NewBizError(ScheduleCheckStatus_InvalidParam, fmt.Sprintf("invalid schedule action: %s", req.Action))
+	}
+	if !req.ScheduledAt.After(s.now()) {
+		return -1, types.NewBizError(ScheduleCheckStatus_InvalidParam, "scheduled_at must be in the future")
+	}
+	product, err := s.productDao.GetProductByID(ctx, productId)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("ProductScheduleService: CreateSchedule: Failed to get product %d: %v", productId, err)
+		return -1, err
+	}
+	if product == nil {
+		return -1, newProductNotExistError(productId)
+	}
+
+	id, err := s.scheduleDao.CreateSchedule(ctx, &model.ProductSchedule{
+		ProductID:   productId,
+		Action:      req.Action,
+		ScheduledAt: req.ScheduledAt,
+		Status:      model.ProductScheduleStatusPending,
+		OperatorID:  types.OperatorFromContext(ctx),
+	})
+	if err != nil {
+		log.Logger.Errorf("ProductScheduleService: CreateSchedule: Failed to create schedule: %v", err)
+		return -1, err
+	}
+	return id, nil
+}
+
+// ListSchedules implements ProductScheduleService.
+func (s *ProductScheduleServiceImpl) ListSchedules(ctx context.Context, productId int, onlyPending bool) ([]*types.ProductScheduleInfo, error) {
+	schedules, err := s.scheduleDao.ListSchedules(ctx, productId, onlyPending)
+	if err != nil {
+		log.Logger.Errorf("ProductScheduleService: ListSchedules: Failed to list schedules: %v", err)
+		return nil, err
+	}
+	ret := make([]*types.ProductScheduleInfo, 0, len(schedules))
+	for _, schedule := range schedules {
+		ret = append(ret, &types.ProductScheduleInfo{
+			ID:          schedule.ID,
+			ProductID:   schedule.ProductID,
+			Action:      schedule.Action,
+			ScheduledAt: schedule.ScheduledAt,
+			Status:      schedule.Status,
+			LastError:   schedule.LastError,
+			Attempts:    schedule.Attempts,
+			ExecutedAt:  schedule.ExecutedAt,
+			CreatedAt:   schedule.CreatedAt,
+		})
+	}
+	return ret, nil
+}
+
+// CancelSchedule implements ProductScheduleService.
+func (s *ProductScheduleServiceImpl) CancelSchedule(ctx context.Context, productId int, scheduleId int) error {
+	schedule, err := s.scheduleDao.GetScheduleByID(ctx, scheduleId)
+	if err != nil {
+		log.Logger.Errorf("ProductScheduleService: CancelSchedule: Failed to get schedule %d: %v", scheduleId, err)
+		return err
+	}
+	if schedule == nil || schedule.ProductID != productId {
+		return types.NewBizError(ScheduleCheckStatus_NotExist, fmt.Sprintf("schedule not found with ID: %d", scheduleId))
+	}
+	err = s.scheduleDao.CancelSchedule(ctx, scheduleId)
+	if err != nil {
+		if errors.Is(err, dao.ErrScheduleNotPending) {
+			return types.NewBizError(ScheduleCheckStatus_NotPending,
+				fmt.Sprintf("schedule (ID: %d) has already been executed or canceled", scheduleId))
+		}
+		log.Logger.Errorf("ProductScheduleService: CancelSchedule: Failed to cancel schedule %d: %v", scheduleId, err)
+		return err
+	}
+	return nil
+}
+
+// RunDueSchedules implements ProductScheduleService.
+// 每个任务先通过数据库条件更新领取，只有领取成功的副本会执行；执行出现非业务错误时释放任务等待下一轮重试，
+// 累计尝试 maxAttempts 次仍出错时标记为失败并保留最后一次的错误
+func (s *ProductScheduleServiceImpl) RunDueSchedules(ctx context.Context) int {
+	token, err := newClaimToken()
+	if err != nil {
+		log.Logger.Errorf("ProductScheduleService: RunDueSchedules: Failed to generate claim token: %v", err)
+		return 0
+	}
+	schedules, err := s.scheduleDao.ClaimDueSchedules(ctx, s.now(), token, s.lease, s.batchSize)
+	if err != nil {
+		log.Logger.Errorf("ProductScheduleService: RunDueSchedules: Failed to claim due schedules: %v", err)
+	}
+
+	finished := 0
+	for _, schedule := range schedules {
+		if s.runSchedule(ctx, schedule) {
+			finished++
+		}
+	}
+	return finished
+}
+
+func (s *ProductScheduleServiceImpl) runSchedule(ctx context.Context, schedule *model.ProductSchedule) bool {
+	status, lastError, retry := s.execute(ctx, schedule)
+	if retry && schedule.Attempts+1 >= s.maxAttempts {
+		log.Logger.Errorf("ProductScheduleService: schedule %d failed after %d attempts: %s", schedule.ID, schedule.Attempts+1, lastError)
+		status, lastError, retry = model.ProductScheduleStatusFailed, fmt.Sprintf("failed after %d attempts: %s", schedule.Attempts+1, lastError), false
+	}
+	if retry {
+		log.Logger.Warnf("ProductScheduleService: schedule %d will be retried: %s", schedule.ID, lastError)
+		if err := s.scheduleDao.ReleaseSchedule(ctx, schedule.ID, schedule.ClaimToken, lastError); err != nil {
+			log.Logger.Errorf("ProductScheduleService: Failed to release schedule %d: %v", schedule.ID, err)
+		}
+		return false
+	}
+	if err := s.scheduleDao.FinishSchedule(ctx, schedule.ID, schedule.ClaimToken, status, lastError); err != nil {
+		log.Logger.Errorf("ProductScheduleService: Failed to finish schedule %d: %v", schedule.ID, err)
+		return false
+	}
+	log.Logger.Infof("ProductScheduleService: schedule %d (%s product %d) finished with status %d",
+		schedule.ID, schedule.Action, schedule.ProductID, status)
+	return true
+}
+
+// execute 执行任务并返回最终状态。商品已处于目标状态时视为执行成功，
+// 这样副本在执行后、写入结果前退出时，其他副本重新领取也不会重复变更
+func (s *ProductScheduleServiceImpl) execute(ctx context.Context, schedule *model.ProductSchedule) (status int, lastError string, retry bool) {
+	product, err := s.productDao.GetProductByID(ctx, schedule.ProductID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return model.ProductScheduleStatusPending, err.Error(), true
+	}
+	if product == nil {
+		return model.ProductScheduleStatusFailed, fmt.Sprintf("product not found with ID: %d", schedule.ProductID), false
+	}
+
+	opCtx := types.WithOperator(ctx, schedule.OperatorID)
+	switch schedule.Action {
+	case model.ProductScheduleActionPublish:
+		if product.Status == ProductStatusPublished {
+			return model.ProductScheduleStatusDone, "", false
+		}
+		err = s.statusChanger.PublishProduct(opCtx, schedule.ProductID)
+	case model.ProductScheduleActionUnpublish:
+		if product.Status == ProductStatusUnpublished {
+			return model.ProductScheduleStatusDone, "", false
+		}
+		err = s.statusChanger.UnpublishProduct(opCtx, schedule.ProductID)
+	default:
+		return model.ProductScheduleStatusFailed, fmt.Sprintf("invalid schedule action: %s", schedule.Action), false
+	}
+
+	if err == nil {
+		return model.ProductScheduleStatusDone, "", false
+	}
+	var bizErr *types.BizError
+	if errors.As(err, &bizErr) && bizErr.Code != ProductCheckStatus_VersionConflict {
+		return model.ProductScheduleStatusFailed, bizErr.Message, false
+	}
+	return model.ProductScheduleStatusPending, err.Error(), true
+}
+
+func newClaimToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
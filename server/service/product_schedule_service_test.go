@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+type fakeStatusChanger struct {
+	published   []int
+	unpublished []int
+	operators   []int
+	err         error
+}
+
+func (f *fakeStatusChanger) PublishProduct(ctx context.Context, id int) error {
+	f.published = append(f.published, id)
+	f.operators = append(f.operators, types.OperatorFromContext(ctx))
+	return f.err
+}
+
+func (f *fakeStatusChanger) UnpublishProduct(ctx context.Context, id int) error {
+	f.unpublished = append(f.unpublished, id)
+	f.operators = append(f.operators, types.OperatorFromContext(ctx))
+	return f.err
+}
+
+func newTestScheduleService(ctrl *gomock.Controller, now time.Time) (*ProductScheduleServiceImpl, *mocks.MockProductScheduleDao, *mocks.MockProductDao, *fakeStatusChanger) {
+	scheduleDao := mocks.NewMockProductScheduleDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	changer := &fakeStatusChanger{}
+	return &ProductScheduleServiceImpl{
+		scheduleDao:   scheduleDao,
+		productDao:    productDao,
+		statusChanger: changer,
+		lease:         time.Minute,
+		batchSize:     10,
+		maxAttempts:   3,
+		now:           func() time.Time { return now },
+	}, scheduleDao, productDao, changer
+}
+
+func TestProductScheduleServiceImpl_CreateSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)
+	s, scheduleDao, productDao, _ := newTestScheduleService(ctrl, now)
+	ctx := types.WithOperator(context.Background(), 7)
+
+	productDao.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}}, nil)
+	scheduleDao.EXPECT().CreateSchedule(ctx, &model.ProductSchedule{
+		ProductID:   1,
+		Action:      model.ProductScheduleActionPublish,
+		ScheduledAt: now.Add(time.Hour),
+		OperatorID:  7,
+	}).Return(3, nil)
+	id, err := s.CreateSchedule(ctx, 1, &types.CreateProductScheduleRequest{Action: "publish", ScheduledAt: now.Add(time.Hour)})
+	if err != nil || id != 3 {
+		t.Errorf("Expected schedule 3, got %d, %v", id, err)
+	}
+
+	_, err = s.CreateSchedule(ctx, 1, &types.CreateProductScheduleRequest{Action: "publish", ScheduledAt: now.Add(-time.Minute)})
+	expectBizErrorCode(t, err, ScheduleCheckStatus_InvalidParam)
+
+	productDao.EXPECT().GetProductByID(ctx, 2).Return(nil, gorm.ErrRecordNotFound)
+	_, err = s.CreateSchedule(ctx, 2, &types.CreateProductScheduleRequest{Action: "unpublish", ScheduledAt: now.Add(time.Hour)})
+	expectBizErrorCode(t, err, ProductCheckStatus_NotExist)
+}
+
+func TestProductScheduleServiceImpl_CancelSchedule(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s, scheduleDao, _, _ := newTestScheduleService(ctrl, time.Now())
+	ctx := context.Background()
+
+	scheduleDao.EXPECT().GetScheduleByID(ctx, 1).Return(&model.ProductSchedule{ID: 1, ProductID: 5}, nil).Times(3)
+	scheduleDao.EXPECT().CancelSchedule(ctx, 1).Return(nil)
+	if err := s.CancelSchedule(ctx, 5, 1); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	// 任务属于其他商品
+	expectBizErrorCode(t, s.CancelSchedule(ctx, 6, 1), ScheduleCheckStatus_NotExist)
+
+	scheduleDao.EXPECT().CancelSchedule(ctx, 1).Return(dao.ErrScheduleNotPending)
+	expectBizErrorCode(t, s.CancelSchedule(ctx, 5, 1), ScheduleCheckStatus_NotPending)
+}
+
+func TestProductScheduleServiceImpl_RunDueSchedules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Date(2025, 10, 1, 8, 0, 0, 0, time.UTC)
+	s, scheduleDao, productDao, changer := newTestScheduleService(ctrl, now)
+	ctx := context.Background()
+
+	schedules := []*model.ProductSchedule{
+		{ID: 1, ProductID: 1, Action: model.ProductScheduleActionPublish, OperatorID: 7, ClaimToken: "t"},
+		{ID: 2, ProductID: 2, Action: model.ProductScheduleActionUnpublish, ClaimToken: "t"},
+		{ID: 3, ProductID: 3, Action: model.ProductScheduleActionPublish, ClaimToken: "t"},
+		{ID: 4, ProductID: 4, Action: model.ProductScheduleActionPublish, ClaimToken: "t"},
+	}
+	scheduleDao.EXPECT().ClaimDueSchedules(ctx, now, gomock.Any(), time.Minute, 10).Return(schedules, nil)
+
+	// 1: 正常上架
+	productDao.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Status: ProductStatusUnpublished}, nil)
+	scheduleDao.EXPECT().FinishSchedule(ctx, 1, "t", model.ProductScheduleStatusDone, "").Return(nil)
+	// 2: 商品已下架，视为已执行
+	productDao.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Status: ProductStatusUnpublished}, nil)
+	scheduleDao.EXPECT().FinishSchedule(ctx, 2, "t", model.ProductScheduleStatusDone, "").Return(nil)
+	// 3: 商品不存在，任务失败
+	productDao.EXPECT().GetProductByID(ctx, 3).Return(nil, gorm.ErrRecordNotFound)
+	scheduleDao.EXPECT().FinishSchedule(ctx, 3, "t", model.ProductScheduleStatusFailed, gomock.Any()).Return(nil)
+	// 4: 数据库错误，释放任务等待重试
+	productDao.EXPECT().GetProductByID(ctx, 4).Return(nil, errors.New("db error"))
+	scheduleDao.EXPECT().ReleaseSchedule(ctx, 4, "t", "db error").Return(nil)
+
+	if finished := s.RunDueSchedules(ctx); finished != 3 {
+		t.Errorf("Expected 3 finished schedules, got %d", finished)
+	}
+	if len(changer.published) != 1 || changer.published[0] != 1 || len(changer.unpublished) != 0 {
+		t.Errorf("Unexpected status changes: published %v, unpublished %v", changer.published, changer.unpublished)
+	}
+	if changer.operators[0] != 7 {
+		t.Errorf("Expected operator 7 to be recorded, got %d", changer.operators[0])
+	}
+}
+
+func TestProductScheduleServiceImpl_RunDueSchedules_InvalidTransition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	s, scheduleDao, productDao, changer := newTestScheduleService(ctrl, now)
+	ctx := context.Background()
+	changer.err = types.NewBizError(ProductCheckStatus_InvalidTransition, "product (ID: 1) cannot change from archived to published")
+
+	scheduleDao.EXPECT().ClaimDueSchedules(ctx, now, gomock.Any(), time.Minute, 10).
+		Return([]*model.ProductSchedule{{ID: 1, ProductID: 1, Action: model.ProductScheduleActionPublish, ClaimToken: "t"}}, nil)
+	productDao.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Status: ProductStatusArchived}, nil)
+	scheduleDao.EXPECT().FinishSchedule(ctx, 1, "t", model.ProductScheduleStatusFailed, changer.err.Error()).Return(nil)
+
+	s.RunDueSchedules(ctx)
+}
+
+func TestProductScheduleServiceImpl_RunDueSchedules_MaxAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	now := time.Now()
+	s, scheduleDao, productDao, changer := newTestScheduleService(ctrl, now)
+	ctx := context.Background()
+	changer.err = types.NewBizError(ProductCheckStatus_VersionConflict, "version conflict")
+
+	scheduleDao.EXPECT().ClaimDueSchedules(ctx, now, gomock.Any(), time.Minute, 10).Return([]*model.ProductSchedule{
+		{ID: 1, ProductID: 1, Action: model.ProductScheduleActionPublish, ClaimToken: "t", Attempts: 1},
+		{ID: 2, ProductID: 2, Action: model.ProductScheduleActionPublish, ClaimToken: "t", Attempts: 2},
+	}, nil)
+	productDao.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Status: ProductStatusUnpublished}, nil)
+	productDao.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Status: ProductStatusUnpublished}, nil)
+	// 1: 还可以重试
+	scheduleDao.EXPECT().ReleaseSchedule(ctx, 1, "t", changer.err.Error()).Return(nil)
+	// 2: 第三次仍然冲突，不再重试
+	scheduleDao.EXPECT().FinishSchedule(ctx, 2, "t", model.ProductScheduleStatusFailed, "failed after 3 attempts: "+changer.err.Error()).Return(nil)
+
+	if finished := s.RunDueSchedules(ctx); finished != 1 {
+		t.Errorf("Expected 1 finished schedule, got %d", finished)
+	}
+}
@@ -2,12 +2,18 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/proxy"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
 	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"gorm.io/gorm"
 )
 
 type ProductService interface {
@@ -15,27 +21,70 @@ type ProductService interface {
 	GetProductByID(ctx context.Context, id int) (productInfo *types.ProductInfo, err error)
 	PublishProduct(ctx context.Context, id int) error
 	UnpublishProduct(ctx context.Context, id int) error
+	TransitionProductStatus(ctx context.Context, id int, toStatus int) error
+	ArchiveProduct(ctx context.Context, id int) error
+	DeleteProduct(ctx context.Context, id int) error
+	RestoreProduct(ctx context.Context, id int) error
+	PurgeDeletedProducts(ctx context.Context, deletedBefore time.Time) (purged int, err error)
+	GetStatusHistory(ctx context.Context, id int) ([]*types.ProductStatusTransitionInfo, error)
+	CheckPublishReadiness(ctx context.Context, id int) (*types.PublishReadiness, error)
 
 	// 商家后台更新商品库存
 	UpdateProductStock(ctx context.Context, id int, newStock int) error
 	GetProductList(ctx context.Context, req types.GetProductListQuery) (list []*types.ProductInfo, count int, err error)
 
 	UpdateStockWithCAS(ctx context.Context, id int, deta int) error
+	AdjustStock(ctx context.Context, id int, req *types.AdjustStockRequest) (*types.StockAdjustmentInfo, error)
+	GetStockAdjustments(ctx context.Context, id int, offset int, limit int) ([]*types.StockAdjustmentInfo, int, error)
+	GetInventoryLedger(ctx context.Context, id int, offset int, limit int) ([]*types.InventoryLedgerEntryInfo, int, error)
+	GetPriceHistory(ctx context.Context, id int, offset int, limit int) ([]*types.PriceChangeInfo, int, error)
+	ReconcileInventory(ctx context.Context, id int) (*types.InventoryReconciliation, error)
+	SubscribeBackInStock(ctx context.Context, id int, userId int) error
+	UnsubscribeBackInStock(ctx context.Context, id int, userId int) error
+	SetLowStockThreshold(ctx context.Context, id int, threshold int) error
+	SetPurchaseLimit(ctx context.Context, id int, maxPerCustomer int) error
+	SetStockMode(ctx context.Context, id int, req *types.UpdateStockModeRequest) error
+	GetLowStockProducts(ctx context.Context, offset int, limit int) ([]*types.LowStockProductInfo, int, error)
 	UpdateProductInfo(ctx context.Context, req *types.UpdateProductInfoRequest) error
+	PatchProductInfo(ctx context.Context, id int, req *types.PatchProductInfoRequest) error
 }
 
 type ProductServiceImpl struct {
-	productDao dao.ProductDao
+	productDao      dao.ProductDao
+	cartItemDao     dao.ShoppingCartItemDao
+	subscriptionDao dao.StockSubscriptionDao
+	promotionDao    dao.PromotionDao
+	exchangeRateDao dao.ExchangeRateDao
+	eventProducer   proxy.EventProducer
 }
 
 func GetProductServiceInstance() *ProductServiceImpl {
 	return &ProductServiceImpl{
-		productDao: dao.GetProductDao(),
+		productDao:      dao.GetProductDao(),
+		cartItemDao:     dao.GetShoppingCartItemDao(),
+		subscriptionDao: dao.GetStockSubscriptionDao(),
+		promotionDao:    dao.GetPromotionDao(),
+		exchangeRateDao: dao.GetExchangeRateDao(),
+		eventProducer:   proxy.GetEventProducer(),
 	}
 }
 
 func (p *ProductServiceImpl) Create(ctx context.Context, product *types.ProductInfo) (productId int, err error) {
-	id, err := p.productDao.CreateProduct(ctx, &model.Product{
+	if !isValidInitialStatus(int(product.Status)) {
+		return -1, types.NewBizError(ProductCheckStatus_InvalidParam,
+			fmt.Sprintf("product cannot be created as %s", productStatusName(product.Status)))
+	}
+	stockMode := product.StockMode
+	if stockMode == "" {
+		stockMode = StockModeNormal
+	}
+	if err := checkStockMode(stockMode, product.MaxBackorder, product.ExpectedShipDate); err != nil {
+		return -1, err
+	}
+	if stockMode == StockModeNormal {
+		product.MaxBackorder, product.ExpectedShipDate = 0, nil
+	}
+	productModel := &model.Product{
 		Name:             product.Name,
 		Category:         product.Category,
 		Price:            product.Price,
@@ -48,6 +97,24 @@ func (p *ProductServiceImpl) Create(ctx context.Context, product *types.ProductI
 		Dimensions:       product.Dimensions,
 		CareInstructions: product.CareInstructions,
 		Status:           product.Status,
+
+		LowStockThreshold: product.LowStockThreshold,
+		MaxPerCustomer:    product.MaxPerCustomer,
+		StockMode:         stockMode,
+		MaxBackorder:      product.MaxBackorder,
+		ExpectedShipDate:  product.ExpectedShipDate,
+	}
+	// 直接上架的商品同样需要通过上架检查
+	if product.Status == ProductStatusPublished {
+		if failed := checkPublishReadiness(productModel, publishCheckConfig()); len(failed) > 0 {
+			return -1, &PublishNotReadyError{FailedChecks: failed}
+		}
+	}
+	id, err := p.productDao.CreateProduct(ctx, productModel, &model.InventoryLedgerEntry{
+		Source:     model.InventorySourceInitial,
+		Delta:      int(product.Stock),
+		StockAfter: int(product.Stock),
+		OperatorID: types.OperatorFromContext(ctx),
 	})
 	if err != nil {
 		log.Logger.Errorf("ProductService: Failed to create product: %v", err)
@@ -66,7 +133,7 @@ func (p *ProductServiceImpl) GetProductByID(ctx context.Context, id int) (produc
 	if product == nil {
 		return nil, nil
 	}
-	return &types.ProductInfo{
+	info := &types.ProductInfo{
 		Name:             product.Name,
 		Category:         product.Category,
 		Price:            product.Price,
@@ -79,25 +146,56 @@ func (p *ProductServiceImpl) GetProductByID(ctx context.Context, id int) (produc
 		Dimensions:       product.Dimensions,
 		CareInstructions: product.CareInstructions,
 		Status:           product.Status,
-	}, nil
+		Version:          product.Version,
+		UpdatedAt:        product.UpdatedAt,
+
+		LowStockThreshold: product.LowStockThreshold,
+		MaxPerCustomer:    product.MaxPerCustomer,
+		StockMode:         product.StockMode,
+		MaxBackorder:      product.MaxBackorder,
+		ExpectedShipDate:  expectedShipDate(product),
+		Availability:      productAvailability(product),
+		Currency:          pricing.Base().Code,
+	}
+	info.SalePrice, info.PromotionName = productSale(product, p.loadPromotions(ctx))
+	return info, nil
 }
 
 const (
-	ProductStatusUnpublished = 0 // 下架状态
-	ProductStatusPublished   = 1 // 上架状态
+	ProductStatusUnpublished   = 0 // 下架状态
+	ProductStatusPublished     = 1 // 上架状态
+	ProductStatusDraft         = 2 // 草稿
+	ProductStatusPendingReview = 3 // 待审核
+	ProductStatusArchived      = 4 // 已归档，不再销售但保留数据
+	ProductStatusDeleted       = 5 // 已删除
+
+	maxCASRetries = 3
+)
+
+const (
+	ProductCheckStatus_VersionConflict       = -4
+	ProductCheckStatus_InvalidParam          = -5
+	ProductCheckStatus_InvalidTransition     = -6
+	ProductCheckStatus_NotReady              = -7
+	ProductCheckStatus_PurchaseLimitExceeded = -8
 )
 
 // GetProductByID 根据ID获取产品信息 (用户侧， 只有上架的商品才能查看详情页)
-func (p *ProductServiceImpl) GetPublishedProductByID(ctx context.Context, id int) (productInfo *types.ProductInfo, err error) {
+// 价格按 currency 指定的币种展示，为空时使用基础货币
+func (p *ProductServiceImpl) GetPublishedProductByID(ctx context.Context, id int, currency string) (productInfo *types.ProductInfo, err error) {
+	displayIn, err := displayCurrency(ctx, p.exchangeRateDao, currency)
+	if err != nil {
+		return nil, err
+	}
 	product, err := p.productDao.GetProductByID(ctx, id)
 	if err != nil {
 		log.Logger.Errorf("ProductService: Failed to get product by ID: %v", err)
 		return nil, err
 	}
-	if product == nil || product.Status == ProductStatusUnpublished {
+	if product == nil || product.Status != ProductStatusPublished {
 		return nil, nil
 	}
-	return &types.ProductInfo{
+	info := &types.ProductInfo{
 		Name:             product.Name,
 		Category:         product.Category,
 		Price:            product.Price,
@@ -110,67 +208,34 @@ func (p *ProductServiceImpl) GetPublishedProductByID(ctx context.Context, id int
 		Dimensions:       product.Dimensions,
 		CareInstructions: product.CareInstructions,
 		Status:           product.Status,
-	}, nil
+		Version:          product.Version,
+		UpdatedAt:        product.UpdatedAt,
+
+		MaxPerCustomer:   product.MaxPerCustomer,
+		StockMode:        product.StockMode,
+		ExpectedShipDate: expectedShipDate(product),
+		Availability:     productAvailability(product),
+	}
+	info.SalePrice, info.PromotionName = productSale(product, p.loadPromotions(ctx))
+	info.LowestPrice30d = p.lowestRecentPrice(ctx, id)
+	convertProductInfo(info, displayIn)
+	return info, nil
 }
 
 // PublishProduct 上架商品
 func (p *ProductServiceImpl) PublishProduct(ctx context.Context, id int) error {
-	// 获取商品当前信息
-	product, err := p.productDao.GetProductByID(ctx, id)
-	if err != nil {
-		log.Logger.Errorf("PublishProduct: Failed to get product by ID: %v", err)
-		return err
-	}
-	if product == nil {
-		return fmt.Errorf("product not found with ID: %d", id)
-	}
-
-	// 检查当前状态
-	if product.Status == ProductStatusPublished {
-		return fmt.Errorf("product (ID: %d) is already published", id)
-	}
-
-	// 更新状态为已上架
-	err = p.productDao.UpdateProductStatus(ctx, id, ProductStatusPublished)
-	if err != nil {
-		log.Logger.Errorf("PublishProduct: Failed to update product status: %v", err)
-		return err
-	}
-
-	return nil
+	return p.TransitionProductStatus(ctx, id, ProductStatusPublished)
 }
 
 // UnpublishProduct 下架商品
 func (p *ProductServiceImpl) UnpublishProduct(ctx context.Context, id int) error {
-	// 获取商品当前信息
-	product, err := p.productDao.GetProductByID(ctx, id)
-	if err != nil {
-		log.Logger.Errorf("UnpublishProduct: Failed to get product by ID: %v", err)
-		return err
-	}
-	if product == nil {
-		return fmt.Errorf("product not found with ID: %d", id)
-	}
-
-	// 检查当前状态
-	if product.Status == ProductStatusUnpublished {
-		return fmt.Errorf("product (ID: %d) is already unpublished", id)
-	}
-
-	// 更新状态为已下架
-	err = p.productDao.UpdateProductStatus(ctx, id, ProductStatusUnpublished)
-	if err != nil {
-		log.Logger.Errorf("UnpublishProduct: Failed to update product status: %v", err)
-		return err
-	}
-
-	return nil
+	return p.TransitionProductStatus(ctx, id, ProductStatusUnpublished)
 }
 
 // UpdateProductStock 更新商品库存
 // 要求：
 // 1. 商品必须存在
-// 2. 商品必须处于下架状态
+// 2. 商品必须处于可编辑状态 (草稿或下架)
 // 3. 新的库存不能小于0
 func (p *ProductServiceImpl) UpdateProductStock(ctx context.Context, id int, newStock int) error {
 	// 检查库存是否合法
@@ -189,34 +254,47 @@ func (p *ProductServiceImpl) UpdateProductStock(ctx context.Context, id int, new
 	}
 
 	// 检查商品状态
-	if product.Status != ProductStatusUnpublished {
-		return fmt.Errorf("cannot update stock for published product (ID: %d)", id)
+	if !isProductEditable(product.Status) {
+		return fmt.Errorf("cannot update stock for %s product (ID: %d)", productStatusName(product.Status), id)
 	}
 
 	// 更新库存
-	err = p.productDao.UpdateProductStock(ctx, id, newStock)
+	err = p.productDao.UpdateProductStock(ctx, &model.InventoryLedgerEntry{
+		ProductID:  id,
+		Source:     model.InventorySourceMerchantSet,
+		StockAfter: newStock,
+		OperatorID: types.OperatorFromContext(ctx),
+	})
 	if err != nil {
 		log.Logger.Errorf("UpdateProductStock: Failed to update stock: %v", err)
 		return err
 	}
+	p.onStockChanged(ctx, product, int64(newStock))
 
 	return nil
 }
 
 func (p *ProductServiceImpl) GetProductList(ctx context.Context, req types.GetProductListQuery) (list []*types.ProductSimplifiedInfo, count int, err error) {
+	displayIn, err := displayCurrency(ctx, p.exchangeRateDao, req.Currency)
+	if err != nil {
+		return nil, -1, err
+	}
 	listRaw, cnt, err := p.productDao.ListProduct(ctx, dao.ListProductQuery{
-		Keyword:    req.Keyword,
-		Category:   req.Category,
-		Offset:     req.Offset,
-		Limit:      req.Limit,
-		IsCustomer: req.IsCustomer,
-		OrderBy:    req.OrderBy,
+		Keyword:      req.Keyword,
+		Category:     req.Category,
+		Tag:          req.Tag,
+		CollectionID: req.CollectionID,
+		Offset:       req.Offset,
+		Limit:        req.Limit,
+		IsCustomer:   req.IsCustomer,
+		OrderBy:      req.OrderBy,
 	})
 	if err != nil {
 		log.Logger.Errorf("GetProductList: Failed to get product list, err: %v", err)
 		return nil, -1, err
 	}
 
+	promotions := p.loadPromotions(ctx)
 	list = make([]*types.ProductSimplifiedInfo, len(listRaw))
 	for k, listModel := range listRaw {
 		list[k] = &types.ProductSimplifiedInfo{
@@ -228,52 +306,123 @@ func (p *ProductServiceImpl) GetProductList(ctx context.Context, req types.GetPr
 			Stock:    listModel.Stock,
 			PicInfo:  listModel.PicInfo,
 			Status:   listModel.Status,
+
+			Availability:     productAvailability(listModel),
+			ExpectedShipDate: expectedShipDate(listModel),
+
+			Version:   listModel.Version,
+			UpdatedAt: listModel.UpdatedAt,
 		}
+		list[k].SalePrice, list[k].PromotionName = productSale(listModel, promotions)
+		convertProductSimplifiedInfo(list[k], displayIn)
 	}
 
 	return list, cnt, nil
 }
 
+// loadPromotions 读取进行中的促销，失败时只记录日志，商品按原价展示
+func (p *ProductServiceImpl) loadPromotions(ctx context.Context) []*model.Promotion {
+	promotions, err := activePromotions(ctx, p.promotionDao)
+	if err != nil {
+		log.Logger.Warnf("ProductService: Failed to load promotions, showing regular prices: %v", err)
+		return nil
+	}
+	return promotions
+}
+
+// productSale 返回商品的促销价与促销名称，没有促销时返回零值
+func productSale(product *model.Product, promotions []*model.Promotion) (int64, string) {
+	price, promotion := salePrice(product, promotions)
+	if promotion == nil {
+		return 0, ""
+	}
+	return price, promotion.Name
+}
+
+// UpdateStockWithCAS 基于版本号增减库存，版本冲突时重新读取商品后重试
 func (p *ProductServiceImpl) UpdateStockWithCAS(ctx context.Context, id, deta int) error {
+	var err error
+	for i := 0; i < maxCASRetries; i++ {
+		err = p.updateStockWithCAS(ctx, id, deta)
+		if !errors.Is(err, dao.ErrVersionConflict) {
+			return err
+		}
+		log.Logger.Warnf("UpdateStockWithCAS: version conflict, product id: %d, retry: %d", id, i+1)
+	}
+	return err
+}
+
+func (p *ProductServiceImpl) updateStockWithCAS(ctx context.Context, id, deta int) error {
 	pModel, err := p.productDao.GetProductByID(ctx, id)
 	if err != nil {
 		log.Logger.Errorf("UpdateStockWithCAS: get product failed, err: %s", err.Error())
 		return err
 	}
 
-	if int(pModel.Stock) + deta < 0 {
+	// 预售/缺货下单的商品库存可以扣减为负数，最低到 -MaxBackorder
+	if deta < 0 && pModel.Stock+int64(deta) < -backorderAllowance(pModel) {
 		log.Logger.Errorf("UpdateStockWithCAS: do not have enough stock, product id: %d, current stock: %d", id, int(pModel.Stock))
 		return fmt.Errorf("do not have enough stock, product id: %d, current stock: %d", id, int(pModel.Stock))
 	}
 
+	if err := p.checkPurchaseLimit(ctx, pModel, deta); err != nil {
+		return err
+	}
+
 	newStock := int(pModel.Stock) + deta
-	err = p.productDao.UpdateStockWithCAS(ctx, id, int(pModel.Version), newStock)
+	err = p.productDao.UpdateStockWithCAS(ctx, int(pModel.Version), &model.InventoryLedgerEntry{
+		ProductID:   id,
+		Source:      model.InventorySourceOrder,
+		ReferenceID: types.StockReferenceFromContext(ctx),
+		Delta:       deta,
+		StockAfter:  newStock,
+		CustomerID:  types.CustomerFromContext(ctx),
+	})
 	if err != nil {
 		log.Logger.Errorf("UpdateStockWithCAS: update failed, err:%s", err.Error())
 		return err
 	}
+	p.onStockChanged(ctx, pModel, int64(newStock))
 
 	return nil
 }
 
+// onStockChanged 库存更新成功后调用，product 为更新前读取的商品
+func (p *ProductServiceImpl) onStockChanged(ctx context.Context, product *model.Product, newStock int64) {
+	p.checkLowStock(ctx, product, newStock)
+	if product.Status == ProductStatusPublished && product.Stock <= 0 && newStock > 0 {
+		p.notifyBackInStock(ctx, product, newStock)
+	}
+}
+
 // UpdateProductInfo 更新商品信息
 // 要求：
 // 1. 商品必须存在
-// 2. 商品必须处于下架状态
+// 2. 商品必须处于可编辑状态 (草稿或下架)
+// 3. 请求中的版本号必须与当前版本一致，否则视为并发修改冲突
 func (p *ProductServiceImpl) UpdateProductInfo(ctx context.Context, req *types.UpdateProductInfoRequest) error {
+	if req.Version == nil {
+		return types.NewBizError(ProductCheckStatus_InvalidParam, "version is required")
+	}
+
 	// 获取商品信息
 	product, err := p.productDao.GetProductByID(ctx, req.ID)
-	if err != nil {
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		log.Logger.Errorf("UpdateProductInfo: Failed to get product by ID: %v", err)
 		return err
 	}
 	if product == nil {
-		return fmt.Errorf("product not found with ID: %d", req.ID)
+		return newProductNotExistError(req.ID)
 	}
 
 	// 检查商品状态
-	if product.Status != ProductStatusUnpublished {
-		return fmt.Errorf("cannot update product info for published product (ID: %d)", req.ID)
+	if !isProductEditable(product.Status) {
+		return fmt.Errorf("cannot update product info for %s product (ID: %d)", productStatusName(product.Status), req.ID)
+	}
+
+	// 检查版本号
+	if product.Version != *req.Version {
+		return newVersionConflictError(req.ID)
 	}
 
 	// 构建更新的商品模型
@@ -291,15 +440,122 @@ func (p *ProductServiceImpl) UpdateProductInfo(ctx context.Context, req *types.U
 		Capacity:         req.Capacity,
 		CareInstructions: req.CareInstructions,
 		Status:           product.Status, // 保持原有状态
-		Version:          product.Version, // 保持原有版本
+		Version:          *req.Version,   // 客户端读取到的版本，DAO 层据此做 CAS 更新
 	}
 
-	// 调用DAO层更新商品信息
-	err = p.productDao.UpdateProduct(ctx, updatedProduct)
+	// 调用DAO层更新商品信息，价格变化时同时记录价格历史
+	var priceChange *model.ProductPriceChange
+	if req.Price != product.Price {
+		priceChange = newPriceChange(ctx, req.ID, product.Price, req.Price)
+	}
+	err = p.productDao.UpdateProduct(ctx, updatedProduct, priceChange)
 	if err != nil {
+		if errors.Is(err, dao.ErrVersionConflict) {
+			return newVersionConflictError(req.ID)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return newProductNotExistError(req.ID)
+		}
 		log.Logger.Errorf("UpdateProductInfo: Failed to update product: %v", err)
 		return err
 	}
 
 	return nil
 }
+
+// PatchProductInfo 部分更新商品信息，只修改请求中给出的字段
+// 要求与 UpdateProductInfo 相同：商品必须存在、处于可编辑状态且版本号一致
+func (p *ProductServiceImpl) PatchProductInfo(ctx context.Context, id int, req *types.PatchProductInfoRequest) error {
+	if req.Version == nil {
+		return types.NewBizError(ProductCheckStatus_InvalidParam, "version is required")
+	}
+	fields, err := buildProductPatchFields(req)
+	if err != nil {
+		return err
+	}
+
+	product, err := p.productDao.GetProductByID(ctx, id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("PatchProductInfo: Failed to get product by ID: %v", err)
+		return err
+	}
+	if product == nil {
+		return newProductNotExistError(id)
+	}
+	if !isProductEditable(product.Status) {
+		return fmt.Errorf("cannot update product info for %s product (ID: %d)", productStatusName(product.Status), id)
+	}
+	if product.Version != *req.Version {
+		return newVersionConflictError(id)
+	}
+
+	var priceChange *model.ProductPriceChange
+	if req.Price != nil && *req.Price != product.Price {
+		priceChange = newPriceChange(ctx, id, product.Price, *req.Price)
+	}
+	err = p.productDao.PatchProduct(ctx, id, *req.Version, fields, priceChange)
+	if err != nil {
+		if errors.Is(err, dao.ErrVersionConflict) {
+			return newVersionConflictError(id)
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return newProductNotExistError(id)
+		}
+		log.Logger.Errorf("PatchProductInfo: Failed to patch product: %v", err)
+		return err
+	}
+	return nil
+}
+
+// buildProductPatchFields 将请求中非 nil 的字段转换为待更新的列，并逐个校验
+func buildProductPatchFields(req *types.PatchProductInfoRequest) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	requiredStrings := []struct {
+		column string
+		value  *string
+	}{
+		{"name", req.Name},
+		{"category", req.Category},
+	}
+	for _, f := range requiredStrings {
+		if f.value == nil {
+			continue
+		}
+		if strings.TrimSpace(*f.value) == "" {
+			return nil, types.NewBizError(ProductCheckStatus_InvalidParam, fmt.Sprintf("%s cannot be empty", f.column))
+		}
+		fields[f.column] = *f.value
+	}
+	if req.Price != nil {
+		if *req.Price < 0 {
+			return nil, types.NewBizError(ProductCheckStatus_InvalidParam, fmt.Sprintf("invalid price: %d", *req.Price))
+		}
+		fields["price"] = *req.Price
+	}
+	optionalStrings := []struct {
+		column string
+		value  *string
+	}{
+		{"desc", req.Desc},
+		{"pic_info", req.PicInfo},
+		{"dimensions", req.Dimensions},
+		{"material", req.Material},
+		{"weight", req.Weight},
+		{"capacity", req.Capacity},
+		{"care_instructions", req.CareInstructions},
+	}
+	for _, f := range optionalStrings {
+		if f.value != nil {
+			fields[f.column] = *f.value
+		}
+	}
+	if len(fields) == 0 {
+		return nil, types.NewBizError(ProductCheckStatus_InvalidParam, "no fields to update")
+	}
+	return fields, nil
+}
+
+func newVersionConflictError(id int) *types.BizError {
+	return types.NewBizError(ProductCheckStatus_VersionConflict,
+		fmt.Sprintf("product (ID: %d) has been modified by others, please reload and retry", id))
+}
@@ -38,9 +38,14 @@ func TestProductServiceImpl_Create(t *testing.T) {
 		Capacity:         "500ml",
 		Dimensions:       "10x10x10cm",
 		CareInstructions: "Handle with care",
+		StockMode:        StockModeNormal,
 	}
 
-	m.EXPECT().CreateProduct(context.Background(), gomock.Eq(productModel)).Return(1, nil)
+	m.EXPECT().CreateProduct(context.Background(), gomock.Eq(productModel), &model.InventoryLedgerEntry{
+		Source:     model.InventorySourceInitial,
+		Delta:      50,
+		StockAfter: 50,
+	}).Return(1, nil)
 
 	testProductServiceImpl := &ProductServiceImpl{
 		productDao: m,
@@ -92,6 +97,7 @@ func TestProductServiceImpl_GetProductByID(t *testing.T) {
 		Capacity:         "500ml",
 		Dimensions:       "10x10x10cm",
 		CareInstructions: "Handle with care",
+		StockMode:        StockModeNormal,
 	}, nil)
 
 	testProductServiceImpl := &ProductServiceImpl{
@@ -116,6 +122,9 @@ func TestProductServiceImpl_GetProductByID(t *testing.T) {
 		Capacity:         "500ml",
 		Dimensions:       "10x10x10cm",
 		CareInstructions: "Handle with care",
+		StockMode:        StockModeNormal,
+		Availability:     AvailabilityInStock,
+		Currency:         "SGD",
 	}
 
 	if !reflect.DeepEqual(productInfo, expectedProductInfo) {
@@ -158,7 +167,7 @@ func TestProductServiceImpl_PublishProduct(t *testing.T) {
 		CareInstructions: "Handle with care",
 	}, nil)
 
-	m.EXPECT().UpdateProductStatus(context.Background(), 1, 1).Return(nil)
+	m.EXPECT().TransitionProductStatus(context.Background(), &model.ProductStatusTransition{ProductID: 1, FromStatus: 0, ToStatus: 1}).Return(nil)
 
 	testProductServiceImpl := &ProductServiceImpl{
 		productDao: m,
@@ -217,7 +226,7 @@ func TestProductServiceImpl_PublishProduct(t *testing.T) {
 		CareInstructions: "Handle with care",
 	}, nil)
 
-	m.EXPECT().UpdateProductStatus(context.Background(), 5, 1).Return(errors.New("database error"))
+	m.EXPECT().TransitionProductStatus(context.Background(), &model.ProductStatusTransition{ProductID: 5, FromStatus: 0, ToStatus: 1}).Return(errors.New("database error"))
 	err = testProductServiceImpl.PublishProduct(context.Background(), 5)
 	if err == nil {
 		t.Errorf("Expected error, got nil")
@@ -245,7 +254,7 @@ func TestProductServiceImpl_UnpublishProduct(t *testing.T) {
 		CareInstructions: "Handle with care",
 	}, nil)
 
-	m.EXPECT().UpdateProductStatus(context.Background(), 1, 0).Return(nil)
+	m.EXPECT().TransitionProductStatus(context.Background(), &model.ProductStatusTransition{ProductID: 1, FromStatus: 1, ToStatus: 0}).Return(nil)
 
 	testProductServiceImpl := &ProductServiceImpl{
 		productDao: m,
@@ -304,7 +313,7 @@ func TestProductServiceImpl_UnpublishProduct(t *testing.T) {
 		CareInstructions: "Handle with care",
 	}, nil)
 
-	m.EXPECT().UpdateProductStatus(context.Background(), 5, 0).Return(errors.New("database error"))
+	m.EXPECT().TransitionProductStatus(context.Background(), &model.ProductStatusTransition{ProductID: 5, FromStatus: 1, ToStatus: 0}).Return(errors.New("database error"))
 
 	err = testProductServiceImpl.UnpublishProduct(context.Background(), 5)
 	if err == nil {
@@ -337,8 +346,9 @@ func TestProductServiceImpl_GetPublishedProductByID(t *testing.T) {
 		CareInstructions: "小心轻放",
 	}
 	m.EXPECT().GetProductByID(context.Background(), 1).Return(publishedProduct, nil)
+	m.EXPECT().GetLowestPriceSince(context.Background(), 1, gomock.Any()).Return(int64(8000), nil)
 
-	productInfo, err := testProductServiceImpl.GetPublishedProductByID(context.Background(), 1)
+	productInfo, err := testProductServiceImpl.GetPublishedProductByID(context.Background(), 1, "")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -352,6 +362,9 @@ func TestProductServiceImpl_GetPublishedProductByID(t *testing.T) {
 		if productInfo.Status != publishedProduct.Status {
 			t.Errorf("Expected status %d, got %d", publishedProduct.Status, productInfo.Status)
 		}
+		if productInfo.LowestPrice30d != 8000 {
+			t.Errorf("Expected lowest price 8000, got %d", productInfo.LowestPrice30d)
+		}
 	}
 
 	// 测试获取未上架商品（应返回nil）
@@ -361,7 +374,7 @@ func TestProductServiceImpl_GetPublishedProductByID(t *testing.T) {
 	}
 	m.EXPECT().GetProductByID(context.Background(), 2).Return(unpublishedProduct, nil)
 
-	productInfo, err = testProductServiceImpl.GetPublishedProductByID(context.Background(), 2)
+	productInfo, err = testProductServiceImpl.GetPublishedProductByID(context.Background(), 2, "")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -372,7 +385,7 @@ func TestProductServiceImpl_GetPublishedProductByID(t *testing.T) {
 	// 测试获取不存在的商品
 	m.EXPECT().GetProductByID(context.Background(), 3).Return(nil, nil)
 
-	productInfo, err = testProductServiceImpl.GetPublishedProductByID(context.Background(), 3)
+	productInfo, err = testProductServiceImpl.GetPublishedProductByID(context.Background(), 3, "")
 	if err != nil {
 		t.Errorf("Expected no error, got %v", err)
 	}
@@ -383,7 +396,7 @@ func TestProductServiceImpl_GetPublishedProductByID(t *testing.T) {
 	// 测试数据库错误的情况
 	m.EXPECT().GetProductByID(context.Background(), 4).Return(nil, errors.New("database error"))
 
-	productInfo, err = testProductServiceImpl.GetPublishedProductByID(context.Background(), 4)
+	productInfo, err = testProductServiceImpl.GetPublishedProductByID(context.Background(), 4, "")
 	if err == nil {
 		t.Error("Expected database error, got nil")
 	}
@@ -612,6 +625,15 @@ func TestProductServiceImpl_GetProductList(t *testing.T) {
 	}
 }
 
+func orderLedgerEntry(id int, delta int, stockAfter int) *model.InventoryLedgerEntry {
+	return &model.InventoryLedgerEntry{
+		ProductID:  id,
+		Source:     model.InventorySourceOrder,
+		Delta:      delta,
+		StockAfter: stockAfter,
+	}
+}
+
 func TestProductServiceImpl_UpdateStockWithCAS(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -630,7 +652,7 @@ func TestProductServiceImpl_UpdateStockWithCAS(t *testing.T) {
 		Stock:   50,
 		Version: 1,
 	}, nil)
-	m.EXPECT().UpdateStockWithCAS(context.Background(), 1, 1, 60).Return(nil)
+	m.EXPECT().UpdateStockWithCAS(context.Background(), 1, orderLedgerEntry(1, 10, 60)).Return(nil)
 
 	err := testProductServiceImpl.UpdateStockWithCAS(context.Background(), 1, 10)
 	if err != nil {
@@ -646,7 +668,7 @@ func TestProductServiceImpl_UpdateStockWithCAS(t *testing.T) {
 		Stock:   50,
 		Version: 1,
 	}, nil)
-	m.EXPECT().UpdateStockWithCAS(context.Background(), 2, 1, 40).Return(nil)
+	m.EXPECT().UpdateStockWithCAS(context.Background(), 1, orderLedgerEntry(2, -10, 40)).Return(nil)
 
 	err = testProductServiceImpl.UpdateStockWithCAS(context.Background(), 2, -10)
 	if err != nil {
@@ -685,7 +707,7 @@ func TestProductServiceImpl_UpdateStockWithCAS(t *testing.T) {
 		Stock:   50,
 		Version: 1,
 	}, nil)
-	m.EXPECT().UpdateStockWithCAS(context.Background(), 5, 1, 60).Return(fmt.Errorf("version conflict"))
+	m.EXPECT().UpdateStockWithCAS(context.Background(), 1, orderLedgerEntry(5, 10, 60)).Return(fmt.Errorf("version conflict"))
 
 	err = testProductServiceImpl.UpdateStockWithCAS(context.Background(), 5, 10)
 	if err == nil {
@@ -693,6 +715,36 @@ func TestProductServiceImpl_UpdateStockWithCAS(t *testing.T) {
 	}
 }
 
+func TestProductServiceImpl_UpdateStockWithCAS_Retry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{
+		productDao: m,
+	}
+	ctx := context.Background()
+
+	// 第一次版本冲突后重新读取商品并成功扣减
+	gomock.InOrder(
+		m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Stock: 50, Version: 1}, nil),
+		m.EXPECT().UpdateStockWithCAS(ctx, 1, orderLedgerEntry(1, -10, 40)).Return(dao.ErrVersionConflict),
+		m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Stock: 45, Version: 2}, nil),
+		m.EXPECT().UpdateStockWithCAS(ctx, 2, orderLedgerEntry(1, -10, 35)).Return(nil),
+	)
+	if err := testProductServiceImpl.UpdateStockWithCAS(ctx, 1, -10); err != nil {
+		t.Errorf("Expected no error after retry, got %v", err)
+	}
+
+	// 持续冲突时放弃重试并返回冲突错误
+	m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Stock: 50, Version: 1}, nil).Times(maxCASRetries)
+	m.EXPECT().UpdateStockWithCAS(ctx, 1, orderLedgerEntry(2, -10, 40)).Return(dao.ErrVersionConflict).Times(maxCASRetries)
+	err := testProductServiceImpl.UpdateStockWithCAS(ctx, 2, -10)
+	if !errors.Is(err, dao.ErrVersionConflict) {
+		t.Errorf("Expected version conflict error, got %v", err)
+	}
+}
+
 func TestProductServiceImpl_UpdateProductInfo(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -722,8 +774,10 @@ func TestProductServiceImpl_UpdateProductInfo(t *testing.T) {
 		Version:          1,
 	}
 
+	version := int64(1)
 	updateRequest := &types.UpdateProductInfoRequest{
 		ID:               1,
+		Version:          &version,
 		Name:             "Updated Product Name",
 		Category:         "Updated Category",
 		Price:            200,
@@ -754,7 +808,14 @@ func TestProductServiceImpl_UpdateProductInfo(t *testing.T) {
 	}
 
 	m.EXPECT().GetProductByID(context.Background(), 1).Return(existingProduct, nil)
-	m.EXPECT().UpdateProduct(context.Background(), expectedUpdatedProduct).Return(nil)
+	m.EXPECT().UpdateProduct(context.Background(), expectedUpdatedProduct, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, product *model.Product, priceChange *model.ProductPriceChange) error {
+			// 价格由 100 改为 200，需要记录价格历史
+			if priceChange == nil || priceChange.ProductID != 1 || priceChange.OldPrice != 100 || priceChange.NewPrice != 200 {
+				t.Errorf("Unexpected price change: %+v", priceChange)
+			}
+			return nil
+		})
 
 	err := testProductServiceImpl.UpdateProductInfo(context.Background(), updateRequest)
 	if err != nil {
@@ -765,8 +826,9 @@ func TestProductServiceImpl_UpdateProductInfo(t *testing.T) {
 	m.EXPECT().GetProductByID(context.Background(), 2).Return(nil, errors.New("product not found"))
 
 	updateRequest2 := &types.UpdateProductInfoRequest{
-		ID:   2,
-		Name: "Test Product",
+		ID:      2,
+		Version: &version,
+		Name:    "Test Product",
 	}
 
 	err = testProductServiceImpl.UpdateProductInfo(context.Background(), updateRequest2)
@@ -778,8 +840,9 @@ func TestProductServiceImpl_UpdateProductInfo(t *testing.T) {
 	m.EXPECT().GetProductByID(context.Background(), 3).Return(nil, nil)
 
 	updateRequest3 := &types.UpdateProductInfoRequest{
-		ID:   3,
-		Name: "Test Product",
+		ID:      3,
+		Version: &version,
+		Name:    "Test Product",
 	}
 
 	err = testProductServiceImpl.UpdateProductInfo(context.Background(), updateRequest3)
@@ -799,8 +862,9 @@ func TestProductServiceImpl_UpdateProductInfo(t *testing.T) {
 	m.EXPECT().GetProductByID(context.Background(), 4).Return(publishedProduct, nil)
 
 	updateRequest4 := &types.UpdateProductInfoRequest{
-		ID:   4,
-		Name: "Updated Name",
+		ID:      4,
+		Version: &version,
+		Name:    "Updated Name",
 	}
 
 	err = testProductServiceImpl.UpdateProductInfo(context.Background(), updateRequest4)
@@ -819,9 +883,11 @@ func TestProductServiceImpl_UpdateProductInfo(t *testing.T) {
 		Version: 2,
 	}
 
+	version5 := int64(2)
 	updateRequest5 := &types.UpdateProductInfoRequest{
-		ID:   5,
-		Name: "Updated Name",
+		ID:      5,
+		Version: &version5,
+		Name:    "Updated Name",
 	}
 
 	expectedUpdatedProduct5 := &model.Product{
@@ -842,12 +908,42 @@ func TestProductServiceImpl_UpdateProductInfo(t *testing.T) {
 	}
 
 	m.EXPECT().GetProductByID(context.Background(), 5).Return(unpublishedProduct, nil)
-	m.EXPECT().UpdateProduct(context.Background(), expectedUpdatedProduct5).Return(errors.New("database error"))
+	m.EXPECT().UpdateProduct(context.Background(), expectedUpdatedProduct5, nil).Return(errors.New("database error"))
 
 	err = testProductServiceImpl.UpdateProductInfo(context.Background(), updateRequest5)
 	if err == nil {
 		t.Error("Expected error when DAO update fails, got nil")
 	}
+	// 测试缺少版本号的情况
+	err = testProductServiceImpl.UpdateProductInfo(context.Background(), &types.UpdateProductInfoRequest{ID: 1, Name: "No Version"})
+	if err == nil {
+		t.Error("Expected error when version is missing, got nil")
+	}
+
+	// 测试客户端版本落后的情况
+	staleVersion := int64(0)
+	m.EXPECT().GetProductByID(context.Background(), 1).Return(existingProduct, nil)
+	err = testProductServiceImpl.UpdateProductInfo(context.Background(), &types.UpdateProductInfoRequest{ID: 1, Version: &staleVersion, Name: "Stale"})
+	var bizErr *types.BizError
+	if !errors.As(err, &bizErr) || bizErr.Code != ProductCheckStatus_VersionConflict {
+		t.Errorf("Expected version conflict error for stale version, got %v", err)
+	}
+
+	// 测试读取后被并发修改导致 CAS 更新失败的情况
+	m.EXPECT().GetProductByID(context.Background(), 1).Return(existingProduct, nil)
+	m.EXPECT().UpdateProduct(context.Background(), gomock.Any(), gomock.Any()).Return(dao.ErrVersionConflict)
+	err = testProductServiceImpl.UpdateProductInfo(context.Background(), updateRequest)
+	if !errors.As(err, &bizErr) || bizErr.Code != ProductCheckStatus_VersionConflict {
+		t.Errorf("Expected version conflict error when CAS update fails, got %v", err)
+	}
+}
+
+func merchantSetLedgerEntry(id int, stock int) *model.InventoryLedgerEntry {
+	return &model.InventoryLedgerEntry{
+		ProductID:  id,
+		Source:     model.InventorySourceMerchantSet,
+		StockAfter: stock,
+	}
 }
 
 func TestProductServiceImpl_UpdateProductStock(t *testing.T) {
@@ -865,7 +961,7 @@ func TestProductServiceImpl_UpdateProductStock(t *testing.T) {
 		Stock:  50,
 		Status: 0,
 	}, nil)
-	m.EXPECT().UpdateProductStock(context.Background(), 1, 60).Return(nil)
+	m.EXPECT().UpdateProductStock(context.Background(), merchantSetLedgerEntry(1, 60)).Return(nil)
 
 	err := testProductServiceImpl.UpdateProductStock(context.Background(), 1, 60)
 	if err != nil {
@@ -894,7 +990,7 @@ func TestProductServiceImpl_UpdateProductStock(t *testing.T) {
 		Stock:  50,
 		Status: 0,
 	}, nil)
-	m.EXPECT().UpdateProductStock(context.Background(), 4, 70).Return(errors.New("database error"))
+	m.EXPECT().UpdateProductStock(context.Background(), merchantSetLedgerEntry(4, 70)).Return(errors.New("database error"))
 
 	err = testProductServiceImpl.UpdateProductStock(context.Background(), 4, 70)
 	if err == nil {
@@ -913,7 +1009,7 @@ func TestProductServiceImpl_UpdateProductStock(t *testing.T) {
 		Stock:  50,
 		Status: 0,
 	}, nil)
-	m.EXPECT().UpdateProductStock(context.Background(), 6, 0).Return(nil)
+	m.EXPECT().UpdateProductStock(context.Background(), merchantSetLedgerEntry(6, 0)).Return(nil)
 
 	err = testProductServiceImpl.UpdateProductStock(context.Background(), 6, 0)
 	if err != nil {
@@ -932,3 +1028,123 @@ func TestProductServiceImpl_UpdateProductStock(t *testing.T) {
 		t.Errorf("Expected error when updating stock for published product, got nil")
 	}
 }
+
+func TestProductServiceImpl_PatchProductInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{
+		productDao: m,
+	}
+	ctx := context.Background()
+	strPtr := func(s string) *string { return &s }
+	int64Ptr := func(i int64) *int64 { return &i }
+
+	existingProduct := &model.Product{
+		Model:            gorm.Model{ID: 1},
+		Name:             "Old Name",
+		Status:           ProductStatusUnpublished,
+		CareInstructions: "Hand wash",
+		Version:          3,
+	}
+
+	t.Run("只更新传入的字段，空字符串会清空字段", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(existingProduct, nil)
+		m.EXPECT().PatchProduct(ctx, 1, int64(3), map[string]interface{}{
+			"price":             int64(500),
+			"care_instructions": "",
+		}, gomock.Any()).DoAndReturn(func(ctx context.Context, id int, version int64, fields map[string]interface{}, priceChange *model.ProductPriceChange) error {
+			if priceChange == nil || priceChange.OldPrice != 0 || priceChange.NewPrice != 500 {
+				t.Errorf("Unexpected price change: %+v", priceChange)
+			}
+			return nil
+		})
+
+		err := testProductServiceImpl.PatchProductInfo(ctx, 1, &types.PatchProductInfoRequest{
+			Version:          int64Ptr(3),
+			Price:            int64Ptr(500),
+			CareInstructions: strPtr(""),
+		})
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("价格不变时不记录价格历史", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(existingProduct, nil)
+		m.EXPECT().PatchProduct(ctx, 1, int64(3), map[string]interface{}{
+			"name":  "New Name",
+			"price": int64(0),
+		}, nil).Return(nil)
+
+		err := testProductServiceImpl.PatchProductInfo(ctx, 1, &types.PatchProductInfoRequest{
+			Version: int64Ptr(3),
+			Name:    strPtr("New Name"),
+			Price:   int64Ptr(0),
+		})
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("校验失败的字段直接返回错误", func(t *testing.T) {
+		reqs := []*types.PatchProductInfoRequest{
+			{Price: int64Ptr(1)},                        // 缺少版本号
+			{Version: int64Ptr(3)},                      // 没有需要修改的字段
+			{Version: int64Ptr(3), Name: strPtr("  ")},  // 名称不能为空
+			{Version: int64Ptr(3), Price: int64Ptr(-1)}, // 价格不能为负
+		}
+		for _, req := range reqs {
+			err := testProductServiceImpl.PatchProductInfo(ctx, 1, req)
+			var bizErr *types.BizError
+			if !errors.As(err, &bizErr) || bizErr.Code != ProductCheckStatus_InvalidParam {
+				t.Errorf("Expected invalid param error for %+v, got %v", req, err)
+			}
+		}
+	})
+
+	t.Run("版本号不一致时返回冲突", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(existingProduct, nil)
+		err := testProductServiceImpl.PatchProductInfo(ctx, 1, &types.PatchProductInfoRequest{
+			Version: int64Ptr(2),
+			Name:    strPtr("New Name"),
+		})
+		var bizErr *types.BizError
+		if !errors.As(err, &bizErr) || bizErr.Code != ProductCheckStatus_VersionConflict {
+			t.Errorf("Expected version conflict error, got %v", err)
+		}
+	})
+
+	t.Run("已上架商品不能修改", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Status: ProductStatusPublished, Version: 1}, nil)
+		err := testProductServiceImpl.PatchProductInfo(ctx, 2, &types.PatchProductInfoRequest{
+			Version: int64Ptr(1),
+			Name:    strPtr("New Name"),
+		})
+		if err == nil {
+			t.Error("Expected error when patching published product, got nil")
+		}
+	})
+}
+
+func TestProductServiceImpl_UpdateProductInfo_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := context.Background()
+	version := int64(1)
+	name := "Mug"
+
+	m.EXPECT().GetProductByID(ctx, 1).Return(nil, gorm.ErrRecordNotFound)
+	err := testProductServiceImpl.UpdateProductInfo(ctx, &types.UpdateProductInfoRequest{ID: 1, Version: &version, Name: name})
+	expectBizErrorCode(t, err, ProductCheckStatus_NotExist)
+
+	// 读取后、更新前商品被删除
+	m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Status: ProductStatusUnpublished, Version: 1}, nil)
+	m.EXPECT().PatchProduct(ctx, 2, version, gomock.Any(), nil).Return(gorm.ErrRecordNotFound)
+	err = testProductServiceImpl.PatchProductInfo(ctx, 2, &types.PatchProductInfoRequest{Version: &version, Name: &name})
+	expectBizErrorCode(t, err, ProductCheckStatus_NotExist)
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+)
+
+// PromotionService lets merchants manage automatic promotions, which apply without a code
+// while their time window is open.
+type PromotionService interface {
+	CreatePromotion(ctx context.Context, req *data.CreatePromotionRequest) (promotionId int, err error)
+	ListPromotions(ctx context.Context, offset, limit int) ([]*data.PromotionVO, int64, error)
+	SetPromotionActive(ctx context.Context, id int, active bool) error
+}
+
+var (
+	promotionServiceInstance PromotionService
+	promotionServiceSyncOnce sync.Once
+)
+
+func GetPromotionService() PromotionService {
+	promotionServiceSyncOnce.Do(func() {
+		promotionServiceInstance = &PromotionServiceImpl{
+			promotionDao: dao.GetPromotionDao(),
+		}
+	})
+	return promotionServiceInstance
+}
+
+type PromotionServiceImpl struct {
+	promotionDao dao.PromotionDao
+}
+
+const (
+	PromotionCheckStatus_NotExist     = -50
+	PromotionCheckStatus_InvalidParam = -51
+)
+
+// CreatePromotion implements PromotionService.
+func (s *PromotionServiceImpl) CreatePromotion(ctx context.Context, req *data.CreatePromotionRequest) (int, error) {
+	if err := checkPromotionRequest(req); err != nil {
+		return -1, err
+	}
+	categories, productIds := joinScope(req.Categories, req.ProductIDs)
+	promotion := &model.Promotion{
+		Name:       req.Name,
+		Type:       req.Type,
+		Categories: categories,
+		ProductIDs: productIds,
+		StartsAt:   req.StartsAt,
+		EndsAt:     req.EndsAt,
+		Active:     true,
+	}
+	switch req.Type {
+	case model.PromotionTypeSale:
+		promotion.Percentage = req.Percentage
+	case model.PromotionTypeBundle:
+		promotion.BuyQuantity = req.BuyQuantity
+		promotion.FreeQuantity = req.FreeQuantity
+	case model.PromotionTypeTiered:
+		promotion.Tiers = make([]model.PromotionTier, 0, len(req.Tiers))
+		for _, tier := range req.Tiers {
+			promotion.Tiers = append(promotion.Tiers, model.PromotionTier{MinQuantity: tier.MinQuantity, Percentage: tier.Percentage})
+		}
+		sort.Slice(promotion.Tiers, func(i, j int) bool {
+			return promotion.Tiers[i].MinQuantity < promotion.Tiers[j].MinQuantity
+		})
+	}
+	id, err := s.promotionDao.CreatePromotion(ctx, promotion)
+	if err != nil {
+		log.Logger.Errorf("PromotionService: CreatePromotion: Failed to create promotion %s: %v", req.Name, err)
+		return -1, err
+	}
+	return id, nil
+}
+
+func checkPromotionRequest(req *data.CreatePromotionRequest) error {
+	switch req.Type {
+	case model.PromotionTypeSale:
+		// a sale price of 0 would read as "no sale"
+		if req.Percentage < 1 || req.Percentage > 99 {
+			return types.NewBizError(PromotionCheckStatus_InvalidParam, "percentage must be between 1 and 99")
+		}
+	case model.PromotionTypeBundle:
+		if req.BuyQuantity < 1 || req.FreeQuantity < 1 {
+			return types.NewBizError(PromotionCheckStatus_InvalidParam, "buy_quantity and free_quantity must be at least 1")
+		}
+	case model.PromotionTypeTiered:
+		if len(req.Tiers) == 0 {
+			return types.NewBizError(PromotionCheckStatus_InvalidParam, "at least one tier is required")
+		}
+		seen := make(map[int]bool, len(req.Tiers))
+		for _, tier := range req.Tiers {
+			if tier.MinQuantity < 1 || tier.Percentage < 1 || tier.Percentage > 100 {
+				return types.NewBizError(PromotionCheckStatus_InvalidParam, "tiers need a min_quantity of at least 1 and a percentage between 1 and 100")
+			}
+			if seen[tier.MinQuantity] {
+				return types.NewBizError(PromotionCheckStatus_InvalidParam, fmt.Sprintf("duplicate tier for min_quantity %d", tier.MinQuantity))
+			}
+			seen[tier.MinQuantity] = true
+		}
+	default:
+		return types.NewBizError(PromotionCheckStatus_InvalidParam, fmt.Sprintf("unknown promotion type %q", req.Type))
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return types.NewBizError(PromotionCheckStatus_InvalidParam, "ends_at must be after starts_at")
+	}
+	for _, id := range req.ProductIDs {
+		if id <= 0 {
+			return types.NewBizError(PromotionCheckStatus_InvalidParam, fmt.Sprintf("invalid product ID %d", id))
+		}
+	}
+	return nil
+}
+
+// ListPromotions implements PromotionService.
+func (s *PromotionServiceImpl) ListPromotions(ctx context.Context, offset, limit int) ([]*data.PromotionVO, int64, error) {
+	promotions, total, err := s.promotionDao.ListPromotions(ctx, offset, limit)
+	if err != nil {
+		log.Logger.Errorf("PromotionService: ListPromotions: Failed to list promotions: %v", err)
+		return nil, 0, err
+	}
+	ret := make([]*data.PromotionVO, 0, len(promotions))
+	for _, promotion := range promotions {
+		scope := newDiscountScope(promotion.Categories, promotion.ProductIDs)
+		tiers := make([]data.PromotionTierVO, 0, len(promotion.Tiers))
+		for _, tier := range promotion.Tiers {
+			tiers = append(tiers, data.PromotionTierVO{MinQuantity: tier.MinQuantity, Percentage: tier.Percentage})
+		}
+		ret = append(ret, &data.PromotionVO{
+			ID:           promotion.ID,
+			Name:         promotion.Name,
+			Type:         promotion.Type,
+			Percentage:   promotion.Percentage,
+			BuyQuantity:  promotion.BuyQuantity,
+			FreeQuantity: promotion.FreeQuantity,
+			Tiers:        tiers,
+			Categories:   scope.categories,
+			ProductIDs:   scope.productIds,
+			StartsAt:     promotion.StartsAt,
+			EndsAt:       promotion.EndsAt,
+			Active:       promotion.Active,
+			CreatedAt:    promotion.CreatedAt,
+		})
+	}
+	return ret, total, nil
+}
+
+// SetPromotionActive implements PromotionService.
+func (s *PromotionServiceImpl) SetPromotionActive(ctx context.Context, id int, active bool) error {
+	promotion, err := s.promotionDao.GetPromotionByID(ctx, id)
+	if err != nil {
+		log.Logger.Errorf("PromotionService: SetPromotionActive: Failed to get promotion %d: %v", id, err)
+		return err
+	}
+	if promotion == nil {
+		return types.NewBizError(PromotionCheckStatus_NotExist, fmt.Sprintf("promotion not found with ID: %d", id))
+	}
+	if err := s.promotionDao.UpdatePromotionActive(ctx, id, active); err != nil {
+		log.Logger.Errorf("PromotionService: SetPromotionActive: Failed to update promotion %d: %v", id, err)
+		return err
+	}
+	return nil
+}
+
+// activePromotions returns the promotions running now; services without a promotion DAO have none.
+func activePromotions(ctx context.Context, promotionDao dao.PromotionDao) ([]*model.Promotion, error) {
+	if promotionDao == nil {
+		return nil, nil
+	}
+	return promotionDao.ListActivePromotions(ctx, time.Now())
+}
+
+// salePrice returns the lowest price any running sale gives the product, and that sale.
+// It returns the regular price and nil when no sale applies.
+func salePrice(product *model.Product, promotions []*model.Promotion) (int64, *model.Promotion) {
+	price, best := product.Price, (*model.Promotion)(nil)
+	for _, promotion := range promotions {
+		if promotion.Type != model.PromotionTypeSale {
+			continue
+		}
+		if !newDiscountScope(promotion.Categories, promotion.ProductIDs).contains(int(product.ID), product.Category) {
+			continue
+		}
+		if discounted := product.Price - product.Price*int64(promotion.Percentage)/100; discounted < price {
+			price, best = discounted, promotion
+		}
+	}
+	return price, best
+}
+
+// promotionDiscounter applies the bundle and tiered promotions running when the cart is priced.
+// Sale promotions are already part of the unit prices.
+type promotionDiscounter struct {
+	promotionDao dao.PromotionDao
+}
+
+func (d *promotionDiscounter) Discounts(ctx context.Context, q *pricing.Quote) ([]*pricing.Line, error) {
+	promotions, err := activePromotions(ctx, d.promotionDao)
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]*pricing.Line, 0)
+	for _, promotion := range promotions {
+		var amount int
+		switch promotion.Type {
+		case model.PromotionTypeBundle:
+			amount = bundleDiscount(promotion, q.Items)
+		case model.PromotionTypeTiered:
+			amount = tieredDiscount(promotion, q.Items)
+		}
+		if amount > 0 {
+			lines = append(lines, &pricing.Line{
+				Code:        fmt.Sprintf("promotion-%d", promotion.ID),
+				Description: promotion.Name,
+				Amount:      amount,
+			})
+		}
+	}
+	return lines, nil
+}
+
+// bundleDiscount groups the eligible units from the most to the least expensive; in every full
+// group of BuyQuantity+FreeQuantity units the cheapest FreeQuantity are free.
+func bundleDiscount(promotion *model.Promotion, items []*pricing.Item) int {
+	groupSize := promotion.BuyQuantity + promotion.FreeQuantity
+	if promotion.BuyQuantity <= 0 || promotion.FreeQuantity <= 0 {
+		return 0
+	}
+	scope := newDiscountScope(promotion.Categories, promotion.ProductIDs)
+	units := make([]int, 0)
+	for _, item := range items {
+		if !scope.contains(item.ProductID, item.Category) {
+			continue
+		}
+		for i := 0; i < item.Quantity; i++ {
+			units = append(units, item.UnitPrice)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(units)))
+	amount := 0
+	for start := 0; start+groupSize <= len(units); start += groupSize {
+		for _, price := range units[start+promotion.BuyQuantity : start+groupSize] {
+			amount += price
+		}
+	}
+	return amount
+}
+
+// tieredDiscount takes the percentage of the highest tier the eligible quantity reaches.
+func tieredDiscount(promotion *model.Promotion, items []*pricing.Item) int {
+	scope := newDiscountScope(promotion.Categories, promotion.ProductIDs)
+	quantity, subtotal := 0, 0
+	for _, item := range items {
+		if scope.contains(item.ProductID, item.Category) {
+			quantity += item.Quantity
+			subtotal += item.UnitPrice * item.Quantity
+		}
+	}
+	percentage := 0
+	for _, tier := range promotion.Tiers {
+		if quantity >= tier.MinQuantity && tier.Percentage > percentage {
+			percentage = tier.Percentage
+		}
+	}
+	return subtotal * percentage / 100
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/pricing"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestCreatePromotion(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	start := time.Now()
+	end := start.Add(7 * 24 * time.Hour)
+
+	t.Run("stores tiers in ascending order", func(t *testing.T) {
+		promotionDao := mocks.NewMockPromotionDao(ctrl)
+		s := &PromotionServiceImpl{promotionDao: promotionDao}
+		promotionDao.EXPECT().CreatePromotion(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, promotion *model.Promotion) (int, error) {
+			if len(promotion.Tiers) != 2 || promotion.Tiers[0].MinQuantity != 3 || promotion.Categories != "plate" || !promotion.Active {
+				t.Errorf("Unexpected promotion: %+v", promotion)
+			}
+			return 4, nil
+		})
+		id, err := s.CreatePromotion(ctx, &data.CreatePromotionRequest{
+			Name:       "Plate sets",
+			Type:       model.PromotionTypeTiered,
+			Tiers:      []data.PromotionTierVO{{MinQuantity: 6, Percentage: 15}, {MinQuantity: 3, Percentage: 10}},
+			Categories: []string{"plate"},
+			StartsAt:   start,
+			EndsAt:     end,
+		})
+		if err != nil || id != 4 {
+			t.Errorf("Expected promotion 4, got %d, %v", id, err)
+		}
+	})
+
+	t.Run("rejects invalid promotions", func(t *testing.T) {
+		s := &PromotionServiceImpl{promotionDao: mocks.NewMockPromotionDao(ctrl)}
+		reqs := []*data.CreatePromotionRequest{
+			{Name: "free vases", Type: model.PromotionTypeSale, Percentage: 100, StartsAt: start, EndsAt: end},
+			{Name: "buy 2", Type: model.PromotionTypeBundle, BuyQuantity: 2, StartsAt: start, EndsAt: end},
+			{Name: "no tiers", Type: model.PromotionTypeTiered, StartsAt: start, EndsAt: end},
+			{Name: "same tier", Type: model.PromotionTypeTiered, Tiers: []data.PromotionTierVO{{MinQuantity: 2, Percentage: 5}, {MinQuantity: 2, Percentage: 10}}, StartsAt: start, EndsAt: end},
+			{Name: "backwards", Type: model.PromotionTypeSale, Percentage: 20, StartsAt: end, EndsAt: start},
+		}
+		for _, req := range reqs {
+			_, err := s.CreatePromotion(ctx, req)
+			var bizErr *types.BizError
+			if !errors.As(err, &bizErr) || bizErr.Code != PromotionCheckStatus_InvalidParam {
+				t.Errorf("Expected InvalidParam for %q, got %v", req.Name, err)
+			}
+		}
+	})
+}
+
+func TestSalePrice(t *testing.T) {
+	vase := &model.Product{Model: gorm.Model{ID: 1}, Category: "vase", Price: 10000}
+	promotions := []*model.Promotion{
+		{ID: 1, Name: "Vase week", Type: model.PromotionTypeSale, Percentage: 20, Categories: "vase"},
+		{ID: 2, Name: "Clearance", Type: model.PromotionTypeSale, Percentage: 30, ProductIDs: "1,7"},
+		{ID: 3, Name: "Mugs", Type: model.PromotionTypeSale, Percentage: 50, Categories: "mug"},
+		{ID: 4, Name: "Bundle", Type: model.PromotionTypeBundle, BuyQuantity: 1, FreeQuantity: 1},
+	}
+	price, promotion := salePrice(vase, promotions)
+	if price != 7000 || promotion == nil || promotion.ID != 2 {
+		t.Errorf("Expected the best sale (7000 from promotion 2), got %d, %+v", price, promotion)
+	}
+	if price, promotion := salePrice(vase, promotions[2:]); price != 10000 || promotion != nil {
+		t.Errorf("Expected the regular price, got %d, %+v", price, promotion)
+	}
+}
+
+func TestBundleAndTieredDiscount(t *testing.T) {
+	items := []*pricing.Item{
+		{ProductID: 1, Category: "mug", UnitPrice: 3000, Quantity: 2},
+		{ProductID: 2, Category: "mug", UnitPrice: 1000, Quantity: 3},
+		{ProductID: 3, Category: "plate", UnitPrice: 500, Quantity: 4},
+	}
+	bundle := &model.Promotion{Type: model.PromotionTypeBundle, BuyQuantity: 2, FreeQuantity: 1, Categories: "mug"}
+	// mugs sorted 3000, 3000, 1000 | 1000, 1000: only the first group is complete
+	if amount := bundleDiscount(bundle, items); amount != 1000 {
+		t.Errorf("Expected bundle discount 1000, got %d", amount)
+	}
+	tiered := &model.Promotion{Type: model.PromotionTypeTiered, Categories: "plate", Tiers: []model.PromotionTier{
+		{MinQuantity: 2, Percentage: 5}, {MinQuantity: 4, Percentage: 10}, {MinQuantity: 6, Percentage: 20},
+	}}
+	if amount := tieredDiscount(tiered, items); amount != 200 {
+		t.Errorf("Expected tiered discount 200, got %d", amount)
+	}
+}
+
+func TestCartService_Promotions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	promotions := []*model.Promotion{
+		{ID: 1, Name: "Vase week", Type: model.PromotionTypeSale, Percentage: 20, Categories: "vase"},
+		{ID: 2, Name: "Buy 2 mugs get 1 free", Type: model.PromotionTypeBundle, BuyQuantity: 2, FreeQuantity: 1, Categories: "mug"},
+	}
+	cartItems := []*model.ShoppingCartItem{
+		{ID: 1, UserID: 1, ProductID: 1, Quantity: 1, SelectStatus: model.CartItemStatusSelected, PriceSnapshot: 8000},
+		{ID: 2, UserID: 1, ProductID: 2, Quantity: 3, SelectStatus: model.CartItemStatusSelected, PriceSnapshot: 2000},
+	}
+	products := []*model.Product{
+		{Model: gorm.Model{ID: 1}, Category: "vase", Price: 10000, Stock: 5, Status: ProductStatu_Online},
+		{Model: gorm.Model{ID: 2}, Category: "mug", Price: 2000, Stock: 5, Status: ProductStatu_Online},
+	}
+	newService := func(promotionDao dao.PromotionDao) *CartServiceImpl {
+		cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+		productDao := mocks.NewMockProductDao(ctrl)
+		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return(cartItems, nil)
+		productDao.EXPECT().GetProductByIDs(ctx, []int{1, 2}).Return(products, nil)
+		rules := &pricing.RuleSet{DefaultRegion: "sg", Regions: map[string]*pricing.RegionRules{"sg": {}}}
+		return &CartServiceImpl{
+			cartItemDao:  cartItemDao,
+			productDao:   productDao,
+			promotionDao: promotionDao,
+			pricer: pricing.NewPipeline(rules, pricing.SubtotalStage(),
+				pricing.DiscountStage(&promotionDiscounter{promotionDao: promotionDao}), pricing.ShippingStage(), pricing.TaxStage()),
+		}
+	}
+
+	t.Run("GetCartItems shows sale prices", func(t *testing.T) {
+		promotionDao := mocks.NewMockPromotionDao(ctrl)
+		promotionDao.EXPECT().ListActivePromotions(ctx, gomock.Any()).Return(promotions, nil)
+		result, err := newService(promotionDao).GetCartItems(ctx, 1, "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		vase := result.CartItems[0]
+		if vase.ProductInfo.Price != 10000 || vase.ProductInfo.SalePrice != 8000 || vase.ProductInfo.PromotionName != "Vase week" || vase.TotalPrice != 8000 {
+			t.Errorf("Unexpected vase item: %+v", vase)
+		}
+		if result.SelectedPrice != 8000+6000 {
+			t.Errorf("Expected selected price 14000, got %d", result.SelectedPrice)
+		}
+	})
+
+	t.Run("EstimatePrice applies sales and bundles", func(t *testing.T) {
+		promotionDao := mocks.NewMockPromotionDao(ctrl)
+		promotionDao.EXPECT().ListActivePromotions(ctx, gomock.Any()).Return(promotions, nil).Times(2)
+		result, err := newService(promotionDao).EstimatePrice(ctx, 1, "", "")
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if result.ProductPrice != 14000 || result.Discount != 2000 || result.Total != 12000 {
+			t.Errorf("Unexpected estimate: %+v", result)
+		}
+		if result.Lines[1].Code != "promotion-2" || result.Lines[1].Amount != -2000 {
+			t.Errorf("Expected a bundle line, got %+v", result.Lines[1])
+		}
+	})
+}
+
+func TestProductServiceImpl_GetPublishedProductByID_Sale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	productDao := mocks.NewMockProductDao(ctrl)
+	promotionDao := mocks.NewMockPromotionDao(ctrl)
+	p := &ProductServiceImpl{productDao: productDao, promotionDao: promotionDao}
+	productDao.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{
+		Model: gorm.Model{ID: 1}, Category: "vase", Price: 10000, Stock: 1, Status: ProductStatusPublished,
+	}, nil).Times(2)
+	productDao.EXPECT().GetLowestPriceSince(ctx, 1, gomock.Any()).Return(int64(10000), nil).Times(2)
+
+	promotionDao.EXPECT().ListActivePromotions(ctx, gomock.Any()).Return([]*model.Promotion{
+		{ID: 1, Name: "Vase week", Type: model.PromotionTypeSale, Percentage: 20, Categories: "vase"},
+	}, nil)
+	info, err := p.GetPublishedProductByID(ctx, 1, "")
+	if err != nil || info.Price != 10000 || info.SalePrice != 8000 || info.PromotionName != "Vase week" {
+		t.Errorf("Expected the sale price, got %+v, %v", info, err)
+	}
+
+	// the product page still renders at the regular price when promotions cannot be loaded
+	promotionDao.EXPECT().ListActivePromotions(ctx, gomock.Any()).Return(nil, errors.New("db down"))
+	info, err = p.GetPublishedProductByID(ctx, 1, "")
+	if err != nil || info.SalePrice != 0 {
+		t.Errorf("Expected the regular price, got %+v, %v", info, err)
+	}
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"gorm.io/gorm"
+)
+
+// SetPurchaseLimit 设置每位顾客累计最多购买的数量，0 表示不限购。
+// 已下单的数量不受影响，新的限购只约束之后的加购和下单
+func (p *ProductServiceImpl) SetPurchaseLimit(ctx context.Context, id int, maxPerCustomer int) error {
+	if maxPerCustomer < 0 {
+		return types.NewBizError(ProductCheckStatus_InvalidParam, "purchase limit cannot be negative")
+	}
+	product, err := p.productDao.GetProductByID(ctx, id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("SetPurchaseLimit: Failed to get product by ID: %v", err)
+		return err
+	}
+	if product == nil {
+		return newProductNotExistError(id)
+	}
+	if err := p.productDao.UpdatePurchaseLimit(ctx, id, maxPerCustomer); err != nil {
+		log.Logger.Errorf("SetPurchaseLimit: Failed to update purchase limit: %v", err)
+		return err
+	}
+	return nil
+}
+
+// checkPurchaseLimit 下单扣减库存时检查顾客累计购买数量 (含本次) 是否超过限购。
+// 订单服务未传递顾客时无法统计，限购商品的扣减直接拒绝。同一商品的并发扣减会因版本号冲突重试，重试时重新统计
+func (p *ProductServiceImpl) checkPurchaseLimit(ctx context.Context, product *model.Product, deta int) error {
+	if deta >= 0 || product.MaxPerCustomer <= 0 {
+		return nil
+	}
+	customerID := types.CustomerFromContext(ctx)
+	if customerID <= 0 {
+		log.Logger.Warnf("UpdateStockWithCAS: customer unknown for product with purchase limit, product id: %d", product.ID)
+		return types.NewBizError(ProductCheckStatus_InvalidParam,
+			fmt.Sprintf("product ID %d is limited to %d per customer, the ordering customer is required", product.ID, product.MaxPerCustomer))
+	}
+	purchased, err := p.productDao.SumCustomerOrderQuantity(ctx, int(product.ID), customerID)
+	if err != nil {
+		log.Logger.Errorf("UpdateStockWithCAS: Failed to sum order quantity of customer %d: %v", customerID, err)
+		return err
+	}
+	if int64(purchased-deta) > product.MaxPerCustomer {
+		log.Logger.Warnf("UpdateStockWithCAS: purchase limit exceeded, product id: %d, customer: %d, purchased: %d, deta: %d",
+			product.ID, customerID, purchased, deta)
+		return types.NewBizError(ProductCheckStatus_PurchaseLimitExceeded,
+			fmt.Sprintf("product ID %d is limited to %d per customer, %d already purchased", product.ID, product.MaxPerCustomer, purchased))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestProductServiceImpl_UpdateStockWithCAS_PurchaseLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := types.WithCustomer(types.WithStockReference(context.Background(), "ORD-2001"), 9)
+	product := &model.Product{Model: gorm.Model{ID: 1}, Stock: 50, Version: 2, MaxPerCustomer: 5}
+
+	t.Run("rejects an order over the limit", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(product, nil)
+		m.EXPECT().SumCustomerOrderQuantity(ctx, 1, 9).Return(3, nil)
+
+		err := testProductServiceImpl.UpdateStockWithCAS(ctx, 1, -3)
+		expectBizErrorCode(t, err, ProductCheckStatus_PurchaseLimitExceeded)
+	})
+
+	t.Run("records the customer on the ledger", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(product, nil)
+		m.EXPECT().SumCustomerOrderQuantity(ctx, 1, 9).Return(3, nil)
+		m.EXPECT().UpdateStockWithCAS(ctx, 2, &model.InventoryLedgerEntry{
+			ProductID:   1,
+			Source:      model.InventorySourceOrder,
+			ReferenceID: "ORD-2001",
+			Delta:       -2,
+			StockAfter:  48,
+			CustomerID:  9,
+		}).Return(nil)
+
+		if err := testProductServiceImpl.UpdateStockWithCAS(ctx, 1, -2); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("rejects an order without the customer", func(t *testing.T) {
+		anonymous := types.WithStockReference(context.Background(), "ORD-2002")
+		m.EXPECT().GetProductByID(anonymous, 1).Return(product, nil)
+
+		err := testProductServiceImpl.UpdateStockWithCAS(anonymous, 1, -1)
+		expectBizErrorCode(t, err, ProductCheckStatus_InvalidParam)
+	})
+
+	t.Run("does not need the customer for products without a limit", func(t *testing.T) {
+		anonymous := context.Background()
+		unlimited := &model.Product{Model: gorm.Model{ID: 2}, Stock: 50, Version: 1}
+		m.EXPECT().GetProductByID(anonymous, 2).Return(unlimited, nil)
+		m.EXPECT().UpdateStockWithCAS(anonymous, 1, gomock.Any()).Return(nil)
+
+		if err := testProductServiceImpl.UpdateStockWithCAS(anonymous, 2, -1); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("does not check restocks", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(product, nil)
+		m.EXPECT().UpdateStockWithCAS(ctx, 2, gomock.Any()).Return(nil)
+
+		if err := testProductServiceImpl.UpdateStockWithCAS(ctx, 1, 3); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestProductServiceImpl_SetPurchaseLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := context.Background()
+
+	expectBizErrorCode(t, testProductServiceImpl.SetPurchaseLimit(ctx, 1, -1), ProductCheckStatus_InvalidParam)
+
+	m.EXPECT().GetProductByID(ctx, 2).Return(nil, gorm.ErrRecordNotFound)
+	expectBizErrorCode(t, testProductServiceImpl.SetPurchaseLimit(ctx, 2, 3), ProductCheckStatus_NotExist)
+
+	m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}}, nil)
+	m.EXPECT().UpdatePurchaseLimit(ctx, 1, 3).Return(nil)
+	if err := testProductServiceImpl.SetPurchaseLimit(ctx, 1, 3); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"gorm.io/gorm"
+)
+
+const SavedItemStatus_NotExist = -12
+
+// ListSavedItems shows the user's wishlist or save-for-later list, or both when list is empty, with
+// the current price and availability of each product in the display currency. Products that have been
+// deleted are left out.
+func (c *CartServiceImpl) ListSavedItems(ctx context.Context, userId int, list string, currency string) (*data.SavedListVO, error) {
+	displayIn, err := displayCurrency(ctx, c.exchangeRateDao, currency)
+	if err != nil {
+		return nil, err
+	}
+	items, err := c.savedItemDao.QuerySavedItems(ctx, &model.SavedItem{UserID: userId, List: list})
+	if err != nil {
+		log.Logger.Errorf("CartService: ListSavedItems: Failed to query saved items: %v", err)
+		return nil, err
+	}
+	ret := &data.SavedListVO{Items: []data.SavedItemVO{}, Currency: displayIn.Code}
+	if len(items) == 0 {
+		return ret, nil
+	}
+	productIds := make([]int, 0, len(items))
+	for _, item := range items {
+		productIds = append(productIds, item.ProductID)
+	}
+	products, err := c.productDao.GetProductByIDs(ctx, productIds)
+	if err != nil {
+		log.Logger.Errorf("CartService: ListSavedItems: Failed to get products by IDs: %v", err)
+		return nil, err
+	}
+	productsById := make(map[int]*model.Product, len(products))
+	for _, product := range products {
+		productsById[int(product.ID)] = product
+	}
+	promotions, err := activePromotions(ctx, c.promotionDao)
+	if err != nil {
+		log.Logger.Errorf("CartService: ListSavedItems: Failed to load promotions: %v", err)
+		return nil, err
+	}
+	for _, item := range items {
+		product, exists := productsById[item.ProductID]
+		if !exists {
+			continue
+		}
+		// priced and checked for stock as if it were in the cart
+		detail := buildCartItemDetail(product, &model.ShoppingCartItem{ID: item.ID, Quantity: item.Quantity}, promotions, displayIn)
+		if product.Status != ProductStatu_Online {
+			detail.Status = data.CartItemStatus_Unavailable
+		}
+		ret.Items = append(ret.Items, data.SavedItemVO{
+			ID:          item.ID,
+			List:        item.List,
+			ProductInfo: detail.ProductInfo,
+			Quantity:    item.Quantity,
+			TotalPrice:  detail.TotalPrice,
+			Status:      detail.Status,
+			SavedAt:     item.UpdatedAt,
+		})
+	}
+	return ret, nil
+}
+
+// SaveItem puts a product on one of the user's lists. Saving a product that is already on the list
+// replaces its quantity. Out of stock products can be saved, products not on sale cannot.
+func (c *CartServiceImpl) SaveItem(ctx context.Context, userId int, req *data.SaveItemRequest) (int, error) {
+	product, err := c.productDao.GetProductByID(ctx, req.ProductID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("CartService: SaveItem: Failed to get product by ID: %v", err)
+		return 0, err
+	}
+	if product == nil || product.Status != ProductStatu_Online {
+		return 0, types.NewBizError(ProductCheckStatus_NotExist, "product not found or not available")
+	}
+	quantity := req.Quantity
+	if quantity <= 0 {
+		quantity = 1
+	}
+	existing, err := c.savedItemDao.QuerySavedItems(ctx, &model.SavedItem{UserID: userId, List: req.List, ProductID: req.ProductID})
+	if err != nil {
+		log.Logger.Errorf("CartService: SaveItem: Failed to query saved items: %v", err)
+		return 0, err
+	}
+	if len(existing) > 0 {
+		item := existing[0]
+		item.Quantity = quantity
+		item.UpdatedAt = time.Now()
+		err := c.savedItemDao.UpdateSavedItem(ctx, item)
+		if err == nil {
+			return item.ID, nil
+		}
+		// removed meanwhile, save it again below
+		if !errors.Is(err, dao.ErrSavedItemGone) {
+			return 0, err
+		}
+	}
+	item := &model.SavedItem{UserID: userId, List: req.List, ProductID: req.ProductID, Quantity: quantity}
+	if err := c.savedItemDao.CreateSavedItem(ctx, item); err != nil {
+		return 0, err
+	}
+	log.Logger.Infof("CartService: SaveItem: User %d saved product %d to %s", userId, req.ProductID, req.List)
+	return item.ID, nil
+}
+
+// DeleteSavedItem implements CartService.
+func (c *CartServiceImpl) DeleteSavedItem(ctx context.Context, userId int, id int) error {
+	deleted, err := c.savedItemDao.DeleteSavedItem(ctx, userId, id)
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return types.NewBizError(SavedItemStatus_NotExist, fmt.Sprintf("saved item %d not found", id))
+	}
+	return nil
+}
+
+// MoveToCart moves a saved item into the cart, adding to the quantity already there. The item stays
+// saved when the product cannot be bought in that quantity.
+func (c *CartServiceImpl) MoveToCart(ctx context.Context, userId int, savedItemId int) error {
+	saved, err := c.savedItemDao.GetSavedItem(ctx, savedItemId)
+	if err != nil {
+		return err
+	}
+	if saved == nil || saved.UserID != userId {
+		return types.NewBizError(SavedItemStatus_NotExist, fmt.Sprintf("saved item %d not found", savedItemId))
+	}
+	existingItems, err := c.cartItemDao.QueryItems(ctx, &model.ShoppingCartItem{UserID: userId, ProductID: saved.ProductID})
+	if err != nil {
+		log.Logger.Errorf("CartService: MoveToCart: Failed to query existing items: %v", err)
+		return err
+	}
+	now := time.Now()
+	cartItem := &model.ShoppingCartItem{UserID: userId, ProductID: saved.ProductID, CreatedAt: now}
+	if len(existingItems) > 0 {
+		cartItem = existingItems[0]
+	} else if bizErr := c.checkCartSize(ctx, userId); bizErr != nil {
+		return bizErr
+	}
+	cartItem.Quantity += saved.Quantity
+	product, bizErr := c.checkProductWithItem(ctx, &data.CartItemBasicVO{
+		UserID:    userId,
+		ProductID: saved.ProductID,
+		Quantity:  cartItem.Quantity,
+	})
+	if bizErr != nil {
+		return bizErr
+	}
+	cartItem.SelectStatus = model.CartItemStatusSelected
+	cartItem.PriceSnapshot = c.currentUnitPrice(ctx, product)
+	cartItem.UpdatedAt = now
+	if err := c.savedItemDao.MoveToCart(ctx, saved, cartItem); err != nil {
+		return savedItemMoveError(err, savedItemId)
+	}
+	log.Logger.Infof("CartService: MoveToCart: Moved saved item %d of user %d to cart item %d", saved.ID, userId, cartItem.ID)
+	return nil
+}
+
+// MoveFromCart moves a cart item to one of the user's lists, replacing the quantity if the product is
+// already on it.
+func (c *CartServiceImpl) MoveFromCart(ctx context.Context, userId int, cartItemId int, list string) error {
+	cartItem, err := c.cartItemDao.GetItemById(ctx, cartItemId)
+	if err != nil {
+		return err
+	}
+	if cartItem == nil || cartItem.UserID != userId {
+		return types.NewBizError(CartItemStatus_NotExist, "cart item not found or does not belong to user")
+	}
+	if list == "" {
+		list = model.SavedListSaveForLater
+	}
+	existing, err := c.savedItemDao.QuerySavedItems(ctx, &model.SavedItem{UserID: userId, List: list, ProductID: cartItem.ProductID})
+	if err != nil {
+		log.Logger.Errorf("CartService: MoveFromCart: Failed to query saved items: %v", err)
+		return err
+	}
+	saved := &model.SavedItem{UserID: userId, List: list, ProductID: cartItem.ProductID, CreatedAt: time.Now()}
+	if len(existing) > 0 {
+		saved = existing[0]
+	}
+	saved.Quantity = cartItem.Quantity
+	saved.UpdatedAt = time.Now()
+	if err := c.savedItemDao.MoveFromCart(ctx, cartItem, saved); err != nil {
+		return savedItemMoveError(err, saved.ID)
+	}
+	log.Logger.Infof("CartService: MoveFromCart: Moved cart item %d of user %d to %s", cartItem.ID, userId, list)
+	return nil
+}
+
+// savedItemMoveError reports an item that another request removed during a move as not found.
+func savedItemMoveError(err error, savedItemId int) error {
+	switch {
+	case errors.Is(err, dao.ErrSavedItemGone):
+		return types.NewBizError(SavedItemStatus_NotExist, fmt.Sprintf("saved item %d not found", savedItemId))
+	case errors.Is(err, dao.ErrCartItemChanged):
+		return types.NewBizError(CartItemStatus_NotExist, "cart item not found or does not belong to user")
+	}
+	return err
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/http/data"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestCartService_ListSavedItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	savedItemDao := mocks.NewMockSavedItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	cartService := &CartServiceImpl{savedItemDao: savedItemDao, productDao: productDao}
+
+	savedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	savedItemDao.EXPECT().QuerySavedItems(ctx, &model.SavedItem{UserID: 1, List: model.SavedListWishlist}).Return([]*model.SavedItem{
+		{ID: 1, UserID: 1, List: model.SavedListWishlist, ProductID: 1, Quantity: 2, UpdatedAt: savedAt},
+		{ID: 2, UserID: 1, List: model.SavedListWishlist, ProductID: 2, Quantity: 1, UpdatedAt: savedAt},
+		{ID: 3, UserID: 1, List: model.SavedListWishlist, ProductID: 3, Quantity: 1, UpdatedAt: savedAt},
+	}, nil)
+	// product 3 has been deleted
+	productDao.EXPECT().GetProductByIDs(ctx, []int{1, 2, 3}).Return([]*model.Product{
+		{Model: gorm.Model{ID: 1}, Name: "mug", Price: 1200, Stock: 1, Status: ProductStatu_Online},
+		{Model: gorm.Model{ID: 2}, Name: "vase", Price: 5000, Stock: 3, Status: ProductStatusUnpublished},
+	}, nil)
+
+	ret, err := cartService.ListSavedItems(ctx, 1, model.SavedListWishlist, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ret.Currency != "SGD" || len(ret.Items) != 2 {
+		t.Fatalf("Expected 2 items in SGD, got %+v", ret)
+	}
+	mug, vase := ret.Items[0], ret.Items[1]
+	if mug.ProductInfo.Price != 1200 || mug.TotalPrice != 2400 || mug.Status != data.CartItemStatus_OutOfStock || !mug.SavedAt.Equal(savedAt) {
+		t.Errorf("Expected the mug at the live price and short of stock, got %+v", mug)
+	}
+	if vase.Status != data.CartItemStatus_Unavailable {
+		t.Errorf("Expected the unpublished vase to be unavailable, got %+v", vase)
+	}
+}
+
+func TestCartService_SaveItem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	savedItemDao := mocks.NewMockSavedItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	cartService := &CartServiceImpl{savedItemDao: savedItemDao, productDao: productDao}
+
+	t.Run("Saves out of stock products", func(t *testing.T) {
+		productDao.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Stock: 0, Status: ProductStatu_Online}, nil)
+		savedItemDao.EXPECT().QuerySavedItems(ctx, &model.SavedItem{UserID: 1, List: model.SavedListWishlist, ProductID: 1}).Return(nil, nil)
+		savedItemDao.EXPECT().CreateSavedItem(ctx, gomock.Any()).DoAndReturn(func(ctx context.Context, item *model.SavedItem) error {
+			if item.Quantity != 1 {
+				t.Errorf("Expected the default quantity 1, got %d", item.Quantity)
+			}
+			item.ID = 5
+			return nil
+		})
+		id, err := cartService.SaveItem(ctx, 1, &data.SaveItemRequest{ProductID: 1, List: model.SavedListWishlist})
+		if err != nil || id != 5 {
+			t.Errorf("Expected saved item 5, got %d, %v", id, err)
+		}
+	})
+
+	t.Run("Rejects products not on sale", func(t *testing.T) {
+		productDao.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Status: ProductStatusUnpublished}, nil)
+		_, err := cartService.SaveItem(ctx, 1, &data.SaveItemRequest{ProductID: 2, List: model.SavedListWishlist})
+		expectBizErrorCode(t, err, ProductCheckStatus_NotExist)
+	})
+}
+
+func TestCartService_MoveToCart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	savedItemDao := mocks.NewMockSavedItemDao(ctrl)
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	productDao := mocks.NewMockProductDao(ctrl)
+	cartService := &CartServiceImpl{savedItemDao: savedItemDao, cartItemDao: cartItemDao, productDao: productDao}
+	saved := &model.SavedItem{ID: 4, UserID: 1, List: model.SavedListSaveForLater, ProductID: 1, Quantity: 2}
+	product := &model.Product{Model: gorm.Model{ID: 1}, Stock: 5, Status: ProductStatu_Online}
+
+	t.Run("Adds to the quantity already in the cart", func(t *testing.T) {
+		savedItemDao.EXPECT().GetSavedItem(ctx, 4).Return(saved, nil)
+		cartItemDao.EXPECT().QueryItems(ctx, &model.ShoppingCartItem{UserID: 1, ProductID: 1}).Return([]*model.ShoppingCartItem{
+			{ID: 9, UserID: 1, ProductID: 1, Quantity: 3, SelectStatus: model.CartItemStatusUnselected},
+		}, nil)
+		productDao.EXPECT().GetProductByID(ctx, 1).Return(product, nil)
+		savedItemDao.EXPECT().MoveToCart(ctx, saved, gomock.Any()).DoAndReturn(func(ctx context.Context, saved *model.SavedItem, item *model.ShoppingCartItem) error {
+			if item.ID != 9 || item.Quantity != 5 || item.SelectStatus != model.CartItemStatusSelected {
+				t.Errorf("Expected cart item 9 selected with 5, got %+v", item)
+			}
+			return nil
+		})
+		if err := cartService.MoveToCart(ctx, 1, 4); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("Keeps the item saved when stock is short", func(t *testing.T) {
+		savedItemDao.EXPECT().GetSavedItem(ctx, 4).Return(saved, nil)
+		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return([]*model.ShoppingCartItem{
+			{ID: 9, UserID: 1, ProductID: 1, Quantity: 4},
+		}, nil)
+		productDao.EXPECT().GetProductByID(ctx, 1).Return(product, nil)
+		expectBizErrorCode(t, cartService.MoveToCart(ctx, 1, 4), ProductCheckStatus_InsufficientStock)
+	})
+
+	t.Run("Rejects another user's item", func(t *testing.T) {
+		savedItemDao.EXPECT().GetSavedItem(ctx, 4).Return(saved, nil)
+		expectBizErrorCode(t, cartService.MoveToCart(ctx, 2, 4), SavedItemStatus_NotExist)
+	})
+
+	t.Run("Reports an item another request already moved", func(t *testing.T) {
+		savedItemDao.EXPECT().GetSavedItem(ctx, 4).Return(saved, nil)
+		cartItemDao.EXPECT().QueryItems(ctx, gomock.Any()).Return([]*model.ShoppingCartItem{
+			{ID: 9, UserID: 1, ProductID: 1, Quantity: 1},
+		}, nil)
+		productDao.EXPECT().GetProductByID(ctx, 1).Return(product, nil)
+		savedItemDao.EXPECT().MoveToCart(ctx, saved, gomock.Any()).Return(dao.ErrSavedItemGone)
+		expectBizErrorCode(t, cartService.MoveToCart(ctx, 1, 4), SavedItemStatus_NotExist)
+	})
+}
+
+func TestCartService_MoveFromCart(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ctx := context.Background()
+	savedItemDao := mocks.NewMockSavedItemDao(ctrl)
+	cartItemDao := mocks.NewMockShoppingCartItemDao(ctrl)
+	cartService := &CartServiceImpl{savedItemDao: savedItemDao, cartItemDao: cartItemDao}
+	cartItem := &model.ShoppingCartItem{ID: 9, UserID: 1, ProductID: 1, Quantity: 3}
+
+	cartItemDao.EXPECT().GetItemById(ctx, 9).Return(cartItem, nil).Times(2)
+	savedItemDao.EXPECT().QuerySavedItems(ctx, &model.SavedItem{UserID: 1, List: model.SavedListSaveForLater, ProductID: 1}).Return(nil, nil)
+	savedItemDao.EXPECT().MoveFromCart(ctx, cartItem, gomock.Any()).DoAndReturn(func(ctx context.Context, item *model.ShoppingCartItem, saved *model.SavedItem) error {
+		if saved.ID != 0 || saved.UserID != 1 || saved.List != model.SavedListSaveForLater || saved.Quantity != 3 {
+			t.Errorf("Expected a new save-for-later item of 3, got %+v", saved)
+		}
+		return nil
+	})
+	if err := cartService.MoveFromCart(ctx, 1, 9, ""); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	expectBizErrorCode(t, cartService.MoveFromCart(ctx, 2, 9, model.SavedListWishlist), CartItemStatus_NotExist)
+}
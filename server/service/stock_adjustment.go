@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"gorm.io/gorm"
+)
+
+// AdjustStock 按增量调整库存并记录调整原因，与 UpdateProductStock 不同，上架中的商品也可以调整。
+// 调整通过版本号 CAS 完成，与下单扣减并发时会重新读取库存后重试
+func (p *ProductServiceImpl) AdjustStock(ctx context.Context, id int, req *types.AdjustStockRequest) (*types.StockAdjustmentInfo, error) {
+	if err := checkAdjustStockRequest(req); err != nil {
+		return nil, err
+	}
+	var err error
+	for i := 0; i < maxCASRetries; i++ {
+		var adjustment *model.StockAdjustment
+		adjustment, err = p.adjustStock(ctx, id, req)
+		if err == nil {
+			return toStockAdjustmentInfo(adjustment), nil
+		}
+		if !errors.Is(err, dao.ErrVersionConflict) {
+			return nil, err
+		}
+		log.Logger.Warnf("AdjustStock: version conflict, product id: %d, retry: %d", id, i+1)
+	}
+	return nil, newVersionConflictError(id)
+}
+
+func (p *ProductServiceImpl) adjustStock(ctx context.Context, id int, req *types.AdjustStockRequest) (*model.StockAdjustment, error) {
+	product, err := p.productDao.GetProductByID(ctx, id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("AdjustStock: Failed to get product by ID: %v", err)
+		return nil, err
+	}
+	if product == nil {
+		return nil, newProductNotExistError(id)
+	}
+	// 预售/缺货接单的商品库存可以为负，下限与扣减库存时一致；补货总是允许的
+	stockAfter := int(product.Stock) + req.Delta
+	if req.Delta < 0 && int64(stockAfter) < -backorderAllowance(product) {
+		return nil, types.NewBizError(ProductCheckStatus_InsufficientStock,
+			fmt.Sprintf("stock cannot go below %d, product id: %d, current stock: %d, delta: %d", -backorderAllowance(product), id, product.Stock, req.Delta))
+	}
+
+	adjustment := &model.StockAdjustment{
+		ProductID:   id,
+		Delta:       req.Delta,
+		StockBefore: int(product.Stock),
+		StockAfter:  stockAfter,
+		Reason:      req.Reason,
+		Note:        req.Note,
+		OperatorID:  types.OperatorFromContext(ctx),
+	}
+	if err := p.productDao.AdjustStockWithCAS(ctx, int(product.Version), adjustment); err != nil {
+		if !errors.Is(err, dao.ErrVersionConflict) {
+			log.Logger.Errorf("AdjustStock: Failed to adjust stock: %v", err)
+		}
+		return nil, err
+	}
+	p.onStockChanged(ctx, product, int64(stockAfter))
+	return adjustment, nil
+}
+
+func checkAdjustStockRequest(req *types.AdjustStockRequest) error {
+	if req.Delta == 0 {
+		return types.NewBizError(ProductCheckStatus_InvalidParam, "delta cannot be 0")
+	}
+	switch req.Reason {
+	case model.StockAdjustReasonRestock:
+		if req.Delta < 0 {
+			return types.NewBizError(ProductCheckStatus_InvalidParam, "restock must increase stock")
+		}
+	case model.StockAdjustReasonDamage:
+		if req.Delta > 0 {
+			return types.NewBizError(ProductCheckStatus_InvalidParam, "damage must decrease stock")
+		}
+	case model.StockAdjustReasonCorrection:
+	default:
+		return types.NewBizError(ProductCheckStatus_InvalidParam, fmt.Sprintf("invalid reason: %s", req.Reason))
+	}
+	return nil
+}
+
+// GetStockAdjustments 按时间倒序分页返回库存调整记录
+func (p *ProductServiceImpl) GetStockAdjustments(ctx context.Context, id int, offset int, limit int) ([]*types.StockAdjustmentInfo, int, error) {
+	adjustments, total, err := p.productDao.ListStockAdjustments(ctx, id, offset, limit)
+	if err != nil {
+		log.Logger.Errorf("GetStockAdjustments: Failed to list stock adjustments: %v", err)
+		return nil, 0, err
+	}
+	ret := make([]*types.StockAdjustmentInfo, 0, len(adjustments))
+	for _, adjustment := range adjustments {
+		ret = append(ret, toStockAdjustmentInfo(adjustment))
+	}
+	return ret, total, nil
+}
+
+func toStockAdjustmentInfo(adjustment *model.StockAdjustment) *types.StockAdjustmentInfo {
+	return &types.StockAdjustmentInfo{
+		ID:          adjustment.ID,
+		Delta:       adjustment.Delta,
+		StockBefore: adjustment.StockBefore,
+		StockAfter:  adjustment.StockAfter,
+		Reason:      adjustment.Reason,
+		Note:        adjustment.Note,
+		OperatorID:  adjustment.OperatorID,
+		CreatedAt:   adjustment.CreatedAt,
+	}
+}
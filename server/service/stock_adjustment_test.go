@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestProductServiceImpl_AdjustStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m}
+	ctx := types.WithOperator(context.Background(), 3)
+
+	t.Run("上架中的商品补货", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Status: ProductStatusPublished, Stock: 5, Version: 2}, nil)
+		m.EXPECT().AdjustStockWithCAS(ctx, 2, &model.StockAdjustment{
+			ProductID:   1,
+			Delta:       10,
+			StockBefore: 5,
+			StockAfter:  15,
+			Reason:      model.StockAdjustReasonRestock,
+			Note:        "kiln batch 12",
+			OperatorID:  3,
+		}).Return(nil)
+
+		info, err := testProductServiceImpl.AdjustStock(ctx, 1, &types.AdjustStockRequest{Delta: 10, Reason: "restock", Note: "kiln batch 12"})
+		if err != nil || info.StockAfter != 15 {
+			t.Errorf("Unexpected result: %+v, %v", info, err)
+		}
+	})
+
+	t.Run("版本冲突后重新读取库存", func(t *testing.T) {
+		gomock.InOrder(
+			m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Stock: 5, Version: 1}, nil),
+			m.EXPECT().AdjustStockWithCAS(ctx, 1, gomock.Any()).Return(dao.ErrVersionConflict),
+			m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Stock: 4, Version: 2}, nil),
+			m.EXPECT().AdjustStockWithCAS(ctx, 2, gomock.Any()).Return(nil),
+		)
+		info, err := testProductServiceImpl.AdjustStock(ctx, 2, &types.AdjustStockRequest{Delta: -1, Reason: "damage"})
+		if err != nil || info.StockBefore != 4 || info.StockAfter != 3 {
+			t.Errorf("Unexpected result: %+v, %v", info, err)
+		}
+	})
+
+	t.Run("库存不能为负", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 3).Return(&model.Product{Model: gorm.Model{ID: 3}, Stock: 1}, nil)
+		_, err := testProductServiceImpl.AdjustStock(ctx, 3, &types.AdjustStockRequest{Delta: -2, Reason: "correction"})
+		expectBizErrorCode(t, err, ProductCheckStatus_InsufficientStock)
+	})
+
+	t.Run("缺货接单的商品可以减到负库存下限", func(t *testing.T) {
+		product := &model.Product{Model: gorm.Model{ID: 4}, Stock: -5, Version: 1, StockMode: StockModeBackorder, MaxBackorder: 10}
+		m.EXPECT().GetProductByID(ctx, 4).Return(product, nil)
+		m.EXPECT().AdjustStockWithCAS(ctx, 1, gomock.Any()).Return(nil)
+		info, err := testProductServiceImpl.AdjustStock(ctx, 4, &types.AdjustStockRequest{Delta: 3, Reason: "restock"})
+		if err != nil || info.StockAfter != -2 {
+			t.Errorf("Unexpected result: %+v, %v", info, err)
+		}
+
+		m.EXPECT().GetProductByID(ctx, 4).Return(product, nil)
+		_, err = testProductServiceImpl.AdjustStock(ctx, 4, &types.AdjustStockRequest{Delta: -6, Reason: "damage"})
+		expectBizErrorCode(t, err, ProductCheckStatus_InsufficientStock)
+	})
+
+	t.Run("普通商品负库存时仍可补货", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 5).Return(&model.Product{Model: gorm.Model{ID: 5}, Stock: -3, Version: 1, StockMode: StockModeNormal}, nil)
+		m.EXPECT().AdjustStockWithCAS(ctx, 1, gomock.Any()).Return(nil)
+		info, err := testProductServiceImpl.AdjustStock(ctx, 5, &types.AdjustStockRequest{Delta: 1, Reason: "restock"})
+		if err != nil || info.StockAfter != -2 {
+			t.Errorf("Unexpected result: %+v, %v", info, err)
+		}
+	})
+
+	t.Run("原因与增减方向不符", func(t *testing.T) {
+		reqs := []*types.AdjustStockRequest{
+			{Delta: -1, Reason: "restock"},
+			{Delta: 1, Reason: "damage"},
+			{Delta: 0, Reason: "correction"},
+			{Delta: 1, Reason: "gift"},
+		}
+		for _, req := range reqs {
+			_, err := testProductServiceImpl.AdjustStock(ctx, 1, req)
+			expectBizErrorCode(t, err, ProductCheckStatus_InvalidParam)
+		}
+	})
+}
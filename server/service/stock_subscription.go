@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/log"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"gorm.io/gorm"
+)
+
+const topicBackInStock = "product_back_in_stock"
+
+// BackInStockMessage 已订阅的商品重新可购买时发送的事件，由通知服务推送给 UserIDs
+type BackInStockMessage struct {
+	ProductID  int       `json:"product_id"`
+	Name       string    `json:"name"`
+	Stock      int64     `json:"stock"`
+	UserIDs    []int     `json:"user_ids"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// SubscribeBackInStock 订阅到货通知，只能订阅已上架且无库存的商品
+func (p *ProductServiceImpl) SubscribeBackInStock(ctx context.Context, id int, userId int) error {
+	product, err := p.productDao.GetProductByID(ctx, id)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Logger.Errorf("SubscribeBackInStock: Failed to get product by ID: %v", err)
+		return err
+	}
+	if product == nil || product.Status != ProductStatusPublished {
+		return newProductNotExistError(id)
+	}
+	if product.Stock > 0 {
+		return types.NewBizError(ProductCheckStatus_InvalidParam, fmt.Sprintf("product (ID: %d) is in stock", id))
+	}
+	return p.subscriptionDao.Subscribe(ctx, id, userId)
+}
+
+// UnsubscribeBackInStock 取消到货通知
+func (p *ProductServiceImpl) UnsubscribeBackInStock(ctx context.Context, id int, userId int) error {
+	return p.subscriptionDao.Unsubscribe(ctx, id, userId)
+}
+
+// notifyBackInStock 通知所有订阅用户并删除已通知的订阅，失败只记录日志：
+// 同步等待 Kafka 确认，未送达时订阅保留，下次补货时再通知
+func (p *ProductServiceImpl) notifyBackInStock(ctx context.Context, product *model.Product, stock int64) {
+	if p.subscriptionDao == nil {
+		return
+	}
+	id := int(product.ID)
+	userIds, err := p.subscriptionDao.ListSubscriberIDs(ctx, id)
+	if err != nil || len(userIds) == 0 {
+		return
+	}
+	msg, err := json.Marshal(&BackInStockMessage{
+		ProductID:  id,
+		Name:       product.Name,
+		Stock:      stock,
+		UserIDs:    userIds,
+		OccurredAt: time.Now(),
+	})
+	if err != nil {
+		log.Logger.Errorf("notifyBackInStock: Failed to marshal message: %v", err)
+		return
+	}
+	if err := p.eventProducer.PublishSync(ctx, topicBackInStock, strconv.Itoa(id), msg); err != nil {
+		log.Logger.Errorf("notifyBackInStock: Failed to publish back in stock event, product id: %d, err: %v", id, err)
+		return
+	}
+	log.Logger.Infof("Product %d is back in stock, notified %d subscribers", id, len(userIds))
+	if err := p.subscriptionDao.DeleteSubscriptions(ctx, id, userIds); err != nil {
+		log.Logger.Errorf("notifyBackInStock: Failed to delete fulfilled subscriptions, product id: %d, err: %v", id, err)
+	}
+}
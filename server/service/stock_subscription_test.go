@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	proxyMocks "github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/proxy/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/dao/mocks"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/repository/model"
+	"github.com/NUS-ISS-Agile-Team/ceramicraft-commodity-mservice/server/types"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestProductServiceImpl_SubscribeBackInStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	s := mocks.NewMockStockSubscriptionDao(ctrl)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m, subscriptionDao: s}
+	ctx := context.Background()
+
+	t.Run("订阅无库存的商品", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Status: ProductStatusPublished}, nil)
+		s.EXPECT().Subscribe(ctx, 1, 7).Return(nil)
+
+		if err := testProductServiceImpl.SubscribeBackInStock(ctx, 1, 7); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("有库存的商品不能订阅", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Status: ProductStatusPublished, Stock: 3}, nil)
+
+		err := testProductServiceImpl.SubscribeBackInStock(ctx, 2, 7)
+		expectBizErrorCode(t, err, ProductCheckStatus_InvalidParam)
+	})
+
+	t.Run("未上架的商品不能订阅", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 3).Return(&model.Product{Model: gorm.Model{ID: 3}, Status: ProductStatusDraft}, nil)
+
+		err := testProductServiceImpl.SubscribeBackInStock(ctx, 3, 7)
+		expectBizErrorCode(t, err, ProductCheckStatus_NotExist)
+	})
+}
+
+func TestProductServiceImpl_AdjustStock_BackInStock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := mocks.NewMockProductDao(ctrl)
+	s := mocks.NewMockStockSubscriptionDao(ctrl)
+	producer := proxyMocks.NewEventProducer(t)
+	testProductServiceImpl := &ProductServiceImpl{productDao: m, subscriptionDao: s, eventProducer: producer}
+	ctx := context.Background()
+
+	t.Run("售罄商品补货后通知并删除订阅", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 1).Return(&model.Product{Model: gorm.Model{ID: 1}, Name: "Mug", Status: ProductStatusPublished, Version: 1}, nil)
+		m.EXPECT().AdjustStockWithCAS(ctx, 1, gomock.Any()).Return(nil)
+		s.EXPECT().ListSubscriberIDs(ctx, 1).Return([]int{7, 9}, nil)
+		producer.On("PublishSync", ctx, topicBackInStock, "1", mock.MatchedBy(func(value []byte) bool {
+			var msg BackInStockMessage
+			return json.Unmarshal(value, &msg) == nil && msg.Stock == 10 && len(msg.UserIDs) == 2
+		})).Return(nil).Once()
+		s.EXPECT().DeleteSubscriptions(ctx, 1, []int{7, 9}).Return(nil)
+
+		if _, err := testProductServiceImpl.AdjustStock(ctx, 1, &types.AdjustStockRequest{Delta: 10, Reason: "restock"}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("通知未送达时保留订阅", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 3).Return(&model.Product{Model: gorm.Model{ID: 3}, Name: "Bowl", Status: ProductStatusPublished, Version: 1}, nil)
+		m.EXPECT().AdjustStockWithCAS(ctx, 1, gomock.Any()).Return(nil)
+		s.EXPECT().ListSubscriberIDs(ctx, 3).Return([]int{7}, nil)
+		producer.On("PublishSync", ctx, topicBackInStock, "3", mock.Anything).Return(errors.New("leader not available")).Once()
+		// 不会调用 DeleteSubscriptions
+
+		if _, err := testProductServiceImpl.AdjustStock(ctx, 3, &types.AdjustStockRequest{Delta: 5, Reason: "restock"}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+
+	t.Run("原本有库存时不通知", func(t *testing.T) {
+		m.EXPECT().GetProductByID(ctx, 2).Return(&model.Product{Model: gorm.Model{ID: 2}, Status: ProductStatusPublished, Stock: 2, Version: 1}, nil)
+		m.EXPECT().AdjustStockWithCAS(ctx, 1, gomock.Any()).Return(nil)
+
+		if _, err := testProductServiceImpl.AdjustStock(ctx, 2, &types.AdjustStockRequest{Delta: 10, Reason: "restock"}); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,20 @@
+package types
+
+type CollectionInfo struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Desc    string `json:"desc"`
+	PicInfo string `json:"pic_info"`
+	Status  int32  `json:"status"` // 0: 隐藏, 1: 用户可见
+}
+
+type SaveCollectionRequest struct {
+	Name    string `json:"name"`
+	Desc    string `json:"desc"`
+	PicInfo string `json:"pic_info"`
+	Status  int32  `json:"status"` // 0: 隐藏, 1: 用户可见
+}
+
+type UpdateCollectionProductsRequest struct {
+	ProductIDs []int `json:"product_ids"` // 按展示顺序排列
+}
@@ -0,0 +1,42 @@
+package types
+
+import "context"
+
+type operatorKey struct{}
+
+// WithOperator 在 context 中记录当前操作人 userID，用于审计记录
+func WithOperator(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, operatorKey{}, userID)
+}
+
+// OperatorFromContext 返回 context 中的操作人 userID，未设置时返回 0 (系统操作)
+func OperatorFromContext(ctx context.Context) int {
+	userID, _ := ctx.Value(operatorKey{}).(int)
+	return userID
+}
+
+type stockReferenceKey struct{}
+
+// WithStockReference 在 context 中记录引起库存变化的业务单号 (如订单号)，写入库存流水
+func WithStockReference(ctx context.Context, referenceID string) context.Context {
+	return context.WithValue(ctx, stockReferenceKey{}, referenceID)
+}
+
+// StockReferenceFromContext 返回 context 中的业务单号，未设置时返回空字符串
+func StockReferenceFromContext(ctx context.Context) string {
+	referenceID, _ := ctx.Value(stockReferenceKey{}).(string)
+	return referenceID
+}
+
+type customerKey struct{}
+
+// WithCustomer 在 context 中记录下单顾客的 userID，用于限购检查和库存流水
+func WithCustomer(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, customerKey{}, userID)
+}
+
+// CustomerFromContext 返回 context 中的下单顾客 userID，未设置时返回 0
+func CustomerFromContext(ctx context.Context) int {
+	userID, _ := ctx.Value(customerKey{}).(int)
+	return userID
+}
@@ -1,18 +1,37 @@
 package types
 
+import "time"
+
 type ProductInfo struct {
-	Name             string `json:"name"`
-	Category         string `json:"category"`
-	Price            int64  `json:"price"`
-	Desc             string `json:"desc"`
-	Stock            int64  `json:"stock"`
-	PicInfo          string `json:"pic_info"`
-	Dimensions       string `json:"dimensions"`
-	Material         string `json:"material"`
-	Weight           string `json:"weight"`
-	Capacity         string `json:"capacity"`
-	CareInstructions string `json:"care_instructions"`
-	Status           int32  `json:"status"` // 0: 未上架, 1: 已上架
+	Name             string `json:"name" binding:"required,max=255"`
+	Category         string `json:"category" binding:"required,max=255"`
+	Price            int64  `json:"price" binding:"min=0"`
+	Desc             string `json:"desc" binding:"max=5000"`
+	Stock            int64  `json:"stock" binding:"min=0"`
+	PicInfo          string `json:"pic_info" binding:"imageid"`
+	Dimensions       string `json:"dimensions" binding:"max=255"`
+	Material         string `json:"material" binding:"max=255"`
+	Weight           string `json:"weight" binding:"max=255"`
+	Capacity         string `json:"capacity" binding:"max=255"`
+	CareInstructions string `json:"care_instructions" binding:"max=5000"`
+	Status           int32  `json:"status" binding:"productstatus"` // 0: 下架, 1: 上架, 2: 草稿, 3: 待审核, 4: 已归档, 5: 已删除
+
+	LowStockThreshold int64 `json:"low_stock_threshold" binding:"min=0"` // 库存小于等于该值时预警，0 表示不预警
+	MaxPerCustomer    int64 `json:"max_per_customer" binding:"min=0"`    // 每位顾客累计最多购买的数量，0 表示不限购
+
+	StockMode        string     `json:"stock_mode" binding:"omitempty,oneof=normal preorder backorder"` // 默认 normal
+	MaxBackorder     int64      `json:"max_backorder" binding:"min=0"`                                  // 库存为 0 后还可以下单的数量
+	ExpectedShipDate *time.Time `json:"expected_ship_date,omitempty"`                                   // 预售商品必填
+	Availability     string     `json:"availability"`                                                   // 仅响应: in_stock, out_of_stock, preorder, backorder
+
+	SalePrice     int64  `json:"sale_price,omitempty"`     // 仅响应: 进行中促销的价格，price 为原价
+	PromotionName string `json:"promotion_name,omitempty"` // 仅响应: 促销活动名称
+
+	LowestPrice30d int64  `json:"lowest_price_30d,omitempty"` // 仅响应 (用户侧): 近 30 天内的最低原价
+	Currency       string `json:"currency"`                   // 仅响应: 以上价格的币种 (ISO 4217)，商家侧始终为基础货币
+
+	Version   int64     `json:"version"`    // 商品版本号，每次修改递增
+	UpdatedAt time.Time `json:"updated_at"` // 最后修改时间
 }
 
 type ProductSimplifiedInfo struct {
@@ -23,43 +42,190 @@ type ProductSimplifiedInfo struct {
 	Desc     string `json:"desc"`
 	Stock    int64  `json:"stock"`
 	PicInfo  string `json:"pic_info"`
-	Status   int32  `json:"status"` // 0: 未上架, 1: 已上架
+	Status   int32  `json:"status"` // 0: 下架, 1: 上架, 2: 草稿, 3: 待审核, 4: 已归档, 5: 已删除
+
+	Availability     string     `json:"availability"` // in_stock, out_of_stock, preorder, backorder
+	ExpectedShipDate *time.Time `json:"expected_ship_date,omitempty"`
+
+	SalePrice     int64  `json:"sale_price,omitempty"` // 进行中促销的价格，price 为原价
+	PromotionName string `json:"promotion_name,omitempty"`
+	Currency      string `json:"currency"` // 价格的币种 (ISO 4217)
+
+	Version   int64     `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 type UpdateProductStatusRequest struct {
-	Status int `json:"status"` // 0-新的状态是下架，1-新的状态是上架
+	Status int `json:"status" binding:"productstatus"` // 目标状态，需符合商品生命周期的状态流转规则
 }
 
 type UpdateProductStockRequest struct {
-	Stock int `json:"stock"`
+	Stock int `json:"stock" binding:"min=0"`
+}
+
+// UpdateStockModeRequest 设置预售/缺货下单，mode 为 normal 时忽略其余字段
+type UpdateStockModeRequest struct {
+	Mode             string     `json:"mode" binding:"required,oneof=normal preorder backorder"`
+	MaxBackorder     int64      `json:"max_backorder" binding:"min=0"`
+	ExpectedShipDate *time.Time `json:"expected_ship_date"`
+}
+
+type UpdateLowStockThresholdRequest struct {
+	Threshold int `json:"threshold" binding:"min=0"` // 0 表示关闭预警
+}
+
+type UpdatePurchaseLimitRequest struct {
+	MaxPerCustomer int `json:"max_per_customer" binding:"min=0"` // 0 表示不限购
 }
 
 type GetProductListQuery struct {
-	Keyword    string `json:"keyword"`
-	Category   string `json:"category"`
-	Offset     int    `json:"offset"`
-	Limit      int    `json:"limit"`
-	IsCustomer bool   `json:"is_customer"`
-	OrderBy    int    `json:"order_by"` // 0-updateTime desc, 1-updateTime inc
+	Keyword      string `json:"keyword"`
+	Category     string `json:"category"`
+	Tag          string `json:"tag"`
+	CollectionID int    `json:"collection_id"`
+	Offset       int    `json:"offset"`
+	Limit        int    `json:"limit"`
+	IsCustomer   bool   `json:"is_customer"`
+	OrderBy      int    `json:"order_by"` // 0-updateTime desc, 1-updateTime inc
+	Currency     string `json:"currency"` // 展示币种，为空时使用基础货币
 }
 
 type GetProductListRequest struct {
 	Keyword  string `json:"keyword"`
 	Category string `json:"category"`
+	Tag      string `json:"tag"`
 	Offset   int    `json:"offset"`
 	OrderBy  int    `json:"order_by"` // 0-updateTime desc, 1-updateTime inc
 }
 
 type UpdateProductInfoRequest struct {
 	ID               int    `json:"id"`
-	Name             string `json:"name"`
-	Category         string `json:"category"`
-	Price            int64  `json:"price"`
-	Desc             string `json:"desc"`
-	PicInfo          string `json:"pic_info"`
-	Dimensions       string `json:"dimensions"`
-	Material         string `json:"material"`
-	Weight           string `json:"weight"`
-	Capacity         string `json:"capacity"`
-	CareInstructions string `json:"care_instructions"`
+	Version          *int64 `json:"version" binding:"required"` // 读取商品时返回的版本号，用于检测并发修改
+	Name             string `json:"name" binding:"required,max=255"`
+	Category         string `json:"category" binding:"required,max=255"`
+	Price            int64  `json:"price" binding:"min=0"`
+	Desc             string `json:"desc" binding:"max=5000"`
+	PicInfo          string `json:"pic_info" binding:"imageid"`
+	Dimensions       string `json:"dimensions" binding:"max=255"`
+	Material         string `json:"material" binding:"max=255"`
+	Weight           string `json:"weight" binding:"max=255"`
+	Capacity         string `json:"capacity" binding:"max=255"`
+	CareInstructions string `json:"care_instructions" binding:"max=5000"`
+}
+
+type UpdateProductTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// PatchProductInfoRequest 部分更新商品信息，字段为 nil (未传或传 null) 表示不修改，
+// 传空字符串表示清空该字段
+type PatchProductInfoRequest struct {
+	Version          *int64  `json:"version" binding:"required"` // 读取商品时返回的版本号，必填
+	Name             *string `json:"name" binding:"omitnil,min=1,max=255"`
+	Category         *string `json:"category" binding:"omitnil,min=1,max=255"`
+	Price            *int64  `json:"price" binding:"omitnil,min=0"`
+	Desc             *string `json:"desc" binding:"omitnil,max=5000"`
+	PicInfo          *string `json:"pic_info" binding:"omitnil,imageid"`
+	Dimensions       *string `json:"dimensions" binding:"omitnil,max=255"`
+	Material         *string `json:"material" binding:"omitnil,max=255"`
+	Weight           *string `json:"weight" binding:"omitnil,max=255"`
+	Capacity         *string `json:"capacity" binding:"omitnil,max=255"`
+	CareInstructions *string `json:"care_instructions" binding:"omitnil,max=5000"`
+}
+
+type ProductStatusTransitionInfo struct {
+	FromStatus int32     `json:"from_status"`
+	ToStatus   int32     `json:"to_status"`
+	OperatorID int       `json:"operator_id"` // 0 表示系统操作
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CreateProductScheduleRequest struct {
+	Action      string    `json:"action" binding:"required,oneof=publish unpublish"`
+	ScheduledAt time.Time `json:"scheduled_at" binding:"required"` // RFC3339 格式，必须晚于当前时间
+}
+
+type ProductScheduleInfo struct {
+	ID          int        `json:"id"`
+	ProductID   int        `json:"product_id"`
+	Action      string     `json:"action"` // publish | unpublish
+	ScheduledAt time.Time  `json:"scheduled_at"`
+	Status      int        `json:"status"` // 0: 等待执行, 1: 已执行, 2: 已取消, 3: 执行失败
+	LastError   string     `json:"last_error,omitempty"`
+	Attempts    int        `json:"attempts"` // 执行出错后重试的次数
+	ExecutedAt  *time.Time `json:"executed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type PurgeDeletedProductsRequest struct {
+	OlderThanDays int `json:"older_than_days" binding:"required,min=1"` // 只清理软删除超过该天数的商品
+}
+
+type ReadinessCheck struct {
+	Check   string `json:"check"` // required_field | image | price | stock | category
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type PublishReadiness struct {
+	Ready        bool             `json:"ready"`
+	FailedChecks []ReadinessCheck `json:"failed_checks"`
+}
+
+type AdjustStockRequest struct {
+	Delta  int    `json:"delta" binding:"required"`                                  // 库存变化量，正数增加，负数减少
+	Reason string `json:"reason" binding:"required,oneof=restock damage correction"` // restock 只能增加，damage 只能减少
+	Note   string `json:"note" binding:"max=255"`
+}
+
+type StockAdjustmentInfo struct {
+	ID          int       `json:"id"`
+	Delta       int       `json:"delta"`
+	StockBefore int       `json:"stock_before"`
+	StockAfter  int       `json:"stock_after"`
+	Reason      string    `json:"reason"`
+	Note        string    `json:"note"`
+	OperatorID  int       `json:"operator_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// InventoryLedgerEntryInfo 库存流水
+type InventoryLedgerEntryInfo struct {
+	ID          int       `json:"id"`
+	Source      string    `json:"source"`       // initial, merchant_set, order, adjustment
+	ReferenceID string    `json:"reference_id"` // 订单号或库存调整记录ID
+	Delta       int       `json:"delta"`
+	StockAfter  int       `json:"stock_after"`
+	OperatorID  int       `json:"operator_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PriceChangeInfo 价格变更记录，EffectiveTo 为 nil 表示当前生效的价格
+type PriceChangeInfo struct {
+	ID            int        `json:"id"`
+	OldPrice      int64      `json:"old_price"` // 创建商品时的初始价格为 0
+	NewPrice      int64      `json:"new_price"`
+	OperatorID    int        `json:"operator_id"` // 0 表示系统操作
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+}
+
+// InventoryReconciliation 库存对账结果，Consistent 为 false 时说明存在未记录流水的库存变化
+type InventoryReconciliation struct {
+	ProductID   int  `json:"product_id"`
+	Stock       int  `json:"stock"`
+	LedgerTotal int  `json:"ledger_total"`
+	Difference  int  `json:"difference"` // Stock - LedgerTotal
+	EntryCount  int  `json:"entry_count"`
+	Consistent  bool `json:"consistent"`
+}
+
+// LowStockProductInfo 库存低于预警阈值的商品
+type LowStockProductInfo struct {
+	ID                int    `json:"id"`
+	Name              string `json:"name"`
+	Category          string `json:"category"`
+	Stock             int64  `json:"stock"`
+	LowStockThreshold int64  `json:"low_stock_threshold"`
+	Status            int32  `json:"status"`
 }